@@ -91,11 +91,51 @@ func (ws *webServer) createGroups() error {
 	}
 	groupsMap["status"] = statusGroup
 
+	err = ws.createIngestGroup(groupsMap)
+	if err != nil {
+		return err
+	}
+
+	err = ws.createDeadLettersGroup(groupsMap)
+	if err != nil {
+		return err
+	}
+
 	ws.groups = groupsMap
 
 	return nil
 }
 
+func (ws *webServer) createIngestGroup(groupsMap map[string]shared.GroupHandler) error {
+	if ws.apiConfig.Ingest.AuthToken == "" {
+		log.Debug("the ingest group is disabled because no auth token is configured")
+		return nil
+	}
+
+	ingestGroup, err := groups.NewIngestGroup(ws.facade, ws.apiConfig.Ingest)
+	if err != nil {
+		return err
+	}
+	groupsMap["ingest"] = ingestGroup
+
+	return nil
+}
+
+func (ws *webServer) createDeadLettersGroup(groupsMap map[string]shared.GroupHandler) error {
+	if ws.apiConfig.DeadLetters.AuthToken == "" {
+		log.Debug("the dead letters group is disabled because no auth token is configured")
+		return nil
+	}
+
+	deadLettersGroup, err := groups.NewDeadLettersGroup(ws.facade, ws.apiConfig.DeadLetters.AuthToken)
+	if err != nil {
+		return err
+	}
+	groupsMap["deadletters"] = deadLettersGroup
+
+	return nil
+}
+
 func (ws *webServer) registerRoutes(ginRouter *gin.Engine) {
 	for groupName, groupHandler := range ws.groups {
 		log.Debug("registering gin API group", "group name", groupName)
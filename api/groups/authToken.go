@@ -0,0 +1,23 @@
+package groups
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkAuthToken will abort the requests that do not carry the provided auth token as a bearer token
+func checkAuthToken(c *gin.Context, authToken []byte) {
+	authorization := c.GetHeader(authorizationField)
+	token := strings.TrimPrefix(authorization, bearerPrefix)
+	isValid := strings.HasPrefix(authorization, bearerPrefix) && subtle.ConstantTimeCompare([]byte(token), authToken) == 1
+	if !isValid {
+		returnStatus(c, nil, http.StatusUnauthorized, "invalid auth token", codeUnauthorized)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
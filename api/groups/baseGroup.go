@@ -37,10 +37,29 @@ func (bg *baseGroup) RegisterRoutes(
 			continue
 		}
 
-		ws.Handle(handlerData.Method, handlerData.Path, handlerData.Handler)
+		ws.Handle(handlerData.Method, handlerData.Path, getHandlersChain(handlerData)...)
 	}
 }
 
+func getHandlersChain(handlerData *shared.EndpointHandlerData) []gin.HandlerFunc {
+	handlers := make([]gin.HandlerFunc, 0, len(handlerData.AdditionalMiddlewares)+1)
+	for _, middleware := range handlerData.AdditionalMiddlewares {
+		if middleware.Position == shared.Before {
+			handlers = append(handlers, middleware.Middleware)
+		}
+	}
+
+	handlers = append(handlers, handlerData.Handler)
+
+	for _, middleware := range handlerData.AdditionalMiddlewares {
+		if middleware.Position == shared.After {
+			handlers = append(handlers, middleware.Middleware)
+		}
+	}
+
+	return handlers
+}
+
 func getEndpointProperties(ws *gin.RouterGroup, path string, apiConfig config.ApiRoutesConfig) endpointProperties {
 	basePath := ws.BasePath()
 
@@ -0,0 +1,114 @@
+package groups
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/api/shared"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/deadletters"
+)
+
+const (
+	listPath     = "/list"
+	resubmitPath = "/resubmit"
+)
+
+// resubmitRequest holds the IDs of the dead letters to be re-submitted. All of them are re-submitted when it is empty
+type resubmitRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type deadLettersGroup struct {
+	*baseGroup
+	facade    shared.FacadeHandler
+	authToken []byte
+}
+
+// NewDeadLettersGroup returns a new instance of dead letters group. All its endpoints require the provided auth token
+func NewDeadLettersGroup(facade shared.FacadeHandler, authToken string) (*deadLettersGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for dead letters group", core.ErrNilFacadeHandler)
+	}
+	if authToken == "" {
+		return nil, fmt.Errorf("%w for dead letters group", errEmptyAuthToken)
+	}
+
+	dlg := &deadLettersGroup{
+		facade:    facade,
+		authToken: []byte(authToken),
+		baseGroup: &baseGroup{},
+	}
+
+	authMiddleware := []shared.AdditionalMiddleware{
+		{
+			Middleware: dlg.checkAuthToken,
+			Position:   shared.Before,
+		},
+	}
+	endpoints := []*shared.EndpointHandlerData{
+		{
+			Path:                  listPath,
+			Handler:               dlg.getDeadLetters,
+			Method:                http.MethodGet,
+			AdditionalMiddlewares: authMiddleware,
+		},
+		{
+			Path:                  resubmitPath,
+			Handler:               dlg.resubmitDeadLetters,
+			Method:                http.MethodPost,
+			AdditionalMiddlewares: authMiddleware,
+		},
+	}
+	dlg.endpoints = endpoints
+
+	return dlg, nil
+}
+
+// checkAuthToken will abort the requests that do not carry the configured auth token as a bearer token
+func (dlg *deadLettersGroup) checkAuthToken(c *gin.Context) {
+	checkAuthToken(c, dlg.authToken)
+}
+
+// getDeadLetters will expose the documents rejected by Elasticsearch in json format
+func (dlg *deadLettersGroup) getDeadLetters(c *gin.Context) {
+	deadLetters, err := dlg.facade.GetDeadLetters()
+	if err != nil {
+		returnStatus(c, nil, http.StatusInternalServerError, err.Error(), codeInternalIssue)
+		return
+	}
+
+	returnStatus(c, gin.H{"deadLetters": deadLetters}, http.StatusOK, "", codeSuccessful)
+}
+
+// resubmitDeadLetters will index again the dead letters from the request body, or all of them when none is provided
+func (dlg *deadLettersGroup) resubmitDeadLetters(c *gin.Context) {
+	req := &resubmitRequest{}
+	if c.Request.ContentLength != 0 {
+		err := c.ShouldBindJSON(req)
+		if err != nil {
+			returnStatus(c, nil, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err.Error()), codeBadRequest)
+			return
+		}
+	}
+
+	response, err := dlg.facade.ResubmitDeadLetters(req.IDs)
+	if errors.Is(err, deadletters.ErrDeadLettersDisabled) {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), codeBadRequest)
+		return
+	}
+	if err != nil {
+		returnStatus(c, nil, http.StatusInternalServerError, err.Error(), codeInternalIssue)
+		return
+	}
+
+	returnStatus(c, gin.H{"result": response}, http.StatusOK, "", codeSuccessful)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dlg *deadLettersGroup) IsInterfaceNil() bool {
+	return dlg == nil
+}
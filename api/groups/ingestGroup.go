@@ -0,0 +1,128 @@
+package groups
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/api/shared"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
+)
+
+const (
+	topicPath          = "/:topic"
+	topicParam         = "topic"
+	versionQueryParam  = "version"
+	bearerPrefix       = "Bearer "
+	codeSuccessful     = "successful"
+	codeBadRequest     = "bad_request"
+	codeTooLarge       = "payload_too_large"
+	codeUnauthorized   = "unauthorized"
+	codeInternalIssue  = "internal_issue"
+	authorizationField = "Authorization"
+
+	defaultMaxPayloadSizeInBytes = 128 * 1024 * 1024
+)
+
+var errEmptyAuthToken = errors.New("empty auth token")
+
+type ingestGroup struct {
+	*baseGroup
+	facade         shared.FacadeHandler
+	authToken      []byte
+	maxPayloadSize int64
+}
+
+// NewIngestGroup returns a new instance of ingest group. All its endpoints require the configured auth token
+func NewIngestGroup(facade shared.FacadeHandler, ingestCfg config.IngestConfig) (*ingestGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for ingest group", core.ErrNilFacadeHandler)
+	}
+	if ingestCfg.AuthToken == "" {
+		return nil, fmt.Errorf("%w for ingest group", errEmptyAuthToken)
+	}
+
+	maxPayloadSize := ingestCfg.MaxPayloadSizeInBytes
+	if maxPayloadSize <= 0 {
+		maxPayloadSize = defaultMaxPayloadSizeInBytes
+	}
+
+	ig := &ingestGroup{
+		facade:         facade,
+		authToken:      []byte(ingestCfg.AuthToken),
+		maxPayloadSize: maxPayloadSize,
+		baseGroup:      &baseGroup{},
+	}
+
+	endpoints := []*shared.EndpointHandlerData{
+		{
+			Path:    topicPath,
+			Handler: ig.ingestPayload,
+			Method:  http.MethodPost,
+			AdditionalMiddlewares: []shared.AdditionalMiddleware{
+				{
+					Middleware: ig.checkAuthToken,
+					Position:   shared.Before,
+				},
+			},
+		},
+	}
+	ig.endpoints = endpoints
+
+	return ig, nil
+}
+
+// checkAuthToken will abort the requests that do not carry the configured auth token as a bearer token
+func (ig *ingestGroup) checkAuthToken(c *gin.Context) {
+	checkAuthToken(c, ig.authToken)
+}
+
+// ingestPayload will index the marshalled outport payload from the request body
+func (ig *ingestGroup) ingestPayload(c *gin.Context) {
+	topic := c.Param(topicParam)
+
+	version := wsindexer.CurrentPayloadVersion
+	versionStr := c.Query(versionQueryParam)
+	if versionStr != "" {
+		parsedVersion, err := strconv.ParseUint(versionStr, 10, 32)
+		if err != nil {
+			returnStatus(c, nil, http.StatusBadRequest, fmt.Sprintf("invalid version: %s", err.Error()), codeBadRequest)
+			return
+		}
+		version = uint32(parsedVersion)
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, ig.maxPayloadSize))
+	maxBytesErr := &http.MaxBytesError{}
+	if errors.As(err, &maxBytesErr) {
+		returnStatus(c, nil, http.StatusRequestEntityTooLarge, fmt.Sprintf("the payload is larger than %d bytes", maxBytesErr.Limit), codeTooLarge)
+		return
+	}
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, fmt.Sprintf("cannot read the request body: %s", err.Error()), codeBadRequest)
+		return
+	}
+
+	err = ig.facade.ProcessPayload(payload, topic, version)
+	if errors.Is(err, wsindexer.ErrUnsupportedPayloadVersion) {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), codeBadRequest)
+		return
+	}
+	if err != nil {
+		returnStatus(c, nil, http.StatusInternalServerError, err.Error(), codeInternalIssue)
+		return
+	}
+
+	returnStatus(c, gin.H{"topic": topic, "version": version}, http.StatusOK, "", codeSuccessful)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ig *ingestGroup) IsInterfaceNil() bool {
+	return ig == nil
+}
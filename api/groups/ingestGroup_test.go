@@ -0,0 +1,101 @@
+package groups
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const shippedApiConfigPath = "../../cmd/elasticindexer/config/api.toml"
+
+func loadShippedApiConfig(t *testing.T) config.ApiRoutesConfig {
+	apiConfig := config.ApiRoutesConfig{}
+	err := core.LoadTomlFile(&apiConfig, shippedApiConfigPath)
+	require.Nil(t, err)
+
+	apiConfig.Ingest.AuthToken = "token"
+	return apiConfig
+}
+
+func createIngestEngine(t *testing.T, facade *mock.FacadeStub, apiConfig config.ApiRoutesConfig) *gin.Engine {
+	ig, err := NewIngestGroup(facade, apiConfig.Ingest)
+	require.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	ig.RegisterRoutes(engine.Group("/ingest"), apiConfig)
+
+	return engine
+}
+
+func postPayload(engine *gin.Engine, payload []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/ingest/SaveBlock?version=1", bytes.NewReader(payload))
+	req.Header.Set(authorizationField, bearerPrefix+"token")
+
+	resp := httptest.NewRecorder()
+	engine.ServeHTTP(resp, req)
+
+	return resp
+}
+
+func TestNewIngestGroup(t *testing.T) {
+	t.Parallel()
+
+	ig, err := NewIngestGroup(nil, config.IngestConfig{AuthToken: "token"})
+	require.Nil(t, ig)
+	require.ErrorIs(t, err, indexerCore.ErrNilFacadeHandler)
+
+	ig, err = NewIngestGroup(&mock.FacadeStub{}, config.IngestConfig{})
+	require.Nil(t, ig)
+	require.ErrorIs(t, err, errEmptyAuthToken)
+
+	ig, err = NewIngestGroup(&mock.FacadeStub{}, config.IngestConfig{AuthToken: "token"})
+	require.Nil(t, err)
+	require.Equal(t, int64(defaultMaxPayloadSizeInBytes), ig.maxPayloadSize)
+}
+
+func TestIngestGroup_ShippedApiConfigShouldOpenTheIngestRoute(t *testing.T) {
+	t.Parallel()
+
+	processed := false
+	facade := &mock.FacadeStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, []byte("payload"), payload)
+			require.Equal(t, "SaveBlock", topic)
+			require.Equal(t, uint32(1), version)
+			processed = true
+			return nil
+		},
+	}
+	engine := createIngestEngine(t, facade, loadShippedApiConfig(t))
+
+	resp := postPayload(engine, []byte("payload"))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.True(t, processed)
+}
+
+func TestIngestGroup_TooLargePayloadShouldBeRejected(t *testing.T) {
+	t.Parallel()
+
+	facade := &mock.FacadeStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Fail(t, "should not process a too large payload")
+			return nil
+		},
+	}
+	apiConfig := loadShippedApiConfig(t)
+	apiConfig.Ingest.MaxPayloadSizeInBytes = 4
+	engine := createIngestEngine(t, facade, apiConfig)
+
+	resp := postPayload(engine, []byte("payload"))
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	require.Contains(t, resp.Body.String(), codeTooLarge)
+}
@@ -13,6 +13,7 @@ import (
 const (
 	metricsPath           = "/metrics"
 	prometheusMetricsPath = "/prometheus-metrics"
+	gapsPath              = "/gaps"
 )
 
 type statusGroup struct {
@@ -42,6 +43,11 @@ func NewStatusGroup(facade shared.FacadeHandler) (*statusGroup, error) {
 			Handler: sg.getPrometheusMetrics,
 			Method:  http.MethodGet,
 		},
+		{
+			Path:    gapsPath,
+			Handler: sg.getGaps,
+			Method:  http.MethodGet,
+		},
 	}
 	sg.endpoints = endpoints
 
@@ -62,6 +68,13 @@ func (sg *statusGroup) getPrometheusMetrics(c *gin.Context) {
 	c.String(http.StatusOK, metricsResults)
 }
 
+// getGaps will expose the gaps detected in the indexed blocks in json format
+func (sg *statusGroup) getGaps(c *gin.Context) {
+	gaps := sg.facade.GetGaps()
+
+	returnStatus(c, gin.H{"gaps": gaps}, http.StatusOK, "", "successful")
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (sg *statusGroup) IsInterfaceNil() bool {
 	return sg == nil
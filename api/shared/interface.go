@@ -19,6 +19,10 @@ type GroupHandler interface {
 type FacadeHandler interface {
 	GetMetrics() map[string]*request.MetricsResponse
 	GetMetricsForPrometheus() string
+	GetGaps() []*request.GapResponse
+	ProcessPayload(payload []byte, topic string, version uint32) error
+	GetDeadLetters() ([]*request.DeadLetterResponse, error)
+	ResubmitDeadLetters(ids []string) (*request.ResubmitResponse, error)
 	IsInterfaceNil() bool
 }
 
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+const (
+	actionIndex  = "index"
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+// bulkOperation holds the raw lines of an operation from a bulk request body: the action line and, for all the
+// actions except delete, the source line
+type bulkOperation struct {
+	lines [][]byte
+}
+
+// getActionAndItem returns the name of the action and the result of the operation
+func (bri *BulkResponseItem) getActionAndItem() (string, Item) {
+	switch {
+	case bri.ItemIndex != nil:
+		return actionIndex, *bri.ItemIndex
+	case bri.ItemCreate != nil:
+		return actionCreate, *bri.ItemCreate
+	case bri.ItemUpdate != nil:
+		return actionUpdate, *bri.ItemUpdate
+	case bri.ItemDelete != nil:
+		return actionDelete, *bri.ItemDelete
+	default:
+		return "", Item{}
+	}
+}
+
+// sendBulkFunc sends a bulk request body, with the client of an Elasticsearch version
+type sendBulkFunc func(ctx context.Context, body []byte, index string) (*esapi.Response, error)
+
+// doBulkRequestWithRetries sends the bulk body and then only the operations that failed with a retryable status, until
+// all of them succeed or the retries are exhausted. The items of a bulk response are in the same order as the
+// operations of the request, so every failed item is mapped to the operation that produced it
+func (rs *requestSettings) doBulkRequestWithRetries(ctx context.Context, body []byte, index string, sendBulk sendBulkFunc) error {
+	operations, err := splitBulkOperations(body)
+	if err != nil {
+		return err
+	}
+
+	failedItems := make([]Item, 0)
+	failedOperations := make([]bulkOperation, 0)
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := withTimeout(ctx, rs.requestTimeouts.Bulk)
+		res, errSend := sendBulk(attemptCtx, body, index)
+		if errSend != nil {
+			cancel()
+			return errSend
+		}
+
+		response, errRead := readBulkResponse(res)
+		cancel()
+		if errRead != nil {
+			return errRead
+		}
+
+		if len(response.Items) != len(operations) {
+			log.Warn("elasticClient.DoBulkRequest: cannot map the failed items, the number of items does not match the request",
+				"num operations", len(operations), "num items", len(response.Items))
+			return createBulkItemsError(append(failedItems, extractFailedItems(response)...))
+		}
+
+		retryOperations := make([]bulkOperation, 0)
+		retryItems := make([]Item, 0)
+		for idx := range response.Items {
+			action, item := response.Items[idx].getActionAndItem()
+			if item.Status < http.StatusBadRequest {
+				continue
+			}
+			if !isRetryableItem(action, item) {
+				failedItems = append(failedItems, item)
+				failedOperations = append(failedOperations, operations[idx])
+				continue
+			}
+
+			retryOperations = append(retryOperations, operations[idx])
+			retryItems = append(retryItems, item)
+		}
+
+		if len(retryOperations) == 0 {
+			break
+		}
+		if attempt >= rs.bulkItemsMaxRetries {
+			return createBulkItemsError(append(failedItems, retryItems...))
+		}
+
+		backOff := rs.bulkItemsRetryBackOff << attempt
+		log.Debug("elasticClient.DoBulkRequest: retrying the failed items",
+			"num items", len(retryOperations),
+			"attempt", attempt+1,
+			"back off", backOff,
+		)
+
+		err = waitBackOff(ctx, backOff)
+		if err != nil {
+			return err
+		}
+
+		operations = retryOperations
+		body = joinBulkOperations(operations)
+	}
+
+	return createFailedBulkItemsError(failedItems, failedOperations)
+}
+
+// createFailedBulkItemsError returns an error that holds the items that cannot be indexed by sending them again,
+// together with their operations
+func createFailedBulkItemsError(failedItems []Item, failedOperations []bulkOperation) error {
+	err := createBulkItemsError(failedItems)
+	if err == nil {
+		return nil
+	}
+
+	items := make([]*dataindexer.FailedBulkItem, 0, len(failedItems))
+	for idx, item := range failedItems {
+		items = append(items, &dataindexer.FailedBulkItem{
+			Index:     item.Index,
+			ID:        item.ID,
+			Status:    item.Status,
+			Reason:    getItemErrorReason(item),
+			Operation: joinBulkOperations(failedOperations[idx : idx+1]),
+		})
+	}
+
+	return &dataindexer.FailedBulkItemsError{
+		Items: items,
+		Err:   err,
+	}
+}
+
+func getItemErrorReason(item Item) string {
+	reason := fmt.Sprintf("%s: %s", item.Error.Type, item.Error.Reason)
+	if item.Error.Cause.Type == "" {
+		return reason
+	}
+
+	return fmt.Sprintf("%s, caused by %s: %s", reason, item.Error.Cause.Type, item.Error.Cause.Reason)
+}
+
+// isRetryableItem returns true if the operation can succeed when it is sent again: the cluster was busy or unavailable,
+// or an update lost a version conflict with a concurrent write of the same document
+func isRetryableItem(action string, item Item) bool {
+	switch {
+	case isRejectedItem(item):
+		return true
+	case item.Status == http.StatusServiceUnavailable:
+		return true
+	case item.Status == http.StatusConflict:
+		return action == actionUpdate
+	default:
+		return false
+	}
+}
+
+func waitBackOff(ctx context.Context, backOff time.Duration) error {
+	timer := time.NewTimer(backOff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// splitBulkOperations splits a bulk request body into operations
+func splitBulkOperations(body []byte) ([]bulkOperation, error) {
+	operations := make([]bulkOperation, 0)
+
+	lines := bytes.Split(body, []byte("\n"))
+	for idx := 0; idx < len(lines); idx++ {
+		if len(bytes.TrimSpace(lines[idx])) == 0 {
+			continue
+		}
+
+		action := make(map[string]json.RawMessage)
+		err := json.Unmarshal(lines[idx], &action)
+		if err != nil {
+			return nil, fmt.Errorf("%w while decoding the action line of a bulk request", err)
+		}
+
+		operation := bulkOperation{
+			lines: [][]byte{lines[idx]},
+		}
+		_, isDelete := action[actionDelete]
+		if !isDelete && idx+1 < len(lines) {
+			idx++
+			operation.lines = append(operation.lines, lines[idx])
+		}
+
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
+func joinBulkOperations(operations []bulkOperation) []byte {
+	buff := &bytes.Buffer{}
+	for _, operation := range operations {
+		for _, line := range operation.lines {
+			buff.Write(line)
+			buff.WriteByte('\n')
+		}
+	}
+
+	return buff.Bytes()
+}
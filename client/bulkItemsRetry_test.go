@@ -0,0 +1,209 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bulkItemOK         = `{"index":{"_index":"transactions","_id":"%s","status":201,"result":"created"}}`
+	bulkItemRejected   = `{"index":{"_index":"transactions","_id":"%s","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}}`
+	bulkItemBadRequest = `{"index":{"_index":"transactions","_id":"%s","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}`
+)
+
+func createBulkResponse(items ...string) string {
+	return `{"took":1,"errors":true,"items":[` + strings.Join(items, ",") + `]}`
+}
+
+func createClientWithBulkHandler(t *testing.T, maxRetries int, handler func(body string) string) *elasticClient {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(handler(string(body))))
+	}))
+	t.Cleanup(ts.Close)
+
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
+		BulkItemsMaxRetries:   maxRetries,
+		BulkItemsRetryBackOff: time.Millisecond,
+	})
+	require.Nil(t, err)
+
+	return esClient
+}
+
+func TestNewElasticClient_InvalidBulkItemsRetries(t *testing.T) {
+	t.Parallel()
+
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{"http://localhost:9200"},
+		},
+		BulkItemsMaxRetries: -1,
+	})
+	require.Nil(t, esClient)
+	require.Equal(t, dataindexer.ErrInvalidBulkItemsRetries, err)
+}
+
+func TestSplitBulkOperations(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"index":{"_id":"h1"}}
+{"nonce":1}
+{"delete":{"_index":"tokens","_id":"t1"}}
+{"update":{"_index":"accounts","_id":"a1"}}
+{"doc":{"balance":"1"},"doc_as_upsert":true}
+`)
+
+	operations, err := splitBulkOperations(body)
+	require.Nil(t, err)
+	require.Len(t, operations, 3)
+	require.Len(t, operations[0].lines, 2)
+	require.Len(t, operations[1].lines, 1)
+	require.Len(t, operations[2].lines, 2)
+	require.Equal(t, body, joinBulkOperations(operations))
+
+	_, err = splitBulkOperations([]byte("not json\n"))
+	require.NotNil(t, err)
+}
+
+func TestElasticClient_DoBulkRequestShouldRetryOnlyTheFailedItems(t *testing.T) {
+	t.Parallel()
+
+	bodies := make([]string, 0)
+	esClient := createClientWithBulkHandler(t, 3, func(body string) string {
+		bodies = append(bodies, body)
+		if len(bodies) == 1 {
+			return createBulkResponse(
+				fmt.Sprintf(bulkItemOK, "h1"),
+				fmt.Sprintf(bulkItemRejected, "h2"),
+				fmt.Sprintf(bulkItemBadRequest, "h3"),
+			)
+		}
+
+		return createBulkResponse(fmt.Sprintf(bulkItemOK, "h2"))
+	})
+
+	buff := bytes.NewBufferString(`{"index":{"_id":"h1"}}
+{"nonce":1}
+{"index":{"_id":"h2"}}
+{"nonce":2}
+{"index":{"_id":"h3"}}
+{"nonce":3}
+`)
+	err := esClient.DoBulkRequest(context.Background(), buff, "transactions")
+	require.NotNil(t, err)
+	require.False(t, errors.Is(err, dataindexer.ErrRejectedBulkItems))
+	require.Contains(t, err.Error(), `"index": "transactions", "id": "h3"`)
+	require.NotContains(t, err.Error(), `"h2"`)
+
+	require.Len(t, bodies, 2)
+	require.Equal(t, "{\"index\":{\"_id\":\"h2\"}}\n{\"nonce\":2}\n", bodies[1])
+
+	var failedItemsErr *dataindexer.FailedBulkItemsError
+	require.True(t, errors.As(err, &failedItemsErr))
+	require.Equal(t, []*dataindexer.FailedBulkItem{
+		{
+			Index:     "transactions",
+			ID:        "h3",
+			Status:    400,
+			Reason:    "mapper_parsing_exception: failed to parse",
+			Operation: []byte("{\"index\":{\"_id\":\"h3\"}}\n{\"nonce\":3}\n"),
+		},
+	}, failedItemsErr.Items)
+}
+
+func TestElasticClient_DoBulkRequestRetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	numCalls := uint32(0)
+	esClient := createClientWithBulkHandler(t, 2, func(body string) string {
+		atomic.AddUint32(&numCalls, 1)
+		return createBulkResponse(fmt.Sprintf(bulkItemRejected, "h1"))
+	})
+
+	err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString("{\"index\":{\"_id\":\"h1\"}}\n{}\n"), "transactions")
+	require.True(t, errors.Is(err, dataindexer.ErrRejectedBulkItems))
+	require.Contains(t, err.Error(), `"id": "h1"`)
+
+	var failedItemsErr *dataindexer.FailedBulkItemsError
+	require.False(t, errors.As(err, &failedItemsErr))
+	require.Equal(t, uint32(3), atomic.LoadUint32(&numCalls))
+}
+
+func TestElasticClient_DoBulkRequestVersionConflicts(t *testing.T) {
+	t.Parallel()
+
+	bodies := make([]string, 0)
+	esClient := createClientWithBulkHandler(t, 1, func(body string) string {
+		bodies = append(bodies, body)
+		if len(bodies) == 1 {
+			return createBulkResponse(
+				`{"update":{"_index":"accounts","_id":"a1","status":409,"error":{"type":"version_conflict_engine_exception"}}}`,
+				`{"create":{"_index":"accounts","_id":"a2","status":409,"error":{"type":"version_conflict_engine_exception"}}}`,
+			)
+		}
+
+		return createBulkResponse(`{"update":{"_index":"accounts","_id":"a1","status":200,"result":"updated"}}`)
+	})
+
+	buff := bytes.NewBufferString(`{"update":{"_id":"a1"}}
+{"doc":{},"doc_as_upsert":true}
+{"create":{"_id":"a2"}}
+{}
+`)
+	err := esClient.DoBulkRequest(context.Background(), buff, "accounts")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), `"id": "a2"`)
+	require.NotContains(t, err.Error(), `"a1"`)
+	require.Len(t, bodies, 2)
+	require.True(t, strings.HasPrefix(bodies[1], `{"update":{"_id":"a1"}}`))
+}
+
+func TestElasticClient_DoBulkRequestWithoutRetries(t *testing.T) {
+	t.Parallel()
+
+	numCalls := uint32(0)
+	esClient := createClientWithBulkHandler(t, 0, func(body string) string {
+		atomic.AddUint32(&numCalls, 1)
+		return createBulkResponse(fmt.Sprintf(bulkItemRejected, "h1"))
+	})
+
+	err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString("{\"index\":{\"_id\":\"h1\"}}\n{}\n"), "transactions")
+	require.True(t, errors.Is(err, dataindexer.ErrRejectedBulkItems))
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numCalls))
+}
+
+func TestElasticClient_DoBulkRequestContextCancelledDuringBackOff(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	esClient := createClientWithBulkHandler(t, 5, func(body string) string {
+		cancel()
+		return createBulkResponse(fmt.Sprintf(bulkItemRejected, "h1"))
+	})
+	esClient.bulkItemsRetryBackOff = time.Hour
+
+	err := esClient.DoBulkRequest(ctx, bytes.NewBufferString("{\"index\":{\"_id\":\"h1\"}}\n{}\n"), "transactions")
+	require.Equal(t, context.Canceled, err)
+}
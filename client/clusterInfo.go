@@ -0,0 +1,151 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	openSearchDistribution = "opensearch"
+
+	// the templates have typeless mappings, supported starting with Elasticsearch 7
+	minElasticsearchMajorVersion  = 7
+	minElasticsearch8MajorVersion = 8
+	minOpenSearchMajorVersion     = 1
+
+	// the point in time API and the _shard_doc sort of the pit package are available starting with Elasticsearch 7.12
+	minPointInTimeMajorVersion = 7
+	minPointInTimeMinorVersion = 12
+)
+
+var errCannotDetectCluster = errors.New("cannot detect the distribution and the version of the cluster")
+
+// clusterInfo holds the distribution and the version of the cluster, as returned by its root endpoint
+type clusterInfo struct {
+	backend      string
+	version      string
+	majorVersion int
+	minorVersion int
+}
+
+func parseClusterInfo(responseBytes []byte) (*clusterInfo, error) {
+	version := gjson.GetBytes(responseBytes, "version.number").String()
+	versionParts := strings.Split(version, ".")
+	majorVersion, err := strconv.Atoi(versionParts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid version %q", errCannotDetectCluster, version)
+	}
+	minorVersion := 0
+	if len(versionParts) > 1 {
+		minorVersion, err = strconv.Atoi(versionParts[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid version %q", errCannotDetectCluster, version)
+		}
+	}
+
+	backend := dataindexer.ElasticsearchBackend
+	if gjson.GetBytes(responseBytes, "version.distribution").String() == openSearchDistribution {
+		backend = dataindexer.OpenSearchBackend
+	}
+
+	return &clusterInfo{
+		backend:      backend,
+		version:      version,
+		majorVersion: majorVersion,
+		minorVersion: minorVersion,
+	}, nil
+}
+
+// supportsPointInTime returns true if the documents can be read with the point in time API of Elasticsearch. OpenSearch
+// has a different point in time API, so its documents are read with the scroll API, like on the older Elasticsearch versions
+func (ci *clusterInfo) supportsPointInTime() bool {
+	if ci.backend != dataindexer.ElasticsearchBackend {
+		return false
+	}
+	if ci.majorVersion != minPointInTimeMajorVersion {
+		return ci.majorVersion > minPointInTimeMajorVersion
+	}
+
+	return ci.minorVersion >= minPointInTimeMinorVersion
+}
+
+// checkBackend checks the cluster against the configured backend. With dataindexer.AutoBackend, the detected
+// distribution is used and only its version is checked
+func (ci *clusterInfo) checkBackend(backend string) error {
+	if backend == dataindexer.AutoBackend {
+		backend = ci.backend
+	}
+
+	distribution := backend
+	minMajorVersion := minElasticsearchMajorVersion
+	switch backend {
+	case dataindexer.OpenSearchBackend:
+		minMajorVersion = minOpenSearchMajorVersion
+	case dataindexer.Elasticsearch8Backend:
+		distribution = dataindexer.ElasticsearchBackend
+		minMajorVersion = minElasticsearch8MajorVersion
+	}
+
+	if ci.backend != distribution {
+		return fmt.Errorf("%w: the configured backend is %s, but the cluster runs %s %s",
+			dataindexer.ErrBackendMismatch, backend, ci.backend, ci.version)
+	}
+	if ci.majorVersion < minMajorVersion {
+		return fmt.Errorf("%w: %s %s, it should be at least %d.0.0",
+			dataindexer.ErrUnsupportedClusterVersion, ci.backend, ci.version, minMajorVersion)
+	}
+
+	return nil
+}
+
+// getClusterInfo detects the distribution and the version of the cluster with the first call and checks them against
+// the configured backend. The setup of the indices calls it first, so a wrong backend stops the indexer at startup
+func (ec *elasticClient) getClusterInfo() (*clusterInfo, error) {
+	ec.mutClusterInfo.Lock()
+	defer ec.mutClusterInfo.Unlock()
+
+	if ec.clusterInfo != nil {
+		return ec.clusterInfo, nil
+	}
+
+	res, err := ec.client.Info()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCannotDetectCluster, err.Error())
+	}
+
+	info, err := checkClusterInfoResponse(res, ec.backend)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("elasticClient: detected the cluster", "backend", info.backend, "version", info.version)
+	ec.clusterInfo = info
+
+	return info, nil
+}
+
+// checkClusterInfoResponse parses the response of the root endpoint and checks the cluster against the configured backend
+func checkClusterInfoResponse(res *esapi.Response, backend string) (*clusterInfo, error) {
+	responseBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCannotDetectCluster, err.Error())
+	}
+
+	info, err := parseClusterInfo(responseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = info.checkBackend(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
@@ -0,0 +1,245 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	elasticsearchInfo = `{"version":{"number":"7.16.2","build_flavor":"default"},"tagline":"You Know, for Search"}`
+	openSearchInfo    = `{"version":{"distribution":"opensearch","number":"2.4.1"},"tagline":"The OpenSearch Project"}`
+)
+
+type clusterRequestsRecorder struct {
+	mut      sync.Mutex
+	info     string
+	requests []string
+}
+
+func (crr *clusterRequestsRecorder) newClient(t *testing.T, backend string) *elasticClient {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		crr.mut.Lock()
+		crr.requests = append(crr.requests, r.Method+" "+r.URL.Path)
+		crr.mut.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(crr.info))
+		case r.Method == http.MethodHead, r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
+		Backend: backend,
+	})
+	require.Nil(t, err)
+
+	return esClient
+}
+
+func TestNewElasticClient_InvalidBackend(t *testing.T) {
+	t.Parallel()
+
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{"http://localhost:9200"},
+		},
+		Backend: "solr",
+	})
+	require.Nil(t, esClient)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidBackend))
+}
+
+func TestParseClusterInfo(t *testing.T) {
+	t.Parallel()
+
+	info, err := parseClusterInfo([]byte(elasticsearchInfo))
+	require.Nil(t, err)
+	require.Equal(t, &clusterInfo{backend: dataindexer.ElasticsearchBackend, version: "7.16.2", majorVersion: 7, minorVersion: 16}, info)
+
+	info, err = parseClusterInfo([]byte(openSearchInfo))
+	require.Nil(t, err)
+	require.Equal(t, &clusterInfo{backend: dataindexer.OpenSearchBackend, version: "2.4.1", majorVersion: 2, minorVersion: 4}, info)
+
+	_, err = parseClusterInfo([]byte(`{"error":"unauthorized"}`))
+	require.True(t, errors.Is(err, errCannotDetectCluster))
+}
+
+func TestClusterInfo_CheckBackend(t *testing.T) {
+	t.Parallel()
+
+	info := &clusterInfo{backend: dataindexer.OpenSearchBackend, version: "1.3.7", majorVersion: 1}
+	require.Nil(t, info.checkBackend(dataindexer.OpenSearchBackend))
+	require.True(t, errors.Is(info.checkBackend(dataindexer.ElasticsearchBackend), dataindexer.ErrBackendMismatch))
+
+	require.Nil(t, info.checkBackend(dataindexer.AutoBackend))
+
+	info = &clusterInfo{backend: dataindexer.ElasticsearchBackend, version: "6.8.23", majorVersion: 6}
+	require.True(t, errors.Is(info.checkBackend(dataindexer.ElasticsearchBackend), dataindexer.ErrUnsupportedClusterVersion))
+	require.True(t, errors.Is(info.checkBackend(dataindexer.AutoBackend), dataindexer.ErrUnsupportedClusterVersion))
+
+	info = &clusterInfo{backend: dataindexer.ElasticsearchBackend, version: "7.17.8", majorVersion: 7}
+	require.Nil(t, info.checkBackend(dataindexer.ElasticsearchBackend))
+	require.True(t, errors.Is(info.checkBackend(dataindexer.Elasticsearch8Backend), dataindexer.ErrUnsupportedClusterVersion))
+
+	info = &clusterInfo{backend: dataindexer.ElasticsearchBackend, version: "8.6.0", majorVersion: 8}
+	require.Nil(t, info.checkBackend(dataindexer.Elasticsearch8Backend))
+	require.Nil(t, info.checkBackend(dataindexer.AutoBackend))
+	require.True(t, errors.Is(info.checkBackend(dataindexer.OpenSearchBackend), dataindexer.ErrBackendMismatch))
+}
+
+func TestClusterInfo_SupportsPointInTime(t *testing.T) {
+	t.Parallel()
+
+	info := &clusterInfo{backend: dataindexer.ElasticsearchBackend, version: "7.11.2", majorVersion: 7, minorVersion: 11}
+	require.False(t, info.supportsPointInTime())
+
+	info = &clusterInfo{backend: dataindexer.ElasticsearchBackend, version: "7.12.0", majorVersion: 7, minorVersion: 12}
+	require.True(t, info.supportsPointInTime())
+
+	info = &clusterInfo{backend: dataindexer.ElasticsearchBackend, version: "8.0.0", majorVersion: 8}
+	require.True(t, info.supportsPointInTime())
+
+	info = &clusterInfo{backend: dataindexer.OpenSearchBackend, version: "2.11.0", majorVersion: 2, minorVersion: 11}
+	require.False(t, info.supportsPointInTime())
+}
+
+func TestElasticClient_GetBackend(t *testing.T) {
+	t.Parallel()
+
+	recorder := &clusterRequestsRecorder{info: openSearchInfo}
+	esClient := recorder.newClient(t, dataindexer.AutoBackend)
+
+	backend, err := esClient.GetBackend()
+	require.Nil(t, err)
+	require.Equal(t, dataindexer.OpenSearchBackend, backend)
+
+	backend, err = esClient.GetBackend()
+	require.Nil(t, err)
+	require.Equal(t, dataindexer.OpenSearchBackend, backend)
+	require.Equal(t, []string{"GET /"}, recorder.requests)
+}
+
+const composableBlocksTemplate = `{
+	"component_templates":[{"name":"blocks-settings","body":{"template":{"settings":{"plugins.index_state_management.rollover_alias":"blocks"}}}}],
+	"index_template":{"index_patterns":["blocks-*"],"composed_of":["blocks-settings"]}
+}`
+
+func TestElasticClient_CheckAndCreateTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("elasticsearch should use the legacy templates", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &clusterRequestsRecorder{info: elasticsearchInfo}
+		esClient := recorder.newClient(t, "")
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(`{"index_patterns":["blocks-*"]}`))
+		require.Nil(t, err)
+		err = esClient.CheckAndCreateTemplate("miniblocks", bytes.NewBufferString(`{"index_patterns":["miniblocks-*"]}`))
+		require.Nil(t, err)
+
+		require.Equal(t, []string{
+			"GET /",
+			"HEAD /_template/blocks",
+			"PUT /_template/blocks",
+			"HEAD /_template/miniblocks",
+			"PUT /_template/miniblocks",
+		}, recorder.requests)
+	})
+
+	t.Run("opensearch should use the composable templates", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &clusterRequestsRecorder{info: openSearchInfo}
+		esClient := recorder.newClient(t, dataindexer.OpenSearchBackend)
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(composableBlocksTemplate))
+		require.Nil(t, err)
+
+		require.Equal(t, []string{
+			"GET /",
+			"HEAD /_component_template/blocks-settings",
+			"PUT /_component_template/blocks-settings",
+			"HEAD /_index_template/blocks",
+			"PUT /_index_template/blocks",
+		}, recorder.requests)
+	})
+
+	t.Run("detected opensearch should use the composable templates", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &clusterRequestsRecorder{info: openSearchInfo}
+		esClient := recorder.newClient(t, dataindexer.AutoBackend)
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(composableBlocksTemplate))
+		require.Nil(t, err)
+
+		require.Equal(t, []string{
+			"GET /",
+			"HEAD /_component_template/blocks-settings",
+			"PUT /_component_template/blocks-settings",
+			"HEAD /_index_template/blocks",
+			"PUT /_index_template/blocks",
+		}, recorder.requests)
+	})
+
+	t.Run("backend mismatch should error", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &clusterRequestsRecorder{info: openSearchInfo}
+		esClient := recorder.newClient(t, dataindexer.ElasticsearchBackend)
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(`{"index_patterns":["blocks-*"]}`))
+		require.True(t, errors.Is(err, dataindexer.ErrBackendMismatch))
+		require.Equal(t, []string{"GET /"}, recorder.requests)
+	})
+}
+
+func TestElasticClient_CheckAndCreatePolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("elasticsearch should skip the policies", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &clusterRequestsRecorder{info: elasticsearchInfo}
+		esClient := recorder.newClient(t, dataindexer.ElasticsearchBackend)
+
+		err := esClient.CheckAndCreatePolicy("blocks_policy", bytes.NewBufferString(`{"policy":{}}`))
+		require.Nil(t, err)
+		require.Equal(t, []string{"GET /"}, recorder.requests)
+	})
+
+	t.Run("opensearch should create the index state management policies", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &clusterRequestsRecorder{info: openSearchInfo}
+		esClient := recorder.newClient(t, dataindexer.OpenSearchBackend)
+
+		err := esClient.CheckAndCreatePolicy("blocks_policy", bytes.NewBufferString(`{"policy":{}}`))
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"GET /",
+			"GET /_plugins/_ism/policies/blocks_policy",
+			"PUT /_plugins/_ism/policies/blocks_policy",
+		}, recorder.requests)
+	})
+}
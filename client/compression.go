@@ -0,0 +1,62 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+)
+
+const (
+	headerContentEncoding = "Content-Encoding"
+	contentEncodingGzip   = "gzip"
+)
+
+// requestBody holds the body of a request, as it has to be sent, together with its context and its extra headers
+type requestBody struct {
+	ctx    context.Context
+	reader io.Reader
+	header map[string]string
+}
+
+// newRequestBody returns the body to be sent. When the requests compression is enabled, the body is gzip-compressed and
+// its uncompressed size is added in the context, so the metrics can report both sizes
+func (rs *requestSettings) newRequestBody(ctx context.Context, body []byte) (*requestBody, error) {
+	if !rs.compressRequests {
+		return &requestBody{
+			ctx:    ctx,
+			reader: bytes.NewReader(body),
+		}, nil
+	}
+
+	compressed, err := gzipBytes(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requestBody{
+		ctx:    context.WithValue(ctx, request.RawSizeContextKey, uint64(len(body))),
+		reader: bytes.NewReader(compressed),
+		header: map[string]string{
+			headerContentEncoding: contentEncodingGzip,
+		},
+	}, nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	buff := &bytes.Buffer{}
+	writer := gzip.NewWriter(buff)
+	_, err := writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buff.Bytes(), nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticClient_NewRequestBody(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"index":{"_id":"1"}}` + "\n" + `{"field":"value"}` + "\n")
+
+	t.Run("compression disabled should not change the body", func(t *testing.T) {
+		t.Parallel()
+
+		ec := &elasticClient{}
+		reqBody, err := ec.newRequestBody(context.Background(), body)
+		require.Nil(t, err)
+		require.Nil(t, reqBody.header)
+		require.Nil(t, reqBody.ctx.Value(request.RawSizeContextKey))
+
+		sent, _ := io.ReadAll(reqBody.reader)
+		require.Equal(t, body, sent)
+	})
+
+	t.Run("compression enabled should gzip the body", func(t *testing.T) {
+		t.Parallel()
+
+		ec := &elasticClient{requestSettings: requestSettings{compressRequests: true}}
+		reqBody, err := ec.newRequestBody(context.Background(), body)
+		require.Nil(t, err)
+		require.Equal(t, contentEncodingGzip, reqBody.header[headerContentEncoding])
+		require.Equal(t, uint64(len(body)), reqBody.ctx.Value(request.RawSizeContextKey))
+
+		gzipReader, err := gzip.NewReader(reqBody.reader)
+		require.Nil(t, err)
+		sent, _ := io.ReadAll(gzipReader)
+		require.Equal(t, body, sent)
+	})
+}
+
+func TestElasticClient_DoBulkRequestCompressed(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"index":{"_id":"1"}}` + "\n" + `{"field":"value"}` + "\n")
+
+	var receivedEncoding string
+	var receivedBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEncoding = r.Header.Get(headerContentEncoding)
+		gzipReader, err := gzip.NewReader(r.Body)
+		require.Nil(t, err)
+		receivedBody, _ = io.ReadAll(gzipReader)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[]}`))
+	}))
+	defer ts.Close()
+
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
+		CompressRequests: true,
+	})
+	require.Nil(t, err)
+
+	err = esClient.DoBulkRequest(context.Background(), bytes.NewBuffer(body), "transactions")
+	require.Nil(t, err)
+	require.Equal(t, contentEncodingGzip, receivedEncoding)
+	require.Equal(t, body, receivedBody)
+}
@@ -1,21 +1,25 @@
 package client
 
 const (
-	headerXSRF                       = "kbn-xsrf"
 	headerContentType                = "Content-Type"
-	kibanaPluginPath                 = "_plugin/kibana/api"
 	numOfErrorsToExtractBulkResponse = 5
+	rejectedExecutionException       = "es_rejected_execution_exception"
 )
 
 var headerContentTypeJSON = []string{"application/json"}
 
 // BulkRequestResponse defines the structure of a bulk request response
 type BulkRequestResponse struct {
-	Errors bool `json:"errors"`
-	Items  []struct {
-		ItemIndex  *Item `json:"index"`
-		ItemUpdate *Item `json:"update"`
-	} `json:"items"`
+	Errors bool               `json:"errors"`
+	Items  []BulkResponseItem `json:"items"`
+}
+
+// BulkResponseItem defines the result of an operation from a bulk response, keyed by the name of the action
+type BulkResponseItem struct {
+	ItemIndex  *Item `json:"index"`
+	ItemCreate *Item `json:"create"`
+	ItemUpdate *Item `json:"update"`
+	ItemDelete *Item `json:"delete"`
 }
 
 // Item defines the structure of an item from a bulk response
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
-	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/pit"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
@@ -18,8 +19,7 @@ import (
 // TODO add more unit tests
 
 const (
-	esConflictsPolicy      = "proceed"
-	errPolicyAlreadyExists = "document already exists"
+	esConflictsPolicy = "proceed"
 )
 
 var log = logger.GetOrCreate("indexer/client")
@@ -29,36 +29,148 @@ type (
 	objectsMap           = map[string]interface{}
 )
 
+// ArgsElasticClient holds all the components needed to create a new instance of elasticClient
+type ArgsElasticClient struct {
+	Config elasticsearch.Config
+	// BulkItemsMaxRetries is the number of times the items of a bulk request that failed with a retryable status are
+	// sent again. 0 means that the failed items are not retried
+	BulkItemsMaxRetries int
+	// BulkItemsRetryBackOff is the delay before the first retry of the failed items. It doubles on every retry
+	BulkItemsRetryBackOff time.Duration
+	// CompressRequests enables the gzip compression of the bulk, update by query and multi get request bodies
+	CompressRequests bool
+	// SearchPageSize is the number of documents fetched with every page of DoScrollRequest. 0 means pit.DefaultPageSize
+	SearchPageSize int
+	// SearchKeepAlive is how long the point in time or the scroll of DoScrollRequest is kept alive between two pages.
+	// 0 means pit.DefaultKeepAlive
+	SearchKeepAlive time.Duration
+	// RequestTimeouts bounds how long every kind of request is allowed to take
+	RequestTimeouts RequestTimeouts
+	// Backend is the distribution the cluster is expected to run, dataindexer.ElasticsearchBackend or
+	// dataindexer.OpenSearchBackend. Empty or dataindexer.AutoBackend means the distribution detected on the cluster is used
+	Backend string
+}
+
+// RequestTimeouts holds how long every kind of request is allowed to take before it is cancelled. 0 means the request
+// is bounded only by the context it is sent with
+type RequestTimeouts struct {
+	// Bulk bounds every attempt of a bulk request, the retries of the failed items get a new timeout
+	Bulk          time.Duration
+	Get           time.Duration
+	Remove        time.Duration
+	UpdateByQuery time.Duration
+	Count         time.Duration
+	// Scroll bounds the request of every page, not the whole iteration
+	Scroll time.Duration
+}
+
+func (rt RequestTimeouts) isValid() bool {
+	return rt.Bulk >= 0 && rt.Get >= 0 && rt.Remove >= 0 && rt.UpdateByQuery >= 0 && rt.Count >= 0 && rt.Scroll >= 0
+}
+
+// requestSettings holds how the requests are sent, the same for the clients of all the Elasticsearch versions
+type requestSettings struct {
+	bulkItemsMaxRetries   int
+	bulkItemsRetryBackOff time.Duration
+	compressRequests      bool
+	searchPageSize        int
+	searchKeepAlive       time.Duration
+	requestTimeouts       RequestTimeouts
+}
+
+func (rs *requestSettings) check() error {
+	if rs.bulkItemsMaxRetries < 0 || rs.bulkItemsRetryBackOff < 0 {
+		return dataindexer.ErrInvalidBulkItemsRetries
+	}
+	if rs.searchPageSize < 0 || rs.searchPageSize > pit.MaxPageSize || rs.searchKeepAlive < 0 {
+		return dataindexer.ErrInvalidSearchSettings
+	}
+	if !rs.requestTimeouts.isValid() {
+		return dataindexer.ErrInvalidRequestTimeouts
+	}
+
+	return nil
+}
+
 type elasticClient struct {
-	elasticBaseUrl string
-	client         *elasticsearch.Client
+	requestSettings
+	client  *elasticsearch.Client
+	backend string
 
-	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
-	// bypassing any possible caching based on the same request
-	countScroll int
+	mutClusterInfo sync.Mutex
+	clusterInfo    *clusterInfo
 }
 
 // NewElasticClient will create a new instance of elasticClient
-func NewElasticClient(cfg elasticsearch.Config) (*elasticClient, error) {
-	if len(cfg.Addresses) == 0 {
+func NewElasticClient(args ArgsElasticClient) (*elasticClient, error) {
+	if len(args.Config.Addresses) == 0 {
 		return nil, dataindexer.ErrNoElasticUrlProvided
 	}
+	settings := requestSettings{
+		bulkItemsMaxRetries:   args.BulkItemsMaxRetries,
+		bulkItemsRetryBackOff: args.BulkItemsRetryBackOff,
+		compressRequests:      args.CompressRequests,
+		searchPageSize:        args.SearchPageSize,
+		searchKeepAlive:       args.SearchKeepAlive,
+		requestTimeouts:       args.RequestTimeouts,
+	}
+	err := settings.check()
+	if err != nil {
+		return nil, err
+	}
+	backend, err := getBackend(args.Backend)
+	if err != nil {
+		return nil, err
+	}
 
-	es, err := elasticsearch.NewClient(cfg)
+	es, err := elasticsearch.NewClient(args.Config)
 	if err != nil {
 		return nil, err
 	}
 
 	ec := &elasticClient{
-		client:         es,
-		elasticBaseUrl: cfg.Addresses[0],
+		requestSettings: settings,
+		client:          es,
+		backend:         backend,
 	}
 
 	return ec, nil
 }
 
-// CheckAndCreateTemplate creates an index template if it does not already exist
+// GetBackend returns the distribution of the cluster, detected with the first request sent to it
+func (ec *elasticClient) GetBackend() (string, error) {
+	info, err := ec.getClusterInfo()
+	if err != nil {
+		return "", err
+	}
+
+	return info.backend, nil
+}
+
+func getBackend(backend string) (string, error) {
+	switch backend {
+	case "", dataindexer.AutoBackend:
+		return dataindexer.AutoBackend, nil
+	case dataindexer.ElasticsearchBackend, dataindexer.OpenSearchBackend:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("%w: %s", dataindexer.ErrInvalidBackend, backend)
+	}
+}
+
+// CheckAndCreateTemplate creates an index template if it does not already exist. On OpenSearch, the template has to be
+// in the format emitted by the composable templates reader, while Elasticsearch keeps the legacy templates, as its
+// built-in composable templates use some of the index names
 func (ec *elasticClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	info, err := ec.getClusterInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.backend == dataindexer.OpenSearchBackend {
+		return checkAndCreateComposableTemplate(ec.client.Transport, templateName, template)
+	}
+
 	if ec.templateExists(templateName) {
 		return nil
 	}
@@ -66,9 +178,21 @@ func (ec *elasticClient) CheckAndCreateTemplate(templateName string, template *b
 	return ec.createIndexTemplate(templateName, template)
 }
 
-// CheckAndCreatePolicy creates a new index policy if it does not already exist
+// CheckAndCreatePolicy creates a new index state management policy if it does not already exist. The policies are
+// created only on OpenSearch, Elasticsearch does not have the index state management plugin
 func (ec *elasticClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
-	if ec.PolicyExists(policyName) {
+	info, err := ec.getClusterInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.backend != dataindexer.OpenSearchBackend {
+		log.Debug("elasticClient.CheckAndCreatePolicy: the index state management policies are created only on OpenSearch",
+			"policy", policyName)
+		return nil
+	}
+
+	if ec.policyExists(policyName) {
 		return nil
 	}
 
@@ -93,42 +217,59 @@ func (ec *elasticClient) CheckAndCreateAlias(alias string, indexName string) err
 	return ec.createAlias(alias, indexName)
 }
 
-// DoBulkRequest will do a bulk of request to elastic server
+// DoBulkRequest will do a bulk of request to elastic server. The items that fail with a retryable status are sent again,
+// with an exponential back off, until they succeed or the retries are exhausted
 func (ec *elasticClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
-	reader := bytes.NewReader(buff.Bytes())
+	return ec.doBulkRequestWithRetries(ctx, buff.Bytes(), index, ec.sendBulkRequest)
+}
+
+func (ec *elasticClient) sendBulkRequest(ctx context.Context, body []byte, index string) (*esapi.Response, error) {
+	reqBody, err := ec.newRequestBody(ctx, body)
+	if err != nil {
+		return nil, err
+	}
 
 	options := make([]func(*esapi.BulkRequest), 0)
 	if index != "" {
 		options = append(options, ec.client.Bulk.WithIndex(index))
 	}
 
-	options = append(options, ec.client.Bulk.WithContext(ctx))
+	options = append(options, ec.client.Bulk.WithContext(reqBody.ctx), ec.client.Bulk.WithHeader(reqBody.header))
 
 	res, err := ec.client.Bulk(
-		reader,
+		reqBody.reader,
 		options...,
 	)
 	if err != nil {
 		log.Warn("elasticClient.DoBulkRequest",
 			"indexer do bulk request no response", err.Error())
-		return err
+		return nil, err
 	}
 
-	return elasticBulkRequestResponseHandler(res)
+	return res, nil
 }
 
 // DoMultiGet wil do a multi get request to Elasticsearch server
 func (ec *elasticClient) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, resBody interface{}) error {
+	ctx, cancel := withTimeout(ctx, ec.requestTimeouts.Get)
+	defer cancel()
+
 	obj := getDocumentsByIDsQuery(ids, withSource)
 	body, err := encode(obj)
 	if err != nil {
 		return err
 	}
 
+	reqBody, err := ec.newRequestBody(ctx, body.Bytes())
+	if err != nil {
+		return err
+	}
+
 	res, err := ec.client.Mget(
-		&body,
+		reqBody.reader,
 		ec.client.Mget.WithIndex(index),
-		ec.client.Mget.WithContext(ctx),
+		ec.client.Mget.WithContext(reqBody.ctx),
+		ec.client.Mget.WithHeader(reqBody.header),
 	)
 	if err != nil {
 		log.Warn("elasticClient.DoMultiGet",
@@ -148,49 +289,7 @@ func (ec *elasticClient) DoMultiGet(ctx context.Context, ids []string, index str
 
 // DoQueryRemove will do a query remove to elasticsearch server
 func (ec *elasticClient) DoQueryRemove(ctx context.Context, index string, body *bytes.Buffer) error {
-	err := ec.doRefresh(index)
-	if err != nil {
-		log.Warn("elasticClient.doRefresh", "cannot do refresh", err)
-	}
-
-	writeIndex, err := ec.getWriteIndex(index)
-	if err != nil {
-		log.Warn("elasticClient.getWriteIndex", "cannot do get write index", err)
-		return err
-	}
-
-	res, err := ec.client.DeleteByQuery(
-		[]string{writeIndex},
-		body,
-		ec.client.DeleteByQuery.WithIgnoreUnavailable(true),
-		ec.client.DeleteByQuery.WithConflicts(esConflictsPolicy),
-		ec.client.DeleteByQuery.WithContext(ctx),
-	)
-
-	if err != nil {
-		log.Warn("elasticClient.DoQueryRemove", "cannot do query remove", err)
-		return err
-	}
-
-	err = parseResponse(res, nil, elasticDefaultErrorResponseHandler)
-	if err != nil {
-		log.Warn("elasticClient.DoQueryRemove", "error parsing response", err)
-		return err
-	}
-
-	return nil
-}
-
-func (ec *elasticClient) doRefresh(index string) error {
-	res, err := ec.client.Indices.Refresh(
-		ec.client.Indices.Refresh.WithIndex(index),
-		ec.client.Indices.Refresh.WithIgnoreUnavailable(true),
-	)
-	if err != nil {
-		return err
-	}
-
-	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+	return ec.doQueryRemove(ctx, ec.client, index, body)
 }
 
 // TemplateExists checks weather a template is already created
@@ -205,40 +304,6 @@ func (ec *elasticClient) indexExists(index string) bool {
 	return exists(res, err)
 }
 
-// PolicyExists checks if a policy was already created
-func (ec *elasticClient) PolicyExists(policy string) bool {
-	policyRoute := fmt.Sprintf(
-		"%s/%s/ism/policies/%s",
-		ec.elasticBaseUrl,
-		kibanaPluginPath,
-		policy,
-	)
-
-	req := newRequest(http.MethodGet, policyRoute, nil)
-	res, err := ec.client.Transport.Perform(req)
-	if err != nil {
-		log.Warn("elasticClient.PolicyExists",
-			"error performing request", err.Error())
-		return false
-	}
-
-	response := &esapi.Response{
-		StatusCode: res.StatusCode,
-		Body:       res.Body,
-		Header:     res.Header,
-	}
-
-	existsRes := &data.Response{}
-	err = parseResponse(response, existsRes, kibanaResponseErrorHandler)
-	if err != nil {
-		log.Warn("elasticClient.PolicyExists",
-			"error returned by kibana api", err.Error())
-		return false
-	}
-
-	return existsRes.Status == http.StatusConflict
-}
-
 // AliasExists checks if an index alias already exists
 func (ec *elasticClient) aliasExists(alias string) bool {
 	aliasRoute := fmt.Sprintf(
@@ -273,42 +338,6 @@ func (ec *elasticClient) createIndex(index string) error {
 	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
 }
 
-// CreatePolicy creates a new policy for elastic indexes. Policies define rollover parameters
-func (ec *elasticClient) createPolicy(policyName string, policy *bytes.Buffer) error {
-	policyRoute := fmt.Sprintf(
-		"%s/_opendistro/_ism/policies/%s",
-		ec.elasticBaseUrl,
-		policyName,
-	)
-
-	req := newRequest(http.MethodPut, policyRoute, policy)
-	req.Header[headerContentType] = headerContentTypeJSON
-	req.Header[headerXSRF] = []string{"false"}
-	res, err := ec.client.Transport.Perform(req)
-	if err != nil {
-		return err
-	}
-
-	response := &esapi.Response{
-		StatusCode: res.StatusCode,
-		Body:       res.Body,
-		Header:     res.Header,
-	}
-
-	existsRes := &data.Response{}
-	err = parseResponse(response, existsRes, kibanaResponseErrorHandler)
-	if err != nil {
-		return err
-	}
-
-	errStr := fmt.Sprintf("%v", existsRes.Error)
-	if existsRes.Status == http.StatusConflict && !strings.Contains(errStr, errPolicyAlreadyExists) {
-		return dataindexer.ErrCouldNotCreatePolicy
-	}
-
-	return nil
-}
-
 // CreateIndexTemplate creates an elasticsearch index template
 func (ec *elasticClient) createIndexTemplate(templateName string, template io.Reader) error {
 	res, err := ec.client.Indices.PutTemplate(templateName, template, ec.client.Indices.PutTemplate.WithContext(context.Background()))
@@ -329,55 +358,14 @@ func (ec *elasticClient) createAlias(alias string, index string) error {
 	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
 }
 
-func (ec *elasticClient) getWriteIndex(alias string) (string, error) {
-	res, err := ec.client.Indices.GetAlias(
-		ec.client.Indices.GetAlias.WithIndex(alias),
-	)
-	if err != nil {
-		return "", err
-	}
-
-	var indexData map[string]struct {
-		Aliases map[string]struct {
-			IsWriteIndex bool `json:"is_write_index"`
-		} `json:"aliases"`
-	}
-	err = parseResponse(res, &indexData, elasticDefaultErrorResponseHandler)
-	if err != nil {
-		return "", err
-	}
-
-	for index, details := range indexData {
-		if len(indexData) == 1 {
-			return index, nil
-		}
-
-		for _, indexAlias := range details.Aliases {
-			if indexAlias.IsWriteIndex {
-				return index, nil
-			}
-		}
-	}
-
-	return alias, nil
-}
-
 // UpdateByQuery will update all the documents that match the provided query from the provided index
 func (ec *elasticClient) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
-	reader := bytes.NewReader(buff.Bytes())
-	res, err := ec.client.UpdateByQuery(
-		[]string{index},
-		ec.client.UpdateByQuery.WithBody(reader),
-		ec.client.UpdateByQuery.WithContext(ctx),
-	)
-	if err != nil {
-		return err
-	}
-	if res.IsError() {
-		return fmt.Errorf("%s", res.String())
-	}
+	return ec.updateByQuery(ctx, ec.client, index, buff)
+}
 
-	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+// Close does nothing, every request of the client is finished before it returns
+func (ec *elasticClient) Close() error {
+	return nil
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -0,0 +1,273 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	elasticsearch8 "github.com/elastic/go-elasticsearch/v8"
+	esapi8 "github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/pit"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+// ArgsElasticClient8 holds all the components needed to create a new instance of elasticClient8. The settings have the
+// same meaning as the ones of ArgsElasticClient
+type ArgsElasticClient8 struct {
+	Config                elasticsearch8.Config
+	BulkItemsMaxRetries   int
+	BulkItemsRetryBackOff time.Duration
+	CompressRequests      bool
+	SearchPageSize        int
+	SearchKeepAlive       time.Duration
+	RequestTimeouts       RequestTimeouts
+}
+
+type elasticClient8 struct {
+	requestSettings
+	client *elasticsearch8.Client
+
+	mutClusterInfo sync.Mutex
+	clusterInfo    *clusterInfo
+}
+
+// NewElasticClient8 will create a new instance of elasticClient8, a client of the Elasticsearch 8.x clusters. The
+// templates it creates are composable index templates, in the format emitted by the composable templates reader
+func NewElasticClient8(args ArgsElasticClient8) (*elasticClient8, error) {
+	if len(args.Config.Addresses) == 0 && args.Config.CloudID == "" {
+		return nil, dataindexer.ErrNoElasticUrlProvided
+	}
+	settings := requestSettings{
+		bulkItemsMaxRetries:   args.BulkItemsMaxRetries,
+		bulkItemsRetryBackOff: args.BulkItemsRetryBackOff,
+		compressRequests:      args.CompressRequests,
+		searchPageSize:        args.SearchPageSize,
+		searchKeepAlive:       args.SearchKeepAlive,
+		requestTimeouts:       args.RequestTimeouts,
+	}
+	err := settings.check()
+	if err != nil {
+		return nil, err
+	}
+
+	es, err := elasticsearch8.NewClient(args.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &elasticClient8{
+		requestSettings: settings,
+		client:          es,
+	}, nil
+}
+
+// toResponse converts a response of the v8 client, so it can be handled as the ones of the v7 client
+func toResponse(res *esapi8.Response) *esapi.Response {
+	if res == nil {
+		return nil
+	}
+
+	return &esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+}
+
+// getClusterInfo detects the version of the cluster with the first call and checks that it runs Elasticsearch 8.x
+func (ec *elasticClient8) getClusterInfo() (*clusterInfo, error) {
+	ec.mutClusterInfo.Lock()
+	defer ec.mutClusterInfo.Unlock()
+
+	if ec.clusterInfo != nil {
+		return ec.clusterInfo, nil
+	}
+
+	res, err := ec.client.Info()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCannotDetectCluster, err.Error())
+	}
+
+	info, err := checkClusterInfoResponse(toResponse(res), dataindexer.Elasticsearch8Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("elasticClient8: detected the cluster", "backend", info.backend, "version", info.version)
+	ec.clusterInfo = info
+
+	return info, nil
+}
+
+// CheckAndCreateTemplate creates the component templates and then the composable index template, if they do not
+// already exist. The template has to be in the format emitted by the composable templates reader
+func (ec *elasticClient8) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	_, err := ec.getClusterInfo()
+	if err != nil {
+		return err
+	}
+
+	return checkAndCreateComposableTemplate(ec.client.Transport, templateName, template)
+}
+
+// CheckAndCreatePolicy does not create the policy. The policies are index state management policies, a plugin that is
+// available only on OpenSearch
+func (ec *elasticClient8) CheckAndCreatePolicy(policyName string, _ *bytes.Buffer) error {
+	_, err := ec.getClusterInfo()
+	if err != nil {
+		return err
+	}
+
+	log.Debug("elasticClient8.CheckAndCreatePolicy: the index state management policies are created only on OpenSearch",
+		"policy", policyName)
+
+	return nil
+}
+
+// CheckAndCreateIndex creates a new index if it does not already exist
+func (ec *elasticClient8) CheckAndCreateIndex(indexName string) error {
+	res, err := ec.client.Indices.Exists([]string{indexName})
+	if exists(toResponse(res), err) {
+		return nil
+	}
+
+	res, err = ec.client.Indices.Create(indexName)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(toResponse(res), nil, elasticDefaultErrorResponseHandler)
+}
+
+// CheckAndCreateAlias creates a new alias if it does not already exist
+func (ec *elasticClient8) CheckAndCreateAlias(alias string, indexName string) error {
+	res, err := ec.client.Indices.ExistsAlias([]string{alias})
+	if exists(toResponse(res), err) {
+		return nil
+	}
+
+	res, err = ec.client.Indices.PutAlias([]string{indexName}, alias)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(toResponse(res), nil, elasticDefaultErrorResponseHandler)
+}
+
+// DoBulkRequest will do a bulk of request to elastic server. The items that fail with a retryable status are sent again,
+// with an exponential back off, until they succeed or the retries are exhausted
+func (ec *elasticClient8) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
+	return ec.doBulkRequestWithRetries(ctx, buff.Bytes(), index, ec.sendBulkRequest)
+}
+
+func (ec *elasticClient8) sendBulkRequest(ctx context.Context, body []byte, index string) (*esapi.Response, error) {
+	reqBody, err := ec.newRequestBody(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]func(*esapi8.BulkRequest), 0)
+	if index != "" {
+		options = append(options, ec.client.Bulk.WithIndex(index))
+	}
+
+	options = append(options, ec.client.Bulk.WithContext(reqBody.ctx), ec.client.Bulk.WithHeader(reqBody.header))
+
+	res, err := ec.client.Bulk(
+		reqBody.reader,
+		options...,
+	)
+	if err != nil {
+		log.Warn("elasticClient8.DoBulkRequest",
+			"indexer do bulk request no response", err.Error())
+		return nil, err
+	}
+
+	return toResponse(res), nil
+}
+
+// DoMultiGet wil do a multi get request to Elasticsearch server
+func (ec *elasticClient8) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, resBody interface{}) error {
+	ctx, cancel := withTimeout(ctx, ec.requestTimeouts.Get)
+	defer cancel()
+
+	obj := getDocumentsByIDsQuery(ids, withSource)
+	body, err := encode(obj)
+	if err != nil {
+		return err
+	}
+
+	reqBody, err := ec.newRequestBody(ctx, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	res, err := ec.client.Mget(
+		reqBody.reader,
+		ec.client.Mget.WithIndex(index),
+		ec.client.Mget.WithContext(reqBody.ctx),
+		ec.client.Mget.WithHeader(reqBody.header),
+	)
+	if err != nil {
+		log.Warn("elasticClient8.DoMultiGet",
+			"cannot do multi get no response", err.Error())
+		return err
+	}
+
+	err = parseResponse(toResponse(res), &resBody, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		log.Warn("elasticClient8.DoMultiGet",
+			"error parsing response", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// DoQueryRemove will do a query remove to elasticsearch server
+func (ec *elasticClient8) DoQueryRemove(ctx context.Context, index string, body *bytes.Buffer) error {
+	return ec.doQueryRemove(ctx, ec.client, index, body)
+}
+
+// UpdateByQuery will update all the documents that match the provided query from the provided index
+func (ec *elasticClient8) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
+	return ec.updateByQuery(ctx, ec.client, index, buff)
+}
+
+// DoCountRequest will get the number of elements that correspond with the provided query
+func (ec *elasticClient8) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
+	return ec.doCountRequest(ctx, ec.client, index, body)
+}
+
+// DoScrollRequest will page through all the documents matching the query with a point in time and search_after,
+// passing every page to the handler
+func (ec *elasticClient8) DoScrollRequest(
+	ctx context.Context,
+	index string,
+	body []byte,
+	withSource bool,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	return pit.Iterate(ctx, pit.ArgsIterator{
+		Client:         ec.client,
+		Index:          index,
+		Query:          body,
+		WithSource:     withSource,
+		PageSize:       ec.searchPageSize,
+		KeepAlive:      ec.searchKeepAlive,
+		RequestTimeout: ec.requestTimeouts.Scroll,
+	}, handlerFunc)
+}
+
+// Close does nothing, every request of the client is finished before it returns
+func (ec *elasticClient8) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ec *elasticClient8) IsInterfaceNil() bool {
+	return ec == nil
+}
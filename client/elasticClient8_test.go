@@ -0,0 +1,257 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	elasticsearch8 "github.com/elastic/go-elasticsearch/v8"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+const elasticsearch8Info = `{"version":{"number":"8.6.0","build_flavor":"default"},"tagline":"You Know, for Search"}`
+
+type cluster8Stub struct {
+	mut      sync.Mutex
+	info     string
+	existing map[string]bool
+	requests []string
+	bodies   map[string]string
+}
+
+func (cs *cluster8Stub) newClient(t *testing.T) *elasticClient8 {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		cs.mut.Lock()
+		defer cs.mut.Unlock()
+
+		request := r.Method + " " + r.URL.Path
+		cs.requests = append(cs.requests, request)
+		if cs.bodies == nil {
+			cs.bodies = make(map[string]string)
+		}
+		cs.bodies[request] = string(body)
+
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(cs.info))
+		case r.Method == http.MethodHead && !cs.existing[r.URL.Path]:
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "/_bulk"):
+			_, _ = w.Write([]byte(`{"errors":false,"items":[{"index":{"_index":"blocks","_id":"1","status":201}}]}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/_alias"):
+			_, _ = w.Write([]byte(`{"blocks-000001":{"aliases":{"blocks":{"is_write_index":true}}}}`))
+		case strings.HasSuffix(r.URL.Path, "/_count"):
+			_, _ = w.Write([]byte(`{"count":7}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/_pit"):
+			_, _ = w.Write([]byte(`{"id":"pit"}`))
+		case r.URL.Path == "/_search":
+			_, _ = w.Write([]byte(`{"pit_id":"pit","hits":{"hits":[{"_id":"a","sort":[0]}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	esClient, err := NewElasticClient8(ArgsElasticClient8{
+		Config: elasticsearch8.Config{
+			Addresses: []string{ts.URL},
+		},
+	})
+	require.Nil(t, err)
+
+	return esClient
+}
+
+func TestNewElasticClient8(t *testing.T) {
+	t.Parallel()
+
+	esClient, err := NewElasticClient8(ArgsElasticClient8{})
+	require.Nil(t, esClient)
+	require.Equal(t, dataindexer.ErrNoElasticUrlProvided, err)
+
+	esClient, err = NewElasticClient8(ArgsElasticClient8{
+		Config:              elasticsearch8.Config{Addresses: []string{"https://localhost:9200"}},
+		BulkItemsMaxRetries: -1,
+	})
+	require.Nil(t, esClient)
+	require.Equal(t, dataindexer.ErrInvalidBulkItemsRetries, err)
+
+	esClient, err = NewElasticClient8(ArgsElasticClient8{
+		Config:          elasticsearch8.Config{Addresses: []string{"https://localhost:9200"}},
+		RequestTimeouts: RequestTimeouts{Count: -1},
+	})
+	require.Nil(t, esClient)
+	require.Equal(t, dataindexer.ErrInvalidRequestTimeouts, err)
+
+	esClient, err = NewElasticClient8(ArgsElasticClient8{
+		Config: elasticsearch8.Config{Addresses: []string{"https://localhost:9200"}},
+	})
+	require.Nil(t, err)
+	require.False(t, esClient.IsInterfaceNil())
+}
+
+func TestElasticClient8_CheckAndCreateTemplate(t *testing.T) {
+	t.Parallel()
+
+	composableTemplate := `{
+		"component_templates": [
+			{"name": "blocks-settings", "body": {"template": {"settings": {"number_of_shards": 3}}}},
+			{"name": "blocks-mappings", "body": {"template": {"mappings": {"properties": {"nonce": {"type": "double"}}}}}}
+		],
+		"index_template": {"index_patterns": ["blocks-*"], "composed_of": ["blocks-settings", "blocks-mappings"], "priority": 500}
+	}`
+
+	t.Run("should create the component templates before the index template", func(t *testing.T) {
+		t.Parallel()
+
+		cs := &cluster8Stub{info: elasticsearch8Info}
+		esClient := cs.newClient(t)
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(composableTemplate))
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"GET /",
+			"HEAD /_component_template/blocks-settings",
+			"PUT /_component_template/blocks-settings",
+			"HEAD /_component_template/blocks-mappings",
+			"PUT /_component_template/blocks-mappings",
+			"HEAD /_index_template/blocks",
+			"PUT /_index_template/blocks",
+		}, cs.requests)
+		require.JSONEq(t, `{"template":{"settings":{"number_of_shards":3}}}`, cs.bodies["PUT /_component_template/blocks-settings"])
+		require.JSONEq(t, `{"index_patterns":["blocks-*"],"composed_of":["blocks-settings","blocks-mappings"],"priority":500}`,
+			cs.bodies["PUT /_index_template/blocks"])
+	})
+
+	t.Run("existing templates should not be created again", func(t *testing.T) {
+		t.Parallel()
+
+		cs := &cluster8Stub{
+			info: elasticsearch8Info,
+			existing: map[string]bool{
+				"/_component_template/blocks-settings": true,
+				"/_component_template/blocks-mappings": true,
+				"/_index_template/blocks":              true,
+			},
+		}
+		esClient := cs.newClient(t)
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(composableTemplate))
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"GET /",
+			"HEAD /_component_template/blocks-settings",
+			"HEAD /_component_template/blocks-mappings",
+			"HEAD /_index_template/blocks",
+		}, cs.requests)
+	})
+
+	t.Run("legacy template should error", func(t *testing.T) {
+		t.Parallel()
+
+		cs := &cluster8Stub{info: elasticsearch8Info}
+		esClient := cs.newClient(t)
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(`{"index_patterns":["blocks-*"]}`))
+		require.True(t, errors.Is(err, errInvalidComposableTemplate))
+	})
+
+	t.Run("elasticsearch 7 cluster should error", func(t *testing.T) {
+		t.Parallel()
+
+		cs := &cluster8Stub{info: elasticsearchInfo}
+		esClient := cs.newClient(t)
+
+		err := esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(composableTemplate))
+		require.True(t, errors.Is(err, dataindexer.ErrUnsupportedClusterVersion))
+		require.Equal(t, []string{"GET /"}, cs.requests)
+	})
+}
+
+func TestElasticClient8_CheckAndCreatePolicyShouldSkipThePolicies(t *testing.T) {
+	t.Parallel()
+
+	cs := &cluster8Stub{info: elasticsearch8Info}
+	esClient := cs.newClient(t)
+
+	err := esClient.CheckAndCreatePolicy("blocks_policy", bytes.NewBufferString(`{"policy":{}}`))
+	require.Nil(t, err)
+	require.Equal(t, []string{"GET /"}, cs.requests)
+}
+
+func TestElasticClient8_CheckAndCreateIndexAndAlias(t *testing.T) {
+	t.Parallel()
+
+	cs := &cluster8Stub{
+		info:     elasticsearch8Info,
+		existing: map[string]bool{"/blocks-000001": true},
+	}
+	esClient := cs.newClient(t)
+
+	err := esClient.CheckAndCreateIndex("blocks-000001")
+	require.Nil(t, err)
+	err = esClient.CheckAndCreateIndex("miniblocks-000001")
+	require.Nil(t, err)
+	err = esClient.CheckAndCreateAlias("miniblocks", "miniblocks-000001")
+	require.Nil(t, err)
+
+	require.Equal(t, []string{
+		"HEAD /blocks-000001",
+		"HEAD /miniblocks-000001",
+		"PUT /miniblocks-000001",
+		"HEAD /_alias/miniblocks",
+		"PUT /miniblocks-000001/_aliases/miniblocks",
+	}, cs.requests)
+}
+
+func TestElasticClient8_Requests(t *testing.T) {
+	t.Parallel()
+
+	cs := &cluster8Stub{info: elasticsearch8Info}
+	esClient := cs.newClient(t)
+
+	bulkBody := "{\"index\":{\"_index\":\"blocks\",\"_id\":\"1\"}}\n{\"nonce\":1}\n"
+	err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString(bulkBody), "")
+	require.Nil(t, err)
+	require.Equal(t, bulkBody, cs.bodies["POST /_bulk"])
+
+	count, err := esClient.DoCountRequest(context.Background(), "blocks", []byte(`{"query":{"match_all":{}}}`))
+	require.Nil(t, err)
+	require.Equal(t, uint64(7), count)
+
+	err = esClient.DoQueryRemove(context.Background(), "blocks", bytes.NewBufferString(`{"query":{"ids":{"values":["1"]}}}`))
+	require.Nil(t, err)
+	require.Equal(t, `{"query":{"ids":{"values":["1"]}}}`, cs.bodies["POST /blocks-000001/_delete_by_query"])
+
+	err = esClient.UpdateByQuery(context.Background(), "blocks", bytes.NewBufferString(`{"script":{}}`))
+	require.Nil(t, err)
+	require.Equal(t, `{"script":{}}`, cs.bodies["POST /blocks/_update_by_query"])
+}
+
+func TestElasticClient8_DoScrollRequestShouldUseThePointInTime(t *testing.T) {
+	t.Parallel()
+
+	cs := &cluster8Stub{info: elasticsearch8Info}
+	esClient := cs.newClient(t)
+
+	numPages := 0
+	err := esClient.DoScrollRequest(context.Background(), "tokens", []byte(`{"query":{"match_all":{}}}`), false, func(responseBytes []byte) error {
+		numPages++
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, numPages)
+	require.Equal(t, []string{"POST /tokens/_pit", "GET /_search", "DELETE /_pit"}, cs.requests)
+	require.Contains(t, cs.bodies["GET /_search"], `"sort":[{"_shard_doc":"asc"}]`)
+}
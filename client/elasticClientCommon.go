@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,9 +10,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v7/esapi"
-	"github.com/multiversx/mx-chain-es-indexer-go/data"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 )
 
@@ -91,63 +92,100 @@ func elasticDefaultErrorResponseHandler(res *esapi.Response) error {
 		res.StatusCode, responseBody, string(bodyBytes))
 }
 
-func elasticBulkRequestResponseHandler(res *esapi.Response) error {
+// readBulkResponse decodes the body of a bulk response and closes it, so the connection can be reused
+func readBulkResponse(res *esapi.Response) (*BulkRequestResponse, error) {
+	defer func() {
+		if res.Body != nil {
+			err := res.Body.Close()
+			if err != nil {
+				log.Warn("elasticClient.readBulkResponse", "could not close body", err.Error())
+			}
+		}
+	}()
+
 	if res.IsError() {
-		return fmt.Errorf("%s", res.String())
+		return nil, fmt.Errorf("%s", res.String())
 	}
 
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return fmt.Errorf("%w cannot read elastic response body bytes", err)
+		return nil, fmt.Errorf("%w cannot read elastic response body bytes", err)
 	}
 
-	return extractErrorFromBulkBodyResponseBytes(bodyBytes)
+	return unmarshalBulkResponse(bodyBytes)
+}
+
+func unmarshalBulkResponse(bodyBytes []byte) (*BulkRequestResponse, error) {
+	response := &BulkRequestResponse{}
+	err := json.Unmarshal(bodyBytes, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
 }
 
 func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte) error {
-	response := BulkRequestResponse{}
-	err := json.Unmarshal(bodyBytes, &response)
+	response, err := unmarshalBulkResponse(bodyBytes)
 	if err != nil {
 		return err
 	}
 
-	count := 0
-	errorsString := ""
-	for _, item := range response.Items {
-		var selectedItem Item
-
-		switch {
-		case item.ItemIndex != nil:
-			selectedItem = *item.ItemIndex
-		case item.ItemUpdate != nil:
-			selectedItem = *item.ItemUpdate
-		}
+	return createBulkItemsError(extractFailedItems(response))
+}
 
-		log.Trace("worked on", "index", selectedItem.Index,
-			"_id", selectedItem.ID,
-			"result", selectedItem.Result,
-			"status", selectedItem.Status,
+func extractFailedItems(response *BulkRequestResponse) []Item {
+	failedItems := make([]Item, 0)
+	for _, responseItem := range response.Items {
+		_, item := responseItem.getActionAndItem()
+		log.Trace("worked on", "index", item.Index,
+			"_id", item.ID,
+			"result", item.Result,
+			"status", item.Status,
 		)
 
-		if selectedItem.Status < http.StatusBadRequest {
+		if item.Status < http.StatusBadRequest {
 			continue
 		}
 
-		count++
-		errorsString += fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s" }}\n`,
-			selectedItem.Index, selectedItem.ID, selectedItem.Status, selectedItem.Error.Type, selectedItem.Error.Reason, selectedItem.Error.Cause.Type, selectedItem.Error.Cause.Reason)
+		failedItems = append(failedItems, item)
+	}
 
+	return failedItems
+}
+
+// createBulkItemsError returns an error that holds the index and the _id of the first failed items
+func createBulkItemsError(failedItems []Item) error {
+	count := 0
+	errorsString := ""
+	hasRejectedItems := false
+	for _, item := range failedItems {
+		hasRejectedItems = hasRejectedItems || isRejectedItem(item)
 		if count == numOfErrorsToExtractBulkResponse {
-			break
+			continue
 		}
+
+		count++
+		errorsString += fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s" }}\n`,
+			item.Index, item.ID, item.Status, item.Error.Type, item.Error.Reason, item.Error.Cause.Type, item.Error.Cause.Reason)
 	}
 	if errorsString == "" {
 		return nil
 	}
+	if hasRejectedItems {
+		return fmt.Errorf("%w: %s", dataindexer.ErrRejectedBulkItems, errorsString)
+	}
 
 	return fmt.Errorf("%s", errorsString)
 }
 
+// isRejectedItem returns true if the item was rejected because the write queue of the cluster is full
+func isRejectedItem(item Item) bool {
+	return item.Status == http.StatusTooManyRequests ||
+		item.Error.Type == rejectedExecutionException ||
+		item.Error.Cause.Type == rejectedExecutionException
+}
+
 func errIsAlreadyExists(response map[string]interface{}) bool {
 	alreadyExistsMessage := "resource_already_exists_exception"
 	errKey := "error"
@@ -194,28 +232,6 @@ func isErrAliasAlreadyExists(response map[string]interface{}) bool {
 	return existsString == aliasExistsMessage
 }
 
-func kibanaResponseErrorHandler(res *esapi.Response) error {
-	errorRes := &data.Response{}
-	decodeErr := loadResponseBody(res.Body, errorRes)
-	if decodeErr != nil {
-		return decodeErr
-	}
-
-	errStr := fmt.Sprintf("%v", errorRes.Error)
-	if errorRes.Status == http.StatusConflict && strings.Contains(errStr, errPolicyAlreadyExists) {
-		return nil
-	}
-
-	if errorRes.Error == nil && errorRes.Status < http.StatusBadRequest {
-		return nil
-	}
-
-	log.Warn("elasticClient.parseResponse",
-		"error returned by elastic API", errorRes.Error,
-		"code", res.StatusCode)
-	return dataindexer.ErrBackOff
-}
-
 func newRequest(method, path string, body *bytes.Buffer) *http.Request {
 	r := http.Request{
 		Method:     method,
@@ -268,3 +284,13 @@ func parseResponse(res *esapi.Response, dest interface{}, errorHandler responseE
 
 	return nil
 }
+
+// withTimeout bounds the provided context with the timeout of a request kind. A 0 timeout keeps only the deadline of the
+// provided context
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
@@ -154,3 +154,35 @@ func TestExtractErrorFromBulkBodyResponseBytesIndex(t *testing.T) {
 	err := extractErrorFromBulkBodyResponseBytes(responseBytes)
 	require.NotNil(t, err)
 }
+
+func TestExtractErrorFromBulkBodyResponseBytesRejectedItems(t *testing.T) {
+	t.Parallel()
+
+	t.Run("too many requests status should signal rejected items", func(t *testing.T) {
+		t.Parallel()
+
+		responseBytes := []byte(`{"took":39,"errors":true,"items":[{"index":{"_index":"transactions-000001","_id":"h1","status":201}},{"index":{"_index":"transactions-000001","_id":"h2","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected execution"}}}]}`)
+
+		err := extractErrorFromBulkBodyResponseBytes(responseBytes)
+		require.True(t, errorsGo.Is(err, dataindexer.ErrRejectedBulkItems))
+	})
+
+	t.Run("rejected execution cause should signal rejected items", func(t *testing.T) {
+		t.Parallel()
+
+		responseBytes := []byte(`{"took":39,"errors":true,"items":[{"index":{"_index":"transactions-000001","_id":"h1","status":500,"error":{"type":"remote_transport_exception","reason":"failed","caused_by":{"type":"es_rejected_execution_exception","reason":"rejected execution"}}}}]}`)
+
+		err := extractErrorFromBulkBodyResponseBytes(responseBytes)
+		require.True(t, errorsGo.Is(err, dataindexer.ErrRejectedBulkItems))
+	})
+
+	t.Run("version conflict should not signal rejected items", func(t *testing.T) {
+		t.Parallel()
+
+		responseBytes := []byte(`{"took":39,"errors":true,"items":[{"index":{"_index":"transactions-000001","_id":"h1","status":409,"error":{"type":"version_conflict_engine_exception","reason":"version conflict"}}}]}`)
+
+		err := extractErrorFromBulkBodyResponseBytes(responseBytes)
+		require.NotNil(t, err)
+		require.False(t, errorsGo.Is(err, dataindexer.ErrRejectedBulkItems))
+	})
+}
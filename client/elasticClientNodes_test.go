@@ -0,0 +1,83 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/stretchr/testify/require"
+)
+
+type nodeRequestsRecorder struct {
+	mut   sync.Mutex
+	paths []string
+}
+
+func (nrr *nodeRequestsRecorder) newNode(t *testing.T, response string) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nrr.mut.Lock()
+		nrr.paths = append(nrr.paths, r.Host+r.URL.Path)
+		nrr.mut.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestElasticClient_MultipleNodesShouldSkipTheUnreachableNode(t *testing.T) {
+	t.Parallel()
+
+	recorder := &nodeRequestsRecorder{}
+	liveNode := recorder.newNode(t, `{"took":1,"errors":false,"items":[]}`)
+	deadNode := httptest.NewServer(http.NotFoundHandler())
+	deadNode.Close()
+
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{deadNode.URL, liveNode.URL},
+			Logger:    &logging.CustomLogger{},
+		},
+	})
+	require.Nil(t, err)
+
+	numRequests := 4
+	for i := 0; i < numRequests; i++ {
+		err = esClient.DoBulkRequest(context.Background(), bytes.NewBufferString("{}\n{}\n"), "transactions")
+		require.Nil(t, err)
+	}
+
+	require.Len(t, recorder.paths, numRequests)
+}
+
+func TestElasticClient_PolicyRequestsShouldBeSpreadOverTheNodes(t *testing.T) {
+	t.Parallel()
+
+	recorder := &nodeRequestsRecorder{}
+	firstNode := recorder.newNode(t, `{"_id":"policy"}`)
+	secondNode := recorder.newNode(t, `{"_id":"policy"}`)
+
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{firstNode.URL, secondNode.URL},
+			Logger:    &logging.CustomLogger{},
+		},
+	})
+	require.Nil(t, err)
+
+	require.True(t, esClient.policyExists("policy"))
+	require.True(t, esClient.policyExists("policy"))
+
+	expectedPath := ismPoliciesPath + "policy"
+	require.ElementsMatch(t, []string{
+		firstNode.Listener.Addr().String() + expectedPath,
+		secondNode.Listener.Addr().String() + expectedPath,
+	}, recorder.paths)
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/multiversx/mx-chain-es-indexer-go/templates"
+	"github.com/tidwall/gjson"
+)
+
+var errInvalidComposableTemplate = errors.New("the template is not in the composable format")
+
+// The requests below are built with the v7 API and sent through the transport of the client, so the clients of all the
+// Elasticsearch versions share them
+
+// doQueryRemove refreshes the index and deletes the documents matching the query from the index the alias writes to
+func (rs *requestSettings) doQueryRemove(ctx context.Context, transport esapi.Transport, index string, body *bytes.Buffer) error {
+	ctx, cancel := withTimeout(ctx, rs.requestTimeouts.Remove)
+	defer cancel()
+
+	err := doRefresh(transport, index)
+	if err != nil {
+		log.Warn("elasticClient.doRefresh", "cannot do refresh", err)
+	}
+
+	writeIndex, err := getWriteIndex(transport, index)
+	if err != nil {
+		log.Warn("elasticClient.getWriteIndex", "cannot do get write index", err)
+		return err
+	}
+
+	ignoreUnavailable := true
+	res, err := esapi.DeleteByQueryRequest{
+		Index:             []string{writeIndex},
+		Body:              body,
+		IgnoreUnavailable: &ignoreUnavailable,
+		Conflicts:         esConflictsPolicy,
+	}.Do(ctx, transport)
+	if err != nil {
+		log.Warn("elasticClient.DoQueryRemove", "cannot do query remove", err)
+		return err
+	}
+
+	err = parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		log.Warn("elasticClient.DoQueryRemove", "error parsing response", err)
+		return err
+	}
+
+	return nil
+}
+
+func doRefresh(transport esapi.Transport, index string) error {
+	ignoreUnavailable := true
+	res, err := esapi.IndicesRefreshRequest{
+		Index:             []string{index},
+		IgnoreUnavailable: &ignoreUnavailable,
+	}.Do(context.Background(), transport)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
+
+func getWriteIndex(transport esapi.Transport, alias string) (string, error) {
+	res, err := esapi.IndicesGetAliasRequest{
+		Index: []string{alias},
+	}.Do(context.Background(), transport)
+	if err != nil {
+		return "", err
+	}
+
+	return getWriteIndexFromResponse(res, alias)
+}
+
+// getWriteIndexFromResponse returns the index the alias writes to, from the response of a get alias request
+func getWriteIndexFromResponse(res *esapi.Response, alias string) (string, error) {
+	var indexData map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}
+	err := parseResponse(res, &indexData, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		return "", err
+	}
+
+	for index, details := range indexData {
+		if len(indexData) == 1 {
+			return index, nil
+		}
+
+		for _, indexAlias := range details.Aliases {
+			if indexAlias.IsWriteIndex {
+				return index, nil
+			}
+		}
+	}
+
+	return alias, nil
+}
+
+// updateByQuery updates all the documents of the index that match the query
+func (rs *requestSettings) updateByQuery(ctx context.Context, transport esapi.Transport, index string, buff *bytes.Buffer) error {
+	ctx, cancel := withTimeout(ctx, rs.requestTimeouts.UpdateByQuery)
+	defer cancel()
+
+	reqBody, err := rs.newRequestBody(ctx, buff.Bytes())
+	if err != nil {
+		return err
+	}
+
+	header := make(http.Header)
+	for key, value := range reqBody.header {
+		header.Add(key, value)
+	}
+
+	res, err := esapi.UpdateByQueryRequest{
+		Index:  []string{index},
+		Body:   reqBody.reader,
+		Header: header,
+	}.Do(reqBody.ctx, transport)
+	if err != nil {
+		return err
+	}
+	if res.IsError() {
+		defer closeBody(res)
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
+
+// doCountRequest returns the number of documents of the index that match the query
+func (rs *requestSettings) doCountRequest(ctx context.Context, transport esapi.Transport, index string, body []byte) (uint64, error) {
+	ctx, cancel := withTimeout(ctx, rs.requestTimeouts.Count)
+	defer cancel()
+
+	res, err := esapi.CountRequest{
+		Index: []string{index},
+		Body:  bytes.NewBuffer(body),
+	}.Do(ctx, transport)
+	if err != nil {
+		return 0, err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return 0, err
+	}
+
+	return gjson.GetBytes(bodyBytes, "count").Uint(), nil
+}
+
+// checkAndCreateComposableTemplate creates the component templates and then the composable index template, if they do
+// not already exist. The template has to be in the format emitted by the composable templates reader
+func checkAndCreateComposableTemplate(transport esapi.Transport, templateName string, template *bytes.Buffer) error {
+	composable := &templates.ComposableTemplate{}
+	err := json.Unmarshal(template.Bytes(), composable)
+	if err != nil {
+		return fmt.Errorf("%w %s: %s", errInvalidComposableTemplate, templateName, err.Error())
+	}
+	if len(composable.IndexTemplate) == 0 {
+		return fmt.Errorf("%w %s: missing index template", errInvalidComposableTemplate, templateName)
+	}
+
+	for _, componentTemplate := range composable.ComponentTemplates {
+		err = checkAndCreateComponentTemplate(transport, componentTemplate.Name, componentTemplate.Body.ToBuffer())
+		if err != nil {
+			return fmt.Errorf("%w when creating the %s component template", err, componentTemplate.Name)
+		}
+	}
+
+	res, err := esapi.IndicesExistsIndexTemplateRequest{
+		Name: templateName,
+	}.Do(context.Background(), transport)
+	if exists(res, err) {
+		return nil
+	}
+
+	res, err = esapi.IndicesPutIndexTemplateRequest{
+		Name: templateName,
+		Body: composable.IndexTemplate.ToBuffer(),
+	}.Do(context.Background(), transport)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
+
+func checkAndCreateComponentTemplate(transport esapi.Transport, name string, body *bytes.Buffer) error {
+	res, err := esapi.ClusterExistsComponentTemplateRequest{
+		Name: name,
+	}.Do(context.Background(), transport)
+	if exists(res, err) {
+		return nil
+	}
+
+	res, err = esapi.ClusterPutComponentTemplateRequest{
+		Name: name,
+		Body: body,
+	}.Do(context.Background(), transport)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
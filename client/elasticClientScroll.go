@@ -10,129 +10,153 @@ import (
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/pit"
 	"github.com/tidwall/gjson"
 )
 
 // DoCountRequest will get the number of elements that correspond with the provided query
 func (ec *elasticClient) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
-	res, err := ec.client.Count(
-		ec.client.Count.WithIndex(index),
-		ec.client.Count.WithBody(bytes.NewBuffer(body)),
-		ec.client.Count.WithContext(ctx),
-	)
-	if err != nil {
-		return 0, err
-	}
-	if err != nil {
-		return 0, err
-	}
+	return ec.doCountRequest(ctx, ec.client, index, body)
+}
 
-	bodyBytes, err := getBytesFromResponse(res)
+// DoScrollRequest will page through all the documents matching the query, passing every page to the handler. The pages
+// are read with a point in time and search_after when the cluster supports it, otherwise with the scroll API
+func (ec *elasticClient) DoScrollRequest(
+	ctx context.Context,
+	index string,
+	body []byte,
+	withSource bool,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	info, err := ec.getClusterInfo()
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	countRes := gjson.Get(string(bodyBytes), "count")
+	if !info.supportsPointInTime() {
+		return ec.doScroll(ctx, index, body, withSource, handlerFunc)
+	}
 
-	return countRes.Uint(), nil
+	return pit.Iterate(ctx, pit.ArgsIterator{
+		Client:         ec.client,
+		Index:          index,
+		Query:          body,
+		WithSource:     withSource,
+		PageSize:       ec.searchPageSize,
+		KeepAlive:      ec.searchKeepAlive,
+		RequestTimeout: ec.requestTimeouts.Scroll,
+	}, handlerFunc)
 }
 
-// DoScrollRequest will perform a documents request using scroll api
-func (ec *elasticClient) DoScrollRequest(
+// doScroll pages through the documents with the scroll API, for OpenSearch and for the Elasticsearch versions that do not
+// have the point in time API
+func (ec *elasticClient) doScroll(
 	ctx context.Context,
 	index string,
 	body []byte,
 	withSource bool,
 	handlerFunc func(responseBytes []byte) error,
 ) error {
-	ec.countScroll++
+	pageSize := ec.searchPageSize
+	if pageSize == 0 {
+		pageSize = pit.DefaultPageSize
+	}
+	keepAlive := ec.searchKeepAlive
+	if keepAlive == 0 {
+		keepAlive = pit.DefaultKeepAlive
+	}
+
+	requestCtx, cancel := withTimeout(ctx, ec.requestTimeouts.Scroll)
 	res, err := ec.client.Search(
-		ec.client.Search.WithSize(9000),
-		ec.client.Search.WithScroll(10*time.Minute+time.Duration(ec.countScroll)*time.Millisecond),
-		ec.client.Search.WithContext(context.Background()),
+		ec.client.Search.WithSize(pageSize),
+		ec.client.Search.WithScroll(keepAlive),
 		ec.client.Search.WithIndex(index),
 		ec.client.Search.WithBody(bytes.NewBuffer(body)),
 		ec.client.Search.WithSource(strconv.FormatBool(withSource)),
-		ec.client.Search.WithContext(ctx),
+		ec.client.Search.WithContext(requestCtx),
 	)
+	bodyBytes, err := readScrollResponse(res, err)
+	cancel()
 	if err != nil {
 		return err
 	}
 
-	bodyBytes, err := getBytesFromResponse(res)
-	if err != nil {
-		return err
-	}
-
-	err = handlerFunc(bodyBytes)
-	if err != nil {
-		return err
-	}
-
-	scrollID := gjson.Get(string(bodyBytes), "_scroll_id")
-	return ec.iterateScroll(scrollID.String(), handlerFunc)
-}
-
-func (ec *elasticClient) iterateScroll(
-	scrollID string,
-	handlerFunc func(responseBytes []byte) error,
-) error {
-	if scrollID == "" {
-		return nil
-	}
+	scrollID := gjson.GetBytes(bodyBytes, "_scroll_id").String()
 	defer func() {
-		err := ec.clearScroll(scrollID)
-		if err != nil {
-			log.Warn("cannot clear scroll", "error", err)
+		// the scroll is cleared even if the context of the iteration was cancelled
+		errClear := ec.clearScroll(scrollID)
+		if errClear != nil {
+			log.Warn("elasticClient.doScroll: cannot clear the scroll", "index", index, "error", errClear)
 		}
 	}()
 
-	for {
-		scrollBodyBytes, errScroll := ec.getScrollResponse(scrollID)
-		if errScroll != nil {
-			return errScroll
+	for gjson.GetBytes(bodyBytes, "hits.hits.#").Int() > 0 {
+		err = handlerFunc(bodyBytes)
+		if err != nil {
+			return err
 		}
-
-		numberOfHits := gjson.Get(string(scrollBodyBytes), "hits.hits.#")
-		if numberOfHits.Int() < 1 {
+		if scrollID == "" {
 			return nil
 		}
-		err := handlerFunc(scrollBodyBytes)
+
+		bodyBytes, err = ec.getScrollPage(ctx, scrollID, keepAlive)
 		if err != nil {
 			return err
 		}
+		newScrollID := gjson.GetBytes(bodyBytes, "_scroll_id").String()
+		if newScrollID != "" {
+			scrollID = newScrollID
+		}
 	}
+
+	return nil
 }
 
-func (ec *elasticClient) getScrollResponse(scrollID string) ([]byte, error) {
-	ec.countScroll++
+func (ec *elasticClient) getScrollPage(ctx context.Context, scrollID string, keepAlive time.Duration) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	requestCtx, cancel := withTimeout(ctx, ec.requestTimeouts.Scroll)
+	defer cancel()
+
 	res, err := ec.client.Scroll(
 		ec.client.Scroll.WithScrollID(scrollID),
-		ec.client.Scroll.WithScroll(2*time.Minute+time.Duration(ec.countScroll)*time.Millisecond),
+		ec.client.Scroll.WithScroll(keepAlive),
+		ec.client.Scroll.WithContext(requestCtx),
 	)
-	if err != nil {
-		return nil, err
-	}
 
-	return getBytesFromResponse(res)
+	return readScrollResponse(res, err)
 }
 
 func (ec *elasticClient) clearScroll(scrollID string) error {
-	resp, err := ec.client.ClearScroll(
+	if scrollID == "" {
+		return nil
+	}
+
+	res, err := ec.client.ClearScroll(
 		ec.client.ClearScroll.WithScrollID(scrollID),
 	)
 	if err != nil {
 		return err
 	}
-	defer closeBody(resp)
+	defer closeBody(res)
 
-	if resp.IsError() && resp.StatusCode != http.StatusNotFound {
-		return fmt.Errorf("error response: %s", resp)
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("error response: %s", res)
 	}
 
 	return nil
 }
 
+func readScrollResponse(res *esapi.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return getBytesFromResponse(res)
+}
+
 func getBytesFromResponse(res *esapi.Response) ([]byte, error) {
 	if res.IsError() {
 		return nil, fmt.Errorf("error response: %s", res)
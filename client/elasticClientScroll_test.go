@@ -6,10 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,12 +31,171 @@ func TestElasticClient_DoCountRequest(t *testing.T) {
 		_, _ = w.Write(byteValue)
 	}
 
-	esClient, _ := NewElasticClient(elasticsearch.Config{
-		Addresses: []string{ts.URL},
-		Logger:    &logging.CustomLogger{},
+	esClient, _ := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
 	})
 
 	count, err := esClient.DoCountRequest(context.Background(), "tokens", []byte(`{}`))
 	require.Nil(t, err)
 	require.Equal(t, uint64(112671), count)
 }
+
+func TestElasticClient_NewClientInvalidSearchSettings(t *testing.T) {
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{"http://localhost:9200"},
+		},
+		SearchPageSize: 10001,
+	})
+	require.Nil(t, esClient)
+	require.Equal(t, indexer.ErrInvalidSearchSettings, err)
+
+	esClient, err = NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{"http://localhost:9200"},
+		},
+		SearchKeepAlive: -time.Second,
+	})
+	require.Nil(t, esClient)
+	require.Equal(t, indexer.ErrInvalidSearchSettings, err)
+}
+
+func TestElasticClient_DoScrollRequest(t *testing.T) {
+	searchBodies := make([]string, 0)
+	closedPit := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(elasticsearchInfo))
+		case r.Method == http.MethodPost && r.URL.Path == "/tokens/_pit":
+			require.Equal(t, "60s", r.URL.Query().Get("keep_alive"))
+			_, _ = w.Write([]byte(`{"id":"pit"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/_pit":
+			closedPit = true
+			_, _ = w.Write([]byte(`{"succeeded":true}`))
+		case r.URL.Path == "/_search":
+			require.Equal(t, "false", r.URL.Query().Get("_source"))
+			searchBodies = append(searchBodies, string(body))
+			if len(searchBodies) == 1 {
+				_, _ = w.Write([]byte(`{"pit_id":"pit","hits":{"hits":[{"_id":"a","sort":[0]},{"_id":"b","sort":[1]}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"pit_id":"pit","hits":{"hits":[{"_id":"c","sort":[2]}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
+		SearchPageSize:  2,
+		SearchKeepAlive: time.Minute,
+	})
+
+	numPages := 0
+	err := esClient.DoScrollRequest(context.Background(), "tokens", []byte(`{"query":{"match_all":{}}}`), false, func(responseBytes []byte) error {
+		numPages++
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, numPages)
+	require.Len(t, searchBodies, 2)
+	require.True(t, closedPit)
+}
+
+func TestElasticClient_DoScrollRequestShouldFallBackToScroll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		info    string
+		backend string
+	}{
+		{
+			name:    "opensearch 1.x",
+			info:    `{"version":{"distribution":"opensearch","number":"1.2.4"}}`,
+			backend: indexer.AutoBackend,
+		},
+		{
+			name:    "opensearch 2.x",
+			info:    openSearchInfo,
+			backend: indexer.OpenSearchBackend,
+		},
+		{
+			name:    "elasticsearch before 7.12",
+			info:    `{"version":{"number":"7.10.2","build_flavor":"default"}}`,
+			backend: indexer.ElasticsearchBackend,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mut := sync.Mutex{}
+			requests := make([]string, 0)
+			numScrollPages := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mut.Lock()
+				defer mut.Unlock()
+
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch {
+				case r.URL.Path == "/":
+					_, _ = w.Write([]byte(tt.info))
+				case r.URL.Path == "/tokens/_search":
+					require.Equal(t, "2", r.URL.Query().Get("size"))
+					require.Equal(t, "60000ms", r.URL.Query().Get("scroll"))
+					_, _ = w.Write([]byte(`{"_scroll_id":"scroll","hits":{"hits":[{"_id":"a"},{"_id":"b"}]}}`))
+				case r.Method == http.MethodGet && r.URL.Path == "/_search/scroll":
+					require.Equal(t, "scroll", r.URL.Query().Get("scroll_id"))
+					numScrollPages++
+					if numScrollPages == 1 {
+						_, _ = w.Write([]byte(`{"_scroll_id":"scroll","hits":{"hits":[{"_id":"c"}]}}`))
+						return
+					}
+					_, _ = w.Write([]byte(`{"_scroll_id":"scroll","hits":{"hits":[]}}`))
+				case r.Method == http.MethodDelete && r.URL.Path == "/_search/scroll/scroll":
+					_, _ = w.Write([]byte(`{"succeeded":true}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			esClient, _ := NewElasticClient(ArgsElasticClient{
+				Config: elasticsearch.Config{
+					Addresses: []string{ts.URL},
+					Logger:    &logging.CustomLogger{},
+				},
+				SearchPageSize:  2,
+				SearchKeepAlive: time.Minute,
+				Backend:         tt.backend,
+			})
+
+			numPages := 0
+			err := esClient.DoScrollRequest(context.Background(), "tokens", []byte(`{"query":{"match_all":{}}}`), false, func(responseBytes []byte) error {
+				numPages++
+				return nil
+			})
+			require.Nil(t, err)
+			require.Equal(t, 2, numPages)
+			require.Equal(t, []string{
+				"GET /",
+				"GET /tokens/_search",
+				"GET /_search/scroll",
+				"GET /_search/scroll",
+				"DELETE /_search/scroll/scroll",
+			}, requests)
+		})
+	}
+}
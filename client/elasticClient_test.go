@@ -1,12 +1,15 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
@@ -16,8 +19,10 @@ import (
 )
 
 func TestElasticClient_NewClientEmptyUrl(t *testing.T) {
-	esClient, err := NewElasticClient(elasticsearch.Config{
-		Addresses: []string{},
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{},
+		},
 	})
 	require.Nil(t, esClient)
 	require.Equal(t, indexer.ErrNoElasticUrlProvided, err)
@@ -35,8 +40,10 @@ func TestElasticClient_NewClient(t *testing.T) {
 		_, _ = w.Write([]byte(resp))
 	}
 
-	esClient, err := NewElasticClient(elasticsearch.Config{
-		Addresses: []string{ts.URL},
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+		},
 	})
 	require.Nil(t, err)
 	require.NotNil(t, esClient)
@@ -57,9 +64,11 @@ func TestElasticClient_DoMultiGet(t *testing.T) {
 		_, _ = w.Write(byteValue)
 	}
 
-	esClient, _ := NewElasticClient(elasticsearch.Config{
-		Addresses: []string{ts.URL},
-		Logger:    &logging.CustomLogger{},
+	esClient, _ := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
 	})
 
 	ids := []string{"id"}
@@ -91,11 +100,13 @@ func TestElasticClient_GetWriteIndexMultipleIndicesBehind(t *testing.T) {
 		_, _ = w.Write(byteValue)
 	}
 
-	esClient, _ := NewElasticClient(elasticsearch.Config{
-		Addresses: []string{ts.URL},
-		Logger:    &logging.CustomLogger{},
+	esClient, _ := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
 	})
-	res, err := esClient.getWriteIndex("blocks")
+	res, err := getWriteIndex(esClient.client, "blocks")
 	require.Nil(t, err)
 	require.Equal(t, "blocks-000004", res)
 }
@@ -115,11 +126,83 @@ func TestElasticClient_GetWriteIndexOneIndex(t *testing.T) {
 		_, _ = w.Write(byteValue)
 	}
 
-	esClient, _ := NewElasticClient(elasticsearch.Config{
-		Addresses: []string{ts.URL},
-		Logger:    &logging.CustomLogger{},
+	esClient, _ := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{ts.URL},
+			Logger:    &logging.CustomLogger{},
+		},
 	})
-	res, err := esClient.getWriteIndex("delegators")
+	res, err := getWriteIndex(esClient.client, "delegators")
 	require.Nil(t, err)
 	require.Equal(t, "delegators-000001", res)
 }
+
+func TestElasticClient_NewClientInvalidRequestTimeouts(t *testing.T) {
+	esClient, err := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{"http://localhost:9200"},
+		},
+		RequestTimeouts: RequestTimeouts{
+			Count: -time.Second,
+		},
+	})
+	require.Nil(t, esClient)
+	require.Equal(t, indexer.ErrInvalidRequestTimeouts, err)
+}
+
+func TestElasticClient_RequestTimeouts(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses:     []string{ts.URL},
+			Logger:        &logging.CustomLogger{},
+			DisableRetry:  true,
+			RetryOnStatus: []int{},
+		},
+		RequestTimeouts: RequestTimeouts{
+			Bulk:  50 * time.Millisecond,
+			Get:   50 * time.Millisecond,
+			Count: 50 * time.Millisecond,
+		},
+	})
+
+	t.Run("multi get", func(t *testing.T) {
+		startTime := time.Now()
+		err := esClient.DoMultiGet(context.Background(), []string{"id"}, "tokens", true, &data.ResponseTokens{})
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Less(t, time.Since(startTime), time.Second)
+	})
+
+	t.Run("bulk", func(t *testing.T) {
+		startTime := time.Now()
+		err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString(`{"index":{"_id":"1"}}`+"\n{}\n"), "tokens")
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Less(t, time.Since(startTime), time.Second)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		startTime := time.Now()
+		_, err := esClient.DoCountRequest(context.Background(), "tokens", []byte(`{}`))
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Less(t, time.Since(startTime), time.Second)
+	})
+
+	t.Run("the deadline of the provided context is kept", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		startTime := time.Now()
+		err := esClient.UpdateByQuery(ctx, "tokens", bytes.NewBufferString(`{}`))
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Less(t, time.Since(startTime), time.Second)
+	})
+}
@@ -0,0 +1,320 @@
+package fanout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+// Policy defines how the writes on a mirror cluster are handled
+type Policy string
+
+const (
+	// PolicyRequired makes a write fail when it fails on the mirror cluster
+	PolicyRequired Policy = "required"
+	// PolicyBestEffort sends the writes to the mirror cluster together with the main cluster, but only logs their failures
+	PolicyBestEffort Policy = "best-effort"
+	// PolicyAsync queues the writes for the mirror cluster and does not wait for them. Their failures are only logged
+	PolicyAsync Policy = "async"
+)
+
+const (
+	// PendingWritesMetricTopic is the identifier for the metric with the number of writes queued for an async mirror
+	PendingWritesMetricTopic = "fanout_pending_writes"
+	// LagMetricTopic is the identifier for the metric with the time the last async write waited in the queue
+	LagMetricTopic = "fanout_lag_ms"
+	// ErrorsMetricTopic is the identifier for the metric with the number of writes that failed on a mirror
+	ErrorsMetricTopic = "fanout_errors"
+	// MirrorLabelName is the name of the label that holds the mirror cluster of the fan-out metrics
+	MirrorLabelName = "mirror"
+
+	defaultAsyncQueueSize  = 1000
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+var log = logger.GetOrCreate("client/fanout")
+
+var (
+	errNilPrimaryClient       = errors.New("nil primary client")
+	errNilMirrorClient        = errors.New("nil mirror client")
+	errEmptyMirrorName        = errors.New("empty mirror name")
+	errDuplicatedMirrorName   = errors.New("duplicated mirror name")
+	errInvalidPolicy          = errors.New("invalid mirror policy")
+	errInvalidQueueSize       = errors.New("invalid async queue size")
+	errMirrorClosed           = errors.New("the mirror cluster client is closed")
+	errInvalidShutdownTimeout = errors.New("invalid shutdown timeout")
+	errAsyncQueueNotDrained   = errors.New("the async queue was not drained within the shutdown timeout")
+)
+
+// ArgsMirror holds the settings of a cluster that receives a copy of every write
+type ArgsMirror struct {
+	Name   string
+	Policy Policy
+	// AsyncQueueSize is the number of writes that can wait to be sent to an async mirror. When the queue is full,
+	// the writes wait for room in it. 0 means defaultAsyncQueueSize
+	AsyncQueueSize int
+	Client         DatabaseClientHandler
+}
+
+// ArgsFanOutClient holds all the components needed to create a new instance of fanOutClient
+type ArgsFanOutClient struct {
+	Primary       DatabaseClientHandler
+	Mirrors       []ArgsMirror
+	StatusMetrics core.StatusMetricsHandler
+	// ShutdownTimeout is the time the async mirrors have, on close, to send the writes left in their queues. 0 means
+	// defaultShutdownTimeout
+	ShutdownTimeout time.Duration
+}
+
+type fanOutClient struct {
+	primary         DatabaseClientHandler
+	mirrors         []*mirror
+	shutdownTimeout time.Duration
+	closeOnce       sync.Once
+	closeErr        error
+}
+
+// NewFanOutClient will create a new instance of fanOutClient. Every write is sent to the primary cluster and to all
+// the mirror clusters, while the reads are served by the primary cluster only
+func NewFanOutClient(args ArgsFanOutClient) (*fanOutClient, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrors := make([]*mirror, 0, len(args.Mirrors))
+	for _, argsMirror := range args.Mirrors {
+		mirrors = append(mirrors, newMirror(argsMirror, args.StatusMetrics))
+	}
+
+	shutdownTimeout := args.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	return &fanOutClient{
+		primary:         args.Primary,
+		mirrors:         mirrors,
+		shutdownTimeout: shutdownTimeout,
+	}, nil
+}
+
+func checkArgs(args ArgsFanOutClient) error {
+	if check.IfNil(args.Primary) {
+		return errNilPrimaryClient
+	}
+	if check.IfNil(args.StatusMetrics) {
+		return core.ErrNilMetricsHandler
+	}
+	if args.ShutdownTimeout < 0 {
+		return errInvalidShutdownTimeout
+	}
+
+	names := make(map[string]struct{})
+	for _, argsMirror := range args.Mirrors {
+		if argsMirror.Name == "" {
+			return errEmptyMirrorName
+		}
+		_, found := names[argsMirror.Name]
+		if found {
+			return fmt.Errorf("%w: %s", errDuplicatedMirrorName, argsMirror.Name)
+		}
+		names[argsMirror.Name] = struct{}{}
+
+		if check.IfNil(argsMirror.Client) {
+			return fmt.Errorf("%w: %s", errNilMirrorClient, argsMirror.Name)
+		}
+		if argsMirror.AsyncQueueSize < 0 {
+			return fmt.Errorf("%w: %s", errInvalidQueueSize, argsMirror.Name)
+		}
+
+		switch argsMirror.Policy {
+		case PolicyRequired, PolicyBestEffort, PolicyAsync:
+		default:
+			return fmt.Errorf("%w for the %s mirror: %s", errInvalidPolicy, argsMirror.Name, argsMirror.Policy)
+		}
+	}
+
+	return nil
+}
+
+// DoBulkRequest will send the bulk request to the primary cluster and to the mirror clusters
+func (foc *fanOutClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
+	body := copyBuffer(buff)
+	return foc.write(ctx, "bulk", func(ctx context.Context, client DatabaseClientHandler) error {
+		return client.DoBulkRequest(ctx, bytes.NewBuffer(body), index)
+	})
+}
+
+// DoQueryRemove will send the delete by query request to the primary cluster and to the mirror clusters
+func (foc *fanOutClient) DoQueryRemove(ctx context.Context, index string, buff *bytes.Buffer) error {
+	body := copyBuffer(buff)
+	return foc.write(ctx, "delete by query", func(ctx context.Context, client DatabaseClientHandler) error {
+		return client.DoQueryRemove(ctx, index, bytes.NewBuffer(body))
+	})
+}
+
+// UpdateByQuery will send the update by query request to the primary cluster and to the mirror clusters
+func (foc *fanOutClient) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
+	body := copyBuffer(buff)
+	return foc.write(ctx, "update by query", func(ctx context.Context, client DatabaseClientHandler) error {
+		return client.UpdateByQuery(ctx, index, bytes.NewBuffer(body))
+	})
+}
+
+// DoMultiGet will fetch the documents from the primary cluster
+func (foc *fanOutClient) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error {
+	return foc.primary.DoMultiGet(ctx, ids, index, withSource, res)
+}
+
+// DoScrollRequest will scroll the documents of the primary cluster
+func (foc *fanOutClient) DoScrollRequest(
+	ctx context.Context,
+	index string,
+	body []byte,
+	withSource bool,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	return foc.primary.DoScrollRequest(ctx, index, body, withSource, handlerFunc)
+}
+
+// DoCountRequest will count the documents of the primary cluster
+func (foc *fanOutClient) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
+	return foc.primary.DoCountRequest(ctx, index, body)
+}
+
+// CheckAndCreateIndex creates the index on all the clusters, if it does not already exist
+func (foc *fanOutClient) CheckAndCreateIndex(index string) error {
+	return foc.setup("create index", func(_ context.Context, client DatabaseClientHandler) error {
+		return client.CheckAndCreateIndex(index)
+	})
+}
+
+// CheckAndCreateAlias creates the alias on all the clusters, if it does not already exist
+func (foc *fanOutClient) CheckAndCreateAlias(alias string, index string) error {
+	return foc.setup("create alias", func(_ context.Context, client DatabaseClientHandler) error {
+		return client.CheckAndCreateAlias(alias, index)
+	})
+}
+
+// CheckAndCreateTemplate creates the index template on all the clusters, if it does not already exist
+func (foc *fanOutClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	body := copyBuffer(template)
+	return foc.setup("create template", func(_ context.Context, client DatabaseClientHandler) error {
+		return client.CheckAndCreateTemplate(templateName, bytes.NewBuffer(body))
+	})
+}
+
+// CheckAndCreatePolicy creates the index policy on all the clusters, if it does not already exist
+func (foc *fanOutClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	body := copyBuffer(policy)
+	return foc.setup("create policy", func(_ context.Context, client DatabaseClientHandler) error {
+		return client.CheckAndCreatePolicy(policyName, bytes.NewBuffer(body))
+	})
+}
+
+// write sends the write to the primary cluster and to the mirror clusters in parallel. The write fails if it fails on
+// the primary cluster or on a required mirror
+func (foc *fanOutClient) write(ctx context.Context, operation string, write writeFunc) error {
+	errs := make([]error, len(foc.mirrors)+1)
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(foc.mirrors) + 1)
+	go func() {
+		defer wg.Done()
+		errs[0] = write(ctx, foc.primary)
+	}()
+	for idx, m := range foc.mirrors {
+		go func(idx int, m *mirror) {
+			defer wg.Done()
+			errs[idx+1] = m.write(ctx, operation, write)
+		}(idx, m)
+	}
+	wg.Wait()
+
+	return joinErrors(errs)
+}
+
+// setup sends the setup request to the primary cluster and then to every mirror cluster, no matter its policy, so
+// the mirrors have the same indices as the primary cluster
+func (foc *fanOutClient) setup(operation string, setup writeFunc) error {
+	err := setup(context.Background(), foc.primary)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range foc.mirrors {
+		err = setup(context.Background(), m.client)
+		if err == nil {
+			continue
+		}
+
+		m.recordError(operation, err)
+		if m.policy == PolicyRequired {
+			return fmt.Errorf("%w on the %s mirror cluster", err, m.name)
+		}
+	}
+
+	return nil
+}
+
+func joinErrors(errs []error) error {
+	nonNilErrs := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			nonNilErrs = append(nonNilErrs, err)
+		}
+	}
+
+	if len(nonNilErrs) == 1 {
+		return nonNilErrs[0]
+	}
+
+	return errors.Join(nonNilErrs...)
+}
+
+func copyBuffer(buff *bytes.Buffer) []byte {
+	if buff == nil {
+		return nil
+	}
+
+	return append([]byte(nil), buff.Bytes()...)
+}
+
+// Close will stop accepting writes and will wait, within the shutdown timeout, for the async mirrors to send the
+// writes left in their queues. Afterwards, the clients of the primary cluster and of the mirror clusters are closed
+func (foc *fanOutClient) Close() error {
+	foc.closeOnce.Do(func() {
+		errs := make([]error, len(foc.mirrors))
+		wg := sync.WaitGroup{}
+		wg.Add(len(foc.mirrors))
+		for idx, m := range foc.mirrors {
+			go func(idx int, m *mirror) {
+				defer wg.Done()
+				errs[idx] = m.close(foc.shutdownTimeout)
+			}(idx, m)
+		}
+		wg.Wait()
+
+		errs = append(errs, foc.primary.Close())
+		for _, m := range foc.mirrors {
+			errs = append(errs, m.client.Close())
+		}
+
+		foc.closeErr = joinErrors(errs)
+	})
+
+	return foc.closeErr
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (foc *fanOutClient) IsInterfaceNil() bool {
+	return foc == nil
+}
@@ -0,0 +1,375 @@
+package fanout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type bulkRecorder struct {
+	mut    sync.Mutex
+	bodies []string
+}
+
+func (br *bulkRecorder) newClient(err error) *mock.DatabaseWriterStub {
+	return &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			br.mut.Lock()
+			br.bodies = append(br.bodies, index+":"+buff.String())
+			br.mut.Unlock()
+
+			return err
+		},
+	}
+}
+
+func (br *bulkRecorder) numBodies() int {
+	br.mut.Lock()
+	defer br.mut.Unlock()
+
+	return len(br.bodies)
+}
+
+func createMockArgsFanOutClient() ArgsFanOutClient {
+	return ArgsFanOutClient{
+		Primary:       &mock.DatabaseWriterStub{},
+		StatusMetrics: metrics.NewStatusMetrics(),
+	}
+}
+
+func TestNewFanOutClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil primary should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFanOutClient()
+		args.Primary = nil
+		foc, err := NewFanOutClient(args)
+		require.Nil(t, foc)
+		require.Equal(t, errNilPrimaryClient, err)
+	})
+
+	t.Run("nil status metrics should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFanOutClient()
+		args.StatusMetrics = nil
+		foc, err := NewFanOutClient(args)
+		require.Nil(t, foc)
+		require.Equal(t, core.ErrNilMetricsHandler, err)
+	})
+
+	t.Run("invalid mirrors should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFanOutClient()
+		args.Mirrors = []ArgsMirror{{Policy: PolicyRequired, Client: &mock.DatabaseWriterStub{}}}
+		_, err := NewFanOutClient(args)
+		require.Equal(t, errEmptyMirrorName, err)
+
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyRequired}}
+		_, err = NewFanOutClient(args)
+		require.True(t, errors.Is(err, errNilMirrorClient))
+
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: "sometimes", Client: &mock.DatabaseWriterStub{}}}
+		_, err = NewFanOutClient(args)
+		require.True(t, errors.Is(err, errInvalidPolicy))
+
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyAsync, AsyncQueueSize: -1, Client: &mock.DatabaseWriterStub{}}}
+		_, err = NewFanOutClient(args)
+		require.True(t, errors.Is(err, errInvalidQueueSize))
+
+		args.Mirrors = []ArgsMirror{
+			{Name: "standby", Policy: PolicyRequired, Client: &mock.DatabaseWriterStub{}},
+			{Name: "standby", Policy: PolicyBestEffort, Client: &mock.DatabaseWriterStub{}},
+		}
+		_, err = NewFanOutClient(args)
+		require.True(t, errors.Is(err, errDuplicatedMirrorName))
+	})
+
+	t.Run("negative shutdown timeout should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFanOutClient()
+		args.ShutdownTimeout = -time.Second
+		foc, err := NewFanOutClient(args)
+		require.Nil(t, foc)
+		require.Equal(t, errInvalidShutdownTimeout, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFanOutClient()
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyAsync, Client: &mock.DatabaseWriterStub{}}}
+		foc, err := NewFanOutClient(args)
+		require.Nil(t, err)
+		require.False(t, foc.IsInterfaceNil())
+	})
+}
+
+func TestFanOutClient_DoBulkRequestShouldMirrorTheWrites(t *testing.T) {
+	t.Parallel()
+
+	recorder := &bulkRecorder{}
+	args := createMockArgsFanOutClient()
+	args.Primary = recorder.newClient(nil)
+	args.Mirrors = []ArgsMirror{
+		{Name: "required", Policy: PolicyRequired, Client: recorder.newClient(nil)},
+		{Name: "bestEffort", Policy: PolicyBestEffort, Client: recorder.newClient(nil)},
+	}
+	foc, _ := NewFanOutClient(args)
+
+	buff := bytes.NewBufferString("body")
+	err := foc.DoBulkRequest(context.Background(), buff, "transactions")
+	require.Nil(t, err)
+	require.Equal(t, []string{"transactions:body", "transactions:body", "transactions:body"}, recorder.bodies)
+}
+
+func TestFanOutClient_DoBulkRequestErrorsByPolicy(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("test error")
+
+	t.Run("primary error should be returned as it is", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &bulkRecorder{}
+		args := createMockArgsFanOutClient()
+		args.Primary = recorder.newClient(testErr)
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyRequired, Client: recorder.newClient(nil)}}
+		foc, _ := NewFanOutClient(args)
+
+		err := foc.DoBulkRequest(context.Background(), bytes.NewBufferString("body"), "transactions")
+		require.Equal(t, testErr, err)
+	})
+
+	t.Run("required mirror error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &bulkRecorder{}
+		args := createMockArgsFanOutClient()
+		args.Primary = recorder.newClient(nil)
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyRequired, Client: recorder.newClient(testErr)}}
+		foc, _ := NewFanOutClient(args)
+
+		err := foc.DoBulkRequest(context.Background(), bytes.NewBufferString("body"), "transactions")
+		require.True(t, errors.Is(err, testErr))
+		require.True(t, strings.Contains(err.Error(), "standby"))
+	})
+
+	t.Run("best effort mirror error should only be counted", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &bulkRecorder{}
+		statusMetrics := metrics.NewStatusMetrics()
+		args := createMockArgsFanOutClient()
+		args.StatusMetrics = statusMetrics
+		args.Primary = recorder.newClient(nil)
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyBestEffort, Client: recorder.newClient(testErr)}}
+		foc, _ := NewFanOutClient(args)
+
+		err := foc.DoBulkRequest(context.Background(), bytes.NewBufferString("body"), "transactions")
+		require.Nil(t, err)
+		require.Contains(t, statusMetrics.GetMetricsForPrometheus(), `fanout_errors{shardID="#",mirror="standby"} 1`)
+	})
+}
+
+func TestFanOutClient_AsyncMirrorShouldNotBlockTheWrites(t *testing.T) {
+	t.Parallel()
+
+	unblockMirror := make(chan struct{})
+	mirrorRecorder := &bulkRecorder{}
+	statusMetrics := metrics.NewStatusMetrics()
+	args := createMockArgsFanOutClient()
+	args.StatusMetrics = statusMetrics
+	args.Mirrors = []ArgsMirror{{
+		Name:   "standby",
+		Policy: PolicyAsync,
+		Client: &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				<-unblockMirror
+				return mirrorRecorder.newClient(nil).DoBulkRequestCalled(buff, index)
+			},
+		},
+	}}
+	foc, _ := NewFanOutClient(args)
+
+	buff := bytes.NewBufferString("first")
+	err := foc.DoBulkRequest(context.Background(), buff, "transactions")
+	require.Nil(t, err)
+	// the caller is free to reuse its buffer once the write returned
+	buff.Reset()
+	buff.WriteString("other")
+	err = foc.DoBulkRequest(context.Background(), bytes.NewBufferString("second"), "transactions")
+	require.Nil(t, err)
+	require.Equal(t, 0, mirrorRecorder.numBodies())
+
+	close(unblockMirror)
+	require.Eventually(t, func() bool {
+		return mirrorRecorder.numBodies() == 2
+	}, time.Second, time.Millisecond)
+	require.Equal(t, []string{"transactions:first", "transactions:second"}, mirrorRecorder.bodies)
+	require.Eventually(t, func() bool {
+		return strings.Contains(statusMetrics.GetMetricsForPrometheus(), `fanout_pending_writes{shardID="#",mirror="standby"} 0`)
+	}, time.Second, time.Millisecond)
+}
+
+func TestFanOutClient_ReadsShouldUseThePrimary(t *testing.T) {
+	t.Parallel()
+
+	numPrimaryReads := 0
+	args := createMockArgsFanOutClient()
+	args.Primary = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			numPrimaryReads++
+			return nil
+		},
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			numPrimaryReads++
+			return nil
+		},
+	}
+	args.Mirrors = []ArgsMirror{{
+		Name:   "standby",
+		Policy: PolicyRequired,
+		Client: &mock.DatabaseWriterStub{
+			DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+			DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		},
+	}}
+	foc, _ := NewFanOutClient(args)
+
+	err := foc.DoMultiGet(context.Background(), []string{"id"}, "tokens", true, nil)
+	require.Nil(t, err)
+	err = foc.DoScrollRequest(context.Background(), "tokens", nil, true, nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, numPrimaryReads)
+}
+
+func TestFanOutClient_SetupShouldReachAllTheMirrors(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("test error")
+	createdIndices := make(map[string]int)
+	mut := sync.Mutex{}
+	newClient := func(name string, err error) *mock.DatabaseWriterStub {
+		return &mock.DatabaseWriterStub{
+			CheckAndCreateIndexCalled: func(index string) error {
+				mut.Lock()
+				createdIndices[name]++
+				mut.Unlock()
+				return err
+			},
+		}
+	}
+
+	args := createMockArgsFanOutClient()
+	args.Primary = newClient("primary", nil)
+	args.Mirrors = []ArgsMirror{
+		{Name: "async", Policy: PolicyAsync, Client: newClient("async", testErr)},
+		{Name: "required", Policy: PolicyRequired, Client: newClient("required", nil)},
+	}
+	foc, _ := NewFanOutClient(args)
+
+	err := foc.CheckAndCreateIndex("transactions")
+	require.Nil(t, err)
+	require.Equal(t, map[string]int{"primary": 1, "async": 1, "required": 1}, createdIndices)
+
+	args.Mirrors = []ArgsMirror{{Name: "required", Policy: PolicyRequired, Client: newClient("required", testErr)}}
+	foc, _ = NewFanOutClient(args)
+	err = foc.CheckAndCreateIndex("transactions")
+	require.True(t, errors.Is(err, testErr))
+}
+
+func TestFanOutClient_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send the queued writes and close the clients", func(t *testing.T) {
+		t.Parallel()
+
+		unblockMirror := make(chan struct{})
+		mirrorRecorder := &bulkRecorder{}
+		numClosed := 0
+		mut := sync.Mutex{}
+		closeCalled := func() error {
+			mut.Lock()
+			numClosed++
+			mut.Unlock()
+			return nil
+		}
+
+		args := createMockArgsFanOutClient()
+		args.Primary = &mock.DatabaseWriterStub{CloseCalled: closeCalled}
+		mirrorClient := mirrorRecorder.newClient(nil)
+		recordBulk := mirrorClient.DoBulkRequestCalled
+		mirrorClient.DoBulkRequestCalled = func(buff *bytes.Buffer, index string) error {
+			<-unblockMirror
+			return recordBulk(buff, index)
+		}
+		mirrorClient.CloseCalled = closeCalled
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyAsync, Client: mirrorClient}}
+		foc, _ := NewFanOutClient(args)
+
+		for i := 0; i < 3; i++ {
+			err := foc.DoBulkRequest(context.Background(), bytes.NewBufferString("body"), "transactions")
+			require.Nil(t, err)
+		}
+		close(unblockMirror)
+
+		err := foc.Close()
+		require.Nil(t, err)
+		require.Equal(t, 3, mirrorRecorder.numBodies())
+		require.Equal(t, 2, numClosed)
+
+		err = foc.DoBulkRequest(context.Background(), bytes.NewBufferString("body"), "transactions")
+		require.True(t, errors.Is(err, errMirrorClosed))
+
+		// the second call should not close the clients again
+		err = foc.Close()
+		require.Nil(t, err)
+		require.Equal(t, 2, numClosed)
+	})
+
+	t.Run("should drop the queued writes after the shutdown timeout", func(t *testing.T) {
+		t.Parallel()
+
+		mirrorRecorder := &bulkRecorder{}
+		args := createMockArgsFanOutClient()
+		args.ShutdownTimeout = 10 * time.Millisecond
+		mirrorClient := mirrorRecorder.newClient(nil)
+		recordBulk := mirrorClient.DoBulkRequestCalled
+		mirrorClient.DoBulkRequestCalled = func(buff *bytes.Buffer, index string) error {
+			// the write is stuck until the shutdown timeout cancels it
+			time.Sleep(50 * time.Millisecond)
+			return recordBulk(buff, index)
+		}
+		args.Mirrors = []ArgsMirror{{Name: "standby", Policy: PolicyAsync, Client: mirrorClient}}
+		foc, _ := NewFanOutClient(args)
+
+		for i := 0; i < 3; i++ {
+			err := foc.DoBulkRequest(context.Background(), bytes.NewBufferString("body"), "transactions")
+			require.Nil(t, err)
+		}
+
+		err := foc.Close()
+		require.True(t, errors.Is(err, errAsyncQueueNotDrained))
+		require.Less(t, mirrorRecorder.numBodies(), 3)
+	})
+}
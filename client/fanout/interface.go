@@ -0,0 +1,24 @@
+package fanout
+
+import (
+	"bytes"
+	"context"
+)
+
+// DatabaseClientHandler defines the actions that a client of a cluster the writes are fanned out to should do
+type DatabaseClientHandler interface {
+	DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error
+	DoQueryRemove(ctx context.Context, index string, buff *bytes.Buffer) error
+	DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error
+	DoScrollRequest(ctx context.Context, index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error)
+	UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error
+
+	CheckAndCreateIndex(index string) error
+	CheckAndCreateAlias(alias string, index string) error
+	CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error
+	CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error
+
+	Close() error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,199 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+)
+
+type writeFunc func(ctx context.Context, client DatabaseClientHandler) error
+
+type asyncWrite struct {
+	operation  string
+	enqueuedAt time.Time
+	write      writeFunc
+}
+
+type mirror struct {
+	name          string
+	policy        Policy
+	client        DatabaseClientHandler
+	statusMetrics core.StatusMetricsHandler
+	pendingTopic  string
+	lagTopic      string
+	errorsTopic   string
+	queue         chan *asyncWrite
+	numErrors     uint64
+
+	// ctx is the context of the async writes. It is cancelled when the queue cannot be drained within the shutdown
+	// timeout
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeChan chan struct{}
+	doneChan  chan struct{}
+	closeOnce sync.Once
+}
+
+func newMirror(args ArgsMirror, statusMetrics core.StatusMetricsHandler) *mirror {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &mirror{
+		name:          args.Name,
+		policy:        args.Policy,
+		client:        args.Client,
+		statusMetrics: statusMetrics,
+		pendingTopic:  request.ExtendTopicWithLabel(PendingWritesMetricTopic, MirrorLabelName, args.Name),
+		lagTopic:      request.ExtendTopicWithLabel(LagMetricTopic, MirrorLabelName, args.Name),
+		errorsTopic:   request.ExtendTopicWithLabel(ErrorsMetricTopic, MirrorLabelName, args.Name),
+		ctx:           ctx,
+		cancel:        cancel,
+		closeChan:     make(chan struct{}),
+		doneChan:      make(chan struct{}),
+	}
+
+	if m.policy != PolicyAsync {
+		close(m.doneChan)
+		return m
+	}
+
+	queueSize := args.AsyncQueueSize
+	if queueSize == 0 {
+		queueSize = defaultAsyncQueueSize
+	}
+	m.queue = make(chan *asyncWrite, queueSize)
+	go m.processQueue()
+
+	return m
+}
+
+// write sends the write to the mirror cluster. Only the errors of a required mirror are returned, the other ones are
+// logged and counted. The writes of an async mirror are queued and the call returns as soon as the write is queued
+func (m *mirror) write(ctx context.Context, operation string, write writeFunc) error {
+	if m.policy == PolicyAsync {
+		return m.enqueue(ctx, operation, write)
+	}
+
+	err := write(ctx, m.client)
+	if err == nil {
+		return nil
+	}
+
+	m.recordError(operation, err)
+	if m.policy == PolicyRequired {
+		return fmt.Errorf("%w on the %s mirror cluster", err, m.name)
+	}
+
+	return nil
+}
+
+func (m *mirror) enqueue(ctx context.Context, operation string, write writeFunc) error {
+	asyncOperation := &asyncWrite{
+		operation:  operation,
+		enqueuedAt: time.Now(),
+		write:      write,
+	}
+
+	select {
+	case <-m.closeChan:
+		return fmt.Errorf("%w: %s", errMirrorClosed, m.name)
+	default:
+	}
+
+	select {
+	case m.queue <- asyncOperation:
+		m.statusMetrics.SetGauge(m.pendingTopic, uint64(len(m.queue)))
+		return nil
+	case <-m.closeChan:
+		return fmt.Errorf("%w: %s", errMirrorClosed, m.name)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *mirror) processQueue() {
+	defer close(m.doneChan)
+
+	for {
+		select {
+		case asyncOperation := <-m.queue:
+			m.processAsyncWrite(asyncOperation)
+		case <-m.closeChan:
+			m.drainQueue()
+			return
+		}
+	}
+}
+
+// drainQueue sends the writes left in the queue when the mirror is closed. It stops when the queue is empty or when
+// the shutdown timeout passes
+func (m *mirror) drainQueue() {
+	for m.ctx.Err() == nil {
+		select {
+		case asyncOperation := <-m.queue:
+			m.processAsyncWrite(asyncOperation)
+		default:
+			return
+		}
+	}
+}
+
+func (m *mirror) processAsyncWrite(asyncOperation *asyncWrite) {
+	// the context of the caller is not used because the caller does not wait for the async writes
+	err := asyncOperation.write(m.ctx, m.client)
+	if err != nil {
+		m.recordError(asyncOperation.operation, err)
+	}
+
+	m.statusMetrics.SetGauge(m.pendingTopic, uint64(len(m.queue)))
+	m.statusMetrics.SetGauge(m.lagTopic, uint64(time.Since(asyncOperation.enqueuedAt).Milliseconds()))
+}
+
+// close stops accepting new writes and waits for the queued ones to be sent. If they are not sent within the shutdown
+// timeout, the write in progress is cancelled and the writes left in the queue are dropped
+func (m *mirror) close(shutdownTimeout time.Duration) error {
+	var err error
+	m.closeOnce.Do(func() {
+		close(m.closeChan)
+		err = m.waitQueueDrained(shutdownTimeout)
+		m.cancel()
+	})
+
+	return err
+}
+
+func (m *mirror) waitQueueDrained(shutdownTimeout time.Duration) error {
+	timer := time.NewTimer(shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-m.doneChan:
+		return nil
+	case <-timer.C:
+	}
+
+	m.cancel()
+	<-m.doneChan
+
+	numDropped := len(m.queue)
+	log.Warn("fanOutClient: the async writes were not sent to the mirror cluster within the shutdown timeout",
+		"mirror", m.name,
+		"dropped writes", numDropped,
+		"shutdown timeout", shutdownTimeout)
+
+	return fmt.Errorf("%w: %d writes were dropped for the %s mirror", errAsyncQueueNotDrained, numDropped, m.name)
+}
+
+func (m *mirror) recordError(operation string, err error) {
+	log.Warn("fanOutClient: cannot write on the mirror cluster",
+		"mirror", m.name,
+		"policy", m.policy,
+		"operation", operation,
+		"error", err.Error())
+
+	numErrors := atomic.AddUint64(&m.numErrors, 1)
+	m.statusMetrics.SetGauge(m.errorsTopic, numErrors)
+}
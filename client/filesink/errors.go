@@ -0,0 +1,13 @@
+package filesink
+
+import "errors"
+
+var errEmptyDirectory = errors.New("empty file sink directory")
+
+var errInvalidFileSize = errors.New("invalid file sink file size")
+
+var errMissingIndex = errors.New("missing index of the bulk action")
+
+var errInvalidBulkAction = errors.New("invalid bulk action")
+
+var errSinkClosed = errors.New("file sink is closed")
@@ -0,0 +1,328 @@
+package filesink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	bulkDirectory          = "bulk"
+	removeDirectory        = "remove"
+	updateByQueryDirectory = "update-by-query"
+	templatesDirectory     = "templates"
+	policiesDirectory      = "policies"
+	shardDirectoryPrefix   = "shard-"
+	noShardDirectory       = "no-shard"
+	templateFileExtension  = ".json"
+
+	deleteAction = "delete"
+)
+
+var log = logger.GetOrCreate("client/filesink")
+
+// ArgsFileSinkClient holds all the components needed to create a new instance of fileSinkClient
+type ArgsFileSinkClient struct {
+	Directory          string
+	MaxFileSizeInBytes int64
+	// ReadOnlyClient is optional. Without it, the multi-get, scroll and count requests return no documents
+	ReadOnlyClient ReadOnlyClientHandler
+}
+
+// queryEntry is the line written for every remove and update-by-query request
+type queryEntry struct {
+	Index string          `json:"index"`
+	Body  json.RawMessage `json:"body"`
+}
+
+type bulkActionMetadata struct {
+	Index string `json:"_index"`
+}
+
+type fileSinkClient struct {
+	mut            sync.Mutex
+	directory      string
+	maxFileSize    int64
+	readOnlyClient ReadOnlyClientHandler
+	files          map[string]*ndjsonFile
+	closed         bool
+}
+
+// NewFileSinkClient will create a new instance of fileSinkClient. Instead of being sent to a cluster, the bulk requests
+// are written in NDJSON files, one directory for every index and shard, that can be loaded later with the _bulk API.
+// The remove and the update-by-query requests are written in separate files
+func NewFileSinkClient(args ArgsFileSinkClient) (*fileSinkClient, error) {
+	if args.Directory == "" {
+		return nil, errEmptyDirectory
+	}
+	if args.MaxFileSizeInBytes <= 0 {
+		return nil, errInvalidFileSize
+	}
+
+	err := os.MkdirAll(args.Directory, directoryPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	var readOnlyClient ReadOnlyClientHandler
+	if !check.IfNil(args.ReadOnlyClient) {
+		readOnlyClient = args.ReadOnlyClient
+	}
+
+	return &fileSinkClient{
+		directory:      args.Directory,
+		maxFileSize:    args.MaxFileSizeInBytes,
+		readOnlyClient: readOnlyClient,
+		files:          make(map[string]*ndjsonFile),
+	}, nil
+}
+
+// DoBulkRequest will write the actions of the bulk request in the files of their indices. The index is used for the
+// actions that do not have their own index
+func (fsc *fileSinkClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
+	entries, indices, err := splitBulkByIndex(buff.Bytes(), index)
+	if err != nil {
+		return err
+	}
+
+	shardDirectory := getShardDirectory(ctx)
+
+	fsc.mut.Lock()
+	defer fsc.mut.Unlock()
+
+	for _, actionsIndex := range indices {
+		err = fsc.write(filepath.Join(bulkDirectory, actionsIndex, shardDirectory), bulkDirectory, entries[actionsIndex].Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitBulkByIndex groups the actions of the bulk body by their index. The returned indices keep the order in which
+// they were found, so the output does not depend on the iteration order of the map
+func splitBulkByIndex(body []byte, defaultIndex string) (map[string]*bytes.Buffer, []string, error) {
+	entries := make(map[string]*bytes.Buffer)
+	indices := make([]string, 0)
+
+	lines := bytes.Split(body, []byte("\n"))
+	for i := 0; i < len(lines); i++ {
+		actionLine := bytes.TrimSpace(lines[i])
+		if len(actionLine) == 0 {
+			continue
+		}
+
+		actions := make(map[string]bulkActionMetadata)
+		err := json.Unmarshal(actionLine, &actions)
+		if err != nil || len(actions) != 1 {
+			return nil, nil, fmt.Errorf("%w on line %d: %s", errInvalidBulkAction, i+1, actionLine)
+		}
+
+		entry := make([]byte, 0, len(actionLine)+1)
+		entry = append(entry, actionLine...)
+		entry = append(entry, '\n')
+
+		actionIndex := defaultIndex
+		for action, metadata := range actions {
+			if metadata.Index != "" {
+				actionIndex = metadata.Index
+			}
+			if action == deleteAction {
+				continue
+			}
+
+			i++
+			if i >= len(lines) {
+				return nil, nil, fmt.Errorf("%w: the %s action on line %d has no source", errInvalidBulkAction, action, i)
+			}
+			entry = append(entry, lines[i]...)
+			entry = append(entry, '\n')
+		}
+		if actionIndex == "" {
+			return nil, nil, fmt.Errorf("%w: %s", errMissingIndex, actionLine)
+		}
+
+		_, found := entries[actionIndex]
+		if !found {
+			entries[actionIndex] = &bytes.Buffer{}
+			indices = append(indices, actionIndex)
+		}
+		entries[actionIndex].Write(entry)
+	}
+
+	return entries, indices, nil
+}
+
+// DoQueryRemove will write the remove request in the remove files of the index
+func (fsc *fileSinkClient) DoQueryRemove(ctx context.Context, index string, buff *bytes.Buffer) error {
+	return fsc.writeQuery(ctx, removeDirectory, index, buff)
+}
+
+// UpdateByQuery will write the update-by-query request in the update-by-query files of the index
+func (fsc *fileSinkClient) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
+	return fsc.writeQuery(ctx, updateByQueryDirectory, index, buff)
+}
+
+func (fsc *fileSinkClient) writeQuery(ctx context.Context, kind string, index string, buff *bytes.Buffer) error {
+	body := &bytes.Buffer{}
+	err := json.Compact(body, buff.Bytes())
+	if err != nil {
+		return fmt.Errorf("%w while writing the %s request of the %s index", err, kind, index)
+	}
+
+	entry, err := json.Marshal(&queryEntry{
+		Index: index,
+		Body:  body.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+	entry = append(entry, '\n')
+
+	fsc.mut.Lock()
+	defer fsc.mut.Unlock()
+
+	return fsc.write(filepath.Join(kind, index, getShardDirectory(ctx)), kind, entry)
+}
+
+// write appends the entry in the current file of the directory. The caller should hold the mutex
+func (fsc *fileSinkClient) write(directory string, prefix string, entry []byte) error {
+	if fsc.closed {
+		return errSinkClosed
+	}
+
+	file, found := fsc.files[directory]
+	if !found {
+		file = newNDJSONFile(filepath.Join(fsc.directory, directory), prefix, fsc.maxFileSize)
+		fsc.files[directory] = file
+	}
+
+	return file.write(entry)
+}
+
+// getShardDirectory returns the directory of the shard that made the request, taken from the topic in the context
+func getShardDirectory(ctx context.Context) string {
+	valueFromCtx := ctx.Value(request.ContextKey)
+	if valueFromCtx == nil {
+		return noShardDirectory
+	}
+
+	_, shardID := request.SplitTopicAndShardID(fmt.Sprintf("%s", valueFromCtx))
+	_, err := strconv.ParseUint(shardID, 10, 32)
+	if err != nil {
+		return noShardDirectory
+	}
+
+	return shardDirectoryPrefix + shardID
+}
+
+// DoMultiGet will get the documents from the read-only cluster. Without it, res is left empty
+func (fsc *fileSinkClient) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error {
+	if fsc.readOnlyClient == nil {
+		return nil
+	}
+
+	return fsc.readOnlyClient.DoMultiGet(ctx, ids, index, withSource, res)
+}
+
+// DoScrollRequest will scroll the documents from the read-only cluster. Without it, the handler is not called
+func (fsc *fileSinkClient) DoScrollRequest(
+	ctx context.Context,
+	index string,
+	body []byte,
+	withSource bool,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	if fsc.readOnlyClient == nil {
+		return nil
+	}
+
+	return fsc.readOnlyClient.DoScrollRequest(ctx, index, body, withSource, handlerFunc)
+}
+
+// DoCountRequest will count the documents from the read-only cluster. Without it, 0 is returned
+func (fsc *fileSinkClient) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
+	if fsc.readOnlyClient == nil {
+		return 0, nil
+	}
+
+	return fsc.readOnlyClient.DoCountRequest(ctx, index, body)
+}
+
+// CheckAndCreateIndex does nothing, the indices are created when the files are loaded
+func (fsc *fileSinkClient) CheckAndCreateIndex(_ string) error {
+	return nil
+}
+
+// CheckAndCreateAlias does nothing, the documents are written under the alias names
+func (fsc *fileSinkClient) CheckAndCreateAlias(_ string, _ string) error {
+	return nil
+}
+
+// CheckAndCreateTemplate will write the template in the templates directory, so it can be created before the files
+// are loaded
+func (fsc *fileSinkClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	return fsc.writeSetupFile(templatesDirectory, templateName, template)
+}
+
+// CheckAndCreatePolicy will write the policy in the policies directory, so it can be created before the files are
+// loaded
+func (fsc *fileSinkClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	return fsc.writeSetupFile(policiesDirectory, policyName, policy)
+}
+
+func (fsc *fileSinkClient) writeSetupFile(kind string, name string, content *bytes.Buffer) error {
+	directory := filepath.Join(fsc.directory, kind)
+	err := os.MkdirAll(directory, directoryPermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(directory, name+templateFileExtension), content.Bytes(), filesPermissions)
+}
+
+// Close will close all the opened files and the read-only client. It is called by the elastic processor when the
+// indexer is closed, after the in-flight blocks are written
+func (fsc *fileSinkClient) Close() error {
+	fsc.mut.Lock()
+	defer fsc.mut.Unlock()
+
+	if fsc.closed {
+		return nil
+	}
+	fsc.closed = true
+
+	var lastErr error
+	for directory, file := range fsc.files {
+		err := file.close()
+		if err != nil {
+			log.Warn("fileSinkClient.Close: cannot close file", "directory", directory, "error", err)
+			lastErr = err
+		}
+	}
+
+	if fsc.readOnlyClient != nil {
+		err := fsc.readOnlyClient.Close()
+		if err != nil {
+			log.Warn("fileSinkClient.Close: cannot close the read-only client", "error", err)
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fsc *fileSinkClient) IsInterfaceNil() bool {
+	return fsc == nil
+}
@@ -0,0 +1,273 @@
+package filesink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsFileSinkClient(t *testing.T) ArgsFileSinkClient {
+	return ArgsFileSinkClient{
+		Directory:          t.TempDir(),
+		MaxFileSizeInBytes: 1024,
+	}
+}
+
+func contextWithShard(topic string, shardID uint32) context.Context {
+	return context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(topic, shardID))
+}
+
+func readFiles(t *testing.T, directory string) []string {
+	entries, err := os.ReadDir(directory)
+	require.Nil(t, err)
+
+	contents := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		require.True(t, strings.HasSuffix(entry.Name(), fileExtension))
+		content, errRead := os.ReadFile(filepath.Join(directory, entry.Name()))
+		require.Nil(t, errRead)
+		contents = append(contents, string(content))
+	}
+
+	return contents
+}
+
+func TestNewFileSinkClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty directory should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFileSinkClient(t)
+		args.Directory = ""
+		fsc, err := NewFileSinkClient(args)
+		require.Nil(t, fsc)
+		require.Equal(t, errEmptyDirectory, err)
+	})
+
+	t.Run("invalid file size should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFileSinkClient(t)
+		args.MaxFileSizeInBytes = 0
+		fsc, err := NewFileSinkClient(args)
+		require.Nil(t, fsc)
+		require.Equal(t, errInvalidFileSize, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFileSinkClient(t)
+		args.Directory = filepath.Join(args.Directory, "dry-run")
+		fsc, err := NewFileSinkClient(args)
+		require.Nil(t, err)
+		require.False(t, fsc.IsInterfaceNil())
+		require.DirExists(t, args.Directory)
+	})
+}
+
+func TestFileSinkClient_DoBulkRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should split the actions by index and shard", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFileSinkClient(t)
+		fsc, _ := NewFileSinkClient(args)
+
+		body := `{ "index" : { "_index": "transactions", "_id" : "tx1" } }
+{"nonce":1}
+{"delete":{"_index":"tokens","_id":"TKN-abcd"}}
+{"update":{ "_index":"transactions","_id":"tx2"}}
+{"doc":{"status":"success"}}
+`
+		err := fsc.DoBulkRequest(contextWithShard(request.BulkTopic, 1), bytes.NewBufferString(body), "")
+		require.Nil(t, err)
+
+		err = fsc.DoBulkRequest(contextWithShard(request.BulkTopic, 2), bytes.NewBufferString(`{"index":{"_id":"hash"}}`+"\n"+`{"round":2}`+"\n"), "blocks")
+		require.Nil(t, err)
+		require.Nil(t, fsc.Close())
+
+		require.Equal(t, []string{`{ "index" : { "_index": "transactions", "_id" : "tx1" } }
+{"nonce":1}
+{"update":{ "_index":"transactions","_id":"tx2"}}
+{"doc":{"status":"success"}}
+`}, readFiles(t, filepath.Join(args.Directory, bulkDirectory, "transactions", "shard-1")))
+		require.Equal(t, []string{`{"delete":{"_index":"tokens","_id":"TKN-abcd"}}` + "\n"},
+			readFiles(t, filepath.Join(args.Directory, bulkDirectory, "tokens", "shard-1")))
+		require.Equal(t, []string{`{"index":{"_id":"hash"}}` + "\n" + `{"round":2}` + "\n"},
+			readFiles(t, filepath.Join(args.Directory, bulkDirectory, "blocks", "shard-2")))
+	})
+
+	t.Run("should rotate the files without splitting the actions", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFileSinkClient(t)
+		args.MaxFileSizeInBytes = 60
+		fsc, _ := NewFileSinkClient(args)
+
+		action := `{"index":{"_index":"accounts","_id":"a"}}` + "\n" + `{"balance":"1"}` + "\n"
+		for i := 0; i < 3; i++ {
+			err := fsc.DoBulkRequest(context.Background(), bytes.NewBufferString(action), "")
+			require.Nil(t, err)
+		}
+		require.Nil(t, fsc.Close())
+
+		require.Equal(t, []string{action, action, action}, readFiles(t, filepath.Join(args.Directory, bulkDirectory, "accounts", noShardDirectory)))
+	})
+
+	t.Run("invalid body should error", func(t *testing.T) {
+		t.Parallel()
+
+		fsc, _ := NewFileSinkClient(createMockArgsFileSinkClient(t))
+
+		err := fsc.DoBulkRequest(context.Background(), bytes.NewBufferString("not json\n"), "blocks")
+		require.True(t, errors.Is(err, errInvalidBulkAction))
+
+		err = fsc.DoBulkRequest(context.Background(), bytes.NewBufferString(`{"index":{"_id":"hash"}}`), "blocks")
+		require.True(t, errors.Is(err, errInvalidBulkAction))
+
+		err = fsc.DoBulkRequest(context.Background(), bytes.NewBufferString(`{"delete":{"_id":"hash"}}`+"\n"), "")
+		require.True(t, errors.Is(err, errMissingIndex))
+	})
+
+	t.Run("closed sink should error", func(t *testing.T) {
+		t.Parallel()
+
+		fsc, _ := NewFileSinkClient(createMockArgsFileSinkClient(t))
+		require.Nil(t, fsc.Close())
+
+		err := fsc.DoBulkRequest(context.Background(), bytes.NewBufferString(`{"delete":{"_id":"hash"}}`+"\n"), "blocks")
+		require.Equal(t, errSinkClosed, err)
+	})
+}
+
+func TestFileSinkClient_DoQueryRemoveAndUpdateByQuery(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsFileSinkClient(t)
+	fsc, _ := NewFileSinkClient(args)
+
+	err := fsc.DoQueryRemove(contextWithShard(request.RemoveTopic, 0), "transactions", bytes.NewBufferString(`{"query": {"ids": {"values": ["tx1"]}}}`))
+	require.Nil(t, err)
+	err = fsc.UpdateByQuery(contextWithShard(request.UpdateTopic, 0), "tokens", bytes.NewBufferString(`{"script": {"source": "ctx._source.x = 1"}}`))
+	require.Nil(t, err)
+	err = fsc.UpdateByQuery(context.Background(), "tokens", bytes.NewBufferString("not json"))
+	require.NotNil(t, err)
+	require.Nil(t, fsc.Close())
+
+	require.Equal(t, []string{`{"index":"transactions","body":{"query":{"ids":{"values":["tx1"]}}}}` + "\n"},
+		readFiles(t, filepath.Join(args.Directory, removeDirectory, "transactions", "shard-0")))
+	require.Equal(t, []string{`{"index":"tokens","body":{"script":{"source":"ctx._source.x = 1"}}}` + "\n"},
+		readFiles(t, filepath.Join(args.Directory, updateByQueryDirectory, "tokens", "shard-0")))
+}
+
+func TestFileSinkClient_Reads(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without read-only client should return empty", func(t *testing.T) {
+		t.Parallel()
+
+		fsc, _ := NewFileSinkClient(createMockArgsFileSinkClient(t))
+
+		res := make(map[string]interface{})
+		err := fsc.DoMultiGet(context.Background(), []string{"id"}, "blocks", true, &res)
+		require.Nil(t, err)
+		require.Empty(t, res)
+
+		err = fsc.DoScrollRequest(context.Background(), "blocks", nil, false, func(_ []byte) error {
+			require.Fail(t, "should not be called")
+			return nil
+		})
+		require.Nil(t, err)
+
+		count, err := fsc.DoCountRequest(context.Background(), "blocks", nil)
+		require.Nil(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("with read-only client should forward the reads", func(t *testing.T) {
+		t.Parallel()
+
+		numReads := 0
+		args := createMockArgsFileSinkClient(t)
+		args.ReadOnlyClient = &mock.DatabaseWriterStub{
+			DoMultiGetCalled: func(_ []string, index string, _ bool, _ interface{}) error {
+				require.Equal(t, "blocks", index)
+				numReads++
+				return nil
+			},
+			DoScrollRequestCalled: func(index string, _ []byte, _ bool, _ func(responseBytes []byte) error) error {
+				numReads++
+				return nil
+			},
+			DoCountRequestCalled: func(_ context.Context, _ string, _ []byte) (uint64, error) {
+				numReads++
+				return 5, nil
+			},
+		}
+		fsc, _ := NewFileSinkClient(args)
+
+		err := fsc.DoMultiGet(context.Background(), []string{"id"}, "blocks", true, nil)
+		require.Nil(t, err)
+		err = fsc.DoScrollRequest(context.Background(), "blocks", nil, false, nil)
+		require.Nil(t, err)
+		count, err := fsc.DoCountRequest(context.Background(), "blocks", nil)
+		require.Nil(t, err)
+		require.Equal(t, uint64(5), count)
+		require.Equal(t, 3, numReads)
+	})
+}
+
+func TestFileSinkClient_CheckAndCreate(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsFileSinkClient(t)
+	fsc, _ := NewFileSinkClient(args)
+
+	require.Nil(t, fsc.CheckAndCreateIndex("blocks-000001"))
+	require.Nil(t, fsc.CheckAndCreateAlias("blocks", "blocks-000001"))
+	require.Nil(t, fsc.CheckAndCreateTemplate("blocks", bytes.NewBufferString(`{"index_patterns":["blocks-*"]}`)))
+	require.Nil(t, fsc.CheckAndCreatePolicy("blocks_policy", bytes.NewBufferString(`{"policy":{}}`)))
+
+	template, err := os.ReadFile(filepath.Join(args.Directory, templatesDirectory, "blocks.json"))
+	require.Nil(t, err)
+	require.Equal(t, `{"index_patterns":["blocks-*"]}`, string(template))
+	policy, err := os.ReadFile(filepath.Join(args.Directory, policiesDirectory, "blocks_policy.json"))
+	require.Nil(t, err)
+	require.Equal(t, `{"policy":{}}`, string(policy))
+}
+
+func TestFileSinkClient_Close(t *testing.T) {
+	t.Parallel()
+
+	numClosed := 0
+	args := createMockArgsFileSinkClient(t)
+	args.ReadOnlyClient = &mock.DatabaseWriterStub{
+		CloseCalled: func() error {
+			numClosed++
+			return nil
+		},
+	}
+	fsc, _ := NewFileSinkClient(args)
+
+	err := fsc.DoBulkRequest(context.Background(), bytes.NewBufferString(`{"delete":{"_id":"hash"}}`+"\n"), "blocks")
+	require.Nil(t, err)
+
+	require.Nil(t, fsc.Close())
+	require.Nil(t, fsc.files[filepath.Join(bulkDirectory, "blocks", noShardDirectory)].file)
+	require.Equal(t, 1, numClosed)
+
+	// the second call should not close the read-only client again
+	require.Nil(t, fsc.Close())
+	require.Equal(t, 1, numClosed)
+}
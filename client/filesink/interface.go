@@ -0,0 +1,14 @@
+package filesink
+
+import (
+	"context"
+)
+
+// ReadOnlyClientHandler defines the reads that the file sink client can forward to a cluster
+type ReadOnlyClientHandler interface {
+	DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error
+	DoScrollRequest(ctx context.Context, index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error)
+	Close() error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,85 @@
+package filesink
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	fileExtension        = ".ndjson"
+	fileTimestamp        = "20060102-150405.000000000"
+	filesPermissions     = 0644
+	directoryPermissions = 0755
+)
+
+// ndjsonFile appends the entries in the files of a directory and starts a new file once the maximum file size is
+// reached. An entry is never split between two files
+type ndjsonFile struct {
+	directory   string
+	prefix      string
+	maxFileSize int64
+	file        *os.File
+	fileSize    int64
+}
+
+func newNDJSONFile(directory string, prefix string, maxFileSize int64) *ndjsonFile {
+	return &ndjsonFile{
+		directory:   directory,
+		prefix:      prefix,
+		maxFileSize: maxFileSize,
+	}
+}
+
+func (nf *ndjsonFile) write(entry []byte) error {
+	shouldRotate := nf.file == nil || (nf.fileSize > 0 && nf.fileSize+int64(len(entry)) > nf.maxFileSize)
+	if shouldRotate {
+		err := nf.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := nf.file.Write(entry)
+	if err != nil {
+		return err
+	}
+	nf.fileSize += int64(len(entry))
+
+	return nil
+}
+
+func (nf *ndjsonFile) rotate() error {
+	err := nf.close()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(nf.directory, directoryPermissions)
+	if err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("%s-%s%s", nf.prefix, time.Now().UTC().Format(fileTimestamp), fileExtension)
+	file, err := os.OpenFile(filepath.Join(nf.directory, fileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filesPermissions)
+	if err != nil {
+		return err
+	}
+
+	nf.file = file
+	nf.fileSize = 0
+
+	return nil
+}
+
+func (nf *ndjsonFile) close() error {
+	if nf.file == nil {
+		return nil
+	}
+
+	err := nf.file.Close()
+	nf.file = nil
+
+	return err
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+const ismPoliciesPath = "/_plugins/_ism/policies/"
+
+// policyExists checks if an index state management policy was already created
+func (ec *elasticClient) policyExists(policyName string) bool {
+	req := newRequest(http.MethodGet, ismPoliciesPath+policyName, nil)
+	res, err := ec.client.Transport.Perform(req)
+	if err != nil {
+		return exists(nil, err)
+	}
+
+	return exists(&esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}, nil)
+}
+
+// createPolicy creates an index state management policy. Policies define the rollover of the indices
+func (ec *elasticClient) createPolicy(policyName string, policy *bytes.Buffer) error {
+	req := newRequest(http.MethodPut, ismPoliciesPath+policyName, policy)
+	req.Header[headerContentType] = headerContentTypeJSON
+	res, err := ec.client.Transport.Perform(req)
+	if err != nil {
+		return err
+	}
+
+	response := &esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+	defer closeBody(response)
+
+	switch {
+	case response.StatusCode == http.StatusConflict:
+		// created in the meantime by another indexer instance
+		return nil
+	case response.IsError():
+		return fmt.Errorf("%w %s: %s", dataindexer.ErrCouldNotCreatePolicy, policyName, response.String())
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,391 @@
+package pit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// DefaultPageSize is the number of documents fetched with every search request when no page size is provided
+	DefaultPageSize = 9000
+	// MaxPageSize is the highest page size accepted by Elasticsearch with its default max_result_window setting
+	MaxPageSize = 10000
+	// DefaultKeepAlive is how long the point in time is kept alive between two pages when no keep alive is provided
+	DefaultKeepAlive = 5 * time.Minute
+
+	// shardDocSort sorts the documents in the most efficient order, the one they are stored in the point in time
+	shardDocSort = `[{"_shard_doc":"asc"}]`
+)
+
+var log = logger.GetOrCreate("client/pit")
+
+var (
+	errNilClient          = errors.New("nil elasticsearch client")
+	errEmptyIndex         = errors.New("empty index")
+	errInvalidPageSize    = errors.New("invalid page size")
+	errInvalidKeepAlive   = errors.New("invalid keep alive")
+	errInvalidTimeout     = errors.New("invalid request timeout")
+	errInvalidQuery       = errors.New("the query is not a JSON object")
+	errInvalidSort        = errors.New("the sort is not a JSON array")
+	errInvalidResumeToken = errors.New("invalid resume token")
+)
+
+// ArgsIterator holds the arguments needed to create a new point in time iterator
+type ArgsIterator struct {
+	// Client sends the requests. Both the v7 and the v8 Elasticsearch clients can be used, as the requests are built
+	// with the v7 API and sent through the transport of the client
+	Client esapi.Transport
+	Index  string
+	// Query is the body of the search request, e.g. {"query":{"match_all":{}}}. The pit, sort, size and search_after
+	// fields are set by the iterator
+	Query      []byte
+	WithSource bool
+	// PageSize is the number of documents fetched with every search request. 0 means DefaultPageSize
+	PageSize int
+	// Sort is the JSON array the documents are sorted by. If empty, the documents are returned in the order they are
+	// stored, which is the fastest one
+	Sort []byte
+	// KeepAlive is how long the point in time is kept alive between two pages. 0 means DefaultKeepAlive
+	KeepAlive time.Duration
+	// RequestTimeout bounds every request that fetches a page, including the one that opens the point in time. 0 means
+	// the requests are bounded only by the context of the iteration
+	RequestTimeout time.Duration
+	// ResumeToken continues an iteration from where the iteration that returned the token stopped. The token is valid
+	// as long as the point in time of that iteration is alive
+	ResumeToken string
+}
+
+type resumeToken struct {
+	PitID       string          `json:"pit_id"`
+	SearchAfter json.RawMessage `json:"search_after"`
+}
+
+type iterator struct {
+	client      esapi.Transport
+	index       string
+	query       map[string]json.RawMessage
+	withSource  bool
+	pageSize    int
+	sort        json.RawMessage
+	keepAlive   string
+	timeout     time.Duration
+	pitID       string
+	searchAfter json.RawMessage
+	done        bool
+}
+
+// NewIterator will create a new instance of iterator that pages through the documents matching the query with a point
+// in time and search_after. The point in time is opened with the first page and has to be released with Close
+func NewIterator(args ArgsIterator) (*iterator, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(args.Query)) > 0 {
+		err = json.Unmarshal(args.Query, &query)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidQuery, err.Error())
+		}
+	}
+
+	it := &iterator{
+		client:     args.Client,
+		index:      args.Index,
+		query:      query,
+		withSource: args.WithSource,
+		pageSize:   args.PageSize,
+		sort:       json.RawMessage(shardDocSort),
+		keepAlive:  formatKeepAlive(args.KeepAlive),
+		timeout:    args.RequestTimeout,
+	}
+	if it.pageSize == 0 {
+		it.pageSize = DefaultPageSize
+	}
+	if len(args.Sort) > 0 {
+		it.sort = args.Sort
+	}
+
+	err = it.applyResumeToken(args.ResumeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return it, nil
+}
+
+func checkArgs(args ArgsIterator) error {
+	if args.Client == nil {
+		return errNilClient
+	}
+	if args.Index == "" {
+		return errEmptyIndex
+	}
+	if args.PageSize < 0 || args.PageSize > MaxPageSize {
+		return fmt.Errorf("%w: %d, it should be at most %d", errInvalidPageSize, args.PageSize, MaxPageSize)
+	}
+	if args.KeepAlive < 0 {
+		return errInvalidKeepAlive
+	}
+	if args.RequestTimeout < 0 {
+		return errInvalidTimeout
+	}
+	hasSort := len(args.Sort) > 0
+	if hasSort && (!gjson.ValidBytes(args.Sort) || !gjson.ParseBytes(args.Sort).IsArray()) {
+		return errInvalidSort
+	}
+
+	return nil
+}
+
+func (it *iterator) applyResumeToken(token string) error {
+	if token == "" {
+		return nil
+	}
+
+	tokenBytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidResumeToken, err.Error())
+	}
+
+	rt := &resumeToken{}
+	err = json.Unmarshal(tokenBytes, rt)
+	if err != nil || rt.PitID == "" {
+		return errInvalidResumeToken
+	}
+
+	it.pitID = rt.PitID
+	it.searchAfter = rt.SearchAfter
+
+	return nil
+}
+
+// Next returns the search response holding the next page of documents. A nil response means there are no more documents
+func (it *iterator) Next(ctx context.Context) ([]byte, error) {
+	if it.done {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	requestCtx, cancel := it.withRequestTimeout(ctx)
+	defer cancel()
+
+	if it.pitID == "" {
+		err := it.openPointInTime(requestCtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	responseBytes, err := it.search(requestCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := gjson.ParseBytes(responseBytes)
+	newPitID := response.Get("pit_id").String()
+	if newPitID != "" {
+		it.pitID = newPitID
+	}
+
+	numHits := response.Get("hits.hits.#").Int()
+	if numHits == 0 {
+		it.done = true
+		return nil, nil
+	}
+	if numHits < int64(it.pageSize) {
+		it.done = true
+	}
+	it.searchAfter = json.RawMessage(response.Get("hits.hits.@reverse.0.sort").Raw)
+
+	return responseBytes, nil
+}
+
+func (it *iterator) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if it.timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, it.timeout)
+}
+
+// ResumeToken returns the token an iteration can be resumed with, from the page after the last returned one
+func (it *iterator) ResumeToken() string {
+	if it.pitID == "" {
+		return ""
+	}
+
+	tokenBytes, err := json.Marshal(&resumeToken{
+		PitID:       it.pitID,
+		SearchAfter: it.searchAfter,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(tokenBytes)
+}
+
+// Close releases the point in time, if one was opened
+func (it *iterator) Close(ctx context.Context) error {
+	if it.pitID == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"id": it.pitID})
+	if err != nil {
+		return err
+	}
+
+	res, err := esapi.ClosePointInTimeRequest{
+		Body: bytes.NewBuffer(body),
+	}.Do(ctx, it.client)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("cannot close the point in time: %s", res)
+	}
+
+	it.pitID = ""
+	return nil
+}
+
+func (it *iterator) openPointInTime(ctx context.Context) error {
+	res, err := esapi.OpenPointInTimeRequest{
+		Index:     []string{it.index},
+		KeepAlive: it.keepAlive,
+	}.Do(ctx, it.client)
+	if err != nil {
+		return err
+	}
+
+	responseBytes, err := readResponse(res)
+	if err != nil {
+		return fmt.Errorf("cannot open a point in time on %s: %w", it.index, err)
+	}
+
+	it.pitID = gjson.GetBytes(responseBytes, "id").String()
+	if it.pitID == "" {
+		return fmt.Errorf("cannot open a point in time on %s: empty id", it.index)
+	}
+
+	return nil
+}
+
+func (it *iterator) search(ctx context.Context) ([]byte, error) {
+	body, err := it.createSearchBody()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := esapi.SearchRequest{
+		Body:   bytes.NewBuffer(body),
+		Source: []string{strconv.FormatBool(it.withSource)},
+	}.Do(ctx, it.client)
+	if err != nil {
+		return nil, err
+	}
+
+	return readResponse(res)
+}
+
+func (it *iterator) createSearchBody() ([]byte, error) {
+	pit, err := json.Marshal(map[string]string{
+		"id":         it.pitID,
+		"keep_alive": it.keepAlive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body := make(map[string]json.RawMessage, len(it.query)+5)
+	for key, value := range it.query {
+		body[key] = value
+	}
+	body["pit"] = pit
+	body["sort"] = it.sort
+	body["size"] = json.RawMessage(strconv.Itoa(it.pageSize))
+	body["track_total_hits"] = json.RawMessage("false")
+	if len(it.searchAfter) > 0 {
+		body["search_after"] = it.searchAfter
+	}
+
+	return json.Marshal(body)
+}
+
+// Iterate passes every page of documents matching the query to the handler and releases the point in time at the end
+func Iterate(ctx context.Context, args ArgsIterator, handlerFunc func(responseBytes []byte) error) error {
+	it, err := NewIterator(args)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the point in time is released even if the context of the iteration was cancelled
+		errClose := it.Close(context.Background())
+		if errClose != nil {
+			log.Warn("pit.Iterate: cannot close the point in time", "index", args.Index, "error", errClose)
+		}
+	}()
+
+	for {
+		responseBytes, errNext := it.Next(ctx)
+		if errNext != nil {
+			return errNext
+		}
+		if responseBytes == nil {
+			return nil
+		}
+
+		err = handlerFunc(responseBytes)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func formatKeepAlive(keepAlive time.Duration) string {
+	if keepAlive == 0 {
+		keepAlive = DefaultKeepAlive
+	}
+
+	seconds := int64(keepAlive / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return strconv.FormatInt(seconds, 10) + "s"
+}
+
+func readResponse(res *esapi.Response) ([]byte, error) {
+	defer closeBody(res)
+
+	if res.IsError() {
+		return nil, fmt.Errorf("error response: %s", res)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+func closeBody(res *esapi.Response) {
+	if res != nil && res.Body != nil {
+		_ = res.Body.Close()
+	}
+}
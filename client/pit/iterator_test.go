@@ -0,0 +1,240 @@
+package pit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type clusterStub struct {
+	mut          sync.Mutex
+	docs         []string
+	searchBodies []string
+	numOpened    int
+	closedPitIDs []string
+}
+
+// ServeHTTP serves the documents sorted by their position, with the position as sort value
+func (cs *clusterStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cs.mut.Lock()
+	defer cs.mut.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/_pit"):
+		cs.numOpened++
+		_, _ = w.Write([]byte(`{"id":"pit-1"}`))
+	case r.Method == http.MethodDelete && r.URL.Path == "/_pit":
+		cs.closedPitIDs = append(cs.closedPitIDs, gjson.GetBytes(body, "id").String())
+		_, _ = w.Write([]byte(`{"succeeded":true}`))
+	case r.URL.Path == "/_search":
+		cs.searchBodies = append(cs.searchBodies, string(body))
+		from := int(gjson.GetBytes(body, "search_after.0").Int())
+		if !gjson.GetBytes(body, "search_after").Exists() {
+			from = -1
+		}
+		size := int(gjson.GetBytes(body, "size").Int())
+
+		hits := make([]string, 0)
+		for idx := from + 1; idx < len(cs.docs) && len(hits) < size; idx++ {
+			hits = append(hits, fmt.Sprintf(`{"_id":"%s","sort":[%d]}`, cs.docs[idx], idx))
+		}
+		_, _ = w.Write([]byte(`{"pit_id":"pit-2","hits":{"hits":[` + strings.Join(hits, ",") + `]}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func createClient(t *testing.T, cs *clusterStub) *elasticsearch.Client {
+	ts := httptest.NewServer(cs)
+	t.Cleanup(ts.Close)
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+	})
+	require.Nil(t, err)
+
+	return client
+}
+
+func getIDs(responseBytes []byte) []string {
+	ids := make([]string, 0)
+	for _, id := range gjson.GetBytes(responseBytes, "hits.hits.#._id").Array() {
+		ids = append(ids, id.String())
+	}
+
+	return ids
+}
+
+func TestNewIterator(t *testing.T) {
+	t.Parallel()
+
+	client, _ := elasticsearch.NewClient(elasticsearch.Config{})
+	createArgs := func() ArgsIterator {
+		return ArgsIterator{
+			Client: client,
+			Index:  "tokens",
+		}
+	}
+
+	args := createArgs()
+	args.Client = nil
+	_, err := NewIterator(args)
+	require.Equal(t, errNilClient, err)
+
+	args = createArgs()
+	args.Index = ""
+	_, err = NewIterator(args)
+	require.Equal(t, errEmptyIndex, err)
+
+	args = createArgs()
+	args.PageSize = MaxPageSize + 1
+	_, err = NewIterator(args)
+	require.ErrorIs(t, err, errInvalidPageSize)
+
+	args = createArgs()
+	args.Sort = []byte(`{"timestamp":"asc"}`)
+	_, err = NewIterator(args)
+	require.Equal(t, errInvalidSort, err)
+
+	args = createArgs()
+	args.Query = []byte(`[1]`)
+	_, err = NewIterator(args)
+	require.ErrorIs(t, err, errInvalidQuery)
+
+	args = createArgs()
+	args.RequestTimeout = -time.Second
+	_, err = NewIterator(args)
+	require.Equal(t, errInvalidTimeout, err)
+
+	args = createArgs()
+	args.ResumeToken = "not a token"
+	_, err = NewIterator(args)
+	require.ErrorIs(t, err, errInvalidResumeToken)
+
+	it, err := NewIterator(createArgs())
+	require.Nil(t, err)
+	require.Equal(t, DefaultPageSize, it.pageSize)
+	require.Equal(t, "300s", it.keepAlive)
+}
+
+func TestIterate(t *testing.T) {
+	t.Parallel()
+
+	cs := &clusterStub{docs: []string{"a", "b", "c", "d", "e"}}
+	pages := make([][]string, 0)
+	err := Iterate(context.Background(), ArgsIterator{
+		Client:   createClient(t, cs),
+		Index:    "tokens",
+		Query:    []byte(`{"query":{"match_all":{}}}`),
+		PageSize: 2,
+	}, func(responseBytes []byte) error {
+		pages = append(pages, getIDs(responseBytes))
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, pages)
+
+	require.Equal(t, 1, cs.numOpened)
+	require.Equal(t, []string{"pit-2"}, cs.closedPitIDs)
+	require.Len(t, cs.searchBodies, 3)
+
+	firstSearch := gjson.Parse(cs.searchBodies[0])
+	require.Equal(t, "pit-1", firstSearch.Get("pit.id").String())
+	require.Equal(t, `{"match_all":{}}`, firstSearch.Get("query").Raw)
+	require.Equal(t, shardDocSort, firstSearch.Get("sort").Raw)
+	require.False(t, firstSearch.Get("search_after").Exists())
+
+	secondSearch := gjson.Parse(cs.searchBodies[1])
+	require.Equal(t, "pit-2", secondSearch.Get("pit.id").String())
+	require.Equal(t, "[1]", secondSearch.Get("search_after").Raw)
+}
+
+func TestIterate_HandlerErrorShouldStopAndClose(t *testing.T) {
+	t.Parallel()
+
+	cs := &clusterStub{docs: []string{"a", "b", "c"}}
+	expectedErr := fmt.Errorf("handler error")
+	err := Iterate(context.Background(), ArgsIterator{
+		Client:   createClient(t, cs),
+		Index:    "tokens",
+		PageSize: 1,
+	}, func(responseBytes []byte) error {
+		return expectedErr
+	})
+	require.Equal(t, expectedErr, err)
+	require.Len(t, cs.searchBodies, 1)
+	require.Equal(t, []string{"pit-2"}, cs.closedPitIDs)
+}
+
+func TestIterate_CancelledContext(t *testing.T) {
+	t.Parallel()
+
+	cs := &clusterStub{docs: []string{"a", "b", "c"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Iterate(ctx, ArgsIterator{
+		Client:   createClient(t, cs),
+		Index:    "tokens",
+		PageSize: 1,
+	}, func(responseBytes []byte) error {
+		cancel()
+		return nil
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Len(t, cs.searchBodies, 1)
+	require.Equal(t, []string{"pit-2"}, cs.closedPitIDs)
+}
+
+func TestIterator_ResumeToken(t *testing.T) {
+	t.Parallel()
+
+	cs := &clusterStub{docs: []string{"a", "b", "c", "d"}}
+	client := createClient(t, cs)
+
+	it, _ := NewIterator(ArgsIterator{
+		Client:   client,
+		Index:    "tokens",
+		PageSize: 2,
+	})
+	require.Equal(t, "", it.ResumeToken())
+
+	responseBytes, err := it.Next(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "b"}, getIDs(responseBytes))
+	token := it.ResumeToken()
+	require.NotEmpty(t, token)
+
+	resumed, err := NewIterator(ArgsIterator{
+		Client:      client,
+		Index:       "tokens",
+		PageSize:    2,
+		ResumeToken: token,
+	})
+	require.Nil(t, err)
+
+	responseBytes, err = resumed.Next(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []string{"c", "d"}, getIDs(responseBytes))
+	// the resumed iteration reuses the point in time of the first one
+	require.Equal(t, 1, cs.numOpened)
+
+	responseBytes, err = resumed.Next(context.Background())
+	require.Nil(t, err)
+	require.Nil(t, responseBytes)
+
+	err = resumed.Close(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []string{"pit-2"}, cs.closedPitIDs)
+}
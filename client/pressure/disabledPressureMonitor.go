@@ -0,0 +1,43 @@
+package pressure
+
+import "time"
+
+type disabledPressureMonitor struct {
+	bulkRequestMaxSize int
+}
+
+// NewDisabledPressureMonitor will create a pressure monitor that does not track anything, always reports a normal
+// pressure and keeps the provided bulk request max size
+func NewDisabledPressureMonitor(bulkRequestMaxSize int) *disabledPressureMonitor {
+	return &disabledPressureMonitor{
+		bulkRequestMaxSize: bulkRequestMaxSize,
+	}
+}
+
+// RecordResponse does nothing
+func (dpm *disabledPressureMonitor) RecordResponse(_ time.Duration, _ int, _ error) {
+}
+
+// RecordRejection does nothing
+func (dpm *disabledPressureMonitor) RecordRejection() {
+}
+
+// GetLevel returns LevelNormal
+func (dpm *disabledPressureMonitor) GetLevel() Level {
+	return LevelNormal
+}
+
+// GetAckDelay returns 0
+func (dpm *disabledPressureMonitor) GetAckDelay() time.Duration {
+	return 0
+}
+
+// GetBulkRequestMaxSize returns the configured bulk request max size
+func (dpm *disabledPressureMonitor) GetBulkRequestMaxSize() int {
+	return dpm.bulkRequestMaxSize
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dpm *disabledPressureMonitor) IsInterfaceNil() bool {
+	return dpm == nil
+}
@@ -0,0 +1,327 @@
+package pressure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+// Level is the pressure level of the Elasticsearch cluster
+type Level uint32
+
+const (
+	// LevelNormal signals that the cluster keeps up with the indexing requests
+	LevelNormal Level = iota
+	// LevelElevated signals that the cluster started to reject requests or to answer slowly
+	LevelElevated
+	// LevelHigh signals that the cluster rejects or times out a significant part of the requests
+	LevelHigh
+)
+
+const (
+	// LevelMetricTopic is the identifier for the pressure level metric
+	LevelMetricTopic = "es_pressure_level"
+	// LatencyP50MetricTopic is the identifier for the 50th percentile of the requests latency metric, in milliseconds
+	LatencyP50MetricTopic = "es_latency_p50_ms"
+	// LatencyP95MetricTopic is the identifier for the 95th percentile of the requests latency metric, in milliseconds
+	LatencyP95MetricTopic = "es_latency_p95_ms"
+	// LatencyP99MetricTopic is the identifier for the 99th percentile of the requests latency metric, in milliseconds
+	LatencyP99MetricTopic = "es_latency_p99_ms"
+	// RejectionsMetricTopic is the identifier for the number of rejected or timed out requests in the window metric
+	RejectionsMetricTopic = "es_rejections_in_window"
+	// AckDelayMetricTopic is the identifier for the acknowledgement delay metric, in milliseconds
+	AckDelayMetricTopic = "ack_delay_ms"
+	// BulkRequestMaxSizeMetricTopic is the identifier for the current bulk request max size metric, in bytes
+	BulkRequestMaxSizeMetricTopic = "bulk_request_max_size_in_bytes"
+
+	// highRejectionsPercentage is the percentage of rejected requests in the window that raises the level to high
+	highRejectionsPercentage = 10
+
+	defaultWindowSize            = 100
+	defaultHighLatencyThreshold  = 2 * time.Second
+	defaultMinBulkRequestMaxSize = 524288 // 512KB
+)
+
+var log = logger.GetOrCreate("client/pressure")
+
+var (
+	errInvalidWindowSize       = errors.New("invalid window size")
+	errInvalidLatencyThreshold = errors.New("invalid latency threshold")
+	errInvalidBulkRequestSize  = errors.New("invalid bulk request size")
+	errInvalidAckDelay         = errors.New("invalid ack delay")
+)
+
+// ArgsPressureMonitor holds all the components needed to create a new instance of pressureMonitor
+type ArgsPressureMonitor struct {
+	StatusMetrics core.StatusMetricsHandler
+	// WindowSize is the number of recent requests the pressure is computed on. 0 means defaultWindowSize
+	WindowSize int
+	// HighLatencyThreshold is the 95th percentile of the latency above which the pressure is elevated. Twice this
+	// value makes the pressure high. 0 means defaultHighLatencyThreshold
+	HighLatencyThreshold time.Duration
+	// AdaptIngestion enables the acknowledgement delay and the bulk requests shrinking while the pressure is not normal
+	AdaptIngestion bool
+	// MaxAckDelay is the acknowledgement delay applied when the pressure is high. Half of it is applied when the
+	// pressure is elevated
+	MaxAckDelay time.Duration
+	// BulkRequestMaxSize is the configured bulk request max size, used while the pressure is normal. It is halved
+	// on every level above normal, without going below MinBulkRequestMaxSize. 0 means defaultMinBulkRequestMaxSize,
+	// capped to BulkRequestMaxSize
+	BulkRequestMaxSize    int
+	MinBulkRequestMaxSize int
+}
+
+type pressureMonitor struct {
+	mut                   sync.RWMutex
+	statusMetrics         core.StatusMetricsHandler
+	highLatencyThreshold  time.Duration
+	adaptIngestion        bool
+	maxAckDelay           time.Duration
+	bulkRequestMaxSize    int
+	minBulkRequestMaxSize int
+
+	latencies    []time.Duration
+	latencyIdx   int
+	outcomes     []bool
+	outcomeIdx   int
+	numOutcomes  int
+	numRejected  int
+	currentLevel Level
+}
+
+// NewPressureMonitor will create a new instance of pressureMonitor. It tracks the latency, the rejected and the
+// timed out requests of the last WindowSize requests sent to the Elasticsearch cluster and computes the pressure level
+func NewPressureMonitor(args ArgsPressureMonitor) (*pressureMonitor, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	windowSize := args.WindowSize
+	if windowSize == 0 {
+		windowSize = defaultWindowSize
+	}
+	highLatencyThreshold := args.HighLatencyThreshold
+	if highLatencyThreshold == 0 {
+		highLatencyThreshold = defaultHighLatencyThreshold
+	}
+	minBulkRequestMaxSize := args.MinBulkRequestMaxSize
+	if minBulkRequestMaxSize == 0 {
+		minBulkRequestMaxSize = defaultMinBulkRequestMaxSize
+	}
+	if minBulkRequestMaxSize > args.BulkRequestMaxSize {
+		minBulkRequestMaxSize = args.BulkRequestMaxSize
+	}
+
+	pm := &pressureMonitor{
+		statusMetrics:         args.StatusMetrics,
+		highLatencyThreshold:  highLatencyThreshold,
+		adaptIngestion:        args.AdaptIngestion,
+		maxAckDelay:           args.MaxAckDelay,
+		bulkRequestMaxSize:    args.BulkRequestMaxSize,
+		minBulkRequestMaxSize: minBulkRequestMaxSize,
+		latencies:             make([]time.Duration, 0, windowSize),
+		outcomes:              make([]bool, windowSize),
+	}
+	pm.setMetrics(latencyPercentiles{})
+
+	return pm, nil
+}
+
+func checkArgs(args ArgsPressureMonitor) error {
+	if check.IfNil(args.StatusMetrics) {
+		return core.ErrNilMetricsHandler
+	}
+	if args.WindowSize < 0 {
+		return errInvalidWindowSize
+	}
+	if args.HighLatencyThreshold < 0 {
+		return errInvalidLatencyThreshold
+	}
+	if args.BulkRequestMaxSize <= 0 || args.MinBulkRequestMaxSize < 0 {
+		return errInvalidBulkRequestSize
+	}
+	if args.MaxAckDelay < 0 {
+		return errInvalidAckDelay
+	}
+
+	return nil
+}
+
+// RecordResponse will record the outcome of a request sent to the Elasticsearch cluster. A request answered with
+// 429 Too Many Requests or one that timed out counts as rejected
+func (pm *pressureMonitor) RecordResponse(duration time.Duration, statusCode int, err error) {
+	isRejected := statusCode == http.StatusTooManyRequests || isTimeout(err)
+
+	pm.mut.Lock()
+	defer pm.mut.Unlock()
+
+	if len(pm.latencies) < cap(pm.latencies) {
+		pm.latencies = append(pm.latencies, duration)
+	} else {
+		pm.latencies[pm.latencyIdx] = duration
+	}
+	pm.latencyIdx = (pm.latencyIdx + 1) % cap(pm.latencies)
+
+	pm.addOutcomeUnprotected(isRejected)
+}
+
+// RecordRejection will record a request that reached the cluster, but whose items were rejected by a full queue
+func (pm *pressureMonitor) RecordRejection() {
+	pm.mut.Lock()
+	defer pm.mut.Unlock()
+
+	pm.addOutcomeUnprotected(true)
+}
+
+func (pm *pressureMonitor) addOutcomeUnprotected(isRejected bool) {
+	if pm.numOutcomes == len(pm.outcomes) && pm.outcomes[pm.outcomeIdx] {
+		pm.numRejected--
+	}
+	if pm.numOutcomes < len(pm.outcomes) {
+		pm.numOutcomes++
+	}
+	if isRejected {
+		pm.numRejected++
+	}
+
+	pm.outcomes[pm.outcomeIdx] = isRejected
+	pm.outcomeIdx = (pm.outcomeIdx + 1) % len(pm.outcomes)
+
+	percentiles := computeLatencyPercentiles(pm.latencies)
+	newLevel := pm.computeLevelUnprotected(percentiles.p95)
+	if newLevel != pm.currentLevel {
+		log.Debug("Elasticsearch pressure level changed", "old level", pm.currentLevel, "new level", newLevel,
+			"rejected requests", pm.numRejected, "p95 latency", percentiles.p95)
+	}
+	pm.currentLevel = newLevel
+
+	pm.setMetrics(percentiles)
+}
+
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (pm *pressureMonitor) computeLevelUnprotected(p95 time.Duration) Level {
+	rejectionsPercentage := pm.numRejected * 100 / pm.numOutcomes
+	if rejectionsPercentage >= highRejectionsPercentage || p95 >= 2*pm.highLatencyThreshold {
+		return LevelHigh
+	}
+	if pm.numRejected > 0 || p95 >= pm.highLatencyThreshold {
+		return LevelElevated
+	}
+
+	return LevelNormal
+}
+
+// GetLevel returns the current pressure level
+func (pm *pressureMonitor) GetLevel() Level {
+	pm.mut.RLock()
+	defer pm.mut.RUnlock()
+
+	return pm.currentLevel
+}
+
+// GetAckDelay returns the duration the acknowledgement of a processed payload should be delayed with, so the
+// sources slow down while the cluster is under pressure
+func (pm *pressureMonitor) GetAckDelay() time.Duration {
+	pm.mut.RLock()
+	defer pm.mut.RUnlock()
+
+	return pm.getAckDelayUnprotected()
+}
+
+func (pm *pressureMonitor) getAckDelayUnprotected() time.Duration {
+	if !pm.adaptIngestion {
+		return 0
+	}
+
+	return pm.maxAckDelay * time.Duration(pm.currentLevel) / time.Duration(LevelHigh)
+}
+
+// GetBulkRequestMaxSize returns the max size of the bulk requests, which shrinks while the cluster is under pressure
+func (pm *pressureMonitor) GetBulkRequestMaxSize() int {
+	pm.mut.RLock()
+	defer pm.mut.RUnlock()
+
+	return pm.getBulkRequestMaxSizeUnprotected()
+}
+
+func (pm *pressureMonitor) getBulkRequestMaxSizeUnprotected() int {
+	if !pm.adaptIngestion {
+		return pm.bulkRequestMaxSize
+	}
+
+	bulkRequestMaxSize := pm.bulkRequestMaxSize >> pm.currentLevel
+	if bulkRequestMaxSize < pm.minBulkRequestMaxSize {
+		return pm.minBulkRequestMaxSize
+	}
+
+	return bulkRequestMaxSize
+}
+
+func (pm *pressureMonitor) setMetrics(percentiles latencyPercentiles) {
+	pm.statusMetrics.SetGauge(LevelMetricTopic, uint64(pm.currentLevel))
+	pm.statusMetrics.SetGauge(LatencyP50MetricTopic, uint64(percentiles.p50.Milliseconds()))
+	pm.statusMetrics.SetGauge(LatencyP95MetricTopic, uint64(percentiles.p95.Milliseconds()))
+	pm.statusMetrics.SetGauge(LatencyP99MetricTopic, uint64(percentiles.p99.Milliseconds()))
+	pm.statusMetrics.SetGauge(RejectionsMetricTopic, uint64(pm.numRejected))
+	pm.statusMetrics.SetGauge(AckDelayMetricTopic, uint64(pm.getAckDelayUnprotected().Milliseconds()))
+	pm.statusMetrics.SetGauge(BulkRequestMaxSizeMetricTopic, uint64(pm.getBulkRequestMaxSizeUnprotected()))
+}
+
+type latencyPercentiles struct {
+	p50 time.Duration
+	p95 time.Duration
+	p99 time.Duration
+}
+
+func computeLatencyPercentiles(latencies []time.Duration) latencyPercentiles {
+	if len(latencies) == 0 {
+		return latencyPercentiles{}
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return latencyPercentiles{
+		p50: percentile(sorted, 50),
+		p95: percentile(sorted, 95),
+		p99: percentile(sorted, 99),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the provided sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pm *pressureMonitor) IsInterfaceNil() bool {
+	return pm == nil
+}
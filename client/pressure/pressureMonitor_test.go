@@ -0,0 +1,155 @@
+package pressure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsPressureMonitor() ArgsPressureMonitor {
+	return ArgsPressureMonitor{
+		StatusMetrics:         metrics.NewStatusMetrics(),
+		WindowSize:            20,
+		HighLatencyThreshold:  time.Second,
+		AdaptIngestion:        true,
+		MaxAckDelay:           4 * time.Second,
+		BulkRequestMaxSize:    4000,
+		MinBulkRequestMaxSize: 1500,
+	}
+}
+
+func TestNewPressureMonitor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil status metrics should error", func(t *testing.T) {
+		args := createMockArgsPressureMonitor()
+		args.StatusMetrics = nil
+		pm, err := NewPressureMonitor(args)
+		require.Nil(t, pm)
+		require.Equal(t, core.ErrNilMetricsHandler, err)
+	})
+	t.Run("invalid window size should error", func(t *testing.T) {
+		args := createMockArgsPressureMonitor()
+		args.WindowSize = -1
+		pm, err := NewPressureMonitor(args)
+		require.Nil(t, pm)
+		require.Equal(t, errInvalidWindowSize, err)
+	})
+	t.Run("invalid latency threshold should error", func(t *testing.T) {
+		args := createMockArgsPressureMonitor()
+		args.HighLatencyThreshold = -1
+		pm, err := NewPressureMonitor(args)
+		require.Nil(t, pm)
+		require.Equal(t, errInvalidLatencyThreshold, err)
+	})
+	t.Run("invalid bulk request size should error", func(t *testing.T) {
+		args := createMockArgsPressureMonitor()
+		args.BulkRequestMaxSize = 0
+		pm, err := NewPressureMonitor(args)
+		require.Nil(t, pm)
+		require.Equal(t, errInvalidBulkRequestSize, err)
+	})
+	t.Run("invalid ack delay should error", func(t *testing.T) {
+		args := createMockArgsPressureMonitor()
+		args.MaxAckDelay = -1
+		pm, err := NewPressureMonitor(args)
+		require.Nil(t, pm)
+		require.Equal(t, errInvalidAckDelay, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		statusMetrics := metrics.NewStatusMetrics()
+		args := createMockArgsPressureMonitor()
+		args.StatusMetrics = statusMetrics
+		pm, err := NewPressureMonitor(args)
+		require.Nil(t, err)
+		require.False(t, pm.IsInterfaceNil())
+		require.Equal(t, LevelNormal, pm.GetLevel())
+		require.Contains(t, statusMetrics.GetMetricsForPrometheus(), LevelMetricTopic)
+	})
+}
+
+func TestPressureMonitor_Rejections(t *testing.T) {
+	t.Parallel()
+
+	pm, _ := NewPressureMonitor(createMockArgsPressureMonitor())
+	for i := 0; i < 19; i++ {
+		pm.RecordResponse(time.Millisecond, http.StatusOK, nil)
+	}
+	require.Equal(t, LevelNormal, pm.GetLevel())
+	require.Equal(t, time.Duration(0), pm.GetAckDelay())
+	require.Equal(t, 4000, pm.GetBulkRequestMaxSize())
+
+	pm.RecordRejection()
+	require.Equal(t, LevelElevated, pm.GetLevel())
+	require.Equal(t, 2*time.Second, pm.GetAckDelay())
+	require.Equal(t, 2000, pm.GetBulkRequestMaxSize())
+
+	pm.RecordResponse(time.Millisecond, http.StatusTooManyRequests, nil)
+	require.Equal(t, LevelHigh, pm.GetLevel())
+	require.Equal(t, 4*time.Second, pm.GetAckDelay())
+	require.Equal(t, 1500, pm.GetBulkRequestMaxSize())
+
+	// the rejections slide out of the window
+	for i := 0; i < 20; i++ {
+		pm.RecordResponse(time.Millisecond, http.StatusOK, nil)
+	}
+	require.Equal(t, LevelNormal, pm.GetLevel())
+	require.Equal(t, 4000, pm.GetBulkRequestMaxSize())
+}
+
+func TestPressureMonitor_TimeoutsShouldCountAsRejections(t *testing.T) {
+	t.Parallel()
+
+	pm, _ := NewPressureMonitor(createMockArgsPressureMonitor())
+	pm.RecordResponse(time.Millisecond, 0, errors.New("connection refused"))
+	require.Equal(t, LevelNormal, pm.GetLevel())
+
+	pm.RecordResponse(time.Millisecond, 0, context.DeadlineExceeded)
+	require.Equal(t, LevelHigh, pm.GetLevel())
+}
+
+func TestPressureMonitor_Latency(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	args := createMockArgsPressureMonitor()
+	args.StatusMetrics = statusMetrics
+	pm, _ := NewPressureMonitor(args)
+	for i := 0; i < 19; i++ {
+		pm.RecordResponse(100*time.Millisecond, http.StatusOK, nil)
+	}
+	pm.RecordResponse(1500*time.Millisecond, http.StatusOK, nil)
+	require.Equal(t, LevelNormal, pm.GetLevel())
+
+	pm.RecordResponse(1500*time.Millisecond, http.StatusOK, nil)
+	require.Equal(t, LevelElevated, pm.GetLevel())
+
+	for i := 0; i < 2; i++ {
+		pm.RecordResponse(2500*time.Millisecond, http.StatusOK, nil)
+	}
+	require.Equal(t, LevelHigh, pm.GetLevel())
+
+	promMetrics := statusMetrics.GetMetricsForPrometheus()
+	require.Contains(t, promMetrics, "es_latency_p50_ms{shardID=\"#\"} 100")
+	require.Contains(t, promMetrics, "es_latency_p99_ms{shardID=\"#\"} 2500")
+	require.Contains(t, promMetrics, "es_pressure_level{shardID=\"#\"} 2")
+}
+
+func TestPressureMonitor_WithoutAdaptIngestion(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsPressureMonitor()
+	args.AdaptIngestion = false
+	pm, _ := NewPressureMonitor(args)
+
+	pm.RecordRejection()
+	require.Equal(t, LevelHigh, pm.GetLevel())
+	require.Equal(t, time.Duration(0), pm.GetAckDelay())
+	require.Equal(t, 4000, pm.GetBulkRequestMaxSize())
+}
@@ -0,0 +1,136 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	errInvalidCACert              = errors.New("no valid certificate found in the CA certificate")
+	errIncompleteClientCert       = errors.New("the client certificate and the client key have to be provided together")
+	errUnexpectedDefaultTransport = errors.New("the default transport is not an http.Transport")
+	errInvalidFingerprint         = errors.New("invalid CA certificate fingerprint, it should be a hex-encoded SHA-256")
+	errFingerprintMismatch        = errors.New("no certificate presented by the node matches the CA certificate fingerprint")
+	errUntrustedNodeCertificate   = errors.New("the certificate of the node is not signed by the CA certificate with the fingerprint")
+)
+
+// ArgsHTTPTransport holds the TLS settings of the connections to the Elasticsearch nodes. The certificates and the key
+// are PEM-encoded
+type ArgsHTTPTransport struct {
+	CACert     []byte
+	ClientCert []byte
+	ClientKey  []byte
+	// CACertFingerprint is the hex-encoded SHA-256 fingerprint of the CA certificate, as printed by Elasticsearch 8 at
+	// its first start. When provided, the nodes are trusted if their certificate is signed by the CA certificate with this
+	// fingerprint, which they have to present together with their own
+	CACertFingerprint  string
+	InsecureSkipVerify bool
+}
+
+// NewHTTPTransport will create a new http transport, based on the default one, that trusts the provided CA
+// certificate and presents the provided client certificate. Without a CA certificate, the system ones are trusted
+func NewHTTPTransport(args ArgsHTTPTransport) (*http.Transport, error) {
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errUnexpectedDefaultTransport
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: args.InsecureSkipVerify,
+	}
+
+	if len(args.CACert) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(args.CACert) {
+			return nil, errInvalidCACert
+		}
+	}
+
+	if args.CACertFingerprint != "" {
+		fingerprint, err := decodeFingerprint(args.CACertFingerprint)
+		if err != nil {
+			return nil, err
+		}
+
+		// the chain is verified by the fingerprint verifier instead of against the trusted CA certificates, as the CA of
+		// the nodes is a self-signed one that is known only by its fingerprint
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = createFingerprintVerifier(fingerprint)
+	}
+
+	hasClientCert := len(args.ClientCert) > 0
+	hasClientKey := len(args.ClientKey) > 0
+	if hasClientCert != hasClientKey {
+		return nil, errIncompleteClientCert
+	}
+	if hasClientCert {
+		clientCert, err := tls.X509KeyPair(args.ClientCert, args.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	httpTransport := defaultTransport.Clone()
+	httpTransport.TLSClientConfig = tlsConfig
+
+	return httpTransport, nil
+}
+
+func decodeFingerprint(fingerprint string) ([]byte, error) {
+	decoded, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidFingerprint, err.Error())
+	}
+	if len(decoded) != sha256.Size {
+		return nil, errInvalidFingerprint
+	}
+
+	return decoded, nil
+}
+
+// createFingerprintVerifier returns the verifier of the connections to nodes whose CA is pinned by its fingerprint. The
+// certificate that matches the fingerprint is the only trusted root, and the certificate of the node has to be signed
+// by it, so presenting the public CA certificate next to a foreign certificate is not enough
+func createFingerprintVerifier(fingerprint []byte) func(state tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errFingerprintMismatch
+		}
+
+		roots := x509.NewCertPool()
+		intermediates := x509.NewCertPool()
+		foundPinnedCert := false
+		for _, cert := range state.PeerCertificates {
+			digest := sha256.Sum256(cert.Raw)
+			if bytes.Equal(digest[:], fingerprint) {
+				roots.AddCert(cert)
+				foundPinnedCert = true
+				continue
+			}
+			intermediates.AddCert(cert)
+		}
+		if !foundPinnedCert {
+			return errFingerprintMismatch
+		}
+
+		_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			DNSName:       state.ServerName,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %s", errUntrustedNodeCertificate, err.Error())
+		}
+
+		return nil
+	}
+}
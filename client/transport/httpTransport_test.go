@@ -0,0 +1,247 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func createTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	parentCert, parentKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	t.Parallel()
+
+	ca := createTestCertificate(t, "ca", nil)
+	client := createTestCertificate(t, "client", ca)
+
+	t.Run("without settings should work", func(t *testing.T) {
+		t.Parallel()
+
+		httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{})
+		require.Nil(t, err)
+		require.Nil(t, httpTransport.TLSClientConfig.RootCAs)
+		require.Empty(t, httpTransport.TLSClientConfig.Certificates)
+		require.False(t, httpTransport.TLSClientConfig.InsecureSkipVerify)
+	})
+
+	t.Run("invalid CA certificate should error", func(t *testing.T) {
+		t.Parallel()
+
+		httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+			CACert: []byte("not a certificate"),
+		})
+		require.Nil(t, httpTransport)
+		require.Equal(t, errInvalidCACert, err)
+	})
+
+	t.Run("invalid CA certificate fingerprint should error", func(t *testing.T) {
+		t.Parallel()
+
+		httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+			CACertFingerprint: "not a fingerprint",
+		})
+		require.Nil(t, httpTransport)
+		require.True(t, errors.Is(err, errInvalidFingerprint))
+
+		httpTransport, err = NewHTTPTransport(ArgsHTTPTransport{
+			CACertFingerprint: "ab:cd",
+		})
+		require.Nil(t, httpTransport)
+		require.Equal(t, errInvalidFingerprint, err)
+	})
+
+	t.Run("client certificate without key should error", func(t *testing.T) {
+		t.Parallel()
+
+		httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+			ClientCert: client.certPEM,
+		})
+		require.Nil(t, httpTransport)
+		require.Equal(t, errIncompleteClientCert, err)
+	})
+
+	t.Run("client key not matching the certificate should error", func(t *testing.T) {
+		t.Parallel()
+
+		httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+			ClientCert: client.certPEM,
+			ClientKey:  ca.keyPEM,
+		})
+		require.Nil(t, httpTransport)
+		require.NotNil(t, err)
+	})
+
+	t.Run("CA and client certificates should be set", func(t *testing.T) {
+		t.Parallel()
+
+		httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+			CACert:     ca.certPEM,
+			ClientCert: client.certPEM,
+			ClientKey:  client.keyPEM,
+		})
+		require.Nil(t, err)
+		require.NotNil(t, httpTransport.TLSClientConfig.RootCAs)
+		require.Len(t, httpTransport.TLSClientConfig.Certificates, 1)
+	})
+}
+
+func TestNewHTTPTransport_MutualTLS(t *testing.T) {
+	t.Parallel()
+
+	ca := createTestCertificate(t, "ca", nil)
+	server := createTestCertificate(t, "server", ca)
+	client := createTestCertificate(t, "client", ca)
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca.cert)
+	serverCert, err := tls.X509KeyPair(server.certPEM, server.keyPEM)
+	require.Nil(t, err)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+		CACert: ca.certPEM,
+	})
+	require.Nil(t, err)
+	_, err = (&http.Client{Transport: httpTransport}).Get(ts.URL)
+	require.NotNil(t, err)
+
+	httpTransport, err = NewHTTPTransport(ArgsHTTPTransport{
+		CACert:     ca.certPEM,
+		ClientCert: client.certPEM,
+		ClientKey:  client.keyPEM,
+	})
+	require.Nil(t, err)
+	resp, err := (&http.Client{Transport: httpTransport}).Get(ts.URL)
+	require.Nil(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewHTTPTransport_CACertFingerprint(t *testing.T) {
+	t.Parallel()
+
+	ca := createTestCertificate(t, "ca", nil)
+	server := createTestCertificate(t, "server", ca)
+	serverCert, err := tls.X509KeyPair(append(server.certPEM, ca.certPEM...), server.keyPEM)
+	require.Nil(t, err)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	caFingerprint := sha256.Sum256(ca.cert.Raw)
+	httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+		CACertFingerprint: hex.EncodeToString(caFingerprint[:]),
+	})
+	require.Nil(t, err)
+	resp, err := (&http.Client{Transport: httpTransport}).Get(ts.URL)
+	require.Nil(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	otherFingerprint := sha256.Sum256([]byte("other certificate"))
+	httpTransport, err = NewHTTPTransport(ArgsHTTPTransport{
+		CACertFingerprint: hex.EncodeToString(otherFingerprint[:]),
+	})
+	require.Nil(t, err)
+	_, err = (&http.Client{Transport: httpTransport}).Get(ts.URL)
+	require.True(t, errors.Is(err, errFingerprintMismatch))
+}
+
+func TestNewHTTPTransport_CACertFingerprintShouldRejectForeignCertificate(t *testing.T) {
+	t.Parallel()
+
+	ca := createTestCertificate(t, "ca", nil)
+	foreignCA := createTestCertificate(t, "foreign ca", nil)
+	foreignServer := createTestCertificate(t, "server", foreignCA)
+	// the pinned CA certificate is public, so it can be sent next to a certificate it did not sign
+	serverCert, err := tls.X509KeyPair(append(foreignServer.certPEM, ca.certPEM...), foreignServer.keyPEM)
+	require.Nil(t, err)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	caFingerprint := sha256.Sum256(ca.cert.Raw)
+	httpTransport, err := NewHTTPTransport(ArgsHTTPTransport{
+		CACertFingerprint: hex.EncodeToString(caFingerprint[:]),
+	})
+	require.Nil(t, err)
+	_, err = (&http.Client{Transport: httpTransport}).Get(ts.URL)
+	require.True(t, errors.Is(err, errUntrustedNodeCertificate))
+}
@@ -0,0 +1,9 @@
+package transport
+
+import "time"
+
+// PressureRecorder defines what a component that tracks the pressure of the Elasticsearch cluster should be able to do
+type PressureRecorder interface {
+	RecordResponse(duration time.Duration, statusCode int, err error)
+	IsInterfaceNil() bool
+}
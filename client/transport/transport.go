@@ -1,8 +1,10 @@
 package transport
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -12,22 +14,40 @@ import (
 	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
 )
 
-var errNilRequest = errors.New("nil request")
+var (
+	errNilRequest          = errors.New("nil request")
+	errNilPressureRecorder = errors.New("nil pressure recorder")
+	errNilTransport        = errors.New("nil transport")
+)
 
 type metricsTransport struct {
-	statusMetrics core.StatusMetricsHandler
-	transport     http.RoundTripper
+	statusMetrics    core.StatusMetricsHandler
+	pressureRecorder PressureRecorder
+	transport        http.RoundTripper
 }
 
-// NewMetricsTransport will create a new instance of metricsTransport
-func NewMetricsTransport(statusMetrics core.StatusMetricsHandler) (*metricsTransport, error) {
+// NewMetricsTransport will create a new instance of metricsTransport that sends the requests through the provided
+// transport. The outcome of every request is also passed to the pressure recorder, so the pressure of the Elasticsearch
+// cluster can be tracked
+func NewMetricsTransport(
+	statusMetrics core.StatusMetricsHandler,
+	pressureRecorder PressureRecorder,
+	transport http.RoundTripper,
+) (*metricsTransport, error) {
 	if check.IfNil(statusMetrics) {
 		return nil, core.ErrNilMetricsHandler
 	}
+	if check.IfNil(pressureRecorder) {
+		return nil, errNilPressureRecorder
+	}
+	if transport == nil {
+		return nil, errNilTransport
+	}
 
 	return &metricsTransport{
-		statusMetrics: statusMetrics,
-		transport:     http.DefaultTransport,
+		statusMetrics:    statusMetrics,
+		pressureRecorder: pressureRecorder,
+		transport:        transport,
 	}, nil
 }
 
@@ -39,7 +59,13 @@ func (m *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 
 	startTime := time.Now()
-	size := req.ContentLength
+	size := uint64(req.ContentLength)
+	gzipSize := uint64(0)
+	rawSize, isCompressed := req.Context().Value(request.RawSizeContextKey).(uint64)
+	if isCompressed {
+		gzipSize = size
+		size = rawSize
+	}
 
 	var statusCode int
 	resp, err := m.transport.RoundTrip(req)
@@ -48,6 +74,7 @@ func (m *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 
 	duration := time.Since(startTime)
+	m.pressureRecorder.RecordResponse(duration, statusCode, err)
 
 	valueFromCtx := req.Context().Value(request.ContextKey)
 	if valueFromCtx == nil {
@@ -58,10 +85,26 @@ func (m *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	m.statusMetrics.AddIndexingData(metrics.ArgsAddIndexingData{
 		StatusCode: statusCode,
 		GotError:   err != nil,
-		MessageLen: uint64(size),
+		GotTimeout: isTimeout(req, err),
+		MessageLen: size,
+		GzipLen:    gzipSize,
 		Topic:      topic,
 		Duration:   duration,
 	})
 
 	return resp, err
 }
+
+// isTimeout returns true if the request failed because its deadline was exceeded, so the timeouts can be told apart
+// from the other errors
+func isTimeout(req *http.Request, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/multiversx/mx-chain-es-indexer-go/core"
 	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
@@ -17,19 +18,27 @@ import (
 func TestNewMetricsTransport(t *testing.T) {
 	t.Parallel()
 
-	transportHandler, err := NewMetricsTransport(nil)
+	transportHandler, err := NewMetricsTransport(nil, &mock.PressureMonitorStub{}, http.DefaultTransport)
 	require.Nil(t, transportHandler)
 	require.Equal(t, core.ErrNilMetricsHandler, err)
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, err = NewMetricsTransport(metricsHandler)
+	transportHandler, err = NewMetricsTransport(metricsHandler, nil, http.DefaultTransport)
+	require.Nil(t, transportHandler)
+	require.Equal(t, errNilPressureRecorder, err)
+
+	transportHandler, err = NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, nil)
+	require.Nil(t, transportHandler)
+	require.Equal(t, errNilTransport, err)
+
+	transportHandler, err = NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, http.DefaultTransport)
 	require.Nil(t, err)
 	require.NotNil(t, transportHandler)
 }
 
 func TestMetricsTransport_NilRequest(t *testing.T) {
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, http.DefaultTransport)
 
 	_, err := transportHandler.RoundTrip(nil)
 	require.Equal(t, errNilRequest, err)
@@ -39,7 +48,7 @@ func TestMetricsTransport_RoundTripNilResponseShouldWork(t *testing.T) {
 	t.Parallel()
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, http.DefaultTransport)
 
 	testErr := errors.New("test")
 	transportHandler.transport = &mock.TransportMock{
@@ -63,7 +72,7 @@ func TestMetricsTransport_RoundTrip(t *testing.T) {
 	t.Parallel()
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, http.DefaultTransport)
 
 	transportHandler.transport = &mock.TransportMock{
 		Response: &http.Response{
@@ -88,7 +97,7 @@ func TestMetricsTransport_RoundTripNoValueInContextShouldNotAddMetrics(t *testin
 	t.Parallel()
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, http.DefaultTransport)
 
 	transportHandler.transport = &mock.TransportMock{
 		Response: &http.Response{
@@ -103,3 +112,93 @@ func TestMetricsTransport_RoundTripNoValueInContextShouldNotAddMetrics(t *testin
 	metricsMap := metricsHandler.GetMetrics()
 	require.Len(t, metricsMap, 0)
 }
+
+func TestMetricsTransport_RoundTripShouldRecordResponseForPressure(t *testing.T) {
+	t.Parallel()
+
+	var recordedStatusCode int
+	var recordedErr error
+	numCalls := 0
+	pressureRecorder := &mock.PressureMonitorStub{
+		RecordResponseCalled: func(duration time.Duration, statusCode int, err error) {
+			numCalls++
+			recordedStatusCode = statusCode
+			recordedErr = err
+		},
+	}
+	transportHandler, _ := NewMetricsTransport(metrics.NewStatusMetrics(), pressureRecorder, http.DefaultTransport)
+
+	transportHandler.transport = &mock.TransportMock{
+		Response: &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+		},
+		Err: nil,
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "dummy", bytes.NewBuffer([]byte("test")))
+	_, _ = transportHandler.RoundTrip(req)
+	require.Equal(t, 1, numCalls)
+	require.Equal(t, http.StatusTooManyRequests, recordedStatusCode)
+	require.Nil(t, recordedErr)
+
+	testErr := errors.New("test")
+	transportHandler.transport = &mock.TransportMock{
+		Response: nil,
+		Err:      testErr,
+	}
+	_, _ = transportHandler.RoundTrip(req)
+	require.Equal(t, 2, numCalls)
+	require.Equal(t, 0, recordedStatusCode)
+	require.Equal(t, testErr, recordedErr)
+}
+
+func TestMetricsTransport_RoundTripCompressedRequest(t *testing.T) {
+	t.Parallel()
+
+	metricsHandler := metrics.NewStatusMetrics()
+	transportHandler, _ := NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, http.DefaultTransport)
+
+	transportHandler.transport = &mock.TransportMock{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+		},
+		Err: nil,
+	}
+
+	testTopic := "test"
+	ctx := context.WithValue(context.Background(), request.ContextKey, testTopic)
+	ctx = context.WithValue(ctx, request.RawSizeContextKey, uint64(100))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "dummy", bytes.NewBuffer([]byte("test")))
+
+	_, _ = transportHandler.RoundTrip(req)
+
+	metricsMap := metricsHandler.GetMetrics()
+	require.Equal(t, uint64(100), metricsMap[testTopic].TotalData)
+	require.Equal(t, uint64(4), metricsMap[testTopic].CompressedData)
+}
+
+func TestMetricsTransport_RoundTripTimeoutShouldBeCounted(t *testing.T) {
+	t.Parallel()
+
+	metricsHandler := metrics.NewStatusMetrics()
+	transportHandler, _ := NewMetricsTransport(metricsHandler, &mock.PressureMonitorStub{}, http.DefaultTransport)
+
+	transportHandler.transport = &mock.TransportMock{
+		Err: context.DeadlineExceeded,
+	}
+
+	testTopic := "test"
+	contextWithValue := context.WithValue(context.Background(), request.ContextKey, testTopic)
+	req, _ := http.NewRequestWithContext(contextWithValue, http.MethodGet, "dummy", nil)
+	_, _ = transportHandler.RoundTrip(req)
+
+	transportHandler.transport = &mock.TransportMock{
+		Err: errors.New("connection refused"),
+	}
+	_, _ = transportHandler.RoundTrip(req)
+
+	metricsMap := metricsHandler.GetMetrics()
+	require.Equal(t, uint64(2), metricsMap[testTopic].OperationsCount)
+	require.Equal(t, uint64(2), metricsMap[testTopic].TotalErrorsCount)
+	require.Equal(t, uint64(1), metricsMap[testTopic].TimeoutsCount)
+}
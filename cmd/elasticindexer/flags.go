@@ -42,6 +42,14 @@ var (
 		Name:  "log-save",
 		Usage: "Boolean option for enabling log saving. If set, it will automatically save all the logs into a file.",
 	}
+	// importDirectory defines a flag for the directory with marshalled outport files that will be imported
+	importDirectory = cli.StringFlag{
+		Name: "import-dir",
+		Usage: "The `" + filePathPlaceholder + "` to a directory with JSON or gogo protobuf marshalled outport files that " +
+			"will be indexed in import-DB mode instead of listening on the WebSocket. The files have to be named " +
+			"<shardID>-<nonce>-<topic>.<json|pb>, for example 0-100-SaveBlock.pb. An interrupted import resumes after " +
+			"the last imported file.",
+	}
 	// disableAnsiColor defines if the logger subsystem should prevent displaying ANSI colors
 	disableAnsiColor = cli.BoolFlag{
 		Name:  "disable-ansi-color",
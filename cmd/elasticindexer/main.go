@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/multiversx/mx-chain-core-go/core/closing"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
 	"github.com/multiversx/mx-chain-es-indexer-go/factory"
 	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
@@ -21,8 +25,9 @@ import (
 )
 
 var (
-	log          = logger.GetOrCreate("indexer")
-	helpTemplate = `NAME:
+	log                = logger.GetOrCreate("indexer")
+	errUncleanShutdown = errors.New("the in-flight data was not drained cleanly")
+	helpTemplate       = `NAME:
    {{.Name}} - {{.Usage}}
 USAGE:
    {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
@@ -63,6 +68,7 @@ func main() {
 		logLevel,
 		logSaveFile,
 		disableAnsiColor,
+		importDirectory,
 	}
 	app.Authors = []cli.Author{
 		{
@@ -98,9 +104,16 @@ func startIndexer(ctx *cli.Context) error {
 	}
 
 	statusMetrics := metrics.NewStatusMetrics()
-	wsHost, err := factory.CreateWsIndexer(cfg, clusterCfg, statusMetrics, ctx.App.Version)
+	importDir := ctx.GlobalString(importDirectory.Name)
+	if importDir != "" {
+		err = importData(cfg, clusterCfg, importDir, statusMetrics, ctx.App.Version)
+		closeFileLogging(fileLogging)
+		return err
+	}
+
+	gapsTracker, err := factory.CreateGapsTracker(statusMetrics)
 	if err != nil {
-		return fmt.Errorf("%w while creating the indexer", err)
+		return fmt.Errorf("%w while creating the gaps tracker", err)
 	}
 
 	apiConfig, err := loadApiConfig(ctx.GlobalString(configurationApiFile.Name))
@@ -108,7 +121,17 @@ func startIndexer(ctx *cli.Context) error {
 		return fmt.Errorf("%w while loading the api config file", err)
 	}
 
-	webServer, err := factory.CreateWebServer(apiConfig, statusMetrics)
+	wsIndexers, err := factory.CreateWsIndexers(cfg, clusterCfg, apiConfig.Ingest, statusMetrics, gapsTracker, ctx.App.Version)
+	if err != nil {
+		return fmt.Errorf("%w while creating the indexer", err)
+	}
+
+	deadLettersHandler, err := factory.CreateDeadLettersHandler(cfg, clusterCfg, statusMetrics, ctx.App.Version)
+	if err != nil {
+		return fmt.Errorf("%w while creating the dead letters handler", err)
+	}
+
+	webServer, err := factory.CreateWebServer(apiConfig, statusMetrics, gapsTracker, wsIndexers.IngestHandler, deadLettersHandler)
 	if err != nil {
 		return fmt.Errorf("%w while creating the web server", err)
 	}
@@ -121,31 +144,126 @@ func startIndexer(ctx *cli.Context) error {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
 
-	retryDuration := time.Duration(clusterCfg.Config.WebSocket.RetryDurationInSec) * time.Second
-	closed := requestSettings(wsHost, retryDuration, interrupt)
-	if !closed {
-		<-interrupt
+	closeChan := make(chan struct{})
+	for idx, wsHost := range wsIndexers.Hosts {
+		retryDuration := time.Duration(clusterCfg.Config.WebSocket[idx].RetryDurationInSec) * time.Second
+		go requestSettings(wsHost, retryDuration, closeChan)
 	}
 
+	<-interrupt
+	close(closeChan)
+
 	log.Info("closing app at user's signal")
-	err = wsHost.Close()
-	if err != nil {
-		log.Error("cannot close ws indexer", "error", err)
-	}
+	isDrainClean := closeWsIndexers(wsIndexers, clusterCfg.Config.WebSocket)
 
 	err = webServer.Close()
 	if err != nil {
 		log.Error("cannot close web server", "error", err)
 	}
 
-	if !check.IfNilReflect(fileLogging) {
-		err = fileLogging.Close()
-		log.LogIfError(err)
+	err = wsIndexers.IngestHandler.Close()
+	if err != nil {
+		log.Error("cannot close the ingest payload handler", "error", err)
+		isDrainClean = false
+	}
+
+	err = deadLettersHandler.Close()
+	if err != nil {
+		log.Error("cannot close the dead letters handler", "error", err)
 	}
+
+	closeFileLogging(fileLogging)
+	if !isDrainClean {
+		return errUncleanShutdown
+	}
+
+	log.Info("the in-flight data was drained cleanly")
 	return nil
 }
 
-func requestSettings(host wsindexer.WSClient, retryDuration time.Duration, close chan os.Signal) bool {
+// closeWsIndexers will close all the WebSocket sources in parallel, so every source drains its in-flight data within
+// the same shutdown timeout. The payload handler of a source is closed directly after its host, because the host closes
+// it only if a node is connected. It returns false if any of them could not be closed cleanly
+func closeWsIndexers(wsIndexers *factory.WsIndexers, sources []config.WebSocketConfig) bool {
+	errs := make([]error, len(wsIndexers.Hosts))
+	wg := sync.WaitGroup{}
+	wg.Add(len(wsIndexers.Hosts))
+	for idx := range wsIndexers.Hosts {
+		go func(idx int) {
+			defer wg.Done()
+			errs[idx] = closeWsIndexer(wsIndexers.Hosts[idx], wsIndexers.PayloadHandlers[idx])
+		}(idx)
+	}
+	wg.Wait()
+
+	isDrainClean := true
+	for idx, err := range errs {
+		if err != nil {
+			log.Error("cannot close ws indexer", "url", sources[idx].URL, "error", err)
+			isDrainClean = false
+		}
+	}
+
+	return isDrainClean
+}
+
+func closeWsIndexer(wsHost wsindexer.WSClient, payloadHandler wsindexer.PayloadHandler) error {
+	errHost := wsHost.Close()
+
+	// closing the payload handler again is a no-op when the host has already closed it
+	errPayloadHandler := payloadHandler.Close()
+	if errPayloadHandler != nil {
+		return errPayloadHandler
+	}
+
+	return errHost
+}
+
+func importData(cfg config.Config, clusterCfg config.ClusterConfig, importDir string, statusMetrics indexerCore.StatusMetricsHandler, version string) error {
+	dataImporter, err := factory.CreateImporter(cfg, clusterCfg, importDir, statusMetrics, version)
+	if err != nil {
+		return fmt.Errorf("%w while creating the importer", err)
+	}
+	defer func() {
+		errClose := dataImporter.Close()
+		if errClose != nil {
+			log.Error("cannot close the importer", "error", errClose)
+		}
+	}()
+
+	importCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupt:
+			log.Info("stopping the import at user's signal")
+			cancel()
+		case <-importCtx.Done():
+		}
+	}()
+
+	log.Info("starting the import", "directory", importDir)
+	err = dataImporter.Import(importCtx)
+	if err != nil {
+		return fmt.Errorf("%w while importing data", err)
+	}
+
+	return nil
+}
+
+func closeFileLogging(fileLogging closing.Closer) {
+	if check.IfNilReflect(fileLogging) {
+		return
+	}
+
+	err := fileLogging.Close()
+	log.LogIfError(err)
+}
+
+func requestSettings(host wsindexer.WSClient, retryDuration time.Duration, close chan struct{}) {
 	timer := time.NewTimer(0)
 	defer timer.Stop()
 
@@ -155,13 +273,13 @@ func requestSettings(host wsindexer.WSClient, retryDuration time.Duration, close
 		case <-timer.C:
 			err := host.Send(emptyMessage, outport.TopicSettings)
 			if err == nil {
-				return false
+				return
 			}
 			log.Debug("unable to request settings - will retry", "error", err)
 
 			timer.Reset(retryDuration)
 		case <-close:
-			return true
+			return
 		}
 	}
 }
@@ -174,10 +292,7 @@ func loadMainConfig(filepath string) (config.Config, error) {
 }
 
 func loadClusterConfig(filepath string) (config.ClusterConfig, error) {
-	cfg := config.ClusterConfig{}
-	err := core.LoadTomlFile(&cfg, filepath)
-
-	return cfg, err
+	return config.LoadClusterConfig(filepath)
 }
 
 // loadApiConfig returns a ApiRoutesConfig by reading the config file provided
@@ -0,0 +1,74 @@
+package main
+
+import (
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+const (
+	filePathPlaceholder = "[path]"
+)
+
+var (
+	configurationFile = cli.StringFlag{
+		Name:  "config",
+		Usage: "The main configuration file to load",
+		Value: "./config/config.toml",
+	}
+	// configurationPreferencesFile defines a flag for the path to the preferences toml configuration file
+	configurationPreferencesFile = cli.StringFlag{
+		Name: "config-preferences",
+		Usage: "The `" + filePathPlaceholder + "` for the preferences configuration file. This TOML file contains " +
+			"the Elasticsearch cluster configuration and the marshaller type that was used by the captured payloads",
+		Value: "./config/prefs.toml",
+	}
+	// inputPath defines a flag for the path to the captured payloads
+	inputPath = cli.StringFlag{
+		Name:  "input",
+		Usage: "The `" + filePathPlaceholder + "` to a capture file or to a directory that contains capture files",
+		Value: "./db/capture",
+	}
+	// source defines a flag for the WebSocket source whose marshaller was used by the captured payloads
+	source = cli.StringFlag{
+		Name: "source",
+		Usage: "The name of the WebSocket source, from the preferences configuration file, that received the captured " +
+			"payloads. If not set, the first configured source is used",
+	}
+	// topics defines a flag for the topics that should be replayed
+	topics = cli.StringSliceFlag{
+		Name:  "topic",
+		Usage: "A topic that should be replayed (e.g. SaveBlock). It can be provided multiple times. If not set, all the topics are replayed",
+	}
+	// shards defines a flag for the shards that should be replayed
+	shards = cli.StringSliceFlag{
+		Name:  "shard",
+		Usage: "A shard ID whose payloads should be replayed. It can be provided multiple times. If not set, all the shards are replayed",
+	}
+	// startNonce defines a flag for the first block nonce that should be replayed
+	startNonce = cli.Uint64Flag{
+		Name:  "start-nonce",
+		Usage: "The first block nonce that should be replayed",
+		Value: 0,
+	}
+	// endNonce defines a flag for the last block nonce that should be replayed
+	endNonce = cli.Uint64Flag{
+		Name:  "end-nonce",
+		Usage: "The last block nonce that should be replayed. 0 means that there is no upper limit",
+		Value: 0,
+	}
+	// speed defines a flag for the replay speed
+	speed = cli.Float64Flag{
+		Name: "speed",
+		Usage: "The replay speed relative to the capture. 1 replays the payloads at the original pace, 2 twice as fast, " +
+			"while 0 replays them as fast as possible",
+		Value: 0,
+	}
+	logLevel = cli.StringFlag{
+		Name: "log-level",
+		Usage: "This flag specifies the logger `level(s)`. It can contain multiple comma-separated value. For example" +
+			", if set to *:INFO the logs for all packages will have the INFO level. However, if set to *:INFO,api:DEBUG" +
+			" the logs for all packages will have the INFO level, excepting the api package which will receive a DEBUG" +
+			" log level.",
+		Value: "*:" + logger.LogInfo.String(),
+	}
+)
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	factoryMarshaller "github.com/multiversx/mx-chain-core-go/marshal/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	processFactory "github.com/multiversx/mx-chain-es-indexer-go/process/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/replayer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/urfave/cli"
+)
+
+var (
+	log          = logger.GetOrCreate("replayer")
+	helpTemplate = `NAME:
+   {{.Name}} - {{.Usage}}
+USAGE:
+   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
+   {{if len .Authors}}
+AUTHOR:
+   {{range .Authors}}{{ . }}{{end}}
+   {{end}}{{if .Commands}}
+GLOBAL OPTIONS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+VERSION:
+   {{.Version}}
+   {{end}}
+`
+)
+
+// version should be populated at build time using ldflags
+var version = "undefined"
+
+func main() {
+	app := cli.NewApp()
+	cli.AppHelpTemplate = helpTemplate
+	app.Name = "Payloads replayer"
+	app.Usage = "This tool will replay the captured outport payloads into an Elasticsearch database"
+	app.Flags = []cli.Flag{
+		configurationFile,
+		configurationPreferencesFile,
+		inputPath,
+		source,
+		topics,
+		shards,
+		startNonce,
+		endNonce,
+		speed,
+		logLevel,
+	}
+	app.Authors = []cli.Author{
+		{
+			Name:  "The MultiversX Team",
+			Email: "contact@multiversx.com",
+		},
+	}
+
+	app.Version = version
+	app.Action = startReplay
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func startReplay(ctx *cli.Context) error {
+	err := logger.SetLogLevel(ctx.GlobalString(logLevel.Name))
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Config{}
+	err = core.LoadTomlFile(&cfg, ctx.GlobalString(configurationFile.Name))
+	if err != nil {
+		return fmt.Errorf("%w while loading the config file", err)
+	}
+
+	clusterCfg, err := config.LoadClusterConfig(ctx.GlobalString(configurationPreferencesFile.Name))
+	if err != nil {
+		return fmt.Errorf("%w while loading the preferences config file", err)
+	}
+
+	files, err := getFilesToReplay(ctx.GlobalString(inputPath.Name))
+	if err != nil {
+		return fmt.Errorf("%w while reading the capture files", err)
+	}
+
+	filter, err := createFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	wsCfg, err := factory.GetWebSocketConfig(clusterCfg, ctx.GlobalString(source.Name))
+	if err != nil {
+		return err
+	}
+
+	payloadMarshaller, err := factoryMarshaller.NewMarshalizer(wsCfg.DataMarshallerType)
+	if err != nil {
+		return err
+	}
+
+	blockContainer, err := processFactory.CreateBlockCreatorsContainer()
+	if err != nil {
+		return err
+	}
+
+	statusMetrics := metrics.NewStatusMetrics()
+	gapsTracker, err := factory.CreateGapsTracker(statusMetrics)
+	if err != nil {
+		return err
+	}
+
+	payloadIndexer, err := factory.CreatePayloadIndexer(cfg, clusterCfg, payloadMarshaller, statusMetrics, gapsTracker, ctx.App.Version)
+	if err != nil {
+		return fmt.Errorf("%w while creating the indexer", err)
+	}
+	defer func() {
+		log.LogIfError(payloadIndexer.Close())
+	}()
+
+	payloadsReplayer, err := replayer.NewReplayer(replayer.ArgsReplayer{
+		Files:          files,
+		PayloadHandler: payloadIndexer,
+		Marshaller:     payloadMarshaller,
+		BlockContainer: blockContainer,
+		Filter:         filter,
+		Speed:          ctx.GlobalFloat64(speed.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	replayCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupt:
+			log.Info("stopping the replay at user's signal")
+			cancel()
+		case <-replayCtx.Done():
+		}
+	}()
+
+	_, err = payloadsReplayer.Replay(replayCtx)
+	return err
+}
+
+func getFilesToReplay(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	return wsindexer.GetCaptureFiles(path)
+}
+
+func createFilter(ctx *cli.Context) (replayer.Filter, error) {
+	filter := replayer.Filter{
+		Topics:     make(map[string]struct{}),
+		Shards:     make(map[uint32]struct{}),
+		StartNonce: ctx.GlobalUint64(startNonce.Name),
+		EndNonce:   ctx.GlobalUint64(endNonce.Name),
+	}
+
+	for _, topic := range ctx.GlobalStringSlice(topics.Name) {
+		filter.Topics[topic] = struct{}{}
+	}
+	for _, shardStr := range ctx.GlobalStringSlice(shards.Name) {
+		shardID, err := strconv.ParseUint(shardStr, 10, 32)
+		if err != nil {
+			return replayer.Filter{}, fmt.Errorf("%w while parsing the shard %s", err, shardStr)
+		}
+		filter.Shards[uint32(shardID)] = struct{}{}
+	}
+
+	return filter, nil
+}
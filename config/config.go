@@ -34,30 +34,134 @@ type Config struct {
 // ClusterConfig will hold the config for the Elasticsearch cluster
 type ClusterConfig struct {
 	Config struct {
-		DisabledIndices []string `toml:"disabled-indices"`
-		WebSocket       struct {
-			URL                string `toml:"url"`
-			Mode               string `toml:"mode"`
-			DataMarshallerType string `toml:"data-marshaller-type"`
-			RetryDurationInSec uint32 `toml:"retry-duration-in-seconds"`
-			BlockingAckOnError bool   `toml:"blocking-ack-on-error"`
-			WithAcknowledge    bool   `toml:"with-acknowledge"`
-			AckTimeoutInSec    uint32 `toml:"acknowledge-timeout-in-seconds"`
-		} `toml:"web-socket"`
-		ElasticCluster struct {
-			UseKibana                 bool   `toml:"use-kibana"`
-			URL                       string `toml:"url"`
-			UserName                  string `toml:"username"`
-			Password                  string `toml:"password"`
-			BulkRequestMaxSizeInBytes int    `toml:"bulk-request-max-size-in-bytes"`
-		} `toml:"elastic-cluster"`
+		DisabledIndices      []string          `toml:"disabled-indices"`
+		ShutdownTimeoutInSec uint32            `toml:"shutdown-timeout-in-seconds"`
+		WebSocket            []WebSocketConfig `toml:"web-socket"`
+		PersistentQueue      struct {
+			Enabled            bool   `toml:"enabled"`
+			Path               string `toml:"path"`
+			SegmentSizeInBytes int64  `toml:"segment-size-in-bytes"`
+		} `toml:"persistent-queue"`
+		Capture struct {
+			Enabled            bool   `toml:"enabled"`
+			Path               string `toml:"path"`
+			MaxFileSizeInBytes int64  `toml:"max-file-size-in-bytes"`
+			MaxNumFiles        int    `toml:"max-num-files"`
+		} `toml:"capture"`
+		Backpressure struct {
+			Enabled                      bool   `toml:"enabled"`
+			WindowSize                   int    `toml:"window-size"`
+			HighLatencyThresholdInMs     uint32 `toml:"high-latency-threshold-in-ms"`
+			MaxAckDelayInMs              uint32 `toml:"max-ack-delay-in-ms"`
+			MinBulkRequestMaxSizeInBytes int    `toml:"min-bulk-request-max-size-in-bytes"`
+		} `toml:"backpressure"`
+		DeadLetters struct {
+			Enabled bool `toml:"enabled"`
+		} `toml:"dead-letters"`
+		TokenCache struct {
+			Enabled  bool `toml:"enabled"`
+			Capacity int  `toml:"capacity"`
+		} `toml:"token-cache"`
+		ElasticCluster ElasticClusterConfig  `toml:"elastic-cluster"`
+		MirrorClusters []MirrorClusterConfig `toml:"mirror-clusters"`
+		PostgreSQL     struct {
+			Enabled            bool   `toml:"enabled"`
+			DSN                string `toml:"dsn"`
+			MaxOpenConnections int    `toml:"max-open-connections"`
+		} `toml:"postgresql"`
+		ClickHouse struct {
+			Enabled             bool   `toml:"enabled"`
+			URL                 string `toml:"url"`
+			Database            string `toml:"database"`
+			UserName            string `toml:"username"`
+			Password            string `toml:"password"`
+			MaxBatchSize        int    `toml:"max-batch-size"`
+			RequestTimeoutInSec uint32 `toml:"request-timeout-in-seconds"`
+		} `toml:"clickhouse"`
+		FileSink struct {
+			Enabled                bool   `toml:"enabled"`
+			Path                   string `toml:"path"`
+			MaxFileSizeInBytes     int64  `toml:"max-file-size-in-bytes"`
+			ReadFromElasticCluster bool   `toml:"read-from-elastic-cluster"`
+		} `toml:"file-sink"`
 	} `toml:"config"`
 }
 
+// ElasticClusterConfig holds the connection and the indexing settings of an Elasticsearch cluster
+type ElasticClusterConfig struct {
+	Backend                   string   `toml:"backend"`
+	UseKibana                 bool     `toml:"use-kibana"`
+	URL                       string   `toml:"url"`
+	URLs                      []string `toml:"urls"`
+	UserName                  string   `toml:"username"`
+	Password                  string   `toml:"password"`
+	APIKey                    string   `toml:"api-key"`
+	ServiceToken              string   `toml:"service-token"`
+	BulkRequestMaxSizeInBytes int      `toml:"bulk-request-max-size-in-bytes"`
+	NumBulkRequestWorkers     int      `toml:"num-bulk-request-workers"`
+	BulkItemsMaxRetries       int      `toml:"bulk-items-max-retries"`
+	BulkItemsRetryBackOffInMs uint32   `toml:"bulk-items-retry-backoff-in-ms"`
+	CompressRequests          bool     `toml:"compress-requests"`
+	SniffOnStart              bool     `toml:"sniff-on-start"`
+	SniffIntervalInSec        uint32   `toml:"sniff-interval-in-seconds"`
+	SearchPageSize            int      `toml:"search-page-size"`
+	SearchKeepAliveInSec      uint32   `toml:"search-keep-alive-in-seconds"`
+	TLS                       struct {
+		CACert             string `toml:"ca-cert"`
+		CACertFingerprint  string `toml:"ca-cert-fingerprint"`
+		ClientCert         string `toml:"client-cert"`
+		ClientKey          string `toml:"client-key"`
+		InsecureSkipVerify bool   `toml:"insecure-skip-verify"`
+	} `toml:"tls"`
+	RequestTimeouts struct {
+		BulkInSec          uint32 `toml:"bulk-in-seconds"`
+		GetInSec           uint32 `toml:"get-in-seconds"`
+		RemoveInSec        uint32 `toml:"remove-in-seconds"`
+		UpdateByQueryInSec uint32 `toml:"update-by-query-in-seconds"`
+		CountInSec         uint32 `toml:"count-in-seconds"`
+		ScrollInSec        uint32 `toml:"scroll-in-seconds"`
+	} `toml:"request-timeouts"`
+}
+
+// MirrorClusterConfig holds the configuration of a cluster that receives a copy of every write of the indexer. The
+// reads are served by the main cluster only
+type MirrorClusterConfig struct {
+	Name           string               `toml:"name"`
+	Policy         string               `toml:"policy"`
+	AsyncQueueSize int                  `toml:"async-queue-size"`
+	ElasticCluster ElasticClusterConfig `toml:"elastic-cluster"`
+}
+
+// WebSocketConfig holds the configuration of a WebSocket source
+type WebSocketConfig struct {
+	Name               string `toml:"name"`
+	URL                string `toml:"url"`
+	Mode               string `toml:"mode"`
+	DataMarshallerType string `toml:"data-marshaller-type"`
+	RetryDurationInSec uint32 `toml:"retry-duration-in-seconds"`
+	BlockingAckOnError bool   `toml:"blocking-ack-on-error"`
+	WithAcknowledge    bool   `toml:"with-acknowledge"`
+	AckTimeoutInSec    uint32 `toml:"acknowledge-timeout-in-seconds"`
+}
+
 // ApiRoutesConfig holds the configuration related to Rest API routes
 type ApiRoutesConfig struct {
 	RestApiInterface string                      `toml:"rest-api-interface"`
 	APIPackages      map[string]APIPackageConfig `toml:"api-packages"`
+	Ingest           IngestConfig                `toml:"ingest"`
+	DeadLetters      DeadLettersApiConfig        `toml:"dead-letters"`
+}
+
+// IngestConfig holds the configuration for the HTTP ingestion of outport payloads
+type IngestConfig struct {
+	AuthToken             string `toml:"auth-token"`
+	DataMarshallerType    string `toml:"data-marshaller-type"`
+	MaxPayloadSizeInBytes int64  `toml:"max-payload-size-in-bytes"`
+}
+
+// DeadLettersApiConfig holds the configuration for the dead letters API routes
+type DeadLettersApiConfig struct {
+	AuthToken string `toml:"auth-token"`
 }
 
 // APIPackageConfig holds the configuration for the routes of each package
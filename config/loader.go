@@ -0,0 +1,30 @@
+package config
+
+import (
+	"github.com/pelletier/go-toml"
+)
+
+// webSocketPath holds the path of the WebSocket sources in the preferences file
+var webSocketPath = []string{"config", "web-socket"}
+
+// LoadClusterConfig will load the preferences file from the provided path. A single [config.web-socket] table, the
+// format used before multiple WebSocket sources were supported, is loaded as a list with one source
+func LoadClusterConfig(path string) (ClusterConfig, error) {
+	tree, err := toml.LoadFile(path)
+	if err != nil {
+		return ClusterConfig{}, err
+	}
+
+	singleSource, isSingleTable := tree.GetPath(webSocketPath).(*toml.Tree)
+	if isSingleTable {
+		tree.SetPath(webSocketPath, []*toml.Tree{singleSource})
+	}
+
+	cfg := ClusterConfig{}
+	err = tree.Unmarshal(&cfg)
+	if err != nil {
+		return ClusterConfig{}, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeClusterConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "prefs.toml")
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.Nil(t, err)
+
+	return path
+}
+
+func TestLoadClusterConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadClusterConfig(filepath.Join(t.TempDir(), "missing.toml"))
+		require.NotNil(t, err)
+	})
+
+	t.Run("single web socket table should be loaded as one source", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeClusterConfig(t, `
+[config]
+    disabled-indices = ["logs"]
+    [config.web-socket]
+        url = "localhost:22111"
+        mode = "client"
+        data-marshaller-type = "json"
+    [config.elastic-cluster]
+        url = "http://localhost:9200"
+`)
+
+		cfg, err := LoadClusterConfig(path)
+		require.Nil(t, err)
+		require.Equal(t, []string{"logs"}, cfg.Config.DisabledIndices)
+		require.Equal(t, []WebSocketConfig{{
+			URL:                "localhost:22111",
+			Mode:               "client",
+			DataMarshallerType: "json",
+		}}, cfg.Config.WebSocket)
+		require.Equal(t, "http://localhost:9200", cfg.Config.ElasticCluster.URL)
+	})
+
+	t.Run("multiple web socket sources should be loaded", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeClusterConfig(t, `
+[config]
+    [[config.web-socket]]
+        name = "shard-0"
+        url = "localhost:22111"
+    [[config.web-socket]]
+        name = "shard-1"
+        url = "localhost:22112"
+`)
+
+		cfg, err := LoadClusterConfig(path)
+		require.Nil(t, err)
+		require.Equal(t, []WebSocketConfig{
+			{Name: "shard-0", URL: "localhost:22111"},
+			{Name: "shard-1", URL: "localhost:22112"},
+		}, cfg.Config.WebSocket)
+	})
+
+	t.Run("shipped preferences file should be loaded", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := LoadClusterConfig("../cmd/elasticindexer/config/prefs.toml")
+		require.Nil(t, err)
+		require.NotEmpty(t, cfg.Config.WebSocket)
+	})
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	secretFilePrefix = "file:"
+	secretEnvPrefix  = "env:"
+)
+
+var errSecretEnvNotSet = errors.New("the environment variable of the secret is not set")
+
+// ResolveSecret returns the value of a secret from the configuration. A value prefixed with "file:" is read from the
+// file at the given path and a value prefixed with "env:" is read from the given environment variable. The surrounding
+// white spaces are removed from the secrets read from files. Any other value is returned as it is
+func ResolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretFilePrefix):
+		content, err := os.ReadFile(strings.TrimPrefix(value, secretFilePrefix))
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(content)), nil
+	case strings.HasPrefix(value, secretEnvPrefix):
+		envName := strings.TrimPrefix(value, secretEnvPrefix)
+		envValue, found := os.LookupEnv(envName)
+		if !found {
+			return "", fmt.Errorf("%w: %s", errSecretEnvNotSet, envName)
+		}
+
+		return envValue, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecret(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clear value should be returned as it is", func(t *testing.T) {
+		t.Parallel()
+
+		secret, err := ResolveSecret("password")
+		require.Nil(t, err)
+		require.Equal(t, "password", secret)
+
+		secret, err = ResolveSecret("")
+		require.Nil(t, err)
+		require.Equal(t, "", secret)
+	})
+
+	t.Run("file value should be read from the file", func(t *testing.T) {
+		t.Parallel()
+
+		secretPath := filepath.Join(t.TempDir(), "secret")
+		err := os.WriteFile(secretPath, []byte("file-password\n"), 0600)
+		require.Nil(t, err)
+
+		secret, err := ResolveSecret("file:" + secretPath)
+		require.Nil(t, err)
+		require.Equal(t, "file-password", secret)
+	})
+
+	t.Run("missing file should error", func(t *testing.T) {
+		t.Parallel()
+
+		secret, err := ResolveSecret("file:" + filepath.Join(t.TempDir(), "missing"))
+		require.True(t, errors.Is(err, os.ErrNotExist))
+		require.Equal(t, "", secret)
+	})
+
+	t.Run("missing env should error", func(t *testing.T) {
+		t.Parallel()
+
+		secret, err := ResolveSecret("env:INDEXER_TEST_MISSING_SECRET")
+		require.True(t, errors.Is(err, errSecretEnvNotSet))
+		require.Equal(t, "", secret)
+	})
+}
+
+func TestResolveSecret_FromEnv(t *testing.T) {
+	t.Setenv("INDEXER_TEST_SECRET", "env-password")
+
+	secret, err := ResolveSecret("env:INDEXER_TEST_SECRET")
+	require.Nil(t, err)
+	require.Equal(t, "env-password", secret)
+}
@@ -5,5 +5,14 @@ import "errors"
 // ErrNilMetricsHandler signals that a nil metrics handler has been provided
 var ErrNilMetricsHandler = errors.New("nil metrics handler")
 
+// ErrNilGapsHandler signals that a nil gaps handler has been provided
+var ErrNilGapsHandler = errors.New("nil gaps handler")
+
+// ErrNilPayloadHandler signals that a nil payload handler has been provided
+var ErrNilPayloadHandler = errors.New("nil payload handler")
+
+// ErrNilDeadLettersHandler signals that a nil dead letters handler has been provided
+var ErrNilDeadLettersHandler = errors.New("nil dead letters handler")
+
 // ErrNilFacadeHandler signal that a nil facade handler has been provided
 var ErrNilFacadeHandler = errors.New("nil facade handler")
@@ -8,11 +8,32 @@ import (
 // StatusMetricsHandler defines the behavior of a component that handles status metrics
 type StatusMetricsHandler interface {
 	AddIndexingData(args metrics.ArgsAddIndexingData)
+	SetGauge(topic string, value uint64)
 	GetMetrics() map[string]*request.MetricsResponse
 	GetMetricsForPrometheus() string
 	IsInterfaceNil() bool
 }
 
+// GapsHandler defines the behavior of a component that exposes the gaps detected in the indexed blocks
+type GapsHandler interface {
+	GetGaps() []*request.GapResponse
+	IsInterfaceNil() bool
+}
+
+// PayloadHandler defines the behavior of a component that processes the marshalled outport payloads
+type PayloadHandler interface {
+	ProcessPayload(payload []byte, topic string, version uint32) error
+	IsInterfaceNil() bool
+}
+
+// DeadLettersHandler defines the behavior of a component that lists and re-submits the documents rejected by Elasticsearch
+type DeadLettersHandler interface {
+	GetDeadLetters() ([]*request.DeadLetterResponse, error)
+	ResubmitDeadLetters(ids []string) (*request.ResubmitResponse, error)
+	Close() error
+	IsInterfaceNil() bool
+}
+
 // WebServerHandler defines the behavior of a component that handles the web server
 type WebServerHandler interface {
 	StartHttpServer() error
@@ -27,6 +27,20 @@ const (
 	ScrollTopic string = "req_scroll"
 )
 
+// RawSizeContextKey is the key for the uncompressed size of the body, added in the context of the compressed requests
+const RawSizeContextKey StringKeyType = "rawSize"
+
+const (
+	labelSeparator      = "|"
+	labelValueSeparator = "="
+)
+
+// Label holds the name and the value of a label that is added to a metric
+type Label struct {
+	Name  string
+	Value string
+}
+
 // MetricsResponse defines the response for status metrics endpoint
 type MetricsResponse struct {
 	TotalData         uint64         `json:"total_data"`
@@ -34,6 +48,8 @@ type MetricsResponse struct {
 	TotalErrorsCount  uint64         `json:"total_errors_count"`
 	ErrorsCount       map[int]uint64 `json:"errors_count,omitempty"`
 	TotalIndexingTime time.Duration  `json:"total_time"`
+	CompressedData    uint64         `json:"total_compressed_data,omitempty"`
+	TimeoutsCount     uint64         `json:"timeouts_count,omitempty"`
 }
 
 // ExtendTopicWithShardID will concatenate topic with shardID
@@ -57,3 +73,58 @@ func SplitTopicAndShardID(topicWithShardID string) (string, string) {
 
 	return strings.Join(split[:shardIDIndex], separator), shardIDStr
 }
+
+// ExtendTopicWithLabel will add the provided label to the topic, so the metric keeps the name of the topic and the
+// value is exported as a label
+func ExtendTopicWithLabel(topic string, name string, value string) string {
+	return topic + labelSeparator + name + labelValueSeparator + value
+}
+
+// SplitTopicAndLabels will extract the labels added to the provided topic
+func SplitTopicAndLabels(topicWithLabels string) (string, []Label) {
+	split := strings.Split(topicWithLabels, labelSeparator)
+
+	labels := make([]Label, 0, len(split)-1)
+	for _, labelStr := range split[1:] {
+		nameAndValue := strings.SplitN(labelStr, labelValueSeparator, 2)
+		if len(nameAndValue) != 2 {
+			continue
+		}
+
+		labels = append(labels, Label{
+			Name:  nameAndValue[0],
+			Value: nameAndValue[1],
+		})
+	}
+
+	return split[0], labels
+}
+
+// GapResponse defines a discontinuity detected in the blocks indexed for a shard
+type GapResponse struct {
+	ShardID    uint32 `json:"shardID"`
+	FromNonce  uint64 `json:"fromNonce"`
+	ToNonce    uint64 `json:"toNonce"`
+	Reason     string `json:"reason"`
+	DetectedAt int64  `json:"detectedAt"`
+}
+
+// DeadLetterResponse defines a document that Elasticsearch rejected and that is kept in the deadletters index
+type DeadLetterResponse struct {
+	ID        string `json:"id"`
+	Index     string `json:"index"`
+	DocID     string `json:"docID"`
+	Operation string `json:"operation"`
+	Status    int    `json:"status"`
+	Reason    string `json:"reason"`
+	ShardID   uint32 `json:"shardID"`
+	Nonce     uint64 `json:"nonce"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ResubmitResponse defines the outcome of re-submitting dead letters. The failed dead letters are kept, together with
+// the reason of the new failure
+type ResubmitResponse struct {
+	Resubmitted []string          `json:"resubmitted"`
+	Failed      map[string]string `json:"failed"`
+}
@@ -35,3 +35,18 @@ func TestSplitTopicAndShardID(t *testing.T) {
 	require.Equal(t, "req_aaaa", topic)
 	require.Equal(t, noShardID, shardID)
 }
+
+func TestExtendTopicWithLabelAndSplit(t *testing.T) {
+	t.Parallel()
+
+	topicWithLabels := ExtendTopicWithLabel(ExtendTopicWithLabel("queue_depth", "source", "shard-0"), "mirror", "dr")
+	require.Equal(t, "queue_depth|source=shard-0|mirror=dr", topicWithLabels)
+
+	topic, labels := SplitTopicAndLabels(topicWithLabels)
+	require.Equal(t, "queue_depth", topic)
+	require.Equal(t, []Label{{Name: "source", Value: "shard-0"}, {Name: "mirror", Value: "dr"}}, labels)
+
+	topic, labels = SplitTopicAndLabels("req_bulk_1")
+	require.Equal(t, "req_bulk_1", topic)
+	require.Empty(t, labels)
+}
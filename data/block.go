@@ -110,3 +110,45 @@ type Miniblock struct {
 	Timestamp                   time.Duration `json:"timestamp"`
 	Reserved                    []byte        `json:"reserved,omitempty"`
 }
+
+// ResponseBlocks is the structure for the blocks multi-get response
+type ResponseBlocks struct {
+	Docs []ResponseBlockDB `json:"docs"`
+}
+
+// ResponseBlockDB is the structure for the block response
+type ResponseBlockDB struct {
+	Found  bool        `json:"found"`
+	ID     string      `json:"_id"`
+	Source SourceBlock `json:"_source"`
+}
+
+// SourceBlock is the structure for the source body of a block, holding only the fields needed when the block is
+// finalized
+type SourceBlock struct {
+	Nonce            uint64   `json:"nonce"`
+	ShardID          uint32   `json:"shardId"`
+	MiniBlocksHashes []string `json:"miniBlocksHashes"`
+}
+
+// IndexingCheckpoint is the structure for the document, from the values index, that holds the last indexed block of
+// a shard
+type IndexingCheckpoint struct {
+	Key       string        `json:"key"`
+	Value     string        `json:"value"`
+	Nonce     uint64        `json:"nonce"`
+	Hash      string        `json:"hash"`
+	Timestamp time.Duration `json:"timestamp"`
+}
+
+// ResponseIndexingCheckpoints is the structure for the indexing checkpoints multi-get response
+type ResponseIndexingCheckpoints struct {
+	Docs []ResponseIndexingCheckpointDB `json:"docs"`
+}
+
+// ResponseIndexingCheckpointDB is the structure for the indexing checkpoint response
+type ResponseIndexingCheckpointDB struct {
+	Found  bool               `json:"found"`
+	ID     string             `json:"_id"`
+	Source IndexingCheckpoint `json:"_source"`
+}
@@ -0,0 +1,14 @@
+package data
+
+// DeadLetter is a structure containing the fields of a document that Elasticsearch rejected
+type DeadLetter struct {
+	ID        string `json:"-"`
+	Index     string `json:"index"`
+	DocID     string `json:"docID"`
+	Operation string `json:"operation"`
+	Status    int    `json:"status"`
+	Reason    string `json:"reason"`
+	ShardID   uint32 `json:"shardID"`
+	Nonce     uint64 `json:"nonce"`
+	Timestamp int64  `json:"timestamp"`
+}
@@ -7,17 +7,37 @@ import (
 )
 
 type metricsFacade struct {
-	statusMetrics core.StatusMetricsHandler
+	statusMetrics  core.StatusMetricsHandler
+	gapsHandler    core.GapsHandler
+	payloadHandler core.PayloadHandler
+	deadLetters    core.DeadLettersHandler
 }
 
 // NewMetricsFacade will create a new instance of metricsFacade
-func NewMetricsFacade(statusMetrics core.StatusMetricsHandler) (*metricsFacade, error) {
+func NewMetricsFacade(
+	statusMetrics core.StatusMetricsHandler,
+	gapsHandler core.GapsHandler,
+	payloadHandler core.PayloadHandler,
+	deadLetters core.DeadLettersHandler,
+) (*metricsFacade, error) {
 	if check.IfNil(statusMetrics) {
 		return nil, core.ErrNilMetricsHandler
 	}
+	if check.IfNil(gapsHandler) {
+		return nil, core.ErrNilGapsHandler
+	}
+	if check.IfNil(payloadHandler) {
+		return nil, core.ErrNilPayloadHandler
+	}
+	if check.IfNil(deadLetters) {
+		return nil, core.ErrNilDeadLettersHandler
+	}
 
 	return &metricsFacade{
-		statusMetrics: statusMetrics,
+		statusMetrics:  statusMetrics,
+		gapsHandler:    gapsHandler,
+		payloadHandler: payloadHandler,
+		deadLetters:    deadLetters,
 	}, nil
 }
 
@@ -31,6 +51,26 @@ func (mf *metricsFacade) GetMetricsForPrometheus() string {
 	return mf.statusMetrics.GetMetricsForPrometheus()
 }
 
+// GetGaps will return the gaps detected in the indexed blocks
+func (mf *metricsFacade) GetGaps() []*request.GapResponse {
+	return mf.gapsHandler.GetGaps()
+}
+
+// ProcessPayload will index the provided marshalled outport payload
+func (mf *metricsFacade) ProcessPayload(payload []byte, topic string, version uint32) error {
+	return mf.payloadHandler.ProcessPayload(payload, topic, version)
+}
+
+// GetDeadLetters will return the documents rejected by Elasticsearch
+func (mf *metricsFacade) GetDeadLetters() ([]*request.DeadLetterResponse, error) {
+	return mf.deadLetters.GetDeadLetters()
+}
+
+// ResubmitDeadLetters will index again the provided dead letters
+func (mf *metricsFacade) ResubmitDeadLetters(ids []string) (*request.ResubmitResponse, error) {
+	return mf.deadLetters.ResubmitDeadLetters(ids)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (mf *metricsFacade) IsInterfaceNil() bool {
 	return mf == nil
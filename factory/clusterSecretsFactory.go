@@ -0,0 +1,57 @@
+package factory
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/client/transport"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+)
+
+type clusterSecrets struct {
+	userName     string
+	password     string
+	apiKey       string
+	serviceToken string
+	tls          transport.ArgsHTTPTransport
+}
+
+type secretField struct {
+	name  string
+	value string
+	dest  *string
+}
+
+// resolveClusterSecrets reads the secrets of the cluster configuration, which can be written in clear, in files or in
+// environment variables
+func resolveClusterSecrets(elasticCluster config.ElasticClusterConfig) (*clusterSecrets, error) {
+	var caCert, clientCert, clientKey string
+	secrets := &clusterSecrets{
+		tls: transport.ArgsHTTPTransport{
+			CACertFingerprint:  elasticCluster.TLS.CACertFingerprint,
+			InsecureSkipVerify: elasticCluster.TLS.InsecureSkipVerify,
+		},
+	}
+	fields := []secretField{
+		{name: "username", value: elasticCluster.UserName, dest: &secrets.userName},
+		{name: "password", value: elasticCluster.Password, dest: &secrets.password},
+		{name: "api-key", value: elasticCluster.APIKey, dest: &secrets.apiKey},
+		{name: "service-token", value: elasticCluster.ServiceToken, dest: &secrets.serviceToken},
+		{name: "tls ca-cert", value: elasticCluster.TLS.CACert, dest: &caCert},
+		{name: "tls client-cert", value: elasticCluster.TLS.ClientCert, dest: &clientCert},
+		{name: "tls client-key", value: elasticCluster.TLS.ClientKey, dest: &clientKey},
+	}
+
+	for _, field := range fields {
+		secret, err := config.ResolveSecret(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("%w while reading the %s of the elastic cluster", err, field.name)
+		}
+		*field.dest = secret
+	}
+
+	secrets.tls.CACert = []byte(caCert)
+	secrets.tls.ClientCert = []byte(clientCert)
+	secrets.tls.ClientKey = []byte(clientKey)
+
+	return secrets, nil
+}
@@ -0,0 +1,22 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/factory"
+)
+
+// CreateDeadLettersHandler will create the component behind the dead letters API routes
+func CreateDeadLettersHandler(
+	cfg config.Config,
+	clusterCfg config.ClusterConfig,
+	statusMetrics core.StatusMetricsHandler,
+	version string,
+) (core.DeadLettersHandler, error) {
+	args, err := createIndexerFactoryArgs(cfg, clusterCfg, nil, statusMetrics, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory.CreateDeadLettersHandler(args)
+}
@@ -0,0 +1,21 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/gaps"
+)
+
+// GapsTracker defines what the gaps tracker created by this factory should be able to do
+type GapsTracker interface {
+	HasCheckpoint(shardID uint32) bool
+	SetCheckpoint(shardID uint32, nonce uint64, hash []byte)
+	ProcessBlock(shardID uint32, nonce uint64, hash []byte, prevHash []byte)
+	core.GapsHandler
+}
+
+// CreateGapsTracker will create a new instance of the component that detects gaps in the indexed blocks
+func CreateGapsTracker(statusMetrics core.StatusMetricsHandler) (GapsTracker, error) {
+	return gaps.NewGapsTracker(gaps.ArgsGapsTracker{
+		StatusMetrics: statusMetrics,
+	})
+}
@@ -0,0 +1,59 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/importer"
+)
+
+// CreateImporter will create a new instance of importer.Importer that indexes the marshalled outport files from the
+// provided directory. JSON and gogo protobuf files are supported, and both are saved through the same elastic processor
+func CreateImporter(
+	cfg config.Config,
+	clusterCfg config.ClusterConfig,
+	importDir string,
+	statusMetrics core.StatusMetricsHandler,
+	version string,
+) (importer.Importer, error) {
+	jsonMarshaller := &marshal.JsonMarshalizer{}
+	args, err := createIndexerFactoryArgs(cfg, clusterCfg, jsonMarshaller, statusMetrics, version)
+	if err != nil {
+		return nil, err
+	}
+
+	elasticProcessor, err := factory.NewElasticProcessor(args)
+	if err != nil {
+		return nil, err
+	}
+	sharedProcessor, err := factory.NewSharedElasticProcessor(elasticProcessor)
+	if err != nil {
+		return nil, err
+	}
+
+	// both data indexers index the same chain, so they share the gaps tracker
+	gapsTracker, err := CreateGapsTracker(statusMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonIndexer, err := factory.NewDataIndexer(jsonMarshaller, sharedProcessor.NewHandle(), gapsTracker, args.ShutdownTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	protoIndexer, err := factory.NewDataIndexer(&marshal.GogoProtoMarshalizer{}, sharedProcessor.NewHandle(), gapsTracker, args.ShutdownTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return importer.NewImporter(importer.ArgsImporter{
+		Directory: importDir,
+		Indexers: map[string]dataindexer.Indexer{
+			importer.JsonFileExtension:  jsonIndexer,
+			importer.ProtoFileExtension: protoIndexer,
+		},
+	})
+}
@@ -0,0 +1,41 @@
+package factory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/factory"
+)
+
+func createMirrorClustersArgs(clusterCfg config.ClusterConfig) ([]factory.ArgsMirrorCluster, error) {
+	mirrorClusters := make([]factory.ArgsMirrorCluster, 0, len(clusterCfg.Config.MirrorClusters))
+	for _, mirrorCfg := range clusterCfg.Config.MirrorClusters {
+		secrets, err := resolveClusterSecrets(mirrorCfg.ElasticCluster)
+		if err != nil {
+			return nil, fmt.Errorf("%w for the %s mirror cluster", err, mirrorCfg.Name)
+		}
+
+		elasticCluster := mirrorCfg.ElasticCluster
+		mirrorClusters = append(mirrorClusters, factory.ArgsMirrorCluster{
+			Name:                  mirrorCfg.Name,
+			Policy:                mirrorCfg.Policy,
+			AsyncQueueSize:        mirrorCfg.AsyncQueueSize,
+			Backend:               elasticCluster.Backend,
+			Urls:                  getElasticUrls(elasticCluster),
+			UserName:              secrets.userName,
+			Password:              secrets.password,
+			APIKey:                secrets.apiKey,
+			ServiceToken:          secrets.serviceToken,
+			SniffOnStart:          elasticCluster.SniffOnStart,
+			CompressRequests:      elasticCluster.CompressRequests,
+			BulkItemsMaxRetries:   elasticCluster.BulkItemsMaxRetries,
+			BulkItemsRetryBackOff: time.Duration(elasticCluster.BulkItemsRetryBackOffInMs) * time.Millisecond,
+			SniffInterval:         time.Duration(elasticCluster.SniffIntervalInSec) * time.Second,
+			RequestTimeouts:       getRequestTimeouts(elasticCluster),
+			TLS:                   secrets.tls,
+		})
+	}
+
+	return mirrorClusters, nil
+}
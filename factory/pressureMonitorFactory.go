@@ -0,0 +1,31 @@
+package factory
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/client/pressure"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+// CreatePressureMonitor will create a new instance of the component that tracks the pressure of the Elasticsearch
+// cluster and, if enabled, slows down the ingestion while the cluster is under pressure
+func CreatePressureMonitor(clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler) (dataindexer.PressureMonitorHandler, error) {
+	backpressureCfg := clusterCfg.Config.Backpressure
+	bulkRequestMaxSize := clusterCfg.Config.ElasticCluster.BulkRequestMaxSizeInBytes
+	if bulkRequestMaxSize == 0 {
+		bulkRequestMaxSize = data.DefaultMaxBulkSize
+	}
+
+	return pressure.NewPressureMonitor(pressure.ArgsPressureMonitor{
+		StatusMetrics:         statusMetrics,
+		WindowSize:            backpressureCfg.WindowSize,
+		HighLatencyThreshold:  time.Duration(backpressureCfg.HighLatencyThresholdInMs) * time.Millisecond,
+		AdaptIngestion:        backpressureCfg.Enabled,
+		MaxAckDelay:           time.Duration(backpressureCfg.MaxAckDelayInMs) * time.Millisecond,
+		BulkRequestMaxSize:    bulkRequestMaxSize,
+		MinBulkRequestMaxSize: backpressureCfg.MinBulkRequestMaxSizeInBytes,
+	})
+}
@@ -8,8 +8,14 @@ import (
 )
 
 // CreateWebServer will create a new instance of core.WebServerHandler
-func CreateWebServer(apiConfig config.ApiRoutesConfig, statusMetricsHandler core.StatusMetricsHandler) (core.WebServerHandler, error) {
-	metricsFacade, err := facade.NewMetricsFacade(statusMetricsHandler)
+func CreateWebServer(
+	apiConfig config.ApiRoutesConfig,
+	statusMetricsHandler core.StatusMetricsHandler,
+	gapsHandler core.GapsHandler,
+	payloadHandler core.PayloadHandler,
+	deadLettersHandler core.DeadLettersHandler,
+) (core.WebServerHandler, error) {
+	metricsFacade, err := facade.NewMetricsFacade(statusMetricsHandler, gapsHandler, payloadHandler, deadLettersHandler)
 	if err != nil {
 		return nil, err
 	}
@@ -1,96 +1,541 @@
 package factory
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
 	"github.com/multiversx/mx-chain-communication-go/websocket/data"
 	factoryHost "github.com/multiversx/mx-chain-communication-go/websocket/factory"
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
 	factoryHasher "github.com/multiversx/mx-chain-core-go/hashing/factory"
 	"github.com/multiversx/mx-chain-core-go/marshal"
 	factoryMarshaller "github.com/multiversx/mx-chain-core-go/marshal/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/client"
 	"github.com/multiversx/mx-chain-es-indexer-go/config"
 	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/queue"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
 var log = logger.GetOrCreate("elasticindexer")
 
-// CreateWsIndexer will create a new instance of wsindexer.WSClient
-func CreateWsIndexer(cfg config.Config, clusterCfg config.ClusterConfig, statusMetrics core.StatusMetricsHandler, version string) (wsindexer.WSClient, error) {
-	wsMarshaller, err := factoryMarshaller.NewMarshalizer(clusterCfg.Config.WebSocket.DataMarshallerType)
+var (
+	errNoWebSocketSources        = errors.New("no WebSocket source configured")
+	errEmptyWebSocketSourceName  = errors.New("empty WebSocket source name")
+	errDuplicatedWebSocketSource = errors.New("duplicated WebSocket source name")
+	errWebSocketSourceNotFound   = errors.New("WebSocket source not found")
+)
+
+// WsIndexers holds the components created for the WebSocket sources and for the ingestion endpoint
+type WsIndexers struct {
+	// Hosts holds the WebSocket host of every source, in the order in which the sources are configured
+	Hosts []wsindexer.WSClient
+	// PayloadHandlers holds the payload handler of every source. A host closes its payload handler only if a node is
+	// connected to it, so the payload handlers have to be closed after the hosts
+	PayloadHandlers []wsindexer.PayloadHandler
+	IngestHandler   wsindexer.PayloadHandler
+}
+
+// CreateWsIndexers will create an instance of wsindexer.WSClient for every configured WebSocket source, together with
+// the wsindexer.PayloadHandler of the ingestion endpoint. All the sources share the same elastic processor, while each
+// of them has its own data indexer, persistent queue and capture files, so the ordering of the payloads is preserved
+// per source. The gaps tracker and the pressure monitor of the Elasticsearch cluster are shared as well. The ingestion
+// endpoint reuses the data indexer of the first source with the same marshaller
+func CreateWsIndexers(
+	cfg config.Config,
+	clusterCfg config.ClusterConfig,
+	ingestCfg config.IngestConfig,
+	statusMetrics core.StatusMetricsHandler,
+	gapsTracker dataindexer.GapsTrackerHandler,
+	version string,
+) (*WsIndexers, error) {
+	err := checkWebSocketSources(clusterCfg.Config.WebSocket)
 	if err != nil {
 		return nil, err
 	}
 
-	dataIndexer, err := createDataIndexer(cfg, clusterCfg, wsMarshaller, statusMetrics, version)
+	args, err := createIndexerFactoryArgs(cfg, clusterCfg, nil, statusMetrics, version)
 	if err != nil {
 		return nil, err
 	}
 
-	args := wsindexer.ArgsIndexer{
-		Marshaller:    wsMarshaller,
-		DataIndexer:   dataIndexer,
-		StatusMetrics: statusMetrics,
+	elasticProcessor, err := factory.NewElasticProcessor(args)
+	if err != nil {
+		return nil, err
 	}
-	indexer, err := wsindexer.NewIndexer(args)
+	sharedProcessor, err := factory.NewSharedElasticProcessor(elasticProcessor)
 	if err != nil {
 		return nil, err
 	}
 
-	host, err := createWsHost(clusterCfg, wsMarshaller)
+	wsIndexers := &WsIndexers{
+		Hosts:           make([]wsindexer.WSClient, 0, len(clusterCfg.Config.WebSocket)),
+		PayloadHandlers: make([]wsindexer.PayloadHandler, 0, len(clusterCfg.Config.WebSocket)),
+	}
+	dataIndexers := make(map[string]wsindexer.DataIndexer)
+	for _, wsCfg := range clusterCfg.Config.WebSocket {
+		host, payloadHandler, dataIndexer, errCreate := createWsIndexer(wsCfg, clusterCfg, sharedProcessor.NewHandle(), gapsTracker, args.PressureMonitor, statusMetrics)
+		if errCreate != nil {
+			closeWsIndexers(wsIndexers)
+			return nil, fmt.Errorf("%w for the WebSocket source %s", errCreate, wsCfg.URL)
+		}
+
+		wsIndexers.Hosts = append(wsIndexers.Hosts, host)
+		wsIndexers.PayloadHandlers = append(wsIndexers.PayloadHandlers, payloadHandler)
+		_, found := dataIndexers[wsCfg.DataMarshallerType]
+		if !found {
+			dataIndexers[wsCfg.DataMarshallerType] = dataIndexer
+		}
+	}
+
+	wsIndexers.IngestHandler, err = createIngestPayloadHandler(ingestCfg, clusterCfg, sharedProcessor, dataIndexers[ingestCfg.DataMarshallerType], gapsTracker, args.PressureMonitor, statusMetrics)
+	if err != nil {
+		closeWsIndexers(wsIndexers)
+		return nil, fmt.Errorf("%w while creating the ingest payload handler", err)
+	}
+
+	return wsIndexers, nil
+}
+
+func checkWebSocketSources(sources []config.WebSocketConfig) error {
+	if len(sources) == 0 {
+		return errNoWebSocketSources
+	}
+	if len(sources) == 1 {
+		return nil
+	}
+
+	names := make(map[string]struct{}, len(sources))
+	for _, wsCfg := range sources {
+		if wsCfg.Name == "" {
+			return fmt.Errorf("%w for the WebSocket source %s", errEmptyWebSocketSourceName, wsCfg.URL)
+		}
+
+		_, found := names[wsCfg.Name]
+		if found {
+			return fmt.Errorf("%w: %s", errDuplicatedWebSocketSource, wsCfg.Name)
+		}
+		names[wsCfg.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+func closeWsIndexers(wsIndexers *WsIndexers) {
+	for _, host := range wsIndexers.Hosts {
+		log.LogIfError(host.Close())
+	}
+	for _, payloadHandler := range wsIndexers.PayloadHandlers {
+		log.LogIfError(payloadHandler.Close())
+	}
+}
+
+// GetWebSocketConfig returns the configuration of the WebSocket source with the provided name. An empty name selects
+// the first configured source
+func GetWebSocketConfig(clusterCfg config.ClusterConfig, name string) (config.WebSocketConfig, error) {
+	if len(clusterCfg.Config.WebSocket) == 0 {
+		return config.WebSocketConfig{}, errNoWebSocketSources
+	}
+	if name == "" {
+		return clusterCfg.Config.WebSocket[0], nil
+	}
+
+	for _, wsCfg := range clusterCfg.Config.WebSocket {
+		if wsCfg.Name == name {
+			return wsCfg, nil
+		}
+	}
+
+	return config.WebSocketConfig{}, fmt.Errorf("%w: %s", errWebSocketSourceNotFound, name)
+}
+
+// createWsIndexer creates the components of a WebSocket source. If one of them cannot be created, the ones already
+// created are closed, together with the provided elastic processor
+func createWsIndexer(
+	wsCfg config.WebSocketConfig,
+	clusterCfg config.ClusterConfig,
+	elasticProcessor dataindexer.ElasticProcessor,
+	gapsTracker dataindexer.GapsTrackerHandler,
+	pressureMonitor dataindexer.PressureMonitorHandler,
+	statusMetrics core.StatusMetricsHandler,
+) (host wsindexer.WSClient, payloadHandler wsindexer.PayloadHandler, dataIndexer wsindexer.DataIndexer, err error) {
+	// every component closes the ones it wraps, so only the outermost components created so far have to be closed
+	closers := []func() error{elasticProcessor.Close}
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, closeComponent := range closers {
+			log.LogIfError(closeComponent())
+		}
+	}()
+
+	wsMarshaller, err := factoryMarshaller.NewMarshalizer(wsCfg.DataMarshallerType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	dataIndexer, err = factory.NewDataIndexer(wsMarshaller, elasticProcessor, gapsTracker, getShutdownTimeout(clusterCfg))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closers = []func() error{dataIndexer.Close}
+
+	recorder, err := createPayloadRecorder(wsCfg, clusterCfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !check.IfNil(recorder) {
+		closers = append(closers, recorder.Close)
+	}
+
+	indexer, err := createIndexer(wsMarshaller, dataIndexer, statusMetrics, recorder, pressureMonitor)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closers = []func() error{indexer.Close}
+
+	payloadHandler, err = createPayloadHandler(wsCfg, clusterCfg, indexer, statusMetrics)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closers = []func() error{payloadHandler.Close}
+
+	wsHost, err := createWsHost(wsCfg, wsMarshaller)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closers = []func() error{wsHost.Close, payloadHandler.Close}
+
+	err = wsHost.SetPayloadHandler(payloadHandler)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return wsHost, payloadHandler, dataIndexer, nil
+}
+
+// CreatePayloadIndexer will create a new instance of wsindexer.PayloadHandler that indexes payloads marshalled with
+// the provided marshaller, without a WebSocket host in front of it
+func CreatePayloadIndexer(
+	cfg config.Config,
+	clusterCfg config.ClusterConfig,
+	payloadMarshaller marshal.Marshalizer,
+	statusMetrics core.StatusMetricsHandler,
+	gapsTracker dataindexer.GapsTrackerHandler,
+	version string,
+) (wsindexer.PayloadHandler, error) {
+	args, err := createIndexerFactoryArgs(cfg, clusterCfg, payloadMarshaller, statusMetrics, version)
 	if err != nil {
 		return nil, err
 	}
+	args.GapsTracker = gapsTracker
 
-	err = host.SetPayloadHandler(indexer)
+	dataIndexer, err := factory.NewIndexer(args)
 	if err != nil {
 		return nil, err
 	}
 
-	return host, nil
+	return createIndexer(payloadMarshaller, dataIndexer, statusMetrics, nil, args.PressureMonitor)
 }
 
-func createDataIndexer(
-	cfg config.Config,
+// createIngestPayloadHandler will create the wsindexer.PayloadHandler that indexes the payloads posted to the ingestion
+// endpoint. The provided data indexer is reused when it is not nil, otherwise a new one is created with a handle of the
+// shared elastic processor. A disabled payload handler is returned when no auth token is configured for the ingestion endpoint
+func createIngestPayloadHandler(
+	ingestCfg config.IngestConfig,
 	clusterCfg config.ClusterConfig,
+	sharedProcessor *factory.SharedElasticProcessor,
+	dataIndexer wsindexer.DataIndexer,
+	gapsTracker dataindexer.GapsTrackerHandler,
+	pressureMonitor dataindexer.PressureMonitorHandler,
+	statusMetrics core.StatusMetricsHandler,
+) (wsindexer.PayloadHandler, error) {
+	if ingestCfg.AuthToken == "" {
+		return wsindexer.NewDisabledPayloadHandler(), nil
+	}
+
+	payloadMarshaller, err := factoryMarshaller.NewMarshalizer(ingestCfg.DataMarshallerType)
+	if err != nil {
+		return nil, err
+	}
+
+	if check.IfNil(dataIndexer) {
+		dataIndexer, err = factory.NewDataIndexer(payloadMarshaller, sharedProcessor.NewHandle(), gapsTracker, getShutdownTimeout(clusterCfg))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info("payloads ingestion over HTTP is enabled", "data marshaller type", ingestCfg.DataMarshallerType)
+
+	return createIndexer(payloadMarshaller, dataIndexer, statusMetrics, nil, pressureMonitor)
+}
+
+func createIndexer(
 	wsMarshaller marshal.Marshalizer,
+	dataIndexer wsindexer.DataIndexer,
+	statusMetrics core.StatusMetricsHandler,
+	recorder wsindexer.PayloadRecorder,
+	ackDelay wsindexer.AckDelayProvider,
+) (wsindexer.PayloadHandler, error) {
+	decoders, err := wsindexer.NewPayloadDecoders(wsMarshaller)
+	if err != nil {
+		return nil, err
+	}
+
+	return wsindexer.NewIndexer(wsindexer.ArgsIndexer{
+		DataIndexer:   dataIndexer,
+		StatusMetrics: statusMetrics,
+		Recorder:      recorder,
+		Decoders:      decoders,
+		AckDelay:      ackDelay,
+	})
+}
+
+func createPayloadRecorder(wsCfg config.WebSocketConfig, clusterCfg config.ClusterConfig) (wsindexer.PayloadRecorder, error) {
+	captureCfg := clusterCfg.Config.Capture
+	if !captureCfg.Enabled {
+		return nil, nil
+	}
+
+	capturePath := getSourcePath(captureCfg.Path, wsCfg)
+	log.Info("payloads capture is enabled", "source", wsCfg.Name, "path", capturePath)
+
+	return wsindexer.NewPayloadRecorder(wsindexer.ArgsPayloadRecorder{
+		Directory:          capturePath,
+		MaxFileSizeInBytes: captureCfg.MaxFileSizeInBytes,
+		MaxNumFiles:        captureCfg.MaxNumFiles,
+	})
+}
+
+func createPayloadHandler(
+	wsCfg config.WebSocketConfig,
+	clusterCfg config.ClusterConfig,
+	indexer wsindexer.PayloadHandler,
+	statusMetrics core.StatusMetricsHandler,
+) (wsindexer.PayloadHandler, error) {
+	queueCfg := clusterCfg.Config.PersistentQueue
+	if !queueCfg.Enabled {
+		return indexer, nil
+	}
+
+	diskQueue, err := queue.NewDiskQueue(queue.ArgsDiskQueue{
+		Directory:             getSourcePath(queueCfg.Path, wsCfg),
+		MaxSegmentSizeInBytes: queueCfg.SegmentSizeInBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payloadHandler, err := queue.NewQueuedPayloadHandler(queue.ArgsQueuedPayloadHandler{
+		Queue:          diskQueue,
+		PayloadHandler: indexer,
+		StatusMetrics:  statusMetrics,
+		RetryDuration:  time.Duration(wsCfg.RetryDurationInSec) * time.Second,
+		SourceName:     wsCfg.Name,
+	})
+	if err != nil {
+		log.LogIfError(diskQueue.Close())
+		return nil, err
+	}
+
+	return payloadHandler, nil
+}
+
+// getSourcePath returns the subdirectory of the provided path that belongs to the WebSocket source
+func getSourcePath(path string, wsCfg config.WebSocketConfig) string {
+	if wsCfg.Name == "" {
+		return path
+	}
+
+	return filepath.Join(path, wsCfg.Name)
+}
+
+func createIndexerFactoryArgs(
+	cfg config.Config,
+	clusterCfg config.ClusterConfig,
+	headerMarshaller marshal.Marshalizer,
 	statusMetrics core.StatusMetricsHandler,
 	version string,
-) (wsindexer.DataIndexer, error) {
+) (factory.ArgsIndexerFactory, error) {
 	marshaller, err := factoryMarshaller.NewMarshalizer(cfg.Config.Marshaller.Type)
 	if err != nil {
-		return nil, err
+		return factory.ArgsIndexerFactory{}, err
 	}
 	hasher, err := factoryHasher.NewHasher(cfg.Config.Hasher.Type)
 	if err != nil {
-		return nil, err
+		return factory.ArgsIndexerFactory{}, err
 	}
 	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.Config.AddressConverter.Length, cfg.Config.AddressConverter.Prefix)
 	if err != nil {
-		return nil, err
+		return factory.ArgsIndexerFactory{}, err
 	}
 	validatorPubkeyConverter, err := pubkeyConverter.NewHexPubkeyConverter(cfg.Config.ValidatorKeysConverter.Length)
 	if err != nil {
-		return nil, err
+		return factory.ArgsIndexerFactory{}, err
+	}
+	pressureMonitor, err := CreatePressureMonitor(clusterCfg, statusMetrics)
+	if err != nil {
+		return factory.ArgsIndexerFactory{}, err
+	}
+	secrets, err := resolveClusterSecrets(clusterCfg.Config.ElasticCluster)
+	if err != nil {
+		return factory.ArgsIndexerFactory{}, err
+	}
+	mirrorClusters, err := createMirrorClustersArgs(clusterCfg)
+	if err != nil {
+		return factory.ArgsIndexerFactory{}, err
+	}
+	postgreSQL, err := createPostgreSQLArgs(clusterCfg)
+	if err != nil {
+		return factory.ArgsIndexerFactory{}, err
+	}
+	clickHouse, err := createClickHouseArgs(clusterCfg)
+	if err != nil {
+		return factory.ArgsIndexerFactory{}, err
 	}
 
-	return factory.NewIndexer(factory.ArgsIndexerFactory{
+	return factory.ArgsIndexerFactory{
 		UseKibana:                clusterCfg.Config.ElasticCluster.UseKibana,
+		Backend:                  clusterCfg.Config.ElasticCluster.Backend,
 		Denomination:             cfg.Config.Economics.Denomination,
 		BulkRequestMaxSize:       clusterCfg.Config.ElasticCluster.BulkRequestMaxSizeInBytes,
-		Url:                      clusterCfg.Config.ElasticCluster.URL,
-		UserName:                 clusterCfg.Config.ElasticCluster.UserName,
-		Password:                 clusterCfg.Config.ElasticCluster.Password,
+		NumBulkRequestWorkers:    clusterCfg.Config.ElasticCluster.NumBulkRequestWorkers,
+		BulkItemsMaxRetries:      clusterCfg.Config.ElasticCluster.BulkItemsMaxRetries,
+		BulkItemsRetryBackOff:    time.Duration(clusterCfg.Config.ElasticCluster.BulkItemsRetryBackOffInMs) * time.Millisecond,
+		DeadLettersEnabled:       clusterCfg.Config.DeadLetters.Enabled,
+		TokenCacheCapacity:       getTokenCacheCapacity(clusterCfg),
+		CompressRequests:         clusterCfg.Config.ElasticCluster.CompressRequests,
+		Urls:                     getElasticUrls(clusterCfg.Config.ElasticCluster),
+		SniffOnStart:             clusterCfg.Config.ElasticCluster.SniffOnStart,
+		SniffInterval:            time.Duration(clusterCfg.Config.ElasticCluster.SniffIntervalInSec) * time.Second,
+		SearchPageSize:           clusterCfg.Config.ElasticCluster.SearchPageSize,
+		SearchKeepAlive:          time.Duration(clusterCfg.Config.ElasticCluster.SearchKeepAliveInSec) * time.Second,
+		RequestTimeouts:          getRequestTimeouts(clusterCfg.Config.ElasticCluster),
+		UserName:                 secrets.userName,
+		Password:                 secrets.password,
+		APIKey:                   secrets.apiKey,
+		ServiceToken:             secrets.serviceToken,
+		TLS:                      secrets.tls,
+		MirrorClusters:           mirrorClusters,
+		PostgreSQL:               postgreSQL,
+		ClickHouse:               clickHouse,
+		FileSink:                 createFileSinkArgs(clusterCfg),
 		EnabledIndexes:           prepareIndices(cfg.Config.AvailableIndices, clusterCfg.Config.DisabledIndices),
 		Marshalizer:              marshaller,
 		Hasher:                   hasher,
 		AddressPubkeyConverter:   addressPubkeyConverter,
 		ValidatorPubkeyConverter: validatorPubkeyConverter,
-		HeaderMarshaller:         wsMarshaller,
+		HeaderMarshaller:         headerMarshaller,
 		StatusMetrics:            statusMetrics,
+		PressureMonitor:          pressureMonitor,
 		Version:                  version,
-	})
+		ShutdownTimeout:          getShutdownTimeout(clusterCfg),
+	}, nil
+}
+
+// createPostgreSQLArgs returns the settings of the PostgreSQL database. Its connection string can be written in clear,
+// in a file or in an environment variable
+func createPostgreSQLArgs(clusterCfg config.ClusterConfig) (factory.ArgsPostgreSQL, error) {
+	postgreSQLCfg := clusterCfg.Config.PostgreSQL
+	if !postgreSQLCfg.Enabled {
+		return factory.ArgsPostgreSQL{}, nil
+	}
+
+	dsn, err := config.ResolveSecret(postgreSQLCfg.DSN)
+	if err != nil {
+		return factory.ArgsPostgreSQL{}, fmt.Errorf("%w while reading the dsn of the postgresql database", err)
+	}
+
+	return factory.ArgsPostgreSQL{
+		Enabled:            true,
+		DSN:                dsn,
+		MaxOpenConnections: postgreSQLCfg.MaxOpenConnections,
+	}, nil
+}
+
+// createClickHouseArgs returns the settings of the ClickHouse server. Its password can be written in clear, in a file or
+// in an environment variable
+func createClickHouseArgs(clusterCfg config.ClusterConfig) (factory.ArgsClickHouse, error) {
+	clickHouseCfg := clusterCfg.Config.ClickHouse
+	if !clickHouseCfg.Enabled {
+		return factory.ArgsClickHouse{}, nil
+	}
+
+	password, err := config.ResolveSecret(clickHouseCfg.Password)
+	if err != nil {
+		return factory.ArgsClickHouse{}, fmt.Errorf("%w while reading the password of the clickhouse server", err)
+	}
+
+	return factory.ArgsClickHouse{
+		Enabled:        true,
+		URL:            clickHouseCfg.URL,
+		Database:       clickHouseCfg.Database,
+		UserName:       clickHouseCfg.UserName,
+		Password:       password,
+		MaxBatchSize:   clickHouseCfg.MaxBatchSize,
+		RequestTimeout: time.Duration(clickHouseCfg.RequestTimeoutInSec) * time.Second,
+	}, nil
+}
+
+// createFileSinkArgs returns the settings of the files that receive the requests instead of the cluster
+func createFileSinkArgs(clusterCfg config.ClusterConfig) factory.ArgsFileSink {
+	fileSinkCfg := clusterCfg.Config.FileSink
+	if !fileSinkCfg.Enabled {
+		return factory.ArgsFileSink{}
+	}
+
+	return factory.ArgsFileSink{
+		Enabled:            true,
+		Directory:          fileSinkCfg.Path,
+		MaxFileSizeInBytes: fileSinkCfg.MaxFileSizeInBytes,
+		ReadFromCluster:    fileSinkCfg.ReadFromElasticCluster,
+	}
+}
+
+// getTokenCacheCapacity returns the capacity of the token cache, or 0 if the token cache is disabled
+func getTokenCacheCapacity(clusterCfg config.ClusterConfig) int {
+	if !clusterCfg.Config.TokenCache.Enabled {
+		return 0
+	}
+
+	return clusterCfg.Config.TokenCache.Capacity
+}
+
+func getRequestTimeouts(elasticCluster config.ElasticClusterConfig) client.RequestTimeouts {
+	timeouts := elasticCluster.RequestTimeouts
+
+	return client.RequestTimeouts{
+		Bulk:          time.Duration(timeouts.BulkInSec) * time.Second,
+		Get:           time.Duration(timeouts.GetInSec) * time.Second,
+		Remove:        time.Duration(timeouts.RemoveInSec) * time.Second,
+		UpdateByQuery: time.Duration(timeouts.UpdateByQueryInSec) * time.Second,
+		Count:         time.Duration(timeouts.CountInSec) * time.Second,
+		Scroll:        time.Duration(timeouts.ScrollInSec) * time.Second,
+	}
+}
+
+func getShutdownTimeout(clusterCfg config.ClusterConfig) time.Duration {
+	return time.Duration(clusterCfg.Config.ShutdownTimeoutInSec) * time.Second
+}
+
+// getElasticUrls returns the addresses of the Elasticsearch nodes. The urls list takes precedence over the single url
+func getElasticUrls(elasticCluster config.ElasticClusterConfig) []string {
+	if len(elasticCluster.URLs) > 0 {
+		return elasticCluster.URLs
+	}
+	if elasticCluster.URL == "" {
+		return nil
+	}
+
+	return []string{elasticCluster.URL}
 }
 
 func prepareIndices(availableIndices, disabledIndices []string) []string {
@@ -112,15 +557,15 @@ func prepareIndices(availableIndices, disabledIndices []string) []string {
 	return indices
 }
 
-func createWsHost(clusterCfg config.ClusterConfig, wsMarshaller marshal.Marshalizer) (factoryHost.FullDuplexHost, error) {
+func createWsHost(wsCfg config.WebSocketConfig, wsMarshaller marshal.Marshalizer) (factoryHost.FullDuplexHost, error) {
 	return factoryHost.CreateWebSocketHost(factoryHost.ArgsWebSocketHost{
 		WebSocketConfig: data.WebSocketConfig{
-			URL:                     clusterCfg.Config.WebSocket.URL,
-			WithAcknowledge:         clusterCfg.Config.WebSocket.WithAcknowledge,
-			Mode:                    clusterCfg.Config.WebSocket.Mode,
-			RetryDurationInSec:      int(clusterCfg.Config.WebSocket.RetryDurationInSec),
-			AcknowledgeTimeoutInSec: int(clusterCfg.Config.WebSocket.AckTimeoutInSec),
-			BlockingAckOnError:      clusterCfg.Config.WebSocket.BlockingAckOnError,
+			URL:                     wsCfg.URL,
+			WithAcknowledge:         wsCfg.WithAcknowledge,
+			Mode:                    wsCfg.Mode,
+			RetryDurationInSec:      int(wsCfg.RetryDurationInSec),
+			AcknowledgeTimeoutInSec: int(wsCfg.AckTimeoutInSec),
+			BlockingAckOnError:      wsCfg.BlockingAckOnError,
 		},
 		Marshaller: wsMarshaller,
 		Log:        log,
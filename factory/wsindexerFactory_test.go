@@ -1,7 +1,17 @@
 package factory
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/factory"
 
 	"github.com/stretchr/testify/require"
 )
@@ -21,3 +31,313 @@ func TestPrepareIndices(t *testing.T) {
 	res = prepareIndices(available, disabled)
 	require.Equal(t, []string{"index1", "index2"}, res)
 }
+
+func TestGetElasticUrls(t *testing.T) {
+	t.Parallel()
+
+	elasticCluster := config.ElasticClusterConfig{}
+	require.Nil(t, getElasticUrls(elasticCluster))
+
+	elasticCluster.URL = "http://localhost:9200"
+	require.Equal(t, []string{"http://localhost:9200"}, getElasticUrls(elasticCluster))
+
+	elasticCluster.URLs = []string{"http://node1:9200", "http://node2:9200"}
+	require.Equal(t, []string{"http://node1:9200", "http://node2:9200"}, getElasticUrls(elasticCluster))
+}
+
+func TestCheckWebSocketSources(t *testing.T) {
+	t.Parallel()
+
+	err := checkWebSocketSources(nil)
+	require.Equal(t, errNoWebSocketSources, err)
+
+	err = checkWebSocketSources([]config.WebSocketConfig{{URL: "localhost:22111"}})
+	require.Nil(t, err)
+
+	err = checkWebSocketSources([]config.WebSocketConfig{{Name: "shard0", URL: "localhost:22111"}, {URL: "localhost:22112"}})
+	require.True(t, errors.Is(err, errEmptyWebSocketSourceName))
+
+	err = checkWebSocketSources([]config.WebSocketConfig{{Name: "shard0", URL: "localhost:22111"}, {Name: "shard0", URL: "localhost:22112"}})
+	require.True(t, errors.Is(err, errDuplicatedWebSocketSource))
+
+	err = checkWebSocketSources([]config.WebSocketConfig{{Name: "shard0", URL: "localhost:22111"}, {Name: "meta", URL: "localhost:22112"}})
+	require.Nil(t, err)
+}
+
+func TestGetWebSocketConfig(t *testing.T) {
+	t.Parallel()
+
+	clusterCfg := config.ClusterConfig{}
+	_, err := GetWebSocketConfig(clusterCfg, "")
+	require.Equal(t, errNoWebSocketSources, err)
+
+	clusterCfg.Config.WebSocket = []config.WebSocketConfig{{Name: "shard0", URL: "localhost:22111"}, {Name: "meta", URL: "localhost:22112"}}
+	wsCfg, err := GetWebSocketConfig(clusterCfg, "")
+	require.Nil(t, err)
+	require.Equal(t, "shard0", wsCfg.Name)
+
+	wsCfg, err = GetWebSocketConfig(clusterCfg, "meta")
+	require.Nil(t, err)
+	require.Equal(t, "localhost:22112", wsCfg.URL)
+
+	_, err = GetWebSocketConfig(clusterCfg, "shard1")
+	require.True(t, errors.Is(err, errWebSocketSourceNotFound))
+}
+
+func TestGetSourcePath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "db/queue", getSourcePath("db/queue", config.WebSocketConfig{}))
+	require.Equal(t, filepath.Join("db/queue", "meta"), getSourcePath("db/queue", config.WebSocketConfig{Name: "meta"}))
+}
+
+func TestCreateIngestPayloadHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty auth token should return a disabled payload handler", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := createIngestPayloadHandler(config.IngestConfig{}, config.ClusterConfig{}, nil, nil, nil, nil, nil)
+		require.Nil(t, err)
+		require.Equal(t, "*wsindexer.disabledPayloadHandler", fmt.Sprintf("%T", handler))
+		require.Nil(t, handler.ProcessPayload([]byte("payload"), "SaveBlock", 1))
+	})
+
+	t.Run("invalid marshaller type should error", func(t *testing.T) {
+		t.Parallel()
+
+		ingestCfg := config.IngestConfig{
+			AuthToken:          "token",
+			DataMarshallerType: "invalid",
+		}
+		handler, err := createIngestPayloadHandler(ingestCfg, config.ClusterConfig{}, nil, nil, nil, nil, nil)
+		require.Nil(t, handler)
+		require.NotNil(t, err)
+	})
+
+	t.Run("should reuse the provided data indexer", func(t *testing.T) {
+		t.Parallel()
+
+		closed := false
+		dataIndexer := &mock.IndexerStub{
+			CloseCalled: func() error {
+				closed = true
+				return nil
+			},
+		}
+		ingestCfg := config.IngestConfig{
+			AuthToken:          "token",
+			DataMarshallerType: "json",
+		}
+		handler, err := createIngestPayloadHandler(ingestCfg, config.ClusterConfig{}, nil, dataIndexer, nil, &mock.PressureMonitorStub{}, metrics.NewStatusMetrics())
+		require.Nil(t, err)
+		require.Nil(t, handler.Close())
+		require.True(t, closed)
+	})
+
+	t.Run("without a data indexer should create one with a handle of the shared elastic processor", func(t *testing.T) {
+		t.Parallel()
+
+		ingestCfg := config.IngestConfig{
+			AuthToken:          "token",
+			DataMarshallerType: "json",
+		}
+		closed := false
+		sharedProcessor, _ := factory.NewSharedElasticProcessor(&mock.ElasticProcessorStub{
+			CloseCalled: func() error {
+				closed = true
+				return nil
+			},
+		})
+		handler, err := createIngestPayloadHandler(ingestCfg, config.ClusterConfig{}, sharedProcessor, nil, &mock.GapsTrackerStub{}, &mock.PressureMonitorStub{}, metrics.NewStatusMetrics())
+		require.Nil(t, err)
+		require.Equal(t, "*wsindexer.indexer", fmt.Sprintf("%T", handler))
+		require.Nil(t, handler.Close())
+		require.True(t, closed)
+	})
+}
+
+func TestCreateWsIndexer_ShouldCloseTheCreatedComponentsOnError(t *testing.T) {
+	t.Parallel()
+
+	createElasticProcessor := func(closed *bool) *mock.ElasticProcessorStub {
+		return &mock.ElasticProcessorStub{
+			CloseCalled: func() error {
+				*closed = true
+				return nil
+			},
+		}
+	}
+
+	t.Run("invalid marshaller type should close the elastic processor", func(t *testing.T) {
+		t.Parallel()
+
+		closed := false
+		wsCfg := config.WebSocketConfig{
+			DataMarshallerType: "invalid",
+		}
+		host, payloadHandler, dataIndexer, err := createWsIndexer(wsCfg, config.ClusterConfig{}, createElasticProcessor(&closed), &mock.GapsTrackerStub{}, &mock.PressureMonitorStub{}, metrics.NewStatusMetrics())
+		require.NotNil(t, err)
+		require.Nil(t, host)
+		require.Nil(t, payloadHandler)
+		require.Nil(t, dataIndexer)
+		require.True(t, closed)
+	})
+
+	t.Run("invalid host mode should close the payload handler", func(t *testing.T) {
+		t.Parallel()
+
+		closed := false
+		wsCfg := config.WebSocketConfig{
+			Name:               "meta",
+			Mode:               "invalid",
+			DataMarshallerType: "json",
+		}
+		clusterCfg := config.ClusterConfig{}
+		clusterCfg.Config.PersistentQueue.Enabled = true
+		clusterCfg.Config.PersistentQueue.Path = t.TempDir()
+		clusterCfg.Config.PersistentQueue.SegmentSizeInBytes = 1024
+		clusterCfg.Config.Capture.Enabled = true
+		clusterCfg.Config.Capture.Path = t.TempDir()
+		clusterCfg.Config.Capture.MaxFileSizeInBytes = 1024
+
+		host, payloadHandler, dataIndexer, err := createWsIndexer(wsCfg, clusterCfg, createElasticProcessor(&closed), &mock.GapsTrackerStub{}, &mock.PressureMonitorStub{}, metrics.NewStatusMetrics())
+		require.NotNil(t, err)
+		require.Nil(t, host)
+		require.Nil(t, payloadHandler)
+		require.Nil(t, dataIndexer)
+		require.True(t, closed)
+	})
+}
+
+func TestResolveClusterSecrets(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "password")
+	err := os.WriteFile(secretPath, []byte("file-password\n"), 0600)
+	require.Nil(t, err)
+	t.Setenv("INDEXER_TEST_CA_CERT", "ca-cert")
+
+	elasticCluster := config.ElasticClusterConfig{}
+	elasticCluster.UserName = "user"
+	elasticCluster.Password = "file:" + secretPath
+	elasticCluster.TLS.CACert = "env:INDEXER_TEST_CA_CERT"
+	elasticCluster.TLS.InsecureSkipVerify = true
+
+	secrets, err := resolveClusterSecrets(elasticCluster)
+	require.Nil(t, err)
+	require.Equal(t, "user", secrets.userName)
+	require.Equal(t, "file-password", secrets.password)
+	require.Equal(t, []byte("ca-cert"), secrets.tls.CACert)
+	require.True(t, secrets.tls.InsecureSkipVerify)
+
+	elasticCluster.APIKey = "env:INDEXER_TEST_MISSING_API_KEY"
+	secrets, err = resolveClusterSecrets(elasticCluster)
+	require.Nil(t, secrets)
+	require.ErrorContains(t, err, "api-key")
+}
+
+func TestCreateMirrorClustersArgs(t *testing.T) {
+	t.Parallel()
+
+	clusterCfg := config.ClusterConfig{}
+	mirrorClusters, err := createMirrorClustersArgs(clusterCfg)
+	require.Nil(t, err)
+	require.Empty(t, mirrorClusters)
+
+	mirrorCfg := config.MirrorClusterConfig{
+		Name:           "standby",
+		Policy:         "async",
+		AsyncQueueSize: 10,
+	}
+	mirrorCfg.ElasticCluster.URL = "http://localhost:9201"
+	mirrorCfg.ElasticCluster.UserName = "user"
+	mirrorCfg.ElasticCluster.BulkItemsRetryBackOffInMs = 500
+	clusterCfg.Config.MirrorClusters = []config.MirrorClusterConfig{mirrorCfg}
+
+	mirrorClusters, err = createMirrorClustersArgs(clusterCfg)
+	require.Nil(t, err)
+	require.Len(t, mirrorClusters, 1)
+	require.Equal(t, "standby", mirrorClusters[0].Name)
+	require.Equal(t, "async", mirrorClusters[0].Policy)
+	require.Equal(t, 10, mirrorClusters[0].AsyncQueueSize)
+	require.Equal(t, []string{"http://localhost:9201"}, mirrorClusters[0].Urls)
+	require.Equal(t, "user", mirrorClusters[0].UserName)
+	require.Equal(t, 500*time.Millisecond, mirrorClusters[0].BulkItemsRetryBackOff)
+
+	clusterCfg.Config.MirrorClusters[0].ElasticCluster.Password = "env:INDEXER_TEST_MISSING_MIRROR_PASSWORD"
+	mirrorClusters, err = createMirrorClustersArgs(clusterCfg)
+	require.Nil(t, mirrorClusters)
+	require.ErrorContains(t, err, "standby")
+}
+
+func TestCreatePostgreSQLArgs(t *testing.T) {
+	t.Setenv("INDEXER_TEST_POSTGRESQL_DSN", "postgres://localhost:5432/indexer")
+
+	clusterCfg := config.ClusterConfig{}
+	clusterCfg.Config.PostgreSQL.DSN = "env:INDEXER_TEST_POSTGRESQL_DSN"
+	postgreSQL, err := createPostgreSQLArgs(clusterCfg)
+	require.Nil(t, err)
+	require.False(t, postgreSQL.Enabled)
+	require.Empty(t, postgreSQL.DSN)
+
+	clusterCfg.Config.PostgreSQL.Enabled = true
+	clusterCfg.Config.PostgreSQL.MaxOpenConnections = 5
+	postgreSQL, err = createPostgreSQLArgs(clusterCfg)
+	require.Nil(t, err)
+	require.True(t, postgreSQL.Enabled)
+	require.Equal(t, "postgres://localhost:5432/indexer", postgreSQL.DSN)
+	require.Equal(t, 5, postgreSQL.MaxOpenConnections)
+
+	clusterCfg.Config.PostgreSQL.DSN = "env:INDEXER_TEST_MISSING_POSTGRESQL_DSN"
+	_, err = createPostgreSQLArgs(clusterCfg)
+	require.ErrorContains(t, err, "postgresql")
+}
+
+func TestCreateClickHouseArgs(t *testing.T) {
+	t.Setenv("INDEXER_TEST_CLICKHOUSE_PASSWORD", "pass")
+
+	clusterCfg := config.ClusterConfig{}
+	clusterCfg.Config.ClickHouse.URL = "http://localhost:8123"
+	clickHouse, err := createClickHouseArgs(clusterCfg)
+	require.Nil(t, err)
+	require.Equal(t, factory.ArgsClickHouse{}, clickHouse)
+
+	clusterCfg.Config.ClickHouse.Enabled = true
+	clusterCfg.Config.ClickHouse.Database = "indexer"
+	clusterCfg.Config.ClickHouse.UserName = "user"
+	clusterCfg.Config.ClickHouse.Password = "env:INDEXER_TEST_CLICKHOUSE_PASSWORD"
+	clusterCfg.Config.ClickHouse.MaxBatchSize = 1000
+	clusterCfg.Config.ClickHouse.RequestTimeoutInSec = 30
+	clickHouse, err = createClickHouseArgs(clusterCfg)
+	require.Nil(t, err)
+	require.Equal(t, factory.ArgsClickHouse{
+		Enabled:        true,
+		URL:            "http://localhost:8123",
+		Database:       "indexer",
+		UserName:       "user",
+		Password:       "pass",
+		MaxBatchSize:   1000,
+		RequestTimeout: 30 * time.Second,
+	}, clickHouse)
+
+	clusterCfg.Config.ClickHouse.Password = "env:INDEXER_TEST_MISSING_CLICKHOUSE_PASSWORD"
+	_, err = createClickHouseArgs(clusterCfg)
+	require.ErrorContains(t, err, "clickhouse")
+}
+
+func TestCreateFileSinkArgs(t *testing.T) {
+	t.Parallel()
+
+	clusterCfg := config.ClusterConfig{}
+	clusterCfg.Config.FileSink.Path = "db/file-sink"
+	require.Equal(t, factory.ArgsFileSink{}, createFileSinkArgs(clusterCfg))
+
+	clusterCfg.Config.FileSink.Enabled = true
+	clusterCfg.Config.FileSink.MaxFileSizeInBytes = 1024
+	clusterCfg.Config.FileSink.ReadFromElasticCluster = true
+	require.Equal(t, factory.ArgsFileSink{
+		Enabled:            true,
+		Directory:          "db/file-sink",
+		MaxFileSizeInBytes: 1024,
+		ReadFromCluster:    true,
+	}, createFileSinkArgs(clusterCfg))
+}
@@ -81,7 +81,7 @@ func TestAccountsESDTDeleteOnRollback(t *testing.T) {
 		ShardID:   2,
 	}
 
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, coreAlteredAccounts, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, coreAlteredAccounts, testNumOfShards))
 	require.Nil(t, err)
 
 	ids := []string{fmt.Sprintf("%s-TOKEN-eeee-02", addr)}
@@ -91,7 +91,7 @@ func TestAccountsESDTDeleteOnRollback(t *testing.T) {
 	require.JSONEq(t, readExpectedResult("./testdata/accountsESDTRollback/account-after-create.json"), string(genericResponse.Docs[0].Source))
 
 	// DO ROLLBACK
-	err = esProc.RemoveAccountsESDT(5040, 2)
+	err = esProc.RemoveAccountsESDT(context.Background(), 5040, 2)
 	require.Nil(t, err)
 
 	err = esClient.DoMultiGet(context.Background(), ids, indexerdata.AccountsESDTIndex, true, genericResponse)
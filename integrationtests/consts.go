@@ -7,4 +7,6 @@ const (
 	esURL = "http://localhost:9200"
 	//nolint
 	addressPrefix = "erd"
+	// backendEnvVariable selects the backend the tests run against, elasticsearch if not set
+	backendEnvVariable = "INDEXER_BACKEND"
 )
@@ -60,7 +60,7 @@ func TestDelegateUnDelegateAndWithdraw(t *testing.T) {
 		},
 	}
 
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
 	require.Nil(t, err)
 
 	ids := []string{"9v/pLAXxUZJ4Oy1U+x5al/Xg5sebh1dYCRTeZwg/u68="}
@@ -92,7 +92,7 @@ func TestDelegateUnDelegateAndWithdraw(t *testing.T) {
 	}
 
 	header.TimeStamp = 5050
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
 	require.Nil(t, err)
 
 	err = esClient.DoMultiGet(context.Background(), ids, indexerdata.DelegatorsIndex, true, genericResponse)
@@ -122,7 +122,7 @@ func TestDelegateUnDelegateAndWithdraw(t *testing.T) {
 	}
 
 	header.TimeStamp = 5060
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
 	require.Nil(t, err)
 
 	err = esClient.DoMultiGet(context.Background(), ids, indexerdata.DelegatorsIndex, true, genericResponse)
@@ -132,7 +132,7 @@ func TestDelegateUnDelegateAndWithdraw(t *testing.T) {
 
 	// revert unDelegate 2
 	header.TimeStamp = 5060
-	err = esProc.RemoveTransactions(header, body)
+	err = esProc.RemoveTransactions(context.Background(), header, body)
 	require.Nil(t, err)
 
 	time.Sleep(time.Second)
@@ -162,7 +162,7 @@ func TestDelegateUnDelegateAndWithdraw(t *testing.T) {
 	}
 
 	header.TimeStamp = 5070
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
 	require.Nil(t, err)
 
 	err = esClient.DoMultiGet(context.Background(), ids, indexerdata.DelegatorsIndex, true, genericResponse)
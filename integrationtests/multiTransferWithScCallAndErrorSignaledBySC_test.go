@@ -92,7 +92,7 @@ func TestMultiTransferCrossShardAndScCallErrorSignaledBySC(t *testing.T) {
 			hex.EncodeToString(scrHash1): {SmartContractResult: scr1, FeeInfo: &outport.FeeInfo{}},
 		},
 	}
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
 	require.Nil(t, err)
 	ids := []string{hex.EncodeToString(txHash)}
 	genericResponse := &GenericResponse{}
@@ -160,7 +160,7 @@ func TestMultiTransferCrossShardAndScCallErrorSignaledBySC(t *testing.T) {
 		},
 	}
 
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
 	require.Nil(t, err)
 
 	ids = []string{hex.EncodeToString(txHash)}
@@ -64,7 +64,7 @@ func TestElasticIndexerSaveTransactions(t *testing.T) {
 			hex.EncodeToString(txHash): txInfo,
 		},
 	}
-	err = esProc.SaveTransactions(createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
+	err = esProc.SaveTransactions(context.Background(), createOutportBlockWithHeader(body, header, pool, nil, testNumOfShards))
 	require.Nil(t, err)
 
 	ids := []string{hex.EncodeToString(txHash)}
@@ -10,6 +10,7 @@ import (
 	"path"
 
 	"github.com/elastic/go-elasticsearch/v7"
+	elasticsearch8 "github.com/elastic/go-elasticsearch/v8"
 	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
 	"github.com/multiversx/mx-chain-es-indexer-go/client"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
@@ -32,9 +33,21 @@ func setLogLevelDebug() {
 
 // nolint
 func createESClient(url string) (elasticproc.DatabaseClientHandler, error) {
-	return client.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{url},
-		Logger:    &logging.CustomLogger{},
+	if os.Getenv(backendEnvVariable) == dataindexer.Elasticsearch8Backend {
+		return client.NewElasticClient8(client.ArgsElasticClient8{
+			Config: elasticsearch8.Config{
+				Addresses: []string{url},
+				Logger:    &logging.CustomLogger{},
+			},
+		})
+	}
+
+	return client.NewElasticClient(client.ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses: []string{url},
+			Logger:    &logging.CustomLogger{},
+		},
+		Backend: os.Getenv(backendEnvVariable),
 	})
 }
 
@@ -56,6 +69,7 @@ func CreateElasticProcessor(
 		AddressPubkeyConverter:   pubKeyConverter,
 		ValidatorPubkeyConverter: mock.NewPubkeyConverterMock(32),
 		DBClient:                 esClient,
+		Backend:                  os.Getenv(backendEnvVariable),
 		EnabledIndexes: []string{dataindexer.TransactionsIndex, dataindexer.LogsIndex, dataindexer.AccountsESDTIndex, dataindexer.ScResultsIndex,
 			dataindexer.ReceiptsIndex, dataindexer.BlockIndex, dataindexer.AccountsIndex, dataindexer.TokensIndex, dataindexer.TagsIndex, dataindexer.EventsIndex,
 			dataindexer.OperationsIndex, dataindexer.DelegatorsIndex, dataindexer.ESDTsIndex, dataindexer.SCDeploysIndex, dataindexer.MiniblocksIndex, dataindexer.ValuesIndex},
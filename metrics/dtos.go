@@ -6,7 +6,9 @@ import "time"
 type ArgsAddIndexingData struct {
 	StatusCode int
 	GotError   bool
+	GotTimeout bool
 	MessageLen uint64
+	GzipLen    uint64
 	Topic      string
 	Duration   time.Duration
 }
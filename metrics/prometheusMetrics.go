@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strconv"
 
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"google.golang.org/protobuf/proto"
@@ -75,6 +76,36 @@ func errorsMetric(metricName, operation string, shardIDStr string, errorsCount m
 	return promMetricAsString(metricFamily)
 }
 
+func gaugeMetric(metricName string, shardIDStr string, labels []request.Label, value uint64) string {
+	labelPairs := []*dto.LabelPair{
+		{
+			Name:  proto.String(shardIDName),
+			Value: proto.String(shardIDStr),
+		},
+	}
+	for _, label := range labels {
+		labelPairs = append(labelPairs, &dto.LabelPair{
+			Name:  proto.String(label.Name),
+			Value: proto.String(label.Value),
+		})
+	}
+
+	metricFamily := &dto.MetricFamily{
+		Name: proto.String(metricName),
+		Type: dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{
+			{
+				Label: labelPairs,
+				Gauge: &dto.Gauge{
+					Value: proto.Float64(float64(value)),
+				},
+			},
+		},
+	}
+
+	return promMetricAsString(metricFamily)
+}
+
 func promMetricAsString(metric *dto.MetricFamily) string {
 	out := bytes.NewBuffer(make([]byte, 0))
 	_, err := expfmt.MetricFamilyToText(out, metric)
@@ -15,11 +15,14 @@ const (
 	errorsCount    = "errors_count"
 	totalTime      = "total_time"
 	totalData      = "total_data"
+	compressedData = "total_compressed_data"
 	requestsErrors = "requests_errors"
+	timeoutsCount  = "timeouts_count"
 )
 
 type statusMetrics struct {
 	metrics map[string]*request.MetricsResponse
+	gauges  map[string]uint64
 	mut     sync.RWMutex
 }
 
@@ -27,6 +30,7 @@ type statusMetrics struct {
 func NewStatusMetrics() *statusMetrics {
 	return &statusMetrics{
 		metrics: make(map[string]*request.MetricsResponse),
+		gauges:  make(map[string]uint64),
 	}
 }
 
@@ -46,16 +50,32 @@ func (sm *statusMetrics) AddIndexingData(args ArgsAddIndexingData) {
 	sm.metrics[topic].OperationsCount++
 	sm.metrics[topic].TotalIndexingTime += args.Duration
 	sm.metrics[topic].TotalData += args.MessageLen
+	sm.metrics[topic].CompressedData += args.GzipLen
 
 	isErrorCode := args.StatusCode >= http.StatusBadRequest
-	if args.GotError || isErrorCode {
+	if args.GotError || args.GotTimeout || isErrorCode {
 		sm.metrics[topic].TotalErrorsCount++
 	}
+	if args.GotTimeout {
+		sm.metrics[topic].TimeoutsCount++
+	}
 	if isErrorCode {
 		sm.metrics[topic].ErrorsCount[args.StatusCode]++
 	}
 }
 
+// SetGauge will set the current value of the gauge metric with the provided topic. The labels added to the topic with
+// request.ExtendTopicWithLabel are exported as labels of the metric
+func (sm *statusMetrics) SetGauge(topic string, value uint64) {
+	topicWithoutLabels, _ := request.SplitTopicAndLabels(topic)
+	gaugeKey := camelToSnake(topicWithoutLabels) + strings.TrimPrefix(topic, topicWithoutLabels)
+
+	sm.mut.Lock()
+	defer sm.mut.Unlock()
+
+	sm.gauges[gaugeKey] = value
+}
+
 // GetMetrics returns the metrics map
 func (sm *statusMetrics) GetMetrics() map[string]*request.MetricsResponse {
 	sm.mut.RLock()
@@ -68,6 +88,7 @@ func (sm *statusMetrics) GetMetrics() map[string]*request.MetricsResponse {
 func (sm *statusMetrics) GetMetricsForPrometheus() string {
 	sm.mut.RLock()
 	metrics := sm.getAllUnprotected()
+	gauges := sm.getAllGaugesUnprotected()
 	sm.mut.RUnlock()
 
 	stringBuilder := strings.Builder{}
@@ -75,12 +96,24 @@ func (sm *statusMetrics) GetMetricsForPrometheus() string {
 	for topicWithShardID, metricsData := range metrics {
 		topic, shardIDStr := request.SplitTopicAndShardID(topicWithShardID)
 		stringBuilder.WriteString(counterMetric(topic, totalData, shardIDStr, metricsData.TotalData))
+		if metricsData.CompressedData > 0 {
+			stringBuilder.WriteString(counterMetric(topic, compressedData, shardIDStr, metricsData.CompressedData))
+		}
 		stringBuilder.WriteString(counterMetric(topic, errorsCount, shardIDStr, metricsData.TotalErrorsCount))
+		if metricsData.TimeoutsCount > 0 {
+			stringBuilder.WriteString(counterMetric(topic, timeoutsCount, shardIDStr, metricsData.TimeoutsCount))
+		}
 		stringBuilder.WriteString(counterMetric(topic, operationCount, shardIDStr, metricsData.OperationsCount))
 		stringBuilder.WriteString(counterMetric(topic, totalTime, shardIDStr, uint64(metricsData.TotalIndexingTime.Milliseconds())))
 		stringBuilder.WriteString(errorsMetric(topic, requestsErrors, shardIDStr, metricsData.ErrorsCount))
 	}
 
+	for topicWithLabels, value := range gauges {
+		topicWithShardID, labels := request.SplitTopicAndLabels(topicWithLabels)
+		topic, shardIDStr := request.SplitTopicAndShardID(topicWithShardID)
+		stringBuilder.WriteString(gaugeMetric(topic, shardIDStr, labels, value))
+	}
+
 	promMetricsOutput := stringBuilder.String()
 
 	return promMetricsOutput
@@ -95,6 +128,15 @@ func (sm *statusMetrics) getAllUnprotected() map[string]*request.MetricsResponse
 	return newMap
 }
 
+func (sm *statusMetrics) getAllGaugesUnprotected() map[string]uint64 {
+	newMap := make(map[string]uint64)
+	for key, value := range sm.gauges {
+		newMap[key] = value
+	}
+
+	return newMap
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (sm *statusMetrics) IsInterfaceNil() bool {
 	return sm == nil
@@ -69,3 +69,77 @@ func TestCamelCaseToSnakeCase(t *testing.T) {
 	require.Equal(t, "one_one_one", camelToSnake("One_One_One"))
 	require.Equal(t, "req_block", camelToSnake("req_block"))
 }
+
+func TestStatusMetrics_SetGauge(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.SetGauge("queueDepth", 10)
+	statusMetricsHandler.SetGauge("queueDepth", 7)
+
+	prometheusMetrics := statusMetricsHandler.GetMetricsForPrometheus()
+	require.Equal(t, `# TYPE queue_depth gauge
+queue_depth{shardID="#"} 7
+
+`, prometheusMetrics)
+}
+
+func TestStatusMetrics_SetGaugeWithLabels(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.SetGauge(request.ExtendTopicWithLabel("queueDepth", "source", "Shard-0"), 3)
+
+	prometheusMetrics := statusMetricsHandler.GetMetricsForPrometheus()
+	require.Equal(t, `# TYPE queue_depth gauge
+queue_depth{shardID="#",source="Shard-0"} 3
+
+`, prometheusMetrics)
+}
+
+func TestStatusMetrics_AddIndexingDataCompressed(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.AddIndexingData(ArgsAddIndexingData{
+		MessageLen: 300,
+		GzipLen:    40,
+		Topic:      "test_1",
+	})
+	statusMetricsHandler.AddIndexingData(ArgsAddIndexingData{
+		MessageLen: 100,
+		GzipLen:    20,
+		Topic:      "test_1",
+	})
+
+	metrics := statusMetricsHandler.GetMetrics()
+	require.Equal(t, uint64(400), metrics["test_1"].TotalData)
+	require.Equal(t, uint64(60), metrics["test_1"].CompressedData)
+
+	prometheusMetrics := statusMetricsHandler.GetMetricsForPrometheus()
+	require.Contains(t, prometheusMetrics, `test{operation="total_data",shardID="1"} 400`)
+	require.Contains(t, prometheusMetrics, `test{operation="total_compressed_data",shardID="1"} 60`)
+}
+
+func TestStatusMetrics_AddIndexingDataWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.AddIndexingData(ArgsAddIndexingData{
+		GotError:   true,
+		GotTimeout: true,
+		Topic:      "test_1",
+	})
+	statusMetricsHandler.AddIndexingData(ArgsAddIndexingData{
+		GotError: true,
+		Topic:    "test_1",
+	})
+
+	metrics := statusMetricsHandler.GetMetrics()
+	require.Equal(t, uint64(2), metrics["test_1"].TotalErrorsCount)
+	require.Equal(t, uint64(1), metrics["test_1"].TimeoutsCount)
+
+	prometheusMetrics := statusMetricsHandler.GetMetricsForPrometheus()
+	require.Contains(t, prometheusMetrics, `test{operation="errors_count",shardID="1"} 2`)
+	require.Contains(t, prometheusMetrics, `test{operation="timeouts_count",shardID="1"} 1`)
+}
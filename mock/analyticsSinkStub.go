@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+// AnalyticsSinkStub -
+type AnalyticsSinkStub struct {
+	SaveBlockResultsCalled func(headerHash []byte, header coreData.HeaderHandler, preparedResults *data.PreparedResults, logsData *data.PreparedLogsResults) error
+	RevertBlockCalled      func(headerHash []byte) error
+}
+
+// SaveBlockResults -
+func (ass *AnalyticsSinkStub) SaveBlockResults(_ context.Context, headerHash []byte, header coreData.HeaderHandler, preparedResults *data.PreparedResults, logsData *data.PreparedLogsResults) error {
+	if ass.SaveBlockResultsCalled != nil {
+		return ass.SaveBlockResultsCalled(headerHash, header, preparedResults, logsData)
+	}
+
+	return nil
+}
+
+// RevertBlock -
+func (ass *AnalyticsSinkStub) RevertBlock(_ context.Context, headerHash []byte) error {
+	if ass.RevertBlockCalled != nil {
+		return ass.RevertBlockCalled(headerHash)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (ass *AnalyticsSinkStub) IsInterfaceNil() bool {
+	return ass == nil
+}
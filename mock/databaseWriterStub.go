@@ -7,20 +7,30 @@ import (
 
 // DatabaseWriterStub -
 type DatabaseWriterStub struct {
-	DoBulkRequestCalled       func(buff *bytes.Buffer, index string) error
-	DoQueryRemoveCalled       func(index string, body *bytes.Buffer) error
-	DoMultiGetCalled          func(ids []string, index string, withSource bool, response interface{}) error
-	CheckAndCreateIndexCalled func(index string) error
-	DoScrollRequestCalled     func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	DoBulkRequestCalled        func(buff *bytes.Buffer, index string) error
+	DoQueryRemoveCalled        func(index string, body *bytes.Buffer) error
+	DoMultiGetCalled           func(ids []string, index string, withSource bool, response interface{}) error
+	CheckAndCreateIndexCalled  func(index string) error
+	DoScrollRequestCalled      func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	UpdateByQueryCalled        func(index string, buff *bytes.Buffer) error
+	CheckAndCreatePolicyCalled func(policyName string, policy *bytes.Buffer) error
+	DoCountRequestCalled       func(ctx context.Context, index string, body []byte) (uint64, error)
+	CloseCalled                func() error
 }
 
 // UpdateByQuery -
-func (dwm *DatabaseWriterStub) UpdateByQuery(_ context.Context, _ string, _ *bytes.Buffer) error {
+func (dwm *DatabaseWriterStub) UpdateByQuery(_ context.Context, index string, buff *bytes.Buffer) error {
+	if dwm.UpdateByQueryCalled != nil {
+		return dwm.UpdateByQueryCalled(index, buff)
+	}
 	return nil
 }
 
 // DoCountRequest -
-func (dwm *DatabaseWriterStub) DoCountRequest(_ context.Context, _ string, _ []byte) (uint64, error) {
+func (dwm *DatabaseWriterStub) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
+	if dwm.DoCountRequestCalled != nil {
+		return dwm.DoCountRequestCalled(ctx, index, body)
+	}
 	return 0, nil
 }
 
@@ -77,7 +87,18 @@ func (dwm *DatabaseWriterStub) CheckAndCreateTemplate(_ string, _ *bytes.Buffer)
 }
 
 // CheckAndCreatePolicy -
-func (dwm *DatabaseWriterStub) CheckAndCreatePolicy(_ string, _ *bytes.Buffer) error {
+func (dwm *DatabaseWriterStub) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	if dwm.CheckAndCreatePolicyCalled != nil {
+		return dwm.CheckAndCreatePolicyCalled(policyName, policy)
+	}
+	return nil
+}
+
+// Close -
+func (dwm *DatabaseWriterStub) Close() error {
+	if dwm.CloseCalled != nil {
+		return dwm.CloseCalled()
+	}
 	return nil
 }
 
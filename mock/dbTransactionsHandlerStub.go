@@ -12,6 +12,7 @@ type DBTransactionProcessorStub struct {
 	PrepareTransactionsForDatabaseCalled func(mbs []*block.MiniBlock, header coreData.HeaderHandler, pool *outport.TransactionPool) *data.PreparedResults
 	SerializeReceiptsCalled              func(recs []*data.Receipt, buffSlice *data.BufferSlice, index string) error
 	SerializeScResultsCalled             func(scrs []*data.ScResult, buffSlice *data.BufferSlice, index string) error
+	GetHexEncodedHashesForRemoveCalled   func(header coreData.HeaderHandler, body *block.Body) ([]string, []string)
 }
 
 // SerializeTransactionsFeeData -
@@ -29,7 +30,11 @@ func (tps *DBTransactionProcessorStub) PrepareTransactionsForDatabase(mbs []*blo
 }
 
 // GetHexEncodedHashesForRemove -
-func (tps *DBTransactionProcessorStub) GetHexEncodedHashesForRemove(_ coreData.HeaderHandler, _ *block.Body) ([]string, []string) {
+func (tps *DBTransactionProcessorStub) GetHexEncodedHashesForRemove(header coreData.HeaderHandler, body *block.Body) ([]string, []string) {
+	if tps.GetHexEncodedHashesForRemoveCalled != nil {
+		return tps.GetHexEncodedHashesForRemoveCalled(header, body)
+	}
+
 	return nil, nil
 }
 
@@ -1,14 +1,17 @@
 package mock
 
 import (
+	"context"
+
 	coreData "github.com/multiversx/mx-chain-core-go/data"
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
 )
 
 // ElasticProcessorStub -
 type ElasticProcessorStub struct {
-	SaveHeaderCalled                 func(outportBlockWithHeader *outport.OutportBlockWithHeader) error
+	SaveHeaderCalled                 func(ctx context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error
 	RemoveHeaderCalled               func(header coreData.HeaderHandler) error
 	RemoveMiniblocksCalled           func(header coreData.HeaderHandler, body *block.Body) error
 	RemoveTransactionsCalled         func(header coreData.HeaderHandler, body *block.Body) error
@@ -19,10 +22,14 @@ type ElasticProcessorStub struct {
 	SaveShardValidatorsPubKeysCalled func(validators *outport.ValidatorsPubKeys) error
 	SaveAccountsCalled               func(accountsData *outport.Accounts) error
 	RemoveAccountsESDTCalled         func(headerTimestamp uint64) error
+	SaveFinalizedBlockCalled         func(finalizedBlock *outport.FinalizedBlock) error
+	GetIndexingCheckpointCalled      func(shardID uint32) (*data.IndexingCheckpoint, error)
+	SaveIndexingCheckpointCalled     func(shardID uint32, checkpoint *data.IndexingCheckpoint) error
+	CloseCalled                      func() error
 }
 
 // RemoveAccountsESDT -
-func (eim *ElasticProcessorStub) RemoveAccountsESDT(headerTimestamp uint64, _ uint32) error {
+func (eim *ElasticProcessorStub) RemoveAccountsESDT(_ context.Context, headerTimestamp uint64, _ uint32) error {
 	if eim.RemoveAccountsESDTCalled != nil {
 		return eim.RemoveAccountsESDTCalled(headerTimestamp)
 	}
@@ -31,15 +38,15 @@ func (eim *ElasticProcessorStub) RemoveAccountsESDT(headerTimestamp uint64, _ ui
 }
 
 // SaveHeader -
-func (eim *ElasticProcessorStub) SaveHeader(obh *outport.OutportBlockWithHeader) error {
+func (eim *ElasticProcessorStub) SaveHeader(ctx context.Context, obh *outport.OutportBlockWithHeader) error {
 	if eim.SaveHeaderCalled != nil {
-		return eim.SaveHeaderCalled(obh)
+		return eim.SaveHeaderCalled(ctx, obh)
 	}
 	return nil
 }
 
 // RemoveHeader -
-func (eim *ElasticProcessorStub) RemoveHeader(header coreData.HeaderHandler) error {
+func (eim *ElasticProcessorStub) RemoveHeader(_ context.Context, header coreData.HeaderHandler) error {
 	if eim.RemoveHeaderCalled != nil {
 		return eim.RemoveHeaderCalled(header)
 	}
@@ -47,7 +54,7 @@ func (eim *ElasticProcessorStub) RemoveHeader(header coreData.HeaderHandler) err
 }
 
 // RemoveMiniblocks -
-func (eim *ElasticProcessorStub) RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error {
+func (eim *ElasticProcessorStub) RemoveMiniblocks(_ context.Context, header coreData.HeaderHandler, body *block.Body) error {
 	if eim.RemoveMiniblocksCalled != nil {
 		return eim.RemoveMiniblocksCalled(header, body)
 	}
@@ -55,7 +62,7 @@ func (eim *ElasticProcessorStub) RemoveMiniblocks(header coreData.HeaderHandler,
 }
 
 // RemoveTransactions -
-func (eim *ElasticProcessorStub) RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error {
+func (eim *ElasticProcessorStub) RemoveTransactions(_ context.Context, header coreData.HeaderHandler, body *block.Body) error {
 	if eim.RemoveMiniblocksCalled != nil {
 		return eim.RemoveTransactionsCalled(header, body)
 	}
@@ -63,7 +70,7 @@ func (eim *ElasticProcessorStub) RemoveTransactions(header coreData.HeaderHandle
 }
 
 // SaveMiniblocks -
-func (eim *ElasticProcessorStub) SaveMiniblocks(header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error {
+func (eim *ElasticProcessorStub) SaveMiniblocks(_ context.Context, header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error {
 	if eim.SaveMiniblocksCalled != nil {
 		return eim.SaveMiniblocksCalled(header, miniBlocks)
 	}
@@ -71,7 +78,7 @@ func (eim *ElasticProcessorStub) SaveMiniblocks(header coreData.HeaderHandler, m
 }
 
 // SaveTransactions -
-func (eim *ElasticProcessorStub) SaveTransactions(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+func (eim *ElasticProcessorStub) SaveTransactions(_ context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error {
 	if eim.SaveTransactionsCalled != nil {
 		return eim.SaveTransactionsCalled(outportBlockWithHeader)
 	}
@@ -79,7 +86,7 @@ func (eim *ElasticProcessorStub) SaveTransactions(outportBlockWithHeader *outpor
 }
 
 // SaveValidatorsRating -
-func (eim *ElasticProcessorStub) SaveValidatorsRating(validatorsRating *outport.ValidatorsRating) error {
+func (eim *ElasticProcessorStub) SaveValidatorsRating(_ context.Context, validatorsRating *outport.ValidatorsRating) error {
 	if eim.SaveValidatorsRatingCalled != nil {
 		return eim.SaveValidatorsRatingCalled(validatorsRating)
 	}
@@ -87,7 +94,7 @@ func (eim *ElasticProcessorStub) SaveValidatorsRating(validatorsRating *outport.
 }
 
 // SaveRoundsInfo -
-func (eim *ElasticProcessorStub) SaveRoundsInfo(info *outport.RoundsInfo) error {
+func (eim *ElasticProcessorStub) SaveRoundsInfo(_ context.Context, info *outport.RoundsInfo) error {
 	if eim.SaveRoundsInfoCalled != nil {
 		return eim.SaveRoundsInfoCalled(info)
 	}
@@ -95,7 +102,7 @@ func (eim *ElasticProcessorStub) SaveRoundsInfo(info *outport.RoundsInfo) error
 }
 
 // SaveShardValidatorsPubKeys -
-func (eim *ElasticProcessorStub) SaveShardValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+func (eim *ElasticProcessorStub) SaveShardValidatorsPubKeys(_ context.Context, validatorsPubKeys *outport.ValidatorsPubKeys) error {
 	if eim.SaveShardValidatorsPubKeysCalled != nil {
 		return eim.SaveShardValidatorsPubKeysCalled(validatorsPubKeys)
 	}
@@ -111,11 +118,47 @@ func (eim *ElasticProcessorStub) SaveAccounts(accounts *outport.Accounts) error
 	return nil
 }
 
+// SaveFinalizedBlock -
+func (eim *ElasticProcessorStub) SaveFinalizedBlock(_ context.Context, finalizedBlock *outport.FinalizedBlock) error {
+	if eim.SaveFinalizedBlockCalled != nil {
+		return eim.SaveFinalizedBlockCalled(finalizedBlock)
+	}
+
+	return nil
+}
+
+// GetIndexingCheckpoint -
+func (eim *ElasticProcessorStub) GetIndexingCheckpoint(_ context.Context, shardID uint32) (*data.IndexingCheckpoint, error) {
+	if eim.GetIndexingCheckpointCalled != nil {
+		return eim.GetIndexingCheckpointCalled(shardID)
+	}
+
+	return nil, nil
+}
+
+// SaveIndexingCheckpoint -
+func (eim *ElasticProcessorStub) SaveIndexingCheckpoint(_ context.Context, shardID uint32, checkpoint *data.IndexingCheckpoint) error {
+	if eim.SaveIndexingCheckpointCalled != nil {
+		return eim.SaveIndexingCheckpointCalled(shardID, checkpoint)
+	}
+
+	return nil
+}
+
 // SetOutportConfig -
 func (eim *ElasticProcessorStub) SetOutportConfig(_ outport.OutportConfig) error {
 	return nil
 }
 
+// Close -
+func (eim *ElasticProcessorStub) Close() error {
+	if eim.CloseCalled != nil {
+		return eim.CloseCalled()
+	}
+
+	return nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (eim *ElasticProcessorStub) IsInterfaceNil() bool {
 	return eim == nil
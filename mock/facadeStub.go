@@ -0,0 +1,72 @@
+package mock
+
+import "github.com/multiversx/mx-chain-es-indexer-go/core/request"
+
+// FacadeStub -
+type FacadeStub struct {
+	GetMetricsCalled              func() map[string]*request.MetricsResponse
+	GetMetricsForPrometheusCalled func() string
+	GetGapsCalled                 func() []*request.GapResponse
+	ProcessPayloadCalled          func(payload []byte, topic string, version uint32) error
+	GetDeadLettersCalled          func() ([]*request.DeadLetterResponse, error)
+	ResubmitDeadLettersCalled     func(ids []string) (*request.ResubmitResponse, error)
+}
+
+// GetMetrics -
+func (fs *FacadeStub) GetMetrics() map[string]*request.MetricsResponse {
+	if fs.GetMetricsCalled != nil {
+		return fs.GetMetricsCalled()
+	}
+
+	return nil
+}
+
+// GetMetricsForPrometheus -
+func (fs *FacadeStub) GetMetricsForPrometheus() string {
+	if fs.GetMetricsForPrometheusCalled != nil {
+		return fs.GetMetricsForPrometheusCalled()
+	}
+
+	return ""
+}
+
+// GetGaps -
+func (fs *FacadeStub) GetGaps() []*request.GapResponse {
+	if fs.GetGapsCalled != nil {
+		return fs.GetGapsCalled()
+	}
+
+	return nil
+}
+
+// ProcessPayload -
+func (fs *FacadeStub) ProcessPayload(payload []byte, topic string, version uint32) error {
+	if fs.ProcessPayloadCalled != nil {
+		return fs.ProcessPayloadCalled(payload, topic, version)
+	}
+
+	return nil
+}
+
+// GetDeadLetters -
+func (fs *FacadeStub) GetDeadLetters() ([]*request.DeadLetterResponse, error) {
+	if fs.GetDeadLettersCalled != nil {
+		return fs.GetDeadLettersCalled()
+	}
+
+	return nil, nil
+}
+
+// ResubmitDeadLetters -
+func (fs *FacadeStub) ResubmitDeadLetters(ids []string) (*request.ResubmitResponse, error) {
+	if fs.ResubmitDeadLettersCalled != nil {
+		return fs.ResubmitDeadLettersCalled(ids)
+	}
+
+	return nil, nil
+}
+
+// IsInterfaceNil -
+func (fs *FacadeStub) IsInterfaceNil() bool {
+	return fs == nil
+}
@@ -0,0 +1,48 @@
+package mock
+
+import "github.com/multiversx/mx-chain-es-indexer-go/core/request"
+
+// GapsTrackerStub -
+type GapsTrackerStub struct {
+	HasCheckpointCalled func(shardID uint32) bool
+	SetCheckpointCalled func(shardID uint32, nonce uint64, hash []byte)
+	ProcessBlockCalled  func(shardID uint32, nonce uint64, hash []byte, prevHash []byte)
+	GetGapsCalled       func() []*request.GapResponse
+}
+
+// HasCheckpoint -
+func (gts *GapsTrackerStub) HasCheckpoint(shardID uint32) bool {
+	if gts.HasCheckpointCalled != nil {
+		return gts.HasCheckpointCalled(shardID)
+	}
+
+	return true
+}
+
+// SetCheckpoint -
+func (gts *GapsTrackerStub) SetCheckpoint(shardID uint32, nonce uint64, hash []byte) {
+	if gts.SetCheckpointCalled != nil {
+		gts.SetCheckpointCalled(shardID, nonce, hash)
+	}
+}
+
+// ProcessBlock -
+func (gts *GapsTrackerStub) ProcessBlock(shardID uint32, nonce uint64, hash []byte, prevHash []byte) {
+	if gts.ProcessBlockCalled != nil {
+		gts.ProcessBlockCalled(shardID, nonce, hash, prevHash)
+	}
+}
+
+// GetGaps -
+func (gts *GapsTrackerStub) GetGaps() []*request.GapResponse {
+	if gts.GetGapsCalled != nil {
+		return gts.GetGapsCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (gts *GapsTrackerStub) IsInterfaceNil() bool {
+	return gts == nil
+}
@@ -0,0 +1,116 @@
+package mock
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+)
+
+// IndexerStub -
+type IndexerStub struct {
+	SaveBlockCalled             func(outportBlock *outport.OutportBlock) error
+	RevertIndexedBlockCalled    func(blockData *outport.BlockData) error
+	SaveRoundsInfoCalled        func(roundsInfos *outport.RoundsInfo) error
+	SaveValidatorsPubKeysCalled func(validatorsPubKeys *outport.ValidatorsPubKeys) error
+	SaveValidatorsRatingCalled  func(ratingData *outport.ValidatorsRating) error
+	SaveAccountsCalled          func(accountsData *outport.Accounts) error
+	FinalizedBlockCalled        func(finalizedBlock *outport.FinalizedBlock) error
+	SetCurrentSettingsCalled    func(cfg outport.OutportConfig) error
+	CloseCalled                 func() error
+	Marshaller                  marshal.Marshalizer
+}
+
+// SaveBlock -
+func (is *IndexerStub) SaveBlock(outportBlock *outport.OutportBlock) error {
+	if is.SaveBlockCalled != nil {
+		return is.SaveBlockCalled(outportBlock)
+	}
+
+	return nil
+}
+
+// RevertIndexedBlock -
+func (is *IndexerStub) RevertIndexedBlock(blockData *outport.BlockData) error {
+	if is.RevertIndexedBlockCalled != nil {
+		return is.RevertIndexedBlockCalled(blockData)
+	}
+
+	return nil
+}
+
+// SaveRoundsInfo -
+func (is *IndexerStub) SaveRoundsInfo(roundsInfos *outport.RoundsInfo) error {
+	if is.SaveRoundsInfoCalled != nil {
+		return is.SaveRoundsInfoCalled(roundsInfos)
+	}
+
+	return nil
+}
+
+// SaveValidatorsPubKeys -
+func (is *IndexerStub) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+	if is.SaveValidatorsPubKeysCalled != nil {
+		return is.SaveValidatorsPubKeysCalled(validatorsPubKeys)
+	}
+
+	return nil
+}
+
+// SaveValidatorsRating -
+func (is *IndexerStub) SaveValidatorsRating(ratingData *outport.ValidatorsRating) error {
+	if is.SaveValidatorsRatingCalled != nil {
+		return is.SaveValidatorsRatingCalled(ratingData)
+	}
+
+	return nil
+}
+
+// SaveAccounts -
+func (is *IndexerStub) SaveAccounts(accountsData *outport.Accounts) error {
+	if is.SaveAccountsCalled != nil {
+		return is.SaveAccountsCalled(accountsData)
+	}
+
+	return nil
+}
+
+// FinalizedBlock -
+func (is *IndexerStub) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	if is.FinalizedBlockCalled != nil {
+		return is.FinalizedBlockCalled(finalizedBlock)
+	}
+
+	return nil
+}
+
+// GetMarshaller -
+func (is *IndexerStub) GetMarshaller() marshal.Marshalizer {
+	return is.Marshaller
+}
+
+// RegisterHandler -
+func (is *IndexerStub) RegisterHandler(_ func() error, _ string) error {
+	return nil
+}
+
+// SetCurrentSettings -
+func (is *IndexerStub) SetCurrentSettings(cfg outport.OutportConfig) error {
+	if is.SetCurrentSettingsCalled != nil {
+		return is.SetCurrentSettingsCalled(cfg)
+	}
+
+	return nil
+}
+
+// Close -
+func (is *IndexerStub) Close() error {
+	if is.CloseCalled != nil {
+		return is.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (is *IndexerStub) IsInterfaceNil() bool {
+	return is == nil
+}
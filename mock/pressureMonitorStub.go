@@ -0,0 +1,48 @@
+package mock
+
+import "time"
+
+// PressureMonitorStub -
+type PressureMonitorStub struct {
+	RecordResponseCalled        func(duration time.Duration, statusCode int, err error)
+	RecordRejectionCalled       func()
+	GetAckDelayCalled           func() time.Duration
+	GetBulkRequestMaxSizeCalled func() int
+}
+
+// RecordResponse -
+func (pms *PressureMonitorStub) RecordResponse(duration time.Duration, statusCode int, err error) {
+	if pms.RecordResponseCalled != nil {
+		pms.RecordResponseCalled(duration, statusCode, err)
+	}
+}
+
+// RecordRejection -
+func (pms *PressureMonitorStub) RecordRejection() {
+	if pms.RecordRejectionCalled != nil {
+		pms.RecordRejectionCalled()
+	}
+}
+
+// GetAckDelay -
+func (pms *PressureMonitorStub) GetAckDelay() time.Duration {
+	if pms.GetAckDelayCalled != nil {
+		return pms.GetAckDelayCalled()
+	}
+
+	return 0
+}
+
+// GetBulkRequestMaxSize -
+func (pms *PressureMonitorStub) GetBulkRequestMaxSize() int {
+	if pms.GetBulkRequestMaxSizeCalled != nil {
+		return pms.GetBulkRequestMaxSizeCalled()
+	}
+
+	return 0
+}
+
+// IsInterfaceNil -
+func (pms *PressureMonitorStub) IsInterfaceNil() bool {
+	return pms == nil
+}
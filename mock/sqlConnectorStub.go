@@ -0,0 +1,227 @@
+package mock
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"sync"
+)
+
+// SQLStatement -
+type SQLStatement struct {
+	Query string
+	Args  []driver.Value
+}
+
+// SQLConnectorStub -
+type SQLConnectorStub struct {
+	ExecCalled  func(query string, args []driver.Value) error
+	QueryCalled func(query string, args []driver.Value) ([]string, [][]driver.Value, error)
+
+	mut          sync.Mutex
+	statements   []SQLStatement
+	numCommits   int
+	numRollbacks int
+}
+
+// Connect -
+func (stub *SQLConnectorStub) Connect(_ context.Context) (driver.Conn, error) {
+	return &sqlConnStub{connector: stub}, nil
+}
+
+// Driver -
+func (stub *SQLConnectorStub) Driver() driver.Driver {
+	return &sqlDriverStub{connector: stub}
+}
+
+// Statements -
+func (stub *SQLConnectorStub) Statements() []SQLStatement {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+
+	return append([]SQLStatement{}, stub.statements...)
+}
+
+// NumCommits -
+func (stub *SQLConnectorStub) NumCommits() int {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+
+	return stub.numCommits
+}
+
+// NumRollbacks -
+func (stub *SQLConnectorStub) NumRollbacks() int {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+
+	return stub.numRollbacks
+}
+
+func (stub *SQLConnectorStub) record(query string, args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+
+	stub.mut.Lock()
+	stub.statements = append(stub.statements, SQLStatement{Query: query, Args: values})
+	stub.mut.Unlock()
+
+	return values
+}
+
+func (stub *SQLConnectorStub) exec(query string, args []driver.NamedValue) error {
+	values := stub.record(query, args)
+	if stub.ExecCalled != nil {
+		return stub.ExecCalled(query, values)
+	}
+
+	return nil
+}
+
+func (stub *SQLConnectorStub) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	values := stub.record(query, args)
+	if stub.QueryCalled == nil {
+		return &sqlRowsStub{}, nil
+	}
+
+	columns, rows, err := stub.QueryCalled(query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlRowsStub{columns: columns, rows: rows}, nil
+}
+
+type sqlDriverStub struct {
+	connector *SQLConnectorStub
+}
+
+// Open -
+func (ds *sqlDriverStub) Open(_ string) (driver.Conn, error) {
+	return &sqlConnStub{connector: ds.connector}, nil
+}
+
+type sqlConnStub struct {
+	connector *SQLConnectorStub
+}
+
+// Prepare -
+func (cs *sqlConnStub) Prepare(query string) (driver.Stmt, error) {
+	return &sqlStmtStub{connector: cs.connector, query: query}, nil
+}
+
+// Close -
+func (cs *sqlConnStub) Close() error {
+	return nil
+}
+
+// Begin -
+func (cs *sqlConnStub) Begin() (driver.Tx, error) {
+	return &sqlTxStub{connector: cs.connector}, nil
+}
+
+// ExecContext -
+func (cs *sqlConnStub) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	err := cs.connector.exec(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+// QueryContext -
+func (cs *sqlConnStub) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return cs.connector.query(query, args)
+}
+
+type sqlStmtStub struct {
+	connector *SQLConnectorStub
+	query     string
+}
+
+// Close -
+func (ss *sqlStmtStub) Close() error {
+	return nil
+}
+
+// NumInput -
+func (ss *sqlStmtStub) NumInput() int {
+	return -1
+}
+
+// Exec -
+func (ss *sqlStmtStub) Exec(args []driver.Value) (driver.Result, error) {
+	err := ss.connector.exec(ss.query, toNamedValues(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+// Query -
+func (ss *sqlStmtStub) Query(args []driver.Value) (driver.Rows, error) {
+	return ss.connector.query(ss.query, toNamedValues(args))
+}
+
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	namedValues := make([]driver.NamedValue, 0, len(args))
+	for i, arg := range args {
+		namedValues = append(namedValues, driver.NamedValue{Ordinal: i + 1, Value: arg})
+	}
+
+	return namedValues
+}
+
+type sqlTxStub struct {
+	connector *SQLConnectorStub
+}
+
+// Commit -
+func (ts *sqlTxStub) Commit() error {
+	ts.connector.mut.Lock()
+	ts.connector.numCommits++
+	ts.connector.mut.Unlock()
+
+	return nil
+}
+
+// Rollback -
+func (ts *sqlTxStub) Rollback() error {
+	ts.connector.mut.Lock()
+	ts.connector.numRollbacks++
+	ts.connector.mut.Unlock()
+
+	return nil
+}
+
+type sqlRowsStub struct {
+	columns []string
+	rows    [][]driver.Value
+	index   int
+}
+
+// Columns -
+func (rs *sqlRowsStub) Columns() []string {
+	return rs.columns
+}
+
+// Close -
+func (rs *sqlRowsStub) Close() error {
+	return nil
+}
+
+// Next -
+func (rs *sqlRowsStub) Next(dest []driver.Value) error {
+	if rs.index >= len(rs.rows) {
+		return io.EOF
+	}
+
+	copy(dest, rs.rows[rs.index])
+	rs.index++
+
+	return nil
+}
@@ -49,6 +49,8 @@ const (
 	ValuesIndex = "values"
 	// EventsIndex is the Elasticsearch index for log events
 	EventsIndex = "events"
+	// DeadLettersIndex is the Elasticsearch index for the documents that were rejected by Elasticsearch
+	DeadLettersIndex = "deadletters"
 
 	// TransactionsPolicy is the Elasticsearch policy for the transactions
 	TransactionsPolicy = "transactions_policy"
@@ -75,3 +77,16 @@ const (
 	// ReceiptsPolicy is the Elasticsearch policy for the receipts
 	ReceiptsPolicy = "receipts_policy"
 )
+
+const (
+	// AutoBackend is the backend type used when the distribution of the cluster is detected when the indexer starts. An
+	// Elasticsearch 8.x cluster detected this way is indexed with the v7 client, like an "elasticsearch" backend
+	AutoBackend = "auto"
+	// ElasticsearchBackend is the backend type of the Elasticsearch clusters
+	ElasticsearchBackend = "elasticsearch"
+	// Elasticsearch8Backend is the backend type of the Elasticsearch 8.x clusters that are indexed with the v8 client and
+	// composable index templates
+	Elasticsearch8Backend = "elasticsearch8"
+	// OpenSearchBackend is the backend type of the OpenSearch clusters
+	OpenSearchBackend = "opensearch"
+)
@@ -1,8 +1,10 @@
 package dataindexer
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
@@ -11,22 +13,43 @@ import (
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-core-go/marshal"
+	indexerData "github.com/multiversx/mx-chain-es-indexer-go/data"
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
 var log = logger.GetOrCreate("dataindexer")
 
+const defaultShutdownTimeout = 30 * time.Second
+
 // ArgDataIndexer is a structure that is used to store all the components that are needed to create an indexer
 type ArgDataIndexer struct {
 	HeaderMarshaller marshal.Marshalizer
 	ElasticProcessor ElasticProcessor
 	BlockContainer   BlockContainerHandler
+	GapsTracker      GapsTrackerHandler
+	// ShutdownTimeout is the time the in-flight operations have to finish after the indexer is closed. A block that
+	// is still being indexed when it passes is rolled back
+	ShutdownTimeout time.Duration
 }
 
 type dataIndexer struct {
 	elasticProcessor ElasticProcessor
 	headerMarshaller marshal.Marshalizer
 	blockContainer   BlockContainerHandler
+	gapsTracker      GapsTrackerHandler
+	shutdownTimeout  time.Duration
+
+	mutState  sync.Mutex
+	closed    bool
+	inFlight  sync.WaitGroup
+	abortChan chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	// ctx is passed to every operation of the elastic processor and it is cancelled together with the abort channel,
+	// so the requests that are in flight when the shutdown deadline passes are cancelled as well
+	ctx       context.Context
+	cancelCtx context.CancelFunc
 }
 
 // NewDataIndexer will create a new data indexer
@@ -36,10 +59,21 @@ func NewDataIndexer(arguments ArgDataIndexer) (*dataIndexer, error) {
 		return nil, err
 	}
 
+	shutdownTimeout := arguments.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
 	dataIndexerObj := &dataIndexer{
 		elasticProcessor: arguments.ElasticProcessor,
 		headerMarshaller: arguments.HeaderMarshaller,
 		blockContainer:   arguments.BlockContainer,
+		gapsTracker:      arguments.GapsTracker,
+		shutdownTimeout:  shutdownTimeout,
+		abortChan:        make(chan struct{}),
+		ctx:              ctx,
+		cancelCtx:        cancelCtx,
 	}
 
 	return dataIndexerObj, nil
@@ -55,6 +89,9 @@ func checkIndexerArgs(arguments ArgDataIndexer) error {
 	if check.IfNilReflect(arguments.BlockContainer) {
 		return ErrNilBlockContainerHandler
 	}
+	if check.IfNil(arguments.GapsTracker) {
+		return ErrNilGapsTracker
+	}
 
 	return nil
 }
@@ -70,6 +107,11 @@ func (di *dataIndexer) getHeaderFromBytes(headerType core.HeaderType, headerByte
 
 // SaveBlock saves the block info in the queue to be sent to elastic
 func (di *dataIndexer) SaveBlock(outportBlock *outport.OutportBlock) error {
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
 	header, err := di.getHeaderFromBytes(core.HeaderType(outportBlock.BlockData.HeaderType), outportBlock.BlockData.HeaderBytes)
 	if err != nil {
 		return err
@@ -93,7 +135,56 @@ func (di *dataIndexer) SaveBlock(outportBlock *outport.OutportBlock) error {
 		outportBlock.TransactionPool = &outport.TransactionPool{}
 	}
 
-	return di.saveBlockData(outportBlock, header)
+	err = di.loadCheckpointIfNeeded(shardID)
+	if err != nil {
+		return err
+	}
+
+	err = di.saveBlockData(outportBlock, header)
+	if err != nil {
+		return err
+	}
+
+	// the checkpoint is saved only after all the data of the block, so a partially indexed block is never marked as
+	// indexed
+	err = di.elasticProcessor.SaveIndexingCheckpoint(di.ctx, shardID, &indexerData.IndexingCheckpoint{
+		Nonce:     headerNonce,
+		Hash:      hex.EncodeToString(headerHash),
+		Timestamp: time.Duration(header.GetTimeStamp()),
+	})
+	if err != nil {
+		return fmt.Errorf("%w when saving the indexing checkpoint, block hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce)
+	}
+
+	di.gapsTracker.ProcessBlock(shardID, headerNonce, headerHash, header.GetPrevHash())
+
+	return nil
+}
+
+// loadCheckpointIfNeeded will load, from the database, the last indexed block of the provided shard if it is not known
+// yet, so the gaps that span a restart are detected as well
+func (di *dataIndexer) loadCheckpointIfNeeded(shardID uint32) error {
+	if di.gapsTracker.HasCheckpoint(shardID) {
+		return nil
+	}
+
+	checkpoint, err := di.elasticProcessor.GetIndexingCheckpoint(di.ctx, shardID)
+	if err != nil {
+		return fmt.Errorf("%w while loading the indexing checkpoint of shard %d", err, shardID)
+	}
+	if checkpoint == nil {
+		return nil
+	}
+
+	hash, err := hex.DecodeString(checkpoint.Hash)
+	if err != nil {
+		return fmt.Errorf("%w while decoding the indexing checkpoint hash of shard %d", err, shardID)
+	}
+
+	di.gapsTracker.SetCheckpoint(shardID, checkpoint.Nonce, hash)
+
+	return nil
 }
 
 func (di *dataIndexer) saveBlockData(outportBlock *outport.OutportBlock, header data.HeaderHandler) error {
@@ -104,85 +195,263 @@ func (di *dataIndexer) saveBlockData(outportBlock *outport.OutportBlock, header
 
 	headerHash := outportBlock.BlockData.HeaderHash
 	headerNonce := header.GetNonce()
-	err := di.elasticProcessor.SaveHeader(outportBlockWithHeader)
+	err := di.elasticProcessor.SaveHeader(di.ctx, outportBlockWithHeader)
 	if err != nil {
-		return fmt.Errorf("%w when saving header block, hash %s, nonce %d",
-			err, hex.EncodeToString(headerHash), headerNonce)
+		return di.handleSaveError(fmt.Errorf("%w when saving header block, hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce), header, outportBlock.BlockData.Body)
 	}
 
 	if len(outportBlock.BlockData.Body.MiniBlocks) == 0 {
 		return nil
 	}
+	if di.isAborted() {
+		return di.rollbackBlock(header, outportBlock.BlockData.Body)
+	}
 
 	miniBlocks := append(outportBlock.BlockData.Body.MiniBlocks, outportBlock.BlockData.IntraShardMiniBlocks...)
-	err = di.elasticProcessor.SaveMiniblocks(header, miniBlocks)
+	err = di.elasticProcessor.SaveMiniblocks(di.ctx, header, miniBlocks)
 	if err != nil {
-		return fmt.Errorf("%w when saving miniblocks, block hash %s, nonce %d",
-			err, hex.EncodeToString(headerHash), headerNonce)
+		return di.handleSaveError(fmt.Errorf("%w when saving miniblocks, block hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce), header, outportBlock.BlockData.Body)
+	}
+	if di.isAborted() {
+		return di.rollbackBlock(header, outportBlock.BlockData.Body)
 	}
 
-	err = di.elasticProcessor.SaveTransactions(outportBlockWithHeader)
+	err = di.elasticProcessor.SaveTransactions(di.ctx, outportBlockWithHeader)
 	if err != nil {
-		return fmt.Errorf("%w when saving transactions, block hash %s, nonce %d",
-			err, hex.EncodeToString(headerHash), headerNonce)
+		return di.handleSaveError(fmt.Errorf("%w when saving transactions, block hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce), header, outportBlock.BlockData.Body)
 	}
 
 	return nil
 }
 
-// Close will stop goroutine that index data in database
+// handleSaveError returns the provided error, unless it was caused by the cancellation of the in-flight requests at
+// the shutdown deadline. In that case, the already saved parts of the block are rolled back
+func (di *dataIndexer) handleSaveError(err error, header data.HeaderHandler, body *block.Body) error {
+	if !di.isAborted() {
+		return err
+	}
+
+	log.Debug("dataIndexer: block indexing cancelled by the shutdown deadline", "error", err)
+
+	return di.rollbackBlock(header, body)
+}
+
+// rollbackBlock will remove the already saved parts of a block whose indexing was aborted by the shutdown deadline
+func (di *dataIndexer) rollbackBlock(header data.HeaderHandler, body *block.Body) error {
+	log.Warn("dataIndexer: shutdown deadline exceeded, rolling back the partially indexed block",
+		"shardID", header.GetShardID(),
+		"nonce", header.GetNonce(),
+	)
+
+	// the context of the indexer is already cancelled, so the rollback gets its own deadline
+	ctx, cancel := context.WithTimeout(context.Background(), di.shutdownTimeout)
+	defer cancel()
+
+	err := di.elasticProcessor.RemoveHeader(ctx, header)
+	if err != nil {
+		return fmt.Errorf("%w and the header could not be removed: %s", ErrBlockIndexingAborted, err.Error())
+	}
+
+	err = di.elasticProcessor.RemoveMiniblocks(ctx, header, body)
+	if err != nil {
+		return fmt.Errorf("%w and the miniblocks could not be removed: %s", ErrBlockIndexingAborted, err.Error())
+	}
+
+	err = di.elasticProcessor.RemoveTransactions(ctx, header, body)
+	if err != nil {
+		return fmt.Errorf("%w and the transactions could not be removed: %s", ErrBlockIndexingAborted, err.Error())
+	}
+
+	err = di.elasticProcessor.RemoveAccountsESDT(ctx, header.GetTimeStamp(), header.GetShardID())
+	if err != nil {
+		return fmt.Errorf("%w and the ESDT accounts could not be removed: %s", ErrBlockIndexingAborted, err.Error())
+	}
+
+	return ErrBlockIndexingAborted
+}
+
+// startOperation returns false if the indexer is closed. Otherwise, the operation is counted as in-flight and it
+// has to be marked as done when it finishes
+func (di *dataIndexer) startOperation() bool {
+	di.mutState.Lock()
+	defer di.mutState.Unlock()
+
+	if di.closed {
+		return false
+	}
+
+	di.inFlight.Add(1)
+	return true
+}
+
+func (di *dataIndexer) isAborted() bool {
+	select {
+	case <-di.abortChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close will stop accepting new data and will wait for the in-flight operations to finish. Every bulk request is
+// sent to the database before the operation that created it returns, so there are no buffers left to be flushed
+// afterwards. If the operations do not finish within the shutdown timeout, the block that is being indexed is rolled
+// back and ErrShutdownDeadlineExceeded is returned. In the end, the elastic processor is closed, so its client can
+// flush the writes it still holds, like the ones queued for the async mirror clusters
 func (di *dataIndexer) Close() error {
-	return nil
+	di.closeOnce.Do(func() {
+		di.mutState.Lock()
+		di.closed = true
+		di.mutState.Unlock()
+
+		di.closeErr = di.drain()
+
+		// the elastic processor is closed only after the in-flight operations stopped using it
+		err := di.elasticProcessor.Close()
+		if err != nil {
+			log.Warn("dataIndexer.Close: cannot close the elastic processor", "error", err)
+			if di.closeErr == nil {
+				di.closeErr = err
+			}
+		}
+	})
+
+	return di.closeErr
+}
+
+func (di *dataIndexer) drain() error {
+	doneChan := make(chan struct{})
+	go func() {
+		di.inFlight.Wait()
+		close(doneChan)
+	}()
+
+	timer := time.NewTimer(di.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-doneChan:
+		log.Debug("dataIndexer.Close: all the in-flight operations finished")
+		return nil
+	case <-timer.C:
+	}
+
+	// the operations still in-flight have the same amount of time to roll back what they have already indexed
+	close(di.abortChan)
+	di.cancelCtx()
+	timer.Reset(di.shutdownTimeout)
+
+	select {
+	case <-doneChan:
+		log.Warn("dataIndexer.Close: the in-flight operations were aborted", "shutdown timeout", di.shutdownTimeout)
+	case <-timer.C:
+		log.Error("dataIndexer.Close: the in-flight operations did not finish", "shutdown timeout", di.shutdownTimeout)
+	}
+
+	return ErrShutdownDeadlineExceeded
 }
 
 // RevertIndexedBlock will remove from database block and miniblocks
 func (di *dataIndexer) RevertIndexedBlock(blockData *outport.BlockData) error {
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
 	header, err := di.getHeaderFromBytes(core.HeaderType(blockData.HeaderType), blockData.HeaderBytes)
 	if err != nil {
 		return err
 	}
 
-	err = di.elasticProcessor.RemoveHeader(header)
+	err = di.elasticProcessor.RemoveHeader(di.ctx, header)
+	if err != nil {
+		return err
+	}
+
+	err = di.elasticProcessor.RemoveMiniblocks(di.ctx, header, blockData.Body)
+	if err != nil {
+		return err
+	}
+
+	err = di.elasticProcessor.RemoveTransactions(di.ctx, header, blockData.Body)
 	if err != nil {
 		return err
 	}
 
-	err = di.elasticProcessor.RemoveMiniblocks(header, blockData.Body)
+	err = di.elasticProcessor.RemoveAccountsESDT(di.ctx, header.GetTimeStamp(), header.GetShardID())
 	if err != nil {
 		return err
 	}
 
-	err = di.elasticProcessor.RemoveTransactions(header, blockData.Body)
+	if header.GetNonce() == 0 {
+		return nil
+	}
+
+	// the timestamp of the previous block is not known here, so the rewound checkpoint has only its nonce and hash
+	err = di.elasticProcessor.SaveIndexingCheckpoint(di.ctx, header.GetShardID(), &indexerData.IndexingCheckpoint{
+		Nonce: header.GetNonce() - 1,
+		Hash:  hex.EncodeToString(header.GetPrevHash()),
+	})
 	if err != nil {
 		return err
 	}
 
-	return di.elasticProcessor.RemoveAccountsESDT(header.GetTimeStamp(), header.GetShardID())
+	di.gapsTracker.SetCheckpoint(header.GetShardID(), header.GetNonce()-1, header.GetPrevHash())
+
+	return nil
 }
 
 // SaveRoundsInfo will save data about a slice of rounds in elasticsearch
 func (di *dataIndexer) SaveRoundsInfo(rounds *outport.RoundsInfo) error {
-	return di.elasticProcessor.SaveRoundsInfo(rounds)
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
+	return di.elasticProcessor.SaveRoundsInfo(di.ctx, rounds)
 }
 
 // SaveValidatorsRating will save all validators rating info to elasticsearch
 func (di *dataIndexer) SaveValidatorsRating(ratingData *outport.ValidatorsRating) error {
-	return di.elasticProcessor.SaveValidatorsRating(ratingData)
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
+	return di.elasticProcessor.SaveValidatorsRating(di.ctx, ratingData)
 }
 
 // SaveValidatorsPubKeys will save all validators public keys to elasticsearch
 func (di *dataIndexer) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
-	return di.elasticProcessor.SaveShardValidatorsPubKeys(validatorsPubKeys)
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
+	return di.elasticProcessor.SaveShardValidatorsPubKeys(di.ctx, validatorsPubKeys)
 }
 
 // SaveAccounts will save the provided accounts
 func (di *dataIndexer) SaveAccounts(accounts *outport.Accounts) error {
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
 	return di.elasticProcessor.SaveAccounts(accounts)
 }
 
-// FinalizedBlock returns nil
-func (di *dataIndexer) FinalizedBlock(_ *outport.FinalizedBlock) error {
-	return nil
+// FinalizedBlock will mark the finalized block, its miniblocks and its transactions as final
+func (di *dataIndexer) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
+	return di.elasticProcessor.SaveFinalizedBlock(di.ctx, finalizedBlock)
 }
 
 // GetMarshaller return the marshaller
@@ -197,6 +466,11 @@ func (di *dataIndexer) RegisterHandler(_ func() error, _ string) error {
 
 // SetCurrentSettings will set the provided settings
 func (di *dataIndexer) SetCurrentSettings(cfg outport.OutportConfig) error {
+	if !di.startOperation() {
+		return ErrIndexerClosed
+	}
+	defer di.inFlight.Done()
+
 	log.Debug("dataIndexer.SetCurrentSettings", "importDBMode", cfg.IsInImportDBMode)
 
 	return di.elasticProcessor.SetOutportConfig(cfg)
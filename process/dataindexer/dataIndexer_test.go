@@ -1,13 +1,17 @@
 package dataindexer
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	coreData "github.com/multiversx/mx-chain-core-go/data"
 	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
 	"github.com/multiversx/mx-chain-es-indexer-go/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -17,6 +21,7 @@ func NewDataIndexerArguments() ArgDataIndexer {
 		ElasticProcessor: &mock.ElasticProcessorStub{},
 		HeaderMarshaller: &mock.MarshalizerMock{},
 		BlockContainer:   &mock.BlockContainerStub{},
+		GapsTracker:      &mock.GapsTrackerStub{},
 	}
 }
 
@@ -58,7 +63,7 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 	}
 
 	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
-		SaveHeaderCalled: func(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+		SaveHeaderCalled: func(_ context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error {
 			countMap[0]++
 			return nil
 		},
@@ -70,6 +75,12 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 			countMap[2]++
 			return nil
 		},
+		SaveIndexingCheckpointCalled: func(shardID uint32, checkpoint *data.IndexingCheckpoint) error {
+			require.Equal(t, 1, countMap[2], "the checkpoint should be saved after the transactions")
+			require.Equal(t, &data.IndexingCheckpoint{Nonce: 8, Hash: "abcd", Timestamp: 1000}, checkpoint)
+			countMap[3]++
+			return nil
+		},
 	}
 	ei, _ := NewDataIndexer(arguments)
 
@@ -77,7 +88,8 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 		BlockData: &outport.BlockData{
 			HeaderType:  string(core.ShardHeaderV2),
 			Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
-			HeaderBytes: []byte("{}"),
+			HeaderBytes: []byte(`{"Header":{"Nonce":8,"TimeStamp":1000}}`),
+			HeaderHash:  []byte{0xab, 0xcd},
 		},
 	}
 	err := ei.SaveBlock(args)
@@ -85,6 +97,38 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 	require.Equal(t, 1, countMap[0])
 	require.Equal(t, 1, countMap[1])
 	require.Equal(t, 1, countMap[2])
+	require.Equal(t, 1, countMap[3])
+}
+
+func TestDataIndexer_SaveBlockWithErrorShouldNotSaveCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SaveTransactionsCalled: func(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+			return localErr
+		},
+		SaveIndexingCheckpointCalled: func(shardID uint32, checkpoint *data.IndexingCheckpoint) error {
+			require.Fail(t, "should not save the checkpoint of a block that was not saved")
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.SaveBlock(&outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+			HeaderBytes: []byte(`{"Header":{"Nonce":8}}`),
+		},
+	})
+	require.True(t, errors.Is(err, localErr))
 }
 
 func TestDataIndexer_SaveRoundInfo(t *testing.T) {
@@ -100,11 +144,11 @@ func TestDataIndexer_SaveRoundInfo(t *testing.T) {
 		},
 	}
 	ei, _ := NewDataIndexer(arguments)
-	_ = ei.Close()
 
 	err := ei.SaveRoundsInfo(&outport.RoundsInfo{})
 	require.True(t, called)
 	require.Nil(t, err)
+	require.Nil(t, ei.Close())
 }
 
 func TestDataIndexer_SaveValidatorsPubKeys(t *testing.T) {
@@ -171,17 +215,268 @@ func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 			countMap[3]++
 			return nil
 		},
+		SaveIndexingCheckpointCalled: func(shardID uint32, checkpoint *data.IndexingCheckpoint) error {
+			require.Equal(t, &data.IndexingCheckpoint{Nonce: 7, Hash: "abcd"}, checkpoint)
+			countMap[4]++
+			return nil
+		},
+	}
+	arguments.GapsTracker = &mock.GapsTrackerStub{
+		SetCheckpointCalled: func(shardID uint32, nonce uint64, hash []byte) {
+			require.Equal(t, uint64(7), nonce)
+			require.Equal(t, []byte{0xab, 0xcd}, hash)
+			countMap[5]++
+		},
 	}
 	ei, _ := NewDataIndexer(arguments)
 
 	err := ei.RevertIndexedBlock(&outport.BlockData{
 		HeaderType:  string(core.ShardHeaderV2),
 		Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
-		HeaderBytes: []byte("{}"),
+		HeaderBytes: []byte(`{"Header":{"Nonce":8,"PrevHash":"q80="}}`),
 	})
 	require.Nil(t, err)
 	require.Equal(t, 1, countMap[0])
 	require.Equal(t, 1, countMap[1])
 	require.Equal(t, 1, countMap[2])
 	require.Equal(t, 1, countMap[3])
+	require.Equal(t, 1, countMap[4])
+	require.Equal(t, 1, countMap[5])
+}
+
+func TestDataIndexer_FinalizedBlock(t *testing.T) {
+	called := false
+
+	finalizedBlock := &outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("hash")}
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SaveFinalizedBlockCalled: func(block *outport.FinalizedBlock) error {
+			require.Equal(t, finalizedBlock, block)
+			called = true
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.FinalizedBlock(finalizedBlock)
+	require.True(t, called)
+	require.Nil(t, err)
+}
+
+func TestDataIndexer_SaveBlockShouldTrackGaps(t *testing.T) {
+	t.Parallel()
+
+	checkpoints := make(map[uint32]uint64)
+	processedNonces := make([]uint64, 0)
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		GetIndexingCheckpointCalled: func(shardID uint32) (*data.IndexingCheckpoint, error) {
+			return &data.IndexingCheckpoint{Nonce: 7, Hash: "abcd"}, nil
+		},
+	}
+	arguments.GapsTracker = &mock.GapsTrackerStub{
+		HasCheckpointCalled: func(shardID uint32) bool {
+			_, found := checkpoints[shardID]
+			return found
+		},
+		SetCheckpointCalled: func(shardID uint32, nonce uint64, hash []byte) {
+			require.Equal(t, []byte{0xab, 0xcd}, hash)
+			checkpoints[shardID] = nonce
+		},
+		ProcessBlockCalled: func(shardID uint32, nonce uint64, hash []byte, prevHash []byte) {
+			processedNonces = append(processedNonces, nonce)
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.SaveBlock(&outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			Body:        &dataBlock.Body{},
+			HeaderBytes: []byte(`{"Header":{"Nonce":8}}`),
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, map[uint32]uint64{0: 7}, checkpoints)
+	require.Equal(t, []uint64{8}, processedNonces)
+}
+
+func TestDataIndexer_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("closed indexer should not accept new data", func(t *testing.T) {
+		t.Parallel()
+
+		numClosed := 0
+		arguments := NewDataIndexerArguments()
+		arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+			SaveRoundsInfoCalled: func(infos *outport.RoundsInfo) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+			CloseCalled: func() error {
+				numClosed++
+				return nil
+			},
+		}
+		ei, _ := NewDataIndexer(arguments)
+
+		require.Nil(t, ei.Close())
+		require.Nil(t, ei.Close())
+		require.Equal(t, ErrIndexerClosed, ei.SaveRoundsInfo(&outport.RoundsInfo{}))
+		require.Equal(t, 1, numClosed)
+	})
+
+	t.Run("elastic processor close error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		arguments := NewDataIndexerArguments()
+		arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+			CloseCalled: func() error {
+				return expectedErr
+			},
+		}
+		ei, _ := NewDataIndexer(arguments)
+
+		require.Equal(t, expectedErr, ei.Close())
+	})
+
+	t.Run("should wait for the in-flight block", func(t *testing.T) {
+		t.Parallel()
+
+		startedChan := make(chan struct{})
+		finishChan := make(chan struct{})
+		arguments := NewDataIndexerArguments()
+		arguments.BlockContainer = &mock.BlockContainerStub{
+			GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+				return dataBlock.NewEmptyHeaderV2Creator(), nil
+			},
+		}
+		arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+			SaveHeaderCalled: func(_ context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+				close(startedChan)
+				<-finishChan
+				return nil
+			},
+			RemoveHeaderCalled: func(header coreData.HeaderHandler) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		ei, _ := NewDataIndexer(arguments)
+
+		saveErrChan := make(chan error, 1)
+		go func() {
+			saveErrChan <- ei.SaveBlock(createOutportBlockWithMiniBlocks())
+		}()
+		<-startedChan
+
+		time.AfterFunc(50*time.Millisecond, func() {
+			close(finishChan)
+		})
+		require.Nil(t, ei.Close())
+		require.Nil(t, <-saveErrChan)
+	})
+
+	t.Run("should roll back the in-flight block after the shutdown timeout", func(t *testing.T) {
+		t.Parallel()
+
+		startedChan := make(chan struct{})
+		finishChan := make(chan struct{})
+		removedHeader := false
+		removedAccountsESDT := false
+		arguments := NewDataIndexerArguments()
+		arguments.ShutdownTimeout = 10 * time.Millisecond
+		arguments.BlockContainer = &mock.BlockContainerStub{
+			GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+				return dataBlock.NewEmptyHeaderV2Creator(), nil
+			},
+		}
+		arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+			SaveHeaderCalled: func(_ context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+				close(startedChan)
+				<-finishChan
+				return nil
+			},
+			SaveMiniblocksCalled: func(header coreData.HeaderHandler, miniBlocks []*dataBlock.MiniBlock) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+			RemoveHeaderCalled: func(header coreData.HeaderHandler) error {
+				removedHeader = true
+				return nil
+			},
+			RemoveAccountsESDTCalled: func(headerTimestamp uint64) error {
+				removedAccountsESDT = true
+				return nil
+			},
+		}
+		ei, _ := NewDataIndexer(arguments)
+
+		saveErrChan := make(chan error, 1)
+		go func() {
+			saveErrChan <- ei.SaveBlock(createOutportBlockWithMiniBlocks())
+		}()
+		<-startedChan
+
+		time.AfterFunc(15*time.Millisecond, func() {
+			close(finishChan)
+		})
+		require.Equal(t, ErrShutdownDeadlineExceeded, ei.Close())
+		require.Equal(t, ErrBlockIndexingAborted, <-saveErrChan)
+		require.True(t, removedHeader)
+		require.True(t, removedAccountsESDT)
+	})
+
+	t.Run("should cancel the in-flight requests after the shutdown timeout", func(t *testing.T) {
+		t.Parallel()
+
+		startedChan := make(chan struct{})
+		removedHeader := false
+		arguments := NewDataIndexerArguments()
+		arguments.ShutdownTimeout = 10 * time.Millisecond
+		arguments.BlockContainer = &mock.BlockContainerStub{
+			GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+				return dataBlock.NewEmptyHeaderV2Creator(), nil
+			},
+		}
+		arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+			SaveHeaderCalled: func(ctx context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+				close(startedChan)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			RemoveHeaderCalled: func(header coreData.HeaderHandler) error {
+				removedHeader = true
+				return nil
+			},
+		}
+		ei, _ := NewDataIndexer(arguments)
+
+		saveErrChan := make(chan error, 1)
+		go func() {
+			saveErrChan <- ei.SaveBlock(createOutportBlockWithMiniBlocks())
+		}()
+		<-startedChan
+
+		require.Equal(t, ErrShutdownDeadlineExceeded, ei.Close())
+		require.Equal(t, ErrBlockIndexingAborted, <-saveErrChan)
+		require.True(t, removedHeader)
+	})
+}
+
+func createOutportBlockWithMiniBlocks() *outport.OutportBlock {
+	return &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+			HeaderBytes: []byte("{}"),
+		},
+	}
 }
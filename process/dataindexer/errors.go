@@ -44,6 +44,9 @@ var ErrHeaderTypeAssertion = errors.New("header type assertion failed")
 // ErrNilElasticBlock signals that a nil elastic block has been provided
 var ErrNilElasticBlock = errors.New("nil elastic block")
 
+// ErrNilIndexingCheckpoint signals that a nil indexing checkpoint has been provided
+var ErrNilIndexingCheckpoint = errors.New("nil indexing checkpoint")
+
 // ErrNilElasticProcessorArguments signals that a nil arguments for elastic processor has been provided
 var ErrNilElasticProcessorArguments = errors.New("nil elastic processor arguments")
 
@@ -88,3 +91,83 @@ var ErrNilOperationsHandler = errors.New("nil operations handler")
 
 // ErrNilBlockContainerHandler signals that a nil block container handler has been provided
 var ErrNilBlockContainerHandler = errors.New("nil bock container handler")
+
+// ErrNilFinalizedBlock signals that a nil finalized block has been provided
+var ErrNilFinalizedBlock = errors.New("nil finalized block")
+
+// ErrNilGapsTracker signals that a nil gaps tracker has been provided
+var ErrNilGapsTracker = errors.New("nil gaps tracker")
+
+// ErrIndexerClosed signals that the indexer was closed and does not accept new data
+var ErrIndexerClosed = errors.New("indexer is closed")
+
+// ErrShutdownDeadlineExceeded signals that the in-flight operations did not finish before the shutdown deadline
+var ErrShutdownDeadlineExceeded = errors.New("shutdown deadline exceeded")
+
+// ErrBlockIndexingAborted signals that the indexing of a block was aborted because of the shutdown deadline
+var ErrBlockIndexingAborted = errors.New("block indexing aborted")
+
+// ErrRejectedBulkItems signals that the Elasticsearch cluster rejected bulk items because it is under pressure
+var ErrRejectedBulkItems = errors.New("bulk items rejected by the cluster")
+
+// ErrNilPressureMonitor signals that a nil pressure monitor has been provided
+var ErrNilPressureMonitor = errors.New("nil pressure monitor")
+
+// ErrNilTokenCache signals that a nil token cache has been provided
+var ErrNilTokenCache = errors.New("nil token cache")
+
+// ErrInvalidBulkItemsRetries signals that an invalid number of retries for the failed bulk items has been provided
+var ErrInvalidBulkItemsRetries = errors.New("invalid number of retries for the failed bulk items")
+
+// ErrMultipleAuthMethods signals that more than one way of authenticating to the cluster has been provided
+var ErrMultipleAuthMethods = errors.New("only one of username and password, api key or service token can be provided")
+
+// ErrInvalidSearchSettings signals that an invalid search page size or point in time keep alive has been provided
+var ErrInvalidSearchSettings = errors.New("invalid search page size or keep alive")
+
+// ErrInvalidRequestTimeouts signals that a negative request timeout has been provided
+var ErrInvalidRequestTimeouts = errors.New("invalid request timeouts")
+
+// ErrInvalidBackend signals that an unknown backend type has been provided
+var ErrInvalidBackend = errors.New("invalid backend, it should be auto, elasticsearch, elasticsearch8 or opensearch")
+
+// ErrBackendMismatch signals that the cluster does not run the configured backend
+var ErrBackendMismatch = errors.New("the cluster does not run the configured backend")
+
+// ErrUnsupportedClusterVersion signals that the version of the cluster is not supported
+var ErrUnsupportedClusterVersion = errors.New("unsupported cluster version")
+
+// ErrIncompatibleMirrorBackend signals that a mirror cluster uses another index templates format than the main cluster
+var ErrIncompatibleMirrorBackend = errors.New("the mirror clusters have to run the same backend as the main cluster")
+
+// ErrNilSQLDatabase signals that a nil SQL database has been provided
+var ErrNilSQLDatabase = errors.New("nil sql database")
+
+// ErrEmptyPostgreSQLDSN signals that the PostgreSQL sink is enabled without the connection string of the database
+var ErrEmptyPostgreSQLDSN = errors.New("empty postgresql dsn")
+
+// ErrPostgreSQLWithMirrorClusters signals that mirror clusters are configured together with the PostgreSQL sink
+var ErrPostgreSQLWithMirrorClusters = errors.New("the mirror clusters cannot be used together with the postgresql sink")
+
+// ErrNilAnalyticsSink signals that a nil analytics sink has been provided
+var ErrNilAnalyticsSink = errors.New("nil analytics sink")
+
+// ErrEmptyClickHouseURL signals that the ClickHouse sink is enabled without the address of the ClickHouse server
+var ErrEmptyClickHouseURL = errors.New("empty clickhouse url")
+
+// ErrInvalidClickHouseBatchSize signals that an invalid maximum number of rows per ClickHouse insert has been provided
+var ErrInvalidClickHouseBatchSize = errors.New("invalid clickhouse batch size")
+
+// ErrPostgreSQLWithClickHouse signals that the ClickHouse sink, which is fed by the elastic processor, is enabled
+// together with the PostgreSQL sink
+var ErrPostgreSQLWithClickHouse = errors.New("the clickhouse sink cannot be used together with the postgresql sink")
+
+// ErrFileSinkWithMirrorClusters signals that mirror clusters are configured together with the file sink
+var ErrFileSinkWithMirrorClusters = errors.New("the mirror clusters cannot be used together with the file sink")
+
+// ErrPostgreSQLWithFileSink signals that the file sink, which replaces the cluster client, is enabled together with the
+// PostgreSQL sink
+var ErrPostgreSQLWithFileSink = errors.New("the file sink cannot be used together with the postgresql sink")
+
+// ErrUnprocessablePayload signals that a payload can never be processed, no matter how many times it is retried
+var ErrUnprocessablePayload = errors.New("unprocessable payload")
@@ -0,0 +1,29 @@
+package dataindexer
+
+import "fmt"
+
+// FailedBulkItem holds an operation of a bulk request that Elasticsearch refused
+type FailedBulkItem struct {
+	Index     string
+	ID        string
+	Status    int
+	Reason    string
+	Operation []byte
+}
+
+// FailedBulkItemsError signals that Elasticsearch refused some items of a bulk request for a reason that sending them
+// again cannot fix, such as a mapping error. All the other items of the request were indexed
+type FailedBulkItemsError struct {
+	Items []*FailedBulkItem
+	Err   error
+}
+
+// Error returns the error message
+func (fbe *FailedBulkItemsError) Error() string {
+	return fmt.Sprintf("%d failed bulk items: %v", len(fbe.Items), fbe.Err)
+}
+
+// Unwrap returns the wrapped error
+func (fbe *FailedBulkItemsError) Unwrap() error {
+	return fbe.Err
+}
@@ -1,29 +1,36 @@
 package dataindexer
 
 import (
+	"context"
 	"math/big"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	coreData "github.com/multiversx/mx-chain-core-go/data"
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
 )
 
 // ElasticProcessor defines the interface for the elastic search indexer
 type ElasticProcessor interface {
-	SaveHeader(outportBlockWithHeader *outport.OutportBlockWithHeader) error
-	RemoveHeader(header coreData.HeaderHandler) error
-	RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error
-	RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error
-	RemoveAccountsESDT(headerTimestamp uint64, shardID uint32) error
-	SaveMiniblocks(header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error
-	SaveTransactions(outportBlockWithHeader *outport.OutportBlockWithHeader) error
-	SaveValidatorsRating(ratingData *outport.ValidatorsRating) error
-	SaveRoundsInfo(rounds *outport.RoundsInfo) error
-	SaveShardValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error
+	SaveHeader(ctx context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error
+	RemoveHeader(ctx context.Context, header coreData.HeaderHandler) error
+	RemoveMiniblocks(ctx context.Context, header coreData.HeaderHandler, body *block.Body) error
+	RemoveTransactions(ctx context.Context, header coreData.HeaderHandler, body *block.Body) error
+	RemoveAccountsESDT(ctx context.Context, headerTimestamp uint64, shardID uint32) error
+	SaveMiniblocks(ctx context.Context, header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error
+	SaveTransactions(ctx context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error
+	SaveValidatorsRating(ctx context.Context, ratingData *outport.ValidatorsRating) error
+	SaveRoundsInfo(ctx context.Context, rounds *outport.RoundsInfo) error
+	SaveShardValidatorsPubKeys(ctx context.Context, validatorsPubKeys *outport.ValidatorsPubKeys) error
 	SaveAccounts(accounts *outport.Accounts) error
+	SaveFinalizedBlock(ctx context.Context, finalizedBlock *outport.FinalizedBlock) error
+	GetIndexingCheckpoint(ctx context.Context, shardID uint32) (*data.IndexingCheckpoint, error)
+	SaveIndexingCheckpoint(ctx context.Context, shardID uint32, checkpoint *data.IndexingCheckpoint) error
 	SetOutportConfig(cfg outport.OutportConfig) error
+	Close() error
 	IsInterfaceNil() bool
 }
 
@@ -70,6 +77,24 @@ type BalanceConverter interface {
 	IsInterfaceNil() bool
 }
 
+// GapsTrackerHandler defines what a component that detects gaps in the indexed blocks should be able to do
+type GapsTrackerHandler interface {
+	HasCheckpoint(shardID uint32) bool
+	SetCheckpoint(shardID uint32, nonce uint64, hash []byte)
+	ProcessBlock(shardID uint32, nonce uint64, hash []byte, prevHash []byte)
+	IsInterfaceNil() bool
+}
+
+// PressureMonitorHandler defines what a component that tracks the pressure of the Elasticsearch cluster should be able
+// to do
+type PressureMonitorHandler interface {
+	RecordResponse(duration time.Duration, statusCode int, err error)
+	RecordRejection()
+	GetAckDelay() time.Duration
+	GetBulkRequestMaxSize() int
+	IsInterfaceNil() bool
+}
+
 // BlockContainerHandler defines what a block container should be able to do
 type BlockContainerHandler interface {
 	Get(headerType core.HeaderType) (block.EmptyBlockCreator, error)
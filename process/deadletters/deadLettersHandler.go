@@ -0,0 +1,160 @@
+package deadletters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const matchAllQuery = `{"query":{"match_all":{}}}`
+
+var log = logger.GetOrCreate("process/deadletters")
+
+// ArgsDeadLettersHandler holds all the components needed to create a new instance of deadLettersHandler
+type ArgsDeadLettersHandler struct {
+	DBClient DatabaseClientHandler
+}
+
+type responseDeadLetters struct {
+	Docs []struct {
+		Found  bool                       `json:"found"`
+		ID     string                     `json:"_id"`
+		Source request.DeadLetterResponse `json:"_source"`
+	} `json:"docs"`
+}
+
+type responseScrollDeadLetters struct {
+	Hits struct {
+		Hits []struct {
+			ID     string                     `json:"_id"`
+			Source request.DeadLetterResponse `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type deadLettersHandler struct {
+	dbClient DatabaseClientHandler
+}
+
+// NewDeadLettersHandler will create a new instance of deadLettersHandler
+func NewDeadLettersHandler(args ArgsDeadLettersHandler) (*deadLettersHandler, error) {
+	if check.IfNil(args.DBClient) {
+		return nil, dataindexer.ErrNilDatabaseClient
+	}
+
+	return &deadLettersHandler{
+		dbClient: args.DBClient,
+	}, nil
+}
+
+// GetDeadLetters returns all the documents from the deadletters index
+func (dlh *deadLettersHandler) GetDeadLetters() ([]*request.DeadLetterResponse, error) {
+	deadLetters := make([]*request.DeadLetterResponse, 0)
+	handlerFunc := func(responseBytes []byte) error {
+		response := &responseScrollDeadLetters{}
+		err := json.Unmarshal(responseBytes, response)
+		if err != nil {
+			return err
+		}
+
+		for idx := range response.Hits.Hits {
+			deadLetter := response.Hits.Hits[idx].Source
+			deadLetter.ID = response.Hits.Hits[idx].ID
+			deadLetters = append(deadLetters, &deadLetter)
+		}
+
+		return nil
+	}
+
+	err := dlh.dbClient.DoScrollRequest(context.Background(), dataindexer.DeadLettersIndex, []byte(matchAllQuery), true, handlerFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return deadLetters, nil
+}
+
+// ResubmitDeadLetters sends again the operations of the provided dead letters, or of all of them when no ID is
+// provided. The dead letters that are indexed successfully are removed from the deadletters index
+func (dlh *deadLettersHandler) ResubmitDeadLetters(ids []string) (*request.ResubmitResponse, error) {
+	deadLetters, err := dlh.getDeadLettersByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &request.ResubmitResponse{
+		Resubmitted: make([]string, 0),
+		Failed:      make(map[string]string),
+	}
+	for _, deadLetter := range deadLetters {
+		err = dlh.resubmitDeadLetter(deadLetter)
+		if err != nil {
+			log.Debug("deadLettersHandler.ResubmitDeadLetters: cannot resubmit", "id", deadLetter.ID, "error", err)
+			response.Failed[deadLetter.ID] = err.Error()
+			continue
+		}
+
+		response.Resubmitted = append(response.Resubmitted, deadLetter.ID)
+	}
+
+	return response, nil
+}
+
+func (dlh *deadLettersHandler) getDeadLettersByIDs(ids []string) ([]*request.DeadLetterResponse, error) {
+	if len(ids) == 0 {
+		return dlh.GetDeadLetters()
+	}
+
+	response := &responseDeadLetters{}
+	err := dlh.dbClient.DoMultiGet(context.Background(), ids, dataindexer.DeadLettersIndex, true, response)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]*request.DeadLetterResponse, 0, len(response.Docs))
+	for idx := range response.Docs {
+		if !response.Docs[idx].Found {
+			return nil, fmt.Errorf("%w: %s", errDeadLetterNotFound, response.Docs[idx].ID)
+		}
+
+		deadLetter := response.Docs[idx].Source
+		deadLetter.ID = response.Docs[idx].ID
+		deadLetters = append(deadLetters, &deadLetter)
+	}
+
+	return deadLetters, nil
+}
+
+// resubmitDeadLetter sends the operation of the dead letter to the index it was refused by and, on success, removes
+// the dead letter. The index of the request is needed for the operations without an _index in their action line
+func (dlh *deadLettersHandler) resubmitDeadLetter(deadLetter *request.DeadLetterResponse) error {
+	err := dlh.dbClient.DoBulkRequest(context.Background(), bytes.NewBufferString(deadLetter.Operation), deadLetter.Index)
+	if err != nil {
+		return err
+	}
+
+	meta := fmt.Sprintf(`{ "delete" : { "_index": "%s", "_id" : "%s" } }%s`, dataindexer.DeadLettersIndex, converters.JsonEscape(deadLetter.ID), "\n")
+	err = dlh.dbClient.DoBulkRequest(context.Background(), bytes.NewBufferString(meta), "")
+	if err != nil {
+		return fmt.Errorf("%w while removing the resubmitted dead letter", err)
+	}
+
+	return nil
+}
+
+// Close will close the database client
+func (dlh *deadLettersHandler) Close() error {
+	return dlh.dbClient.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dlh *deadLettersHandler) IsInterfaceNil() bool {
+	return dlh == nil
+}
@@ -0,0 +1,127 @@
+package deadletters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+const deadLetterSource = `{"index":"transactions-000001","docID":"h1","operation":"{\"index\":{\"_id\":\"h1\"}}\n{\"nonce\":1}\n","status":400,"reason":"mapper_parsing_exception","shardID":1,"nonce":10,"timestamp":5}`
+
+func TestNewDeadLettersHandler(t *testing.T) {
+	t.Parallel()
+
+	dlh, err := NewDeadLettersHandler(ArgsDeadLettersHandler{})
+	require.Nil(t, dlh)
+	require.Equal(t, dataindexer.ErrNilDatabaseClient, err)
+
+	dlh, err = NewDeadLettersHandler(ArgsDeadLettersHandler{DBClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, err)
+	require.False(t, dlh.IsInterfaceNil())
+}
+
+func TestDeadLettersHandler_GetDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	dlh, _ := NewDeadLettersHandler(ArgsDeadLettersHandler{
+		DBClient: &mock.DatabaseWriterStub{
+			DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+				require.Equal(t, dataindexer.DeadLettersIndex, index)
+				return handlerFunc([]byte(`{"hits":{"hits":[{"_id":"transactions-000001_h1","_source":` + deadLetterSource + `}]}}`))
+			},
+		},
+	})
+
+	deadLetters, err := dlh.GetDeadLetters()
+	require.Nil(t, err)
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, "transactions-000001_h1", deadLetters[0].ID)
+	require.Equal(t, "h1", deadLetters[0].DocID)
+	require.Equal(t, uint64(10), deadLetters[0].Nonce)
+	require.Equal(t, "{\"index\":{\"_id\":\"h1\"}}\n{\"nonce\":1}\n", deadLetters[0].Operation)
+}
+
+func TestDeadLettersHandler_ResubmitDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dead letter not found should error", func(t *testing.T) {
+		t.Parallel()
+
+		dlh, _ := NewDeadLettersHandler(ArgsDeadLettersHandler{
+			DBClient: &mock.DatabaseWriterStub{
+				DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+					return json.Unmarshal([]byte(`{"docs":[{"_id":"missing","found":false}]}`), response)
+				},
+			},
+		})
+
+		response, err := dlh.ResubmitDeadLetters([]string{"missing"})
+		require.Nil(t, response)
+		require.True(t, errors.Is(err, errDeadLetterNotFound))
+	})
+
+	t.Run("should resubmit and remove the indexed dead letters", func(t *testing.T) {
+		t.Parallel()
+
+		bulkBodies := make([]string, 0)
+		dlh, _ := NewDeadLettersHandler(ArgsDeadLettersHandler{
+			DBClient: &mock.DatabaseWriterStub{
+				DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+					require.Equal(t, []string{"d1", "d2"}, ids)
+					return json.Unmarshal([]byte(`{"docs":[{"_id":"d1","found":true,"_source":`+deadLetterSource+`},{"_id":"d2","found":true,"_source":`+strings.Replace(deadLetterSource, "h1", "h2", -1)+`}]}`), response)
+				},
+				DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+					bulkBodies = append(bulkBodies, buff.String())
+					if strings.Contains(buff.String(), "h2") {
+						return errors.New("mapper_parsing_exception")
+					}
+
+					return nil
+				},
+			},
+		})
+
+		response, err := dlh.ResubmitDeadLetters([]string{"d1", "d2"})
+		require.Nil(t, err)
+		require.Equal(t, []string{"d1"}, response.Resubmitted)
+		require.Equal(t, map[string]string{"d2": "mapper_parsing_exception"}, response.Failed)
+
+		require.Len(t, bulkBodies, 3)
+		require.Equal(t, "{\"index\":{\"_id\":\"h1\"}}\n{\"nonce\":1}\n", bulkBodies[0])
+		require.Equal(t, `{ "delete" : { "_index": "deadletters", "_id" : "d1" } }`+"\n", bulkBodies[1])
+	})
+}
+
+func TestDeadLettersHandler_CloseShouldCloseTheDatabaseClient(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	dlh, _ := NewDeadLettersHandler(ArgsDeadLettersHandler{DBClient: &mock.DatabaseWriterStub{
+		CloseCalled: func() error {
+			return localErr
+		},
+	}})
+
+	require.Equal(t, localErr, dlh.Close())
+}
+
+func TestDisabledDeadLettersHandler(t *testing.T) {
+	t.Parallel()
+
+	ddlh := NewDisabledDeadLettersHandler()
+	require.False(t, ddlh.IsInterfaceNil())
+
+	deadLetters, err := ddlh.GetDeadLetters()
+	require.Nil(t, err)
+	require.Empty(t, deadLetters)
+
+	_, err = ddlh.ResubmitDeadLetters(nil)
+	require.Equal(t, ErrDeadLettersDisabled, err)
+	require.Nil(t, ddlh.Close())
+}
@@ -0,0 +1,30 @@
+package deadletters
+
+import "github.com/multiversx/mx-chain-es-indexer-go/core/request"
+
+type disabledDeadLettersHandler struct{}
+
+// NewDisabledDeadLettersHandler will create a dead letters handler to be used when the dead letters are disabled
+func NewDisabledDeadLettersHandler() *disabledDeadLettersHandler {
+	return &disabledDeadLettersHandler{}
+}
+
+// GetDeadLetters returns an empty slice
+func (ddlh *disabledDeadLettersHandler) GetDeadLetters() ([]*request.DeadLetterResponse, error) {
+	return make([]*request.DeadLetterResponse, 0), nil
+}
+
+// ResubmitDeadLetters returns ErrDeadLettersDisabled
+func (ddlh *disabledDeadLettersHandler) ResubmitDeadLetters(_ []string) (*request.ResubmitResponse, error) {
+	return nil, ErrDeadLettersDisabled
+}
+
+// Close does nothing
+func (ddlh *disabledDeadLettersHandler) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ddlh *disabledDeadLettersHandler) IsInterfaceNil() bool {
+	return ddlh == nil
+}
@@ -0,0 +1,8 @@
+package deadletters
+
+import "errors"
+
+var errDeadLetterNotFound = errors.New("dead letter not found")
+
+// ErrDeadLettersDisabled signals that the dead letters are not enabled
+var ErrDeadLettersDisabled = errors.New("dead letters are disabled")
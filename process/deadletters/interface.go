@@ -0,0 +1,15 @@
+package deadletters
+
+import (
+	"bytes"
+	"context"
+)
+
+// DatabaseClientHandler defines the actions that the dead letters handler needs from the database client
+type DatabaseClientHandler interface {
+	DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error
+	DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error
+	DoScrollRequest(ctx context.Context, index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	Close() error
+	IsInterfaceNil() bool
+}
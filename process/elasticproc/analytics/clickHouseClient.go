@@ -0,0 +1,76 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	headerUser     = "X-ClickHouse-User"
+	headerKey      = "X-ClickHouse-Key"
+	maxErrorLength = 1024
+)
+
+// clickHouseClient sends the queries to the HTTP interface of a ClickHouse server
+type clickHouseClient struct {
+	url        string
+	database   string
+	userName   string
+	password   string
+	httpClient *http.Client
+}
+
+// exec runs a query that does not return any data. The params are bound to the {name:Type} placeholders of the query
+func (cc *clickHouseClient) exec(ctx context.Context, query string, params map[string]string) error {
+	values := url.Values{}
+	for name, value := range params {
+		values.Set("param_"+name, value)
+	}
+
+	return cc.do(ctx, values, strings.NewReader(query))
+}
+
+// insert sends the rows of the body, one JSON object per line, to the provided table
+func (cc *clickHouseClient) insert(ctx context.Context, table string, body *bytes.Buffer) error {
+	values := url.Values{}
+	values.Set("query", "INSERT INTO "+table+" FORMAT JSONEachRow")
+
+	return cc.do(ctx, values, body)
+}
+
+func (cc *clickHouseClient) do(ctx context.Context, values url.Values, body io.Reader) error {
+	if cc.database != "" {
+		values.Set("database", cc.database)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cc.url+"/?"+values.Encode(), body)
+	if err != nil {
+		return err
+	}
+	if cc.userName != "" {
+		req.Header.Set(headerUser, cc.userName)
+		req.Header.Set(headerKey, cc.password)
+	}
+
+	resp, err := cc.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	// ClickHouse answers a failed query with the text of the exception
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	return fmt.Errorf("%w, status code %d: %s", errClickHouseRequestFailed, resp.StatusCode, strings.TrimSpace(string(message)))
+}
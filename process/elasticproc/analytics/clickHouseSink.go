@@ -0,0 +1,181 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("indexer/process/analytics")
+
+// ArgsClickHouseSink holds all dependencies required by the ClickHouse sink in order to create new instances
+type ArgsClickHouseSink struct {
+	// URL is the address of the HTTP interface of the ClickHouse server, e.g. http://localhost:8123
+	URL      string
+	Database string
+	UserName string
+	Password string
+	// MaxBatchSize is the maximum number of rows sent in an insert request
+	MaxBatchSize int
+	// RequestTimeout is the timeout of every request. 0 means no timeout
+	RequestTimeout time.Duration
+}
+
+type clickHouseSink struct {
+	client       *clickHouseClient
+	maxBatchSize int
+	getVersion   func() uint64
+}
+
+// NewClickHouseSink will create a sink that saves the transactions, the smart contract results, the receipts and the
+// events of every block in ClickHouse tables. The tables that do not exist yet are created
+func NewClickHouseSink(args ArgsClickHouseSink) (*clickHouseSink, error) {
+	if args.URL == "" {
+		return nil, dataindexer.ErrEmptyClickHouseURL
+	}
+	if args.MaxBatchSize < 1 {
+		return nil, dataindexer.ErrInvalidClickHouseBatchSize
+	}
+
+	chs := &clickHouseSink{
+		client: &clickHouseClient{
+			url:        strings.TrimSuffix(args.URL, "/"),
+			database:   args.Database,
+			userName:   args.UserName,
+			password:   args.Password,
+			httpClient: &http.Client{Timeout: args.RequestTimeout},
+		},
+		maxBatchSize: args.MaxBatchSize,
+		getVersion: func() uint64 {
+			return uint64(time.Now().UnixNano())
+		},
+	}
+
+	err := chs.createTables(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return chs, nil
+}
+
+func (chs *clickHouseSink) createTables(ctx context.Context) error {
+	for _, statement := range createTableStatements {
+		err := chs.client.exec(ctx, statement, nil)
+		if err != nil {
+			return fmt.Errorf("%w while creating the clickhouse tables", err)
+		}
+	}
+
+	return nil
+}
+
+// SaveBlockResults will save the transactions, the smart contract results, the receipts and the events of a block. The
+// rows are versioned, so a block that is saved again replaces its rows instead of duplicating them
+func (chs *clickHouseSink) SaveBlockResults(
+	ctx context.Context,
+	headerHash []byte,
+	header coreData.HeaderHandler,
+	preparedResults *data.PreparedResults,
+	logsData *data.PreparedLogsResults,
+) error {
+	block := blockInfo{
+		hash:    hex.EncodeToString(headerHash),
+		shardID: header.GetShardID(),
+		version: chs.getVersion(),
+	}
+
+	txs := make([]interface{}, 0, len(preparedResults.Transactions))
+	for _, tx := range preparedResults.Transactions {
+		txs = append(txs, newTransactionRow(tx, block))
+	}
+	err := chs.insert(ctx, transactionsTable, txs)
+	if err != nil {
+		return err
+	}
+
+	scrs := make([]interface{}, 0, len(preparedResults.ScResults))
+	for _, scr := range preparedResults.ScResults {
+		scrs = append(scrs, newSCResultRow(scr, block))
+	}
+	err = chs.insert(ctx, scResultsTable, scrs)
+	if err != nil {
+		return err
+	}
+
+	receipts := make([]interface{}, 0, len(preparedResults.Receipts))
+	for _, receipt := range preparedResults.Receipts {
+		receipts = append(receipts, newReceiptRow(receipt, block))
+	}
+	err = chs.insert(ctx, receiptsTable, receipts)
+	if err != nil {
+		return err
+	}
+
+	events := make([]interface{}, 0, len(logsData.DBEvents))
+	for _, event := range logsData.DBEvents {
+		events = append(events, newEventRow(event, block))
+	}
+
+	return chs.insert(ctx, eventsTable, events)
+}
+
+// insert sends the rows in batches of at most maxBatchSize rows
+func (chs *clickHouseSink) insert(ctx context.Context, table string, rows []interface{}) error {
+	for start := 0; start < len(rows); start += chs.maxBatchSize {
+		end := start + chs.maxBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		body := &bytes.Buffer{}
+		encoder := json.NewEncoder(body)
+		for _, row := range rows[start:end] {
+			err := encoder.Encode(row)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := chs.client.insert(ctx, table, body)
+		if err != nil {
+			return fmt.Errorf("%w when inserting in the %s clickhouse table", err, table)
+		}
+	}
+
+	return nil
+}
+
+// RevertBlock will mark as deleted all the rows saved by the block with the provided hash
+func (chs *clickHouseSink) RevertBlock(ctx context.Context, headerHash []byte) error {
+	params := map[string]string{
+		"hash":    hex.EncodeToString(headerHash),
+		"version": strconv.FormatUint(chs.getVersion(), 10),
+	}
+
+	for _, table := range tables {
+		err := chs.client.exec(ctx, fmt.Sprintf(revertStatement, table, table), params)
+		if err != nil {
+			return fmt.Errorf("%w when reverting the block in the %s clickhouse table", err, table)
+		}
+	}
+
+	log.Debug("clickHouseSink.RevertBlock", "hash", params["hash"])
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (chs *clickHouseSink) IsInterfaceNil() bool {
+	return chs == nil
+}
@@ -0,0 +1,237 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+type clickHouseRequest struct {
+	params  map[string]string
+	headers http.Header
+	body    string
+}
+
+// clickHouseServerStub records the requests sent to the HTTP interface of ClickHouse and answers them with the
+// provided status code
+type clickHouseServerStub struct {
+	*httptest.Server
+	mut        sync.Mutex
+	requests   []clickHouseRequest
+	statusCode int
+}
+
+func newClickHouseServerStub(t *testing.T) *clickHouseServerStub {
+	stub := &clickHouseServerStub{statusCode: http.StatusOK}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		params := make(map[string]string)
+		for key := range r.URL.Query() {
+			params[key] = r.URL.Query().Get(key)
+		}
+
+		stub.mut.Lock()
+		stub.requests = append(stub.requests, clickHouseRequest{params: params, headers: r.Header, body: string(body)})
+		statusCode := stub.statusCode
+		stub.mut.Unlock()
+
+		w.WriteHeader(statusCode)
+		if statusCode != http.StatusOK {
+			_, _ = w.Write([]byte("Code: 60. DB::Exception: Table default.transactions does not exist\n"))
+		}
+	}))
+	t.Cleanup(stub.Close)
+
+	return stub
+}
+
+func (stub *clickHouseServerStub) setStatusCode(statusCode int) {
+	stub.mut.Lock()
+	stub.statusCode = statusCode
+	stub.mut.Unlock()
+}
+
+func (stub *clickHouseServerStub) getRequests() []clickHouseRequest {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+
+	return append([]clickHouseRequest{}, stub.requests...)
+}
+
+func (stub *clickHouseServerStub) resetRequests() {
+	stub.mut.Lock()
+	stub.requests = nil
+	stub.mut.Unlock()
+}
+
+func createMockArgsClickHouseSink(url string) ArgsClickHouseSink {
+	return ArgsClickHouseSink{
+		URL:          url,
+		Database:     "indexer",
+		UserName:     "user",
+		Password:     "pass",
+		MaxBatchSize: 100,
+	}
+}
+
+func TestNewClickHouseSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty url should error", func(t *testing.T) {
+		t.Parallel()
+
+		sink, err := NewClickHouseSink(createMockArgsClickHouseSink(""))
+		require.Nil(t, sink)
+		require.Equal(t, dataindexer.ErrEmptyClickHouseURL, err)
+	})
+
+	t.Run("invalid batch size should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsClickHouseSink("http://localhost:8123")
+		args.MaxBatchSize = 0
+		sink, err := NewClickHouseSink(args)
+		require.Nil(t, sink)
+		require.Equal(t, dataindexer.ErrInvalidClickHouseBatchSize, err)
+	})
+
+	t.Run("should create the tables", func(t *testing.T) {
+		t.Parallel()
+
+		server := newClickHouseServerStub(t)
+		sink, err := NewClickHouseSink(createMockArgsClickHouseSink(server.URL + "/"))
+		require.Nil(t, err)
+		require.False(t, sink.IsInterfaceNil())
+
+		requests := server.getRequests()
+		require.Len(t, requests, len(tables))
+		for i, table := range tables {
+			require.True(t, strings.HasPrefix(requests[i].body, "CREATE TABLE IF NOT EXISTS "+table+" ("))
+			require.Contains(t, requests[i].body, "ENGINE = ReplacingMergeTree(version, is_deleted)")
+			require.Equal(t, map[string]string{"database": "indexer"}, requests[i].params)
+			require.Equal(t, "user", requests[i].headers.Get(headerUser))
+			require.Equal(t, "pass", requests[i].headers.Get(headerKey))
+		}
+	})
+
+	t.Run("failed request should error", func(t *testing.T) {
+		t.Parallel()
+
+		server := newClickHouseServerStub(t)
+		server.setStatusCode(http.StatusInternalServerError)
+
+		sink, err := NewClickHouseSink(createMockArgsClickHouseSink(server.URL))
+		require.Nil(t, sink)
+		require.True(t, errors.Is(err, errClickHouseRequestFailed))
+		require.Contains(t, err.Error(), "status code 500: Code: 60. DB::Exception")
+	})
+}
+
+func TestClickHouseSink_SaveBlockResults(t *testing.T) {
+	t.Parallel()
+
+	server := newClickHouseServerStub(t)
+	args := createMockArgsClickHouseSink(server.URL)
+	args.MaxBatchSize = 2
+	sink, err := NewClickHouseSink(args)
+	require.Nil(t, err)
+	sink.getVersion = func() uint64 {
+		return 7
+	}
+	server.resetRequests()
+
+	preparedResults := &data.PreparedResults{
+		Transactions: []*data.Transaction{
+			{Hash: "tx1", Value: "10", Timestamp: 1000},
+			{Hash: "tx2", Tokens: []string{"TKN-abcd"}, ESDTValues: []string{"5"}},
+			{Hash: "tx3"},
+		},
+		Receipts: []*data.Receipt{{Hash: "receipt", TxHash: "tx1"}},
+	}
+	logsData := &data.PreparedLogsResults{
+		DBEvents: []*data.LogEvent{{ID: "tx1-0", Identifier: "ESDTTransfer", Topics: []string{"aa"}}},
+	}
+	err = sink.SaveBlockResults(context.Background(), []byte("hash"), &dataBlock.Header{ShardID: 1}, preparedResults, logsData)
+	require.Nil(t, err)
+
+	requests := server.getRequests()
+	require.Len(t, requests, 4)
+	require.Equal(t, "INSERT INTO transactions FORMAT JSONEachRow", requests[0].params["query"])
+	require.Equal(t, "indexer", requests[0].params["database"])
+	require.Equal(t, "INSERT INTO transactions FORMAT JSONEachRow", requests[1].params["query"])
+	require.Equal(t, "INSERT INTO receipts FORMAT JSONEachRow", requests[2].params["query"])
+	require.Equal(t, "INSERT INTO events FORMAT JSONEachRow", requests[3].params["query"])
+
+	lines := strings.Split(strings.TrimSpace(requests[0].body), "\n")
+	require.Len(t, lines, 2)
+	row := &transactionRow{}
+	err = json.Unmarshal([]byte(lines[0]), row)
+	require.Nil(t, err)
+	require.Equal(t, &transactionRow{
+		BlockHash:  "68617368",
+		Hash:       "tx1",
+		ShardID:    1,
+		Value:      "10",
+		Tokens:     []string{},
+		ESDTValues: []string{},
+		Timestamp:  1000,
+		Version:    7,
+	}, row)
+	require.Contains(t, lines[1], `"tokens":["TKN-abcd"],"esdt_values":["5"]`)
+	require.Len(t, strings.Split(strings.TrimSpace(requests[1].body), "\n"), 1)
+	require.Contains(t, requests[3].body, `"block_hash":"68617368","id":"tx1-0","shard_id":1`)
+}
+
+func TestClickHouseSink_SaveBlockResultsFailedInsertShouldError(t *testing.T) {
+	t.Parallel()
+
+	server := newClickHouseServerStub(t)
+	sink, err := NewClickHouseSink(createMockArgsClickHouseSink(server.URL))
+	require.Nil(t, err)
+	server.setStatusCode(http.StatusBadRequest)
+
+	preparedResults := &data.PreparedResults{
+		ScResults: []*data.ScResult{{Hash: "scr"}},
+	}
+	err = sink.SaveBlockResults(context.Background(), []byte("hash"), &dataBlock.Header{}, preparedResults, &data.PreparedLogsResults{})
+	require.True(t, errors.Is(err, errClickHouseRequestFailed))
+	require.Contains(t, err.Error(), "sc_results")
+}
+
+func TestClickHouseSink_RevertBlock(t *testing.T) {
+	t.Parallel()
+
+	server := newClickHouseServerStub(t)
+	sink, err := NewClickHouseSink(createMockArgsClickHouseSink(server.URL))
+	require.Nil(t, err)
+	sink.getVersion = func() uint64 {
+		return 8
+	}
+	server.resetRequests()
+
+	err = sink.RevertBlock(context.Background(), []byte("hash"))
+	require.Nil(t, err)
+
+	requests := server.getRequests()
+	require.Len(t, requests, len(tables))
+	for i, table := range tables {
+		require.Equal(t, "INSERT INTO "+table+" SELECT * REPLACE ({version:UInt64} AS version, 1 AS is_deleted) FROM "+
+			table+" FINAL WHERE block_hash = {hash:String}", requests[i].body)
+		require.Equal(t, map[string]string{
+			"database":      "indexer",
+			"param_hash":    "68617368",
+			"param_version": "8",
+		}, requests[i].params)
+	}
+}
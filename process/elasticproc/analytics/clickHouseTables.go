@@ -0,0 +1,311 @@
+package analytics
+
+import (
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+const (
+	transactionsTable = "transactions"
+	scResultsTable    = "sc_results"
+	receiptsTable     = "receipts"
+	eventsTable       = "events"
+)
+
+// tables holds the name of every table written by the sink
+var tables = []string{transactionsTable, scResultsTable, receiptsTable, eventsTable}
+
+// Every table is a ReplacingMergeTree sorted by the hash of the block that saved the row, so the rows of a block saved
+// again, e.g. after a retry, replace the previous ones, and the rows of a reverted block are replaced by copies marked
+// as deleted. The row with the highest version wins and a SELECT ... FINAL skips the deleted rows
+var createTableStatements = []string{
+	`CREATE TABLE IF NOT EXISTS transactions (
+    block_hash String,
+    hash String,
+    miniblock_hash String,
+    shard_id UInt32,
+    nonce UInt64,
+    round UInt64,
+    sender String,
+    receiver String,
+    sender_shard UInt32,
+    receiver_shard UInt32,
+    value String,
+    value_num Float64,
+    fee String,
+    fee_num Float64,
+    gas_price UInt64,
+    gas_limit UInt64,
+    gas_used UInt64,
+    status LowCardinality(String),
+    type LowCardinality(String),
+    operation LowCardinality(String),
+    function String,
+    is_sc_call Bool,
+    is_relayed Bool,
+    tokens Array(String),
+    esdt_values Array(String),
+    timestamp DateTime,
+    version UInt64,
+    is_deleted UInt8 DEFAULT 0
+) ENGINE = ReplacingMergeTree(version, is_deleted)
+PARTITION BY toYYYYMM(timestamp)
+ORDER BY (block_hash, hash)`,
+	`CREATE TABLE IF NOT EXISTS sc_results (
+    block_hash String,
+    hash String,
+    miniblock_hash String,
+    shard_id UInt32,
+    nonce UInt64,
+    sender String,
+    receiver String,
+    sender_shard UInt32,
+    receiver_shard UInt32,
+    value String,
+    value_num Float64,
+    original_tx_hash String,
+    prev_tx_hash String,
+    call_type LowCardinality(String),
+    return_message String,
+    status LowCardinality(String),
+    operation LowCardinality(String),
+    function String,
+    tokens Array(String),
+    esdt_values Array(String),
+    timestamp DateTime,
+    version UInt64,
+    is_deleted UInt8 DEFAULT 0
+) ENGINE = ReplacingMergeTree(version, is_deleted)
+PARTITION BY toYYYYMM(timestamp)
+ORDER BY (block_hash, hash)`,
+	`CREATE TABLE IF NOT EXISTS receipts (
+    block_hash String,
+    hash String,
+    shard_id UInt32,
+    tx_hash String,
+    sender String,
+    value String,
+    data String,
+    timestamp DateTime,
+    version UInt64,
+    is_deleted UInt8 DEFAULT 0
+) ENGINE = ReplacingMergeTree(version, is_deleted)
+PARTITION BY toYYYYMM(timestamp)
+ORDER BY (block_hash, hash)`,
+	`CREATE TABLE IF NOT EXISTS events (
+    block_hash String,
+    id String,
+    shard_id UInt32,
+    tx_hash String,
+    original_tx_hash String,
+    log_address String,
+    address String,
+    identifier LowCardinality(String),
+    topics Array(String),
+    data String,
+    additional_data Array(String),
+    event_order Int32,
+    tx_order Int32,
+    timestamp DateTime,
+    version UInt64,
+    is_deleted UInt8 DEFAULT 0
+) ENGINE = ReplacingMergeTree(version, is_deleted)
+PARTITION BY toYYYYMM(timestamp)
+ORDER BY (block_hash, id)`,
+}
+
+// revertStatement copies the rows of the reverted block with a higher version and marks the copies as deleted
+const revertStatement = `INSERT INTO %s SELECT * REPLACE ({version:UInt64} AS version, 1 AS is_deleted) FROM %s FINAL WHERE block_hash = {hash:String}`
+
+// blockInfo holds the fields of the block that are added to every row
+type blockInfo struct {
+	hash    string
+	shardID uint32
+	version uint64
+}
+
+type transactionRow struct {
+	BlockHash     string   `json:"block_hash"`
+	Hash          string   `json:"hash"`
+	MBHash        string   `json:"miniblock_hash"`
+	ShardID       uint32   `json:"shard_id"`
+	Nonce         uint64   `json:"nonce"`
+	Round         uint64   `json:"round"`
+	Sender        string   `json:"sender"`
+	Receiver      string   `json:"receiver"`
+	SenderShard   uint32   `json:"sender_shard"`
+	ReceiverShard uint32   `json:"receiver_shard"`
+	Value         string   `json:"value"`
+	ValueNum      float64  `json:"value_num"`
+	Fee           string   `json:"fee"`
+	FeeNum        float64  `json:"fee_num"`
+	GasPrice      uint64   `json:"gas_price"`
+	GasLimit      uint64   `json:"gas_limit"`
+	GasUsed       uint64   `json:"gas_used"`
+	Status        string   `json:"status"`
+	Type          string   `json:"type"`
+	Operation     string   `json:"operation"`
+	Function      string   `json:"function"`
+	IsScCall      bool     `json:"is_sc_call"`
+	IsRelayed     bool     `json:"is_relayed"`
+	Tokens        []string `json:"tokens"`
+	ESDTValues    []string `json:"esdt_values"`
+	Timestamp     uint64   `json:"timestamp"`
+	Version       uint64   `json:"version"`
+}
+
+type scResultRow struct {
+	BlockHash      string   `json:"block_hash"`
+	Hash           string   `json:"hash"`
+	MBHash         string   `json:"miniblock_hash"`
+	ShardID        uint32   `json:"shard_id"`
+	Nonce          uint64   `json:"nonce"`
+	Sender         string   `json:"sender"`
+	Receiver       string   `json:"receiver"`
+	SenderShard    uint32   `json:"sender_shard"`
+	ReceiverShard  uint32   `json:"receiver_shard"`
+	Value          string   `json:"value"`
+	ValueNum       float64  `json:"value_num"`
+	OriginalTxHash string   `json:"original_tx_hash"`
+	PrevTxHash     string   `json:"prev_tx_hash"`
+	CallType       string   `json:"call_type"`
+	ReturnMessage  string   `json:"return_message"`
+	Status         string   `json:"status"`
+	Operation      string   `json:"operation"`
+	Function       string   `json:"function"`
+	Tokens         []string `json:"tokens"`
+	ESDTValues     []string `json:"esdt_values"`
+	Timestamp      uint64   `json:"timestamp"`
+	Version        uint64   `json:"version"`
+}
+
+type receiptRow struct {
+	BlockHash string `json:"block_hash"`
+	Hash      string `json:"hash"`
+	ShardID   uint32 `json:"shard_id"`
+	TxHash    string `json:"tx_hash"`
+	Sender    string `json:"sender"`
+	Value     string `json:"value"`
+	Data      string `json:"data"`
+	Timestamp uint64 `json:"timestamp"`
+	Version   uint64 `json:"version"`
+}
+
+type eventRow struct {
+	BlockHash      string   `json:"block_hash"`
+	ID             string   `json:"id"`
+	ShardID        uint32   `json:"shard_id"`
+	TxHash         string   `json:"tx_hash"`
+	OriginalTxHash string   `json:"original_tx_hash"`
+	LogAddress     string   `json:"log_address"`
+	Address        string   `json:"address"`
+	Identifier     string   `json:"identifier"`
+	Topics         []string `json:"topics"`
+	Data           string   `json:"data"`
+	AdditionalData []string `json:"additional_data"`
+	Order          int      `json:"event_order"`
+	TxOrder        int      `json:"tx_order"`
+	Timestamp      uint64   `json:"timestamp"`
+	Version        uint64   `json:"version"`
+}
+
+func newTransactionRow(tx *data.Transaction, block blockInfo) *transactionRow {
+	return &transactionRow{
+		BlockHash:     block.hash,
+		Hash:          tx.Hash,
+		MBHash:        tx.MBHash,
+		ShardID:       block.shardID,
+		Nonce:         tx.Nonce,
+		Round:         tx.Round,
+		Sender:        tx.Sender,
+		Receiver:      tx.Receiver,
+		SenderShard:   tx.SenderShard,
+		ReceiverShard: tx.ReceiverShard,
+		Value:         tx.Value,
+		ValueNum:      tx.ValueNum,
+		Fee:           tx.Fee,
+		FeeNum:        tx.FeeNum,
+		GasPrice:      tx.GasPrice,
+		GasLimit:      tx.GasLimit,
+		GasUsed:       tx.GasUsed,
+		Status:        tx.Status,
+		Type:          tx.Type,
+		Operation:     tx.Operation,
+		Function:      tx.Function,
+		IsScCall:      tx.IsScCall,
+		IsRelayed:     tx.IsRelayed,
+		Tokens:        nonNil(tx.Tokens),
+		ESDTValues:    nonNil(tx.ESDTValues),
+		Timestamp:     uint64(tx.Timestamp),
+		Version:       block.version,
+	}
+}
+
+func newSCResultRow(scr *data.ScResult, block blockInfo) *scResultRow {
+	return &scResultRow{
+		BlockHash:      block.hash,
+		Hash:           scr.Hash,
+		MBHash:         scr.MBHash,
+		ShardID:        block.shardID,
+		Nonce:          scr.Nonce,
+		Sender:         scr.Sender,
+		Receiver:       scr.Receiver,
+		SenderShard:    scr.SenderShard,
+		ReceiverShard:  scr.ReceiverShard,
+		Value:          scr.Value,
+		ValueNum:       scr.ValueNum,
+		OriginalTxHash: scr.OriginalTxHash,
+		PrevTxHash:     scr.PrevTxHash,
+		CallType:       scr.CallType,
+		ReturnMessage:  scr.ReturnMessage,
+		Status:         scr.Status,
+		Operation:      scr.Operation,
+		Function:       scr.Function,
+		Tokens:         nonNil(scr.Tokens),
+		ESDTValues:     nonNil(scr.ESDTValues),
+		Timestamp:      uint64(scr.Timestamp),
+		Version:        block.version,
+	}
+}
+
+func newReceiptRow(receipt *data.Receipt, block blockInfo) *receiptRow {
+	return &receiptRow{
+		BlockHash: block.hash,
+		Hash:      receipt.Hash,
+		ShardID:   block.shardID,
+		TxHash:    receipt.TxHash,
+		Sender:    receipt.Sender,
+		Value:     receipt.Value,
+		Data:      receipt.Data,
+		Timestamp: uint64(receipt.Timestamp),
+		Version:   block.version,
+	}
+}
+
+func newEventRow(event *data.LogEvent, block blockInfo) *eventRow {
+	return &eventRow{
+		BlockHash:      block.hash,
+		ID:             event.ID,
+		ShardID:        block.shardID,
+		TxHash:         event.TxHash,
+		OriginalTxHash: event.OriginalTxHash,
+		LogAddress:     event.LogAddress,
+		Address:        event.Address,
+		Identifier:     event.Identifier,
+		Topics:         nonNil(event.Topics),
+		Data:           event.Data,
+		AdditionalData: nonNil(event.AdditionalData),
+		Order:          event.Order,
+		TxOrder:        event.TxOrder,
+		Timestamp:      uint64(event.Timestamp),
+		Version:        block.version,
+	}
+}
+
+// nonNil returns an empty slice instead of a nil one, because ClickHouse does not accept null for an array column
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
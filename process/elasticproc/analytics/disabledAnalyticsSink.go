@@ -0,0 +1,31 @@
+package analytics
+
+import (
+	"context"
+
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+type disabledAnalyticsSink struct{}
+
+// NewDisabledAnalyticsSink will create an analytics sink that does not save anything, so the data is saved in
+// Elasticsearch only
+func NewDisabledAnalyticsSink() *disabledAnalyticsSink {
+	return &disabledAnalyticsSink{}
+}
+
+// SaveBlockResults does nothing
+func (das *disabledAnalyticsSink) SaveBlockResults(_ context.Context, _ []byte, _ coreData.HeaderHandler, _ *data.PreparedResults, _ *data.PreparedLogsResults) error {
+	return nil
+}
+
+// RevertBlock does nothing
+func (das *disabledAnalyticsSink) RevertBlock(_ context.Context, _ []byte) error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (das *disabledAnalyticsSink) IsInterfaceNil() bool {
+	return das == nil
+}
@@ -0,0 +1,5 @@
+package analytics
+
+import "errors"
+
+var errClickHouseRequestFailed = errors.New("clickhouse request failed")
@@ -3,6 +3,7 @@ package block
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	coreData "github.com/multiversx/mx-chain-core-go/data"
@@ -12,6 +13,11 @@ import (
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
 )
 
+const (
+	lastFinalNonceKeyPrefix     = "last-final-nonce-"
+	indexingCheckpointKeyPrefix = "indexing-checkpoint-"
+)
+
 // SerializeBlock will serialize a block for database
 func (bp *blockProcessor) SerializeBlock(elasticBlock *data.Block, buffSlice *data.BufferSlice, index string) error {
 	if elasticBlock == nil {
@@ -52,3 +58,55 @@ func (bp *blockProcessor) SerializeEpochInfoData(header coreData.HeaderHandler,
 
 	return buffSlice.PutData(meta, serializedData)
 }
+
+// SerializeFinalizedBlock will serialize the update that marks the block with the provided hash as final
+func (bp *blockProcessor) SerializeFinalizedBlock(headerHash string, finalizedAt int64, buffSlice *data.BufferSlice, index string) error {
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(headerHash), "\n"))
+	serializedData := []byte(fmt.Sprintf(`{"doc":{"final":true,"finalizedAt":%d}}`, finalizedAt))
+
+	return buffSlice.PutData(meta, serializedData)
+}
+
+// SerializeLastFinalNonce will serialize the last final nonce of the provided shard for the values index
+func (bp *blockProcessor) SerializeLastFinalNonce(shardID uint32, nonce uint64, buffSlice *data.BufferSlice, index string) error {
+	keyValueObj := &data.KeyValueObj{
+		Key:   LastFinalNonceKey(shardID),
+		Value: strconv.FormatUint(nonce, 10),
+	}
+
+	meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, index, keyValueObj.Key, "\n"))
+	serializedData, errMarshal := json.Marshal(keyValueObj)
+	if errMarshal != nil {
+		return errMarshal
+	}
+
+	return buffSlice.PutData(meta, serializedData)
+}
+
+// LastFinalNonceKey returns the key, from the values index, that holds the last final nonce of the provided shard
+func LastFinalNonceKey(shardID uint32) string {
+	return fmt.Sprintf("%s%d", lastFinalNonceKeyPrefix, shardID)
+}
+
+// SerializeIndexingCheckpoint will serialize the indexing checkpoint of the provided shard for the values index
+func (bp *blockProcessor) SerializeIndexingCheckpoint(shardID uint32, checkpoint *data.IndexingCheckpoint, buffSlice *data.BufferSlice, index string) error {
+	if checkpoint == nil {
+		return dataindexer.ErrNilIndexingCheckpoint
+	}
+
+	checkpoint.Key = IndexingCheckpointKey(shardID)
+	checkpoint.Value = strconv.FormatUint(checkpoint.Nonce, 10)
+
+	meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, index, checkpoint.Key, "\n"))
+	serializedData, errMarshal := json.Marshal(checkpoint)
+	if errMarshal != nil {
+		return errMarshal
+	}
+
+	return buffSlice.PutData(meta, serializedData)
+}
+
+// IndexingCheckpointKey returns the key, from the values index, that holds the indexing checkpoint of the provided shard
+func IndexingCheckpointKey(shardID uint32) string {
+	return fmt.Sprintf("%s%d", indexingCheckpointKeyPrefix, shardID)
+}
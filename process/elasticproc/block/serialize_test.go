@@ -106,3 +106,45 @@ func TestBlockProcessor_SerializeBlockEpochStartMeta(t *testing.T) {
 {"nonce":1,"round":2,"epoch":3,"miniBlocksHashes":["mb1Hash","mbHash2"],"notarizedBlocksHashes":["notarized1"],"proposer":5,"validators":[0,1,2,3,4,5],"pubKeyBitmap":"00000110","size":345,"sizeTxs":0,"timestamp":123456,"stateRootHash":"stateHash","prevHash":"prevHash","shardId":4294967295,"txCount":100,"notarizedTxsCount":120,"accumulatedFees":"1000","developerFees":"50","epochStartBlock":true,"searchOrder":1010,"epochStartInfo":{"totalSupply":"100","totalToDistribute":"55","totalNewlyMinted":"20","rewardsPerBlock":"15","rewardsForProtocolSustainability":"2","nodePrice":"10","prevEpochStartRound":222,"prevEpochStartHash":"7072657645706f6368"},"gasProvided":0,"gasRefunded":0,"gasPenalized":0,"maxGasLimit":0}
 `, buffSlice.Buffers()[0].String())
 }
+
+func TestBlockProcessor_SerializeFinalizedBlock(t *testing.T) {
+	t.Parallel()
+
+	bp, _ := NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := bp.SerializeFinalizedBlock("h1", 1000, buffSlice, "blocks")
+	require.Nil(t, err)
+	require.Equal(t, `{ "update" : { "_index":"blocks", "_id" : "h1" } }
+{"doc":{"final":true,"finalizedAt":1000}}
+`, buffSlice.Buffers()[0].String())
+}
+
+func TestBlockProcessor_SerializeLastFinalNonce(t *testing.T) {
+	t.Parallel()
+
+	bp, _ := NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := bp.SerializeLastFinalNonce(2, 150, buffSlice, "values")
+	require.Nil(t, err)
+	require.Equal(t, `{ "index" : { "_index":"values", "_id" : "last-final-nonce-2" } }
+{"key":"last-final-nonce-2","value":"150"}
+`, buffSlice.Buffers()[0].String())
+}
+
+func TestBlockProcessor_SerializeIndexingCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	bp, _ := NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := bp.SerializeIndexingCheckpoint(2, nil, buffSlice, "values")
+	require.Equal(t, dataindexer.ErrNilIndexingCheckpoint, err)
+
+	err = bp.SerializeIndexingCheckpoint(2, &data.IndexingCheckpoint{Nonce: 150, Hash: "abcd", Timestamp: 1000}, buffSlice, "values")
+	require.Nil(t, err)
+	require.Equal(t, `{ "index" : { "_index":"values", "_id" : "indexing-checkpoint-2" } }
+{"key":"indexing-checkpoint-2","value":"150","nonce":150,"hash":"abcd","timestamp":1000}
+`, buffSlice.Buffers()[0].String())
+}
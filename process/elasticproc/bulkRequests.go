@@ -0,0 +1,219 @@
+package elasticproc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+const deleteAction = "delete"
+
+type bulkActionMeta struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+// bulkOrigin identifies the block that produced the bulk requests
+type bulkOrigin struct {
+	shardID uint32
+	nonce   uint64
+}
+
+type documentKey struct {
+	index string
+	id    string
+}
+
+// doBulkRequests sends the provided buffers. The nonce identifies the block the buffers belong to, or is 0 for the data
+// that does not come from a block
+func (ei *elasticProcessor) doBulkRequests(ctx context.Context, index string, buffSlice []*bytes.Buffer, shardID uint32, nonce uint64) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, shardID))
+	origin := bulkOrigin{
+		shardID: shardID,
+		nonce:   nonce,
+	}
+	if ei.numBulkRequestWorkers <= 1 || len(buffSlice) <= 1 {
+		return ei.doBulkRequestsSequentially(ctxWithValue, index, buffSlice, origin)
+	}
+
+	return ei.doBulkRequestsInParallel(ctxWithValue, cancel, index, buffSlice, origin)
+}
+
+func (ei *elasticProcessor) doBulkRequestsSequentially(ctx context.Context, index string, buffSlice []*bytes.Buffer, origin bulkOrigin) error {
+	for idx := range buffSlice {
+		err := ei.doBulkRequest(ctx, index, buffSlice[idx], origin)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBulkRequestsInParallel will send the buffers with at most numBulkRequestWorkers requests in flight. A buffer
+// that holds a document of an earlier buffer is sent only after the earlier buffer was indexed, so the operations on
+// the same document keep their order. On the first error, the context is cancelled and the buffers that were not sent
+// yet are dropped. All the errors are returned
+func (ei *elasticProcessor) doBulkRequestsInParallel(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	index string,
+	buffSlice []*bytes.Buffer,
+	origin bulkOrigin,
+) error {
+	dependencies := computeBulkDependencies(index, buffSlice)
+	doneChans := make([]chan struct{}, len(buffSlice))
+	for idx := range doneChans {
+		doneChans[idx] = make(chan struct{})
+	}
+
+	errs := make([]error, len(buffSlice))
+	semaphore := make(chan struct{}, ei.numBulkRequestWorkers)
+	wg := sync.WaitGroup{}
+	wg.Add(len(buffSlice))
+	for idx := range buffSlice {
+		go func(idx int) {
+			defer wg.Done()
+			defer close(doneChans[idx])
+
+			// the dependencies are awaited before taking a slot, so the slots are not held by blocked requests
+			for _, dependency := range dependencies[idx] {
+				<-doneChans[dependency]
+			}
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() {
+				<-semaphore
+			}()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			errs[idx] = ei.doBulkRequest(ctx, index, buffSlice[idx], origin)
+			if errs[idx] != nil {
+				cancel()
+			}
+		}(idx)
+	}
+	wg.Wait()
+
+	return aggregateBulkErrors(errs)
+}
+
+func (ei *elasticProcessor) doBulkRequest(ctx context.Context, index string, buff *bytes.Buffer, origin bulkOrigin) error {
+	err := ei.elasticClient.DoBulkRequest(ctx, buff, index)
+	if errors.Is(err, elasticIndexer.ErrRejectedBulkItems) {
+		ei.pressureMonitor.RecordRejection()
+	}
+
+	var failedItemsErr *elasticIndexer.FailedBulkItemsError
+	if !ei.deadLettersEnabled || !errors.As(err, &failedItemsErr) {
+		return err
+	}
+
+	errDeadLetters := ei.indexDeadLetters(ctx, failedItemsErr.Items, origin)
+	if errDeadLetters != nil {
+		log.Warn("elasticProcessor.doBulkRequest: cannot index the dead letters", "error", errDeadLetters)
+		return err
+	}
+
+	return nil
+}
+
+// aggregateBulkErrors returns the errors of the bulk requests, leaving out the requests that were aborted by the
+// cancellation that another error triggered
+func aggregateBulkErrors(errs []error) error {
+	bulkErrors := make([]error, 0)
+	for _, err := range errs {
+		if err == nil || errors.Is(err, context.Canceled) {
+			continue
+		}
+
+		bulkErrors = append(bulkErrors, err)
+	}
+
+	switch len(bulkErrors) {
+	case 0:
+		return nil
+	case 1:
+		return bulkErrors[0]
+	default:
+		return errors.Join(bulkErrors...)
+	}
+}
+
+// computeBulkDependencies returns, for every buffer, the earlier buffers it has to wait for. A buffer depends on the
+// last earlier buffer that holds any of its documents
+func computeBulkDependencies(index string, buffSlice []*bytes.Buffer) [][]int {
+	lastBufferOfDocument := make(map[documentKey]int)
+	dependencies := make([][]int, len(buffSlice))
+	for idx, buff := range buffSlice {
+		uniqueDependencies := make(map[int]struct{})
+		for _, key := range extractDocumentKeys(index, buff.Bytes()) {
+			lastIdx, found := lastBufferOfDocument[key]
+			if found && lastIdx != idx {
+				uniqueDependencies[lastIdx] = struct{}{}
+			}
+			lastBufferOfDocument[key] = idx
+		}
+
+		for dependency := range uniqueDependencies {
+			dependencies[idx] = append(dependencies[idx], dependency)
+		}
+	}
+
+	return dependencies
+}
+
+// extractDocumentKeys returns the index and the _id of every document from the provided bulk body. The action lines
+// without an _index belong to the index the bulk request is sent to
+func extractDocumentKeys(index string, bulkBody []byte) []documentKey {
+	keys := make([]documentKey, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(bulkBody))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(bulkBody)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		action := make(map[string]bulkActionMeta)
+		err := json.Unmarshal(line, &action)
+		if err != nil {
+			log.Warn("elasticProcessor.extractDocumentKeys: cannot decode bulk action line", "error", err)
+			continue
+		}
+
+		hasSource := true
+		for actionName, meta := range action {
+			keyIndex := meta.Index
+			if keyIndex == "" {
+				keyIndex = index
+			}
+			keys = append(keys, documentKey{index: keyIndex, id: meta.ID})
+			hasSource = actionName != deleteAction
+		}
+
+		// the source line of the document is skipped
+		if hasSource {
+			scanner.Scan()
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,162 @@
+package elasticproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createBulkBuffer(lines ...string) *bytes.Buffer {
+	buff := &bytes.Buffer{}
+	for _, line := range lines {
+		buff.WriteString(line + "\n")
+	}
+
+	return buff
+}
+
+func TestExtractDocumentKeys(t *testing.T) {
+	t.Parallel()
+
+	buff := createBulkBuffer(
+		`{ "index" : { "_index":"transactions", "_id" : "h1" } }`,
+		`{"nonce":1}`,
+		`{"update":{"_index":"accounts","_id":"a1"}}`,
+		`{"script":{"source":"ctx._source.balance = params.balance"},"upsert":{}}`,
+		`{ "delete" : { "_index": "tokens", "_id" : "t1" } }`,
+		`{ "index" : { "_id" : "r1" } }`,
+		`{"round":1}`,
+	)
+
+	keys := extractDocumentKeys("rounds", buff.Bytes())
+	require.Equal(t, []documentKey{
+		{index: "transactions", id: "h1"},
+		{index: "accounts", id: "a1"},
+		{index: "tokens", id: "t1"},
+		{index: "rounds", id: "r1"},
+	}, keys)
+}
+
+func TestComputeBulkDependencies(t *testing.T) {
+	t.Parallel()
+
+	buffSlice := []*bytes.Buffer{
+		createBulkBuffer(`{"update":{"_index":"accounts","_id":"a1"}}`, `{}`),
+		createBulkBuffer(`{"update":{"_index":"accounts","_id":"a2"}}`, `{}`),
+		createBulkBuffer(`{"update":{"_index":"accounts","_id":"a1"}}`, `{}`, `{"update":{"_index":"accounts","_id":"a2"}}`, `{}`),
+		createBulkBuffer(`{"update":{"_index":"tokens","_id":"a1"}}`, `{}`),
+		createBulkBuffer(`{"update":{"_index":"accounts","_id":"a1"}}`, `{}`),
+	}
+
+	dependencies := computeBulkDependencies("", buffSlice)
+	require.Len(t, dependencies, 5)
+	require.Empty(t, dependencies[0])
+	require.Empty(t, dependencies[1])
+	require.ElementsMatch(t, []int{0, 1}, dependencies[2])
+	require.Empty(t, dependencies[3])
+	require.Equal(t, []int{2}, dependencies[4])
+}
+
+func TestElasticProcessor_DoBulkRequestsInParallel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should respect the number of workers and the order of the documents", func(t *testing.T) {
+		t.Parallel()
+
+		buffSlice := []*bytes.Buffer{
+			createBulkBuffer(`{"update":{"_index":"accounts","_id":"a1"}}`, `{"order":0}`),
+			createBulkBuffer(`{"update":{"_index":"accounts","_id":"a2"}}`, `{"order":1}`),
+			createBulkBuffer(`{"update":{"_index":"accounts","_id":"a3"}}`, `{"order":2}`),
+			createBulkBuffer(`{"update":{"_index":"accounts","_id":"a1"}}`, `{"order":3}`),
+		}
+
+		mut := sync.Mutex{}
+		sentBuffers := make([]string, 0)
+		inFlight := int32(0)
+		maxInFlight := int32(0)
+		args := createMockElasticProcessorArgs()
+		args.NumBulkRequestWorkers = 2
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+
+				mut.Lock()
+				if current > maxInFlight {
+					maxInFlight = current
+				}
+				mut.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mut.Lock()
+				sentBuffers = append(sentBuffers, buff.String())
+				mut.Unlock()
+
+				return nil
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.doBulkRequests(context.Background(), "", buffSlice, 0, 0)
+		require.Nil(t, err)
+		require.Len(t, sentBuffers, 4)
+		require.LessOrEqual(t, maxInFlight, int32(2))
+
+		firstIdx, lastIdx := -1, -1
+		for idx, sentBuffer := range sentBuffers {
+			if sentBuffer == buffSlice[0].String() {
+				firstIdx = idx
+			}
+			if sentBuffer == buffSlice[3].String() {
+				lastIdx = idx
+			}
+		}
+		require.Less(t, firstIdx, lastIdx)
+	})
+
+	t.Run("errors should be aggregated and the dependent requests should not be sent", func(t *testing.T) {
+		t.Parallel()
+
+		buffSlice := []*bytes.Buffer{
+			createBulkBuffer(`{"update":{"_index":"accounts","_id":"a1"}}`, `{"order":0}`),
+			createBulkBuffer(`{"update":{"_index":"accounts","_id":"a2"}}`, `{"order":1}`),
+			createBulkBuffer(`{"update":{"_index":"accounts","_id":"a1"}}`, `{"order":2}`),
+		}
+
+		err0 := errors.New("error 0")
+		err1 := errors.New("error 1")
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		numCalls := int32(0)
+		args := createMockElasticProcessorArgs()
+		args.NumBulkRequestWorkers = 3
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				atomic.AddInt32(&numCalls, 1)
+				// both independent requests are in flight when they fail
+				wg.Done()
+				wg.Wait()
+
+				if buff.String() == buffSlice[0].String() {
+					return err0
+				}
+
+				return err1
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.doBulkRequests(context.Background(), "", buffSlice, 0, 0)
+		require.True(t, errors.Is(err, err0))
+		require.True(t, errors.Is(err, err1))
+		require.Equal(t, int32(2), atomic.LoadInt32(&numCalls))
+	})
+}
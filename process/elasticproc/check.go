@@ -39,6 +39,15 @@ func checkArguments(arguments *ArgElasticProcessor) error {
 	if check.IfNilReflect(arguments.OperationsProc) {
 		return elasticIndexer.ErrNilOperationsHandler
 	}
+	if check.IfNil(arguments.PressureMonitor) {
+		return elasticIndexer.ErrNilPressureMonitor
+	}
+	if check.IfNil(arguments.TokenCache) {
+		return elasticIndexer.ErrNilTokenCache
+	}
+	if check.IfNil(arguments.AnalyticsSink) {
+		return elasticIndexer.ErrNilAnalyticsSink
+	}
 
 	return nil
 }
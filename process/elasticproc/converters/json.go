@@ -39,3 +39,21 @@ func PrepareHashesForQueryRemove(hashes []string) *bytes.Buffer {
 
 	return bytes.NewBuffer([]byte(deleteQuery))
 }
+
+// PrepareQueryForFinalizedMarker will prepare the update-by-query request that marks as final all the documents whose
+// provided field holds one of the provided values
+func PrepareQueryForFinalizedMarker(field string, values []string, finalizedAt int64) *bytes.Buffer {
+	if len(values) == 0 {
+		values = []string{}
+	}
+
+	serializedValues, _ := json.Marshal(values)
+	codeToExecute := `
+	ctx._source.final = true;
+	ctx._source.finalizedAt = params.finalizedAt;
+`
+	query := fmt.Sprintf(`{"query": {"terms": {"%s": %s}},"script": {"source": "%s","lang": "painless","params": {"finalizedAt": %d}}}`,
+		field, serializedValues, FormatPainlessSource(codeToExecute), finalizedAt)
+
+	return bytes.NewBuffer([]byte(query))
+}
@@ -36,3 +36,13 @@ func TestPrepareHashesForQueryRemove(t *testing.T) {
 	res = PrepareHashesForQueryRemove([]string{""})
 	require.Equal(t, `{"query": {"ids": {"values": [""]}}}`, res.String())
 }
+
+func TestPrepareQueryForFinalizedMarker(t *testing.T) {
+	t.Parallel()
+
+	res := PrepareQueryForFinalizedMarker("miniBlockHash", []string{"mb1", "mb2"}, 1000)
+	require.Equal(t, `{"query": {"terms": {"miniBlockHash": ["mb1","mb2"]}},"script": {"source": "ctx._source.final = true;ctx._source.finalizedAt = params.finalizedAt;","lang": "painless","params": {"finalizedAt": 1000}}}`, res.String())
+
+	res = PrepareQueryForFinalizedMarker("_id", nil, 1)
+	require.Equal(t, `{"query": {"terms": {"_id": []}},"script": {"source": "ctx._source.final = true;ctx._source.finalizedAt = params.finalizedAt;","lang": "painless","params": {"finalizedAt": 1}}}`, res.String())
+}
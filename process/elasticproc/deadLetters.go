@@ -0,0 +1,77 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+// indexDeadLetters moves the provided failed items into the deadletters index
+func (ei *elasticProcessor) indexDeadLetters(ctx context.Context, failedItems []*elasticIndexer.FailedBulkItem, origin bulkOrigin) error {
+	timestamp := time.Now().Unix()
+	buffSlice := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
+	for _, item := range failedItems {
+		log.Warn("elasticProcessor.indexDeadLetters: document moved to the dead letters",
+			"index", item.Index,
+			"id", item.ID,
+			"status", item.Status,
+			"reason", item.Reason,
+			"shardID", origin.shardID,
+			"nonce", origin.nonce,
+		)
+
+		deadLetter := &data.DeadLetter{
+			ID:        createDeadLetterID(item),
+			Index:     item.Index,
+			DocID:     item.ID,
+			Operation: string(item.Operation),
+			Status:    item.Status,
+			Reason:    item.Reason,
+			ShardID:   origin.shardID,
+			Nonce:     origin.nonce,
+			Timestamp: timestamp,
+		}
+		err := serializeDeadLetter(deadLetter, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, buff := range buffSlice.Buffers() {
+		err := ei.elasticClient.DoBulkRequest(ctx, buff, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createDeadLetterID returns the same ID every time a document is refused, so a document refused again replaces its
+// previous dead letter. The documents without an _id get a generated ID
+func createDeadLetterID(item *elasticIndexer.FailedBulkItem) string {
+	if item.ID == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s_%s", item.Index, item.ID)
+}
+
+func serializeDeadLetter(deadLetter *data.DeadLetter, buffSlice *data.BufferSlice) error {
+	meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s" } }%s`, elasticIndexer.DeadLettersIndex, "\n"))
+	if deadLetter.ID != "" {
+		meta = []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.DeadLettersIndex, converters.JsonEscape(deadLetter.ID), "\n"))
+	}
+
+	serializedData, err := json.Marshal(deadLetter)
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutData(meta, serializedData)
+}
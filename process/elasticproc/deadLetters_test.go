@@ -0,0 +1,123 @@
+package elasticproc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_DoBulkRequestsWithDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	failedItemsErr := &elasticIndexer.FailedBulkItemsError{
+		Items: []*elasticIndexer.FailedBulkItem{
+			{
+				Index:     "transactions-000001",
+				ID:        "h1",
+				Status:    400,
+				Reason:    "mapper_parsing_exception: failed to parse",
+				Operation: []byte("{\"index\":{\"_index\":\"transactions\",\"_id\":\"h1\"}}\n{\"nonce\":1}\n"),
+			},
+		},
+		Err: errors.New("failed items"),
+	}
+
+	t.Run("dead letters disabled should return the error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockElasticProcessorArgs()
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				return failedItemsErr
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.doBulkRequests(context.Background(), "", []*bytes.Buffer{createBulkBuffer(`{"index":{"_id":"h1"}}`, `{}`)}, 1, 10)
+		require.Equal(t, failedItemsErr, err)
+	})
+
+	t.Run("failed items should be moved into the dead letters", func(t *testing.T) {
+		t.Parallel()
+
+		deadLettersBodies := make([]string, 0)
+		args := createMockElasticProcessorArgs()
+		args.DeadLettersEnabled = true
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				if strings.Contains(buff.String(), elasticIndexer.DeadLettersIndex) {
+					deadLettersBodies = append(deadLettersBodies, buff.String())
+					return nil
+				}
+
+				return failedItemsErr
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.doBulkRequests(context.Background(), "", []*bytes.Buffer{createBulkBuffer(`{"index":{"_id":"h1"}}`, `{}`)}, 1, 10)
+		require.Nil(t, err)
+		require.Len(t, deadLettersBodies, 1)
+
+		lines := strings.Split(deadLettersBodies[0], "\n")
+		require.Equal(t, `{ "index" : { "_index":"deadletters", "_id" : "transactions-000001_h1" } }`, lines[0])
+
+		deadLetter := &data.DeadLetter{}
+		err = json.Unmarshal([]byte(lines[1]), deadLetter)
+		require.Nil(t, err)
+		require.Equal(t, "transactions-000001", deadLetter.Index)
+		require.Equal(t, "h1", deadLetter.DocID)
+		require.Equal(t, string(failedItemsErr.Items[0].Operation), deadLetter.Operation)
+		require.Equal(t, "mapper_parsing_exception: failed to parse", deadLetter.Reason)
+		require.Equal(t, uint32(1), deadLetter.ShardID)
+		require.Equal(t, uint64(10), deadLetter.Nonce)
+	})
+
+	t.Run("cannot index the dead letters should return the error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockElasticProcessorArgs()
+		args.DeadLettersEnabled = true
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				if strings.Contains(buff.String(), elasticIndexer.DeadLettersIndex) {
+					return errors.New("cannot index the dead letters")
+				}
+
+				return failedItemsErr
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.doBulkRequests(context.Background(), "", []*bytes.Buffer{createBulkBuffer(`{"index":{"_id":"h1"}}`, `{}`)}, 1, 10)
+		require.Equal(t, failedItemsErr, err)
+	})
+}
+
+func TestElasticProcessor_DeadLettersIndexShouldBeCreatedOnlyWhenEnabled(t *testing.T) {
+	t.Parallel()
+
+	createdIndexes := make([]string, 0)
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		CheckAndCreateIndexCalled: func(index string) error {
+			createdIndexes = append(createdIndexes, index)
+			return nil
+		},
+	}
+	_, _ = NewElasticProcessor(args)
+	require.NotContains(t, createdIndexes, elasticIndexer.DeadLettersIndex+"-"+elasticIndexer.IndexSuffix)
+
+	createdIndexes = make([]string, 0)
+	args.DeadLettersEnabled = true
+	_, _ = NewElasticProcessor(args)
+	require.Contains(t, createdIndexes, elasticIndexer.DeadLettersIndex+"-"+elasticIndexer.IndexSuffix)
+}
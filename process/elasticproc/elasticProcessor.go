@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
@@ -17,6 +18,7 @@ import (
 	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
 	"github.com/multiversx/mx-chain-es-indexer-go/data"
 	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	indexerBlock "github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tags"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tokeninfo"
@@ -39,38 +41,50 @@ const versionStr = "indexer-version"
 // ArgElasticProcessor holds all dependencies required by the elasticProcessor in order to create
 // new instances
 type ArgElasticProcessor struct {
-	BulkRequestMaxSize int
-	UseKibana          bool
-	ImportDB           bool
-	IndexTemplates     map[string]*bytes.Buffer
-	IndexPolicies      map[string]*bytes.Buffer
-	EnabledIndexes     map[string]struct{}
-	TransactionsProc   DBTransactionsHandler
-	AccountsProc       DBAccountHandler
-	BlockProc          DBBlockHandler
-	MiniblocksProc     DBMiniblocksHandler
-	StatisticsProc     DBStatisticsHandler
-	ValidatorsProc     DBValidatorsHandler
-	DBClient           DatabaseClientHandler
-	LogsAndEventsProc  DBLogsAndEventsHandler
-	OperationsProc     OperationsHandler
-	Version            string
+	ImportDB          bool
+	IndexTemplates    map[string]*bytes.Buffer
+	IndexPolicies     map[string]*bytes.Buffer
+	EnabledIndexes    map[string]struct{}
+	TransactionsProc  DBTransactionsHandler
+	AccountsProc      DBAccountHandler
+	BlockProc         DBBlockHandler
+	MiniblocksProc    DBMiniblocksHandler
+	StatisticsProc    DBStatisticsHandler
+	ValidatorsProc    DBValidatorsHandler
+	DBClient          DatabaseClientHandler
+	LogsAndEventsProc DBLogsAndEventsHandler
+	OperationsProc    OperationsHandler
+	PressureMonitor   PressureMonitor
+	TokenCache        TokenCache
+	AnalyticsSink     AnalyticsSink
+	Version           string
+	// NumBulkRequestWorkers is the maximum number of bulk requests of the same operation that are sent in
+	// parallel. 0 or 1 means that the bulk requests are sent one after another
+	NumBulkRequestWorkers int
+	// DeadLettersEnabled moves the documents that Elasticsearch refuses for a reason that a retry cannot fix, such as
+	// a mapping error, into the deadletters index, so the rest of the block is still indexed
+	DeadLettersEnabled bool
 }
 
 type elasticProcessor struct {
-	bulkRequestMaxSize int
-	importDB           bool
-	enabledIndexes     map[string]struct{}
-	mutex              sync.RWMutex
-	elasticClient      DatabaseClientHandler
-	accountsProc       DBAccountHandler
-	blockProc          DBBlockHandler
-	transactionsProc   DBTransactionsHandler
-	miniblocksProc     DBMiniblocksHandler
-	statisticsProc     DBStatisticsHandler
-	validatorsProc     DBValidatorsHandler
-	logsAndEventsProc  DBLogsAndEventsHandler
-	operationsProc     OperationsHandler
+	importDB          bool
+	enabledIndexes    map[string]struct{}
+	mutex             sync.RWMutex
+	elasticClient     DatabaseClientHandler
+	accountsProc      DBAccountHandler
+	blockProc         DBBlockHandler
+	transactionsProc  DBTransactionsHandler
+	miniblocksProc    DBMiniblocksHandler
+	statisticsProc    DBStatisticsHandler
+	validatorsProc    DBValidatorsHandler
+	logsAndEventsProc DBLogsAndEventsHandler
+	operationsProc    OperationsHandler
+	pressureMonitor   PressureMonitor
+	tokenCache        TokenCache
+	analyticsSink     AnalyticsSink
+
+	numBulkRequestWorkers int
+	deadLettersEnabled    bool
 }
 
 // NewElasticProcessor handles Elasticsearch operations such as initialization, adding, modifying or removing data
@@ -81,20 +95,25 @@ func NewElasticProcessor(arguments *ArgElasticProcessor) (*elasticProcessor, err
 	}
 
 	ei := &elasticProcessor{
-		elasticClient:      arguments.DBClient,
-		enabledIndexes:     arguments.EnabledIndexes,
-		accountsProc:       arguments.AccountsProc,
-		blockProc:          arguments.BlockProc,
-		miniblocksProc:     arguments.MiniblocksProc,
-		transactionsProc:   arguments.TransactionsProc,
-		statisticsProc:     arguments.StatisticsProc,
-		validatorsProc:     arguments.ValidatorsProc,
-		logsAndEventsProc:  arguments.LogsAndEventsProc,
-		operationsProc:     arguments.OperationsProc,
-		bulkRequestMaxSize: arguments.BulkRequestMaxSize,
-	}
-
-	err = ei.init(arguments.UseKibana, arguments.IndexTemplates, arguments.IndexPolicies)
+		elasticClient:     arguments.DBClient,
+		enabledIndexes:    arguments.EnabledIndexes,
+		accountsProc:      arguments.AccountsProc,
+		blockProc:         arguments.BlockProc,
+		miniblocksProc:    arguments.MiniblocksProc,
+		transactionsProc:  arguments.TransactionsProc,
+		statisticsProc:    arguments.StatisticsProc,
+		validatorsProc:    arguments.ValidatorsProc,
+		logsAndEventsProc: arguments.LogsAndEventsProc,
+		operationsProc:    arguments.OperationsProc,
+		pressureMonitor:   arguments.PressureMonitor,
+		tokenCache:        arguments.TokenCache,
+		analyticsSink:     arguments.AnalyticsSink,
+
+		numBulkRequestWorkers: arguments.NumBulkRequestWorkers,
+		deadLettersEnabled:    arguments.DeadLettersEnabled,
+	}
+
+	err = ei.init(arguments.IndexTemplates, arguments.IndexPolicies)
 	if err != nil {
 		return nil, err
 	}
@@ -105,19 +124,16 @@ func NewElasticProcessor(arguments *ArgElasticProcessor) (*elasticProcessor, err
 }
 
 // TODO move all the index create part in a new component
-func (ei *elasticProcessor) init(useKibana bool, indexTemplates, _ map[string]*bytes.Buffer) error {
+func (ei *elasticProcessor) init(indexTemplates, indexPolicies map[string]*bytes.Buffer) error {
 	err := ei.createOpenDistroTemplates(indexTemplates)
 	if err != nil {
 		return err
 	}
 
-	//nolint
-	if useKibana {
-		// TODO: Re-activate after we think of a solid way to handle forks+rotating indexes
-		// err = ei.createIndexPolicies(indexPolicies)
-		// if err != nil {
-		//	return err
-		// }
+	// the policies are provided only with Kibana and the client creates them only on OpenSearch
+	err = ei.createIndexPolicies(indexPolicies)
+	if err != nil {
+		return err
 	}
 
 	err = ei.createIndexTemplates(indexTemplates)
@@ -164,10 +180,9 @@ func (ei *elasticProcessor) indexVersion(version string) error {
 	return ei.elasticClient.DoBulkRequest(context.Background(), buffSlice.Buffers()[0], "")
 }
 
-// nolint
 func (ei *elasticProcessor) createIndexPolicies(indexPolicies map[string]*bytes.Buffer) error {
 	indexesPolicies := []string{elasticIndexer.TransactionsPolicy, elasticIndexer.BlockPolicy, elasticIndexer.MiniblocksPolicy, elasticIndexer.RatingPolicy, elasticIndexer.RoundsPolicy, elasticIndexer.ValidatorsPolicy,
-		elasticIndexer.AccountsPolicy, elasticIndexer.AccountsESDTPolicy, elasticIndexer.AccountsHistoryPolicy, elasticIndexer.AccountsESDTHistoryPolicy, elasticIndexer.AccountsESDTIndex, elasticIndexer.ReceiptsPolicy, elasticIndexer.ScResultsPolicy}
+		elasticIndexer.AccountsPolicy, elasticIndexer.AccountsESDTPolicy, elasticIndexer.AccountsHistoryPolicy, elasticIndexer.AccountsESDTHistoryPolicy, elasticIndexer.ReceiptsPolicy, elasticIndexer.ScResultsPolicy}
 	for _, indexPolicyName := range indexesPolicies {
 		indexPolicy := getTemplateByName(indexPolicyName, indexPolicies)
 		if indexPolicy != nil {
@@ -194,7 +209,7 @@ func (ei *elasticProcessor) createOpenDistroTemplates(indexTemplates map[string]
 }
 
 func (ei *elasticProcessor) createIndexTemplates(indexTemplates map[string]*bytes.Buffer) error {
-	for _, index := range indexes {
+	for _, index := range ei.getIndexes() {
 		indexTemplate := getTemplateByName(index, indexTemplates)
 		if indexTemplate != nil {
 			err := ei.elasticClient.CheckAndCreateTemplate(index, indexTemplate)
@@ -208,7 +223,7 @@ func (ei *elasticProcessor) createIndexTemplates(indexTemplates map[string]*byte
 
 func (ei *elasticProcessor) createIndexes() error {
 
-	for _, index := range indexes {
+	for _, index := range ei.getIndexes() {
 		indexName := fmt.Sprintf("%s-%s", index, elasticIndexer.IndexSuffix)
 		err := ei.elasticClient.CheckAndCreateIndex(indexName)
 		if err != nil {
@@ -219,7 +234,7 @@ func (ei *elasticProcessor) createIndexes() error {
 }
 
 func (ei *elasticProcessor) createAliases() error {
-	for _, index := range indexes {
+	for _, index := range ei.getIndexes() {
 		indexName := fmt.Sprintf("%s-%s", index, elasticIndexer.IndexSuffix)
 		err := ei.elasticClient.CheckAndCreateAlias(index, indexName)
 		if err != nil {
@@ -230,6 +245,15 @@ func (ei *elasticProcessor) createAliases() error {
 	return nil
 }
 
+// getIndexes returns the indexes to be created, including the deadletters index when the dead letters are enabled
+func (ei *elasticProcessor) getIndexes() []string {
+	if !ei.deadLettersEnabled {
+		return indexes
+	}
+
+	return append([]string{elasticIndexer.DeadLettersIndex}, indexes...)
+}
+
 func getTemplateByName(templateName string, templateList map[string]*bytes.Buffer) *bytes.Buffer {
 	if template, ok := templateList[templateName]; ok {
 		return template
@@ -240,7 +264,7 @@ func getTemplateByName(templateName string, templateList map[string]*bytes.Buffe
 }
 
 // SaveHeader will prepare and save information about a header in elasticsearch server
-func (ei *elasticProcessor) SaveHeader(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+func (ei *elasticProcessor) SaveHeader(ctx context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error {
 	if !ei.isIndexEnabled(elasticIndexer.BlockIndex) {
 		return nil
 	}
@@ -250,7 +274,7 @@ func (ei *elasticProcessor) SaveHeader(outportBlockWithHeader *outport.OutportBl
 		return err
 	}
 
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	buffSlice := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
 	err = ei.blockProc.SerializeBlock(elasticBlock, buffSlice, elasticIndexer.BlockIndex)
 	if err != nil {
 		return err
@@ -261,7 +285,43 @@ func (ei *elasticProcessor) SaveHeader(outportBlockWithHeader *outport.OutportBl
 		return err
 	}
 
-	return ei.doBulkRequests("", buffSlice.Buffers(), outportBlockWithHeader.ShardID)
+	return ei.doBulkRequests(ctx, "", buffSlice.Buffers(), outportBlockWithHeader.ShardID, outportBlockWithHeader.Header.GetNonce())
+}
+
+// SaveIndexingCheckpoint will save, in the values index, the last indexed block of the provided shard. It should be
+// called only after all the data of the block was saved
+func (ei *elasticProcessor) SaveIndexingCheckpoint(ctx context.Context, shardID uint32, checkpoint *data.IndexingCheckpoint) error {
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		return nil
+	}
+
+	buffSlice := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
+	err := ei.blockProc.SerializeIndexingCheckpoint(shardID, checkpoint, buffSlice, elasticIndexer.ValuesIndex)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBulkRequests(ctx, "", buffSlice.Buffers(), shardID, checkpoint.Nonce)
+}
+
+// GetIndexingCheckpoint returns the last indexed block of the provided shard, as saved in the values index. It returns
+// nil if no block was indexed for the shard
+func (ei *elasticProcessor) GetIndexingCheckpoint(ctx context.Context, shardID uint32) (*data.IndexingCheckpoint, error) {
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		return nil, nil
+	}
+
+	responseCheckpoints := &data.ResponseIndexingCheckpoints{}
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, []string{indexerBlock.IndexingCheckpointKey(shardID)}, elasticIndexer.ValuesIndex, true, responseCheckpoints)
+	if err != nil {
+		return nil, err
+	}
+	if len(responseCheckpoints.Docs) == 0 || !responseCheckpoints.Docs[0].Found {
+		return nil, nil
+	}
+
+	return &responseCheckpoints.Docs[0].Source, nil
 }
 
 func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, buffSlice *data.BufferSlice) error {
@@ -274,13 +334,13 @@ func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, bu
 }
 
 // RemoveHeader will remove a block from elasticsearch server
-func (ei *elasticProcessor) RemoveHeader(header coreData.HeaderHandler) error {
+func (ei *elasticProcessor) RemoveHeader(ctx context.Context, header coreData.HeaderHandler) error {
 	headerHash, err := ei.blockProc.ComputeHeaderHash(header)
 	if err != nil {
 		return err
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
 	return ei.elasticClient.DoQueryRemove(
 		ctxWithValue,
 		elasticIndexer.BlockIndex,
@@ -289,13 +349,13 @@ func (ei *elasticProcessor) RemoveHeader(header coreData.HeaderHandler) error {
 }
 
 // RemoveMiniblocks will remove all miniblocks that are in header from elasticsearch server
-func (ei *elasticProcessor) RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error {
+func (ei *elasticProcessor) RemoveMiniblocks(ctx context.Context, header coreData.HeaderHandler, body *block.Body) error {
 	encodedMiniblocksHashes := ei.miniblocksProc.GetMiniblocksHashesHexEncoded(header, body)
 	if len(encodedMiniblocksHashes) == 0 {
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
 	return ei.elasticClient.DoQueryRemove(
 		ctxWithValue,
 		elasticIndexer.MiniblocksIndex,
@@ -304,39 +364,52 @@ func (ei *elasticProcessor) RemoveMiniblocks(header coreData.HeaderHandler, body
 }
 
 // RemoveTransactions will remove transaction that are in miniblock from the elasticsearch server
-func (ei *elasticProcessor) RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error {
+func (ei *elasticProcessor) RemoveTransactions(ctx context.Context, header coreData.HeaderHandler, body *block.Body) error {
+	// the events of the removed block are not available anymore, so the tokens they changed cannot be told apart
+	ei.tokenCache.Purge()
+
 	encodedTxsHashes, encodedScrsHashes := ei.transactionsProc.GetHexEncodedHashesForRemove(header, body)
 	shardID := header.GetShardID()
 
-	err := ei.removeIfHashesNotEmpty(elasticIndexer.TransactionsIndex, encodedTxsHashes, shardID)
+	err := ei.removeIfHashesNotEmpty(ctx, elasticIndexer.TransactionsIndex, encodedTxsHashes, shardID)
 	if err != nil {
 		return err
 	}
 
-	err = ei.removeIfHashesNotEmpty(elasticIndexer.ScResultsIndex, encodedScrsHashes, shardID)
+	err = ei.removeIfHashesNotEmpty(ctx, elasticIndexer.ScResultsIndex, encodedScrsHashes, shardID)
 	if err != nil {
 		return err
 	}
 
-	err = ei.removeIfHashesNotEmpty(elasticIndexer.OperationsIndex, append(encodedTxsHashes, encodedScrsHashes...), shardID)
+	err = ei.removeIfHashesNotEmpty(ctx, elasticIndexer.OperationsIndex, append(encodedTxsHashes, encodedScrsHashes...), shardID)
 	if err != nil {
 		return err
 	}
 
-	err = ei.removeIfHashesNotEmpty(elasticIndexer.LogsIndex, append(encodedTxsHashes, encodedScrsHashes...), shardID)
+	err = ei.removeIfHashesNotEmpty(ctx, elasticIndexer.LogsIndex, append(encodedTxsHashes, encodedScrsHashes...), shardID)
 	if err != nil {
 		return err
 	}
 
-	err = ei.removeFromIndexByTimestampAndShardID(header.GetTimeStamp(), header.GetShardID(), elasticIndexer.EventsIndex)
+	err = ei.removeFromIndexByTimestampAndShardID(ctx, header.GetTimeStamp(), header.GetShardID(), elasticIndexer.EventsIndex)
 	if err != nil {
 		return err
 	}
 
-	return ei.updateDelegatorsInCaseOfRevert(header, body)
+	err = ei.updateDelegatorsInCaseOfRevert(ctx, header, body)
+	if err != nil {
+		return err
+	}
+
+	headerHash, err := ei.blockProc.ComputeHeaderHash(header)
+	if err != nil {
+		return err
+	}
+
+	return ei.analyticsSink.RevertBlock(ctx, headerHash)
 }
 
-func (ei *elasticProcessor) updateDelegatorsInCaseOfRevert(header coreData.HeaderHandler, body *block.Body) error {
+func (ei *elasticProcessor) updateDelegatorsInCaseOfRevert(ctx context.Context, header coreData.HeaderHandler, body *block.Body) error {
 	// delegators index should be updated in case of revert only if the observer is in Metachain and the reverted block has miniblocks
 	isMeta := header.GetShardID() == core.MetachainShardId
 	hasMiniblocks := len(body.MiniBlocks) > 0
@@ -345,17 +418,17 @@ func (ei *elasticProcessor) updateDelegatorsInCaseOfRevert(header coreData.Heade
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, header.GetShardID()))
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, header.GetShardID()))
 	delegatorsQuery := ei.logsAndEventsProc.PrepareDelegatorsQueryInCaseOfRevert(header.GetTimeStamp())
 	return ei.elasticClient.UpdateByQuery(ctxWithValue, elasticIndexer.DelegatorsIndex, delegatorsQuery)
 }
 
-func (ei *elasticProcessor) removeIfHashesNotEmpty(index string, hashes []string, shardID uint32) error {
+func (ei *elasticProcessor) removeIfHashesNotEmpty(ctx context.Context, index string, hashes []string, shardID uint32) error {
 	if len(hashes) == 0 {
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
 	return ei.elasticClient.DoQueryRemove(
 		ctxWithValue,
 		index,
@@ -364,17 +437,17 @@ func (ei *elasticProcessor) removeIfHashesNotEmpty(index string, hashes []string
 }
 
 // RemoveAccountsESDT will remove data from accountsesdt index and accountsesdthistory
-func (ei *elasticProcessor) RemoveAccountsESDT(headerTimestamp uint64, shardID uint32) error {
-	err := ei.removeFromIndexByTimestampAndShardID(headerTimestamp, shardID, elasticIndexer.AccountsESDTIndex)
+func (ei *elasticProcessor) RemoveAccountsESDT(ctx context.Context, headerTimestamp uint64, shardID uint32) error {
+	err := ei.removeFromIndexByTimestampAndShardID(ctx, headerTimestamp, shardID, elasticIndexer.AccountsESDTIndex)
 	if err != nil {
 		return err
 	}
 
-	return ei.removeFromIndexByTimestampAndShardID(headerTimestamp, shardID, elasticIndexer.AccountsESDTHistoryIndex)
+	return ei.removeFromIndexByTimestampAndShardID(ctx, headerTimestamp, shardID, elasticIndexer.AccountsESDTHistoryIndex)
 }
 
-func (ei *elasticProcessor) removeFromIndexByTimestampAndShardID(headerTimestamp uint64, shardID uint32, index string) error {
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+func (ei *elasticProcessor) removeFromIndexByTimestampAndShardID(ctx context.Context, headerTimestamp uint64, shardID uint32, index string) error {
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
 	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"shardID": {"query": %d,"operator": "AND"}}},{"match": {"timestamp": {"query": "%d","operator": "AND"}}}]}}}`, shardID, headerTimestamp)
 
 	return ei.elasticClient.DoQueryRemove(
@@ -385,7 +458,7 @@ func (ei *elasticProcessor) removeFromIndexByTimestampAndShardID(headerTimestamp
 }
 
 // SaveMiniblocks will prepare and save information about miniblocks in elasticsearch server
-func (ei *elasticProcessor) SaveMiniblocks(header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error {
+func (ei *elasticProcessor) SaveMiniblocks(ctx context.Context, header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error {
 	if !ei.isIndexEnabled(elasticIndexer.MiniblocksIndex) {
 		return nil
 	}
@@ -395,21 +468,21 @@ func (ei *elasticProcessor) SaveMiniblocks(header coreData.HeaderHandler, miniBl
 		return nil
 	}
 
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	buffSlice := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
 	ei.miniblocksProc.SerializeBulkMiniBlocks(mbs, buffSlice, elasticIndexer.MiniblocksIndex, header.GetShardID())
 
-	return ei.doBulkRequests("", buffSlice.Buffers(), header.GetShardID())
+	return ei.doBulkRequests(ctx, "", buffSlice.Buffers(), header.GetShardID(), header.GetNonce())
 }
 
 // SaveTransactions will prepare and save information about a transactions in elasticsearch server
-func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader) error {
+func (ei *elasticProcessor) SaveTransactions(ctx context.Context, obh *outport.OutportBlockWithHeader) error {
 	headerTimestamp := obh.Header.GetTimeStamp()
 
 	miniBlocks := append(obh.BlockData.Body.MiniBlocks, obh.BlockData.IntraShardMiniBlocks...)
 	preparedResults := ei.transactionsProc.PrepareTransactionsForDatabase(miniBlocks, obh.Header, obh.TransactionPool, ei.isImportDB(), obh.NumberOfShards)
 	logsData := ei.logsAndEventsProc.ExtractDataFromLogs(obh.TransactionPool.Logs, preparedResults, headerTimestamp, obh.Header.GetShardID(), obh.NumberOfShards)
 
-	buffers := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	buffers := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
 	err := ei.indexTransactions(preparedResults.Transactions, logsData.TxHashStatusInfo, obh.Header, buffers)
 	if err != nil {
 		return err
@@ -425,7 +498,7 @@ func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader
 		return err
 	}
 
-	err = ei.indexNFTCreateInfo(logsData.Tokens, obh.AlteredAccounts, buffers, obh.ShardID)
+	err = ei.indexNFTCreateInfo(ctx, logsData.Tokens, obh.AlteredAccounts, buffers, obh.ShardID)
 	if err != nil {
 		return err
 	}
@@ -451,7 +524,7 @@ func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader
 	}
 
 	tagsCount := tags.NewTagsCount()
-	err = ei.indexAlteredAccounts(headerTimestamp, logsData.NFTsDataUpdates, obh.AlteredAccounts, buffers, tagsCount, obh.Header.GetShardID())
+	err = ei.indexAlteredAccounts(ctx, headerTimestamp, logsData.NFTsDataUpdates, obh.AlteredAccounts, buffers, tagsCount, obh.Header.GetShardID())
 	if err != nil {
 		return err
 	}
@@ -461,7 +534,7 @@ func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader
 		return err
 	}
 
-	err = ei.indexTokens(logsData.TokensInfo, logsData.NFTsDataUpdates, buffers, obh.ShardID)
+	err = ei.indexTokens(ctx, logsData.TokensInfo, logsData.NFTsDataUpdates, buffers, obh.ShardID, obh.Header.GetNonce())
 	if err != nil {
 		return err
 	}
@@ -471,7 +544,7 @@ func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader
 		return err
 	}
 
-	err = ei.indexNFTBurnInfo(logsData.TokensSupply, buffers, obh.ShardID)
+	err = ei.indexNFTBurnInfo(ctx, logsData.TokensSupply, buffers, obh.ShardID)
 	if err != nil {
 		return err
 	}
@@ -490,7 +563,19 @@ func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader
 		return err
 	}
 
-	return ei.doBulkRequests("", buffers.Buffers(), obh.ShardID)
+	err = ei.doBulkRequests(ctx, "", buffers.Buffers(), obh.ShardID, obh.Header.GetNonce())
+	if err != nil {
+		return err
+	}
+
+	err = ei.analyticsSink.SaveBlockResults(ctx, obh.BlockData.HeaderHash, obh.Header, preparedResults, logsData)
+	if err != nil {
+		return err
+	}
+
+	ei.updateTokenCache(logsData.TokensInfo)
+
+	return nil
 }
 
 func (ei *elasticProcessor) prepareAndIndexRolesData(tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties, buffSlice *data.BufferSlice, index string) error {
@@ -582,7 +667,7 @@ func (ei *elasticProcessor) prepareAndIndexOperations(
 }
 
 // SaveValidatorsRating will save validators rating
-func (ei *elasticProcessor) SaveValidatorsRating(ratingData *outport.ValidatorsRating) error {
+func (ei *elasticProcessor) SaveValidatorsRating(ctx context.Context, ratingData *outport.ValidatorsRating) error {
 	if !ei.isIndexEnabled(elasticIndexer.RatingIndex) {
 		return nil
 	}
@@ -592,11 +677,11 @@ func (ei *elasticProcessor) SaveValidatorsRating(ratingData *outport.ValidatorsR
 		return err
 	}
 
-	return ei.doBulkRequests(elasticIndexer.RatingIndex, buffSlice, ratingData.ShardID)
+	return ei.doBulkRequests(ctx, elasticIndexer.RatingIndex, buffSlice, ratingData.ShardID, 0)
 }
 
 // SaveShardValidatorsPubKeys will prepare and save information about a shard validators public keys in elasticsearch server
-func (ei *elasticProcessor) SaveShardValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+func (ei *elasticProcessor) SaveShardValidatorsPubKeys(ctx context.Context, validatorsPubKeys *outport.ValidatorsPubKeys) error {
 	if !ei.isIndexEnabled(elasticIndexer.ValidatorsIndex) {
 		return nil
 	}
@@ -606,22 +691,23 @@ func (ei *elasticProcessor) SaveShardValidatorsPubKeys(validatorsPubKeys *outpor
 		return err
 	}
 
-	return ei.doBulkRequests(elasticIndexer.ValidatorsIndex, buffSlice, validatorsPubKeys.ShardID)
+	return ei.doBulkRequests(ctx, elasticIndexer.ValidatorsIndex, buffSlice, validatorsPubKeys.ShardID, 0)
 }
 
 // SaveRoundsInfo will prepare and save information about a slice of rounds in elasticsearch server
-func (ei *elasticProcessor) SaveRoundsInfo(rounds *outport.RoundsInfo) error {
+func (ei *elasticProcessor) SaveRoundsInfo(ctx context.Context, rounds *outport.RoundsInfo) error {
 	if !ei.isIndexEnabled(elasticIndexer.RoundsIndex) {
 		return nil
 	}
 
 	buff := ei.statisticsProc.SerializeRoundsInfo(rounds)
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, rounds.ShardID))
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, rounds.ShardID))
 	return ei.elasticClient.DoBulkRequest(ctxWithValue, buff, elasticIndexer.RoundsIndex)
 }
 
 func (ei *elasticProcessor) indexAlteredAccounts(
+	ctx context.Context,
 	timestamp uint64,
 	updatesNFTsData []*data.NFTDataUpdate,
 	coreAlteredAccounts map[string]*alteredAccount.AlteredAccount,
@@ -636,10 +722,11 @@ func (ei *elasticProcessor) indexAlteredAccounts(
 		return err
 	}
 
-	return ei.saveAccountsESDT(timestamp, accountsToIndexESDT, updatesNFTsData, buffSlice, tagsCount, shardID)
+	return ei.saveAccountsESDT(ctx, timestamp, accountsToIndexESDT, updatesNFTsData, buffSlice, tagsCount, shardID)
 }
 
 func (ei *elasticProcessor) saveAccountsESDT(
+	ctx context.Context,
 	timestamp uint64,
 	wrappedAccounts []*data.AccountESDT,
 	updatesNFTsData []*data.NFTDataUpdate,
@@ -648,7 +735,7 @@ func (ei *elasticProcessor) saveAccountsESDT(
 	shardID uint32,
 ) error {
 	accountsESDTMap, tokensData := ei.accountsProc.PrepareAccountsMapESDT(timestamp, wrappedAccounts, tagsCount, shardID)
-	err := ei.addTokenTypeAndCurrentOwnerInAccountsESDT(tokensData, accountsESDTMap, shardID)
+	err := ei.addTokenTypeAndCurrentOwnerInAccountsESDT(ctx, tokensData, accountsESDTMap, shardID)
 	if err != nil {
 		return err
 	}
@@ -661,14 +748,12 @@ func (ei *elasticProcessor) saveAccountsESDT(
 	return ei.saveAccountsESDTHistory(timestamp, accountsESDTMap, buffSlice, shardID)
 }
 
-func (ei *elasticProcessor) addTokenTypeAndCurrentOwnerInAccountsESDT(tokensData data.TokensHandler, accountsESDTMap map[string]*data.AccountInfo, shardID uint32) error {
+func (ei *elasticProcessor) addTokenTypeAndCurrentOwnerInAccountsESDT(ctx context.Context, tokensData data.TokensHandler, accountsESDTMap map[string]*data.AccountInfo, shardID uint32) error {
 	if check.IfNil(tokensData) || tokensData.Len() == 0 {
 		return nil
 	}
 
-	responseTokens := &data.ResponseTokens{}
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
-	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	responseTokens, err := ei.getTokensTypeAndOwner(ctx, tokensData.GetAllTokens(), shardID)
 	if err != nil {
 		return err
 	}
@@ -700,15 +785,13 @@ func (ei *elasticProcessor) indexAccountsESDT(
 	return ei.accountsProc.SerializeAccountsESDT(accountsESDTMap, updatesNFTsData, buffSlice, elasticIndexer.AccountsESDTIndex)
 }
 
-func (ei *elasticProcessor) indexNFTCreateInfo(tokensData data.TokensHandler, coreAlteredAccounts map[string]*alteredAccount.AlteredAccount, buffSlice *data.BufferSlice, shardID uint32) error {
+func (ei *elasticProcessor) indexNFTCreateInfo(ctx context.Context, tokensData data.TokensHandler, coreAlteredAccounts map[string]*alteredAccount.AlteredAccount, buffSlice *data.BufferSlice, shardID uint32) error {
 	shouldSkipIndex := !ei.isIndexEnabled(elasticIndexer.TokensIndex) || tokensData.Len() == 0
 	if shouldSkipIndex {
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
-	responseTokens := &data.ResponseTokens{}
-	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	responseTokens, err := ei.getTokensTypeAndOwner(ctx, tokensData.GetAllTokens(), shardID)
 	if err != nil {
 		return err
 	}
@@ -721,15 +804,13 @@ func (ei *elasticProcessor) indexNFTCreateInfo(tokensData data.TokensHandler, co
 	return ei.accountsProc.SerializeNFTCreateInfo(tokens, buffSlice, elasticIndexer.TokensIndex)
 }
 
-func (ei *elasticProcessor) indexNFTBurnInfo(tokensData data.TokensHandler, buffSlice *data.BufferSlice, shardID uint32) error {
+func (ei *elasticProcessor) indexNFTBurnInfo(ctx context.Context, tokensData data.TokensHandler, buffSlice *data.BufferSlice, shardID uint32) error {
 	shouldSkipIndex := !ei.isIndexEnabled(elasticIndexer.TokensIndex) || tokensData.Len() == 0
 	if shouldSkipIndex {
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
-	responseTokens := &data.ResponseTokens{}
-	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	responseTokens, err := ei.getTokensTypeAndOwner(ctx, tokensData.GetAllTokens(), shardID)
 	if err != nil {
 		return err
 	}
@@ -741,7 +822,7 @@ func (ei *elasticProcessor) indexNFTBurnInfo(tokensData data.TokensHandler, buff
 
 // SaveAccounts will prepare and save information about provided accounts in elasticsearch server
 func (ei *elasticProcessor) SaveAccounts(accountsData *outport.Accounts) error {
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	buffSlice := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
 
 	accounts := make([]*data.Account, 0, len(accountsData.AlteredAccounts))
 	for _, account := range accountsData.AlteredAccounts {
@@ -821,17 +902,64 @@ func (ei *elasticProcessor) isIndexEnabled(index string) bool {
 	return isEnabled
 }
 
-func (ei *elasticProcessor) doBulkRequests(index string, buffSlice []*bytes.Buffer, shardID uint32) error {
-	var err error
-	for idx := range buffSlice {
-		ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, shardID))
-		err = ei.elasticClient.DoBulkRequest(ctxWithValue, buffSlice[idx], index)
+// SaveFinalizedBlock will mark as final the block with the provided hash, together with its miniblocks and
+// transactions, and will save the block nonce as the last final nonce of the block's shard
+func (ei *elasticProcessor) SaveFinalizedBlock(ctx context.Context, finalizedBlock *outport.FinalizedBlock) error {
+	if finalizedBlock == nil {
+		return elasticIndexer.ErrNilFinalizedBlock
+	}
+	if !ei.isIndexEnabled(elasticIndexer.BlockIndex) {
+		return nil
+	}
+
+	shardID := finalizedBlock.ShardID
+	headerHash := hex.EncodeToString(finalizedBlock.HeaderHash)
+	responseBlocks := &data.ResponseBlocks{}
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, []string{headerHash}, elasticIndexer.BlockIndex, true, responseBlocks)
+	if err != nil {
+		return err
+	}
+	if len(responseBlocks.Docs) == 0 || !responseBlocks.Docs[0].Found {
+		log.Debug("elasticProcessor.SaveFinalizedBlock: block not found", "hash", headerHash, "shardID", shardID)
+		return nil
+	}
+
+	finalizedAt := time.Now().Unix()
+	indexedBlock := responseBlocks.Docs[0].Source
+	buffSlice := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
+	err = ei.blockProc.SerializeFinalizedBlock(headerHash, finalizedAt, buffSlice, elasticIndexer.BlockIndex)
+	if err != nil {
+		return err
+	}
+
+	if ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		err = ei.blockProc.SerializeLastFinalNonce(shardID, indexedBlock.Nonce, buffSlice, elasticIndexer.ValuesIndex)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	err = ei.doBulkRequests(ctx, "", buffSlice.Buffers(), shardID, indexedBlock.Nonce)
+	if err != nil {
+		return err
+	}
+
+	err = ei.markAsFinalIfValuesNotEmpty(ctx, elasticIndexer.MiniblocksIndex, "_id", indexedBlock.MiniBlocksHashes, finalizedAt, shardID)
+	if err != nil {
+		return err
+	}
+
+	return ei.markAsFinalIfValuesNotEmpty(ctx, elasticIndexer.TransactionsIndex, "miniBlockHash", indexedBlock.MiniBlocksHashes, finalizedAt, shardID)
+}
+
+func (ei *elasticProcessor) markAsFinalIfValuesNotEmpty(ctx context.Context, index string, field string, values []string, finalizedAt int64, shardID uint32) error {
+	if len(values) == 0 || !ei.isIndexEnabled(index) {
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, shardID))
+	return ei.elasticClient.UpdateByQuery(ctxWithValue, index, converters.PrepareQueryForFinalizedMarker(field, values, finalizedAt))
 }
 
 // SetOutportConfig will set the outport config
@@ -851,6 +979,12 @@ func (ei *elasticProcessor) isImportDB() bool {
 	return ei.importDB
 }
 
+// Close will close the database client. The writes of the client that are still pending, like the ones queued for
+// the async mirror clusters, are flushed within the shutdown timeout of the client
+func (ei *elasticProcessor) Close() error {
+	return ei.elasticClient.Close()
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (ei *elasticProcessor) IsInterfaceNil() bool {
 	return ei == nil
@@ -2,8 +2,10 @@ package elasticproc
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/multiversx/mx-chain-es-indexer-go/mock"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/accounts"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/analytics"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/logsevents"
@@ -23,6 +26,7 @@ import (
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/operations"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/statistics"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tags"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tokencache"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/transactions"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/validators"
 	"github.com/stretchr/testify/require"
@@ -39,6 +43,9 @@ func newElasticsearchProcessor(elasticsearchWriter DatabaseClientHandler, argume
 		validatorsProc:    arguments.ValidatorsProc,
 		statisticsProc:    arguments.StatisticsProc,
 		logsAndEventsProc: arguments.LogsAndEventsProc,
+		pressureMonitor:   arguments.PressureMonitor,
+		tokenCache:        arguments.TokenCache,
+		analyticsSink:     arguments.AnalyticsSink,
 	}
 }
 
@@ -87,6 +94,9 @@ func createMockElasticProcessorArgs() *ArgElasticProcessor {
 		BlockProc:         bp,
 		LogsAndEventsProc: lp,
 		OperationsProc:    op,
+		PressureMonitor:   &mock.PressureMonitorStub{},
+		TokenCache:        tokencache.NewDisabledTokenCache(),
+		AnalyticsSink:     analytics.NewDisabledAnalyticsSink(),
 	}
 }
 
@@ -200,6 +210,33 @@ func TestNewElasticProcessor(t *testing.T) {
 			},
 			exErr: dataindexer.ErrNilTransactionsHandler,
 		},
+		{
+			name: "NilPressureMonitor",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.PressureMonitor = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilPressureMonitor,
+		},
+		{
+			name: "NilTokenCache",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.TokenCache = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilTokenCache,
+		},
+		{
+			name: "NilAnalyticsSink",
+			args: func() *ArgElasticProcessor {
+				arguments := createMockElasticProcessorArgs()
+				arguments.AnalyticsSink = nil
+				return arguments
+			},
+			exErr: dataindexer.ErrNilAnalyticsSink,
+		},
 		{
 			name: "InitError",
 			args: func() *ArgElasticProcessor {
@@ -224,13 +261,23 @@ func TestNewElasticProcessor(t *testing.T) {
 }
 
 func TestNewElasticProcessorWithKibana(t *testing.T) {
+	createdPolicies := make([]string, 0)
 	args := createMockElasticProcessorArgs()
-	args.UseKibana = true
-	args.DBClient = &mock.DatabaseWriterStub{}
+	args.IndexPolicies = map[string]*bytes.Buffer{
+		dataindexer.BlockPolicy:        bytes.NewBufferString("{}"),
+		dataindexer.AccountsESDTPolicy: bytes.NewBufferString("{}"),
+	}
+	args.DBClient = &mock.DatabaseWriterStub{
+		CheckAndCreatePolicyCalled: func(policyName string, policy *bytes.Buffer) error {
+			createdPolicies = append(createdPolicies, policyName)
+			return nil
+		},
+	}
 
 	elasticProc, err := NewElasticProcessor(args)
 	require.NoError(t, err)
 	require.NotNil(t, elasticProc)
+	require.Equal(t, []string{dataindexer.BlockPolicy, dataindexer.AccountsESDTPolicy}, createdPolicies)
 }
 
 func TestElasticProcessor_RemoveHeader(t *testing.T) {
@@ -249,7 +296,7 @@ func TestElasticProcessor_RemoveHeader(t *testing.T) {
 	elasticProc, err := NewElasticProcessor(args)
 	require.NoError(t, err)
 
-	err = elasticProc.RemoveHeader(&dataBlock.Header{})
+	err = elasticProc.RemoveHeader(context.Background(), &dataBlock.Header{})
 	require.Nil(t, err)
 	require.True(t, called)
 }
@@ -315,7 +362,7 @@ func TestElasticProcessor_RemoveMiniblocks(t *testing.T) {
 			mb1, mb2, mb3, mb4,
 		},
 	}
-	err = elasticProc.RemoveMiniblocks(header, body)
+	err = elasticProc.RemoveMiniblocks(context.Background(), header, body)
 	require.Nil(t, err)
 	require.True(t, called)
 }
@@ -332,7 +379,7 @@ func TestElasticseachDatabaseSaveHeader_RequestError(t *testing.T) {
 	arguments.BlockProc, _ = block.NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
 	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
 
-	err := elasticDatabase.SaveHeader(createEmptyOutportBlockWithHeader())
+	err := elasticDatabase.SaveHeader(context.Background(), createEmptyOutportBlockWithHeader())
 	require.Equal(t, localErr, err)
 }
 
@@ -366,8 +413,42 @@ func TestElasticseachSaveTransactions(t *testing.T) {
 	}
 
 	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
-	err := elasticDatabase.SaveTransactions(outportBlock)
+	err := elasticDatabase.SaveTransactions(context.Background(), outportBlock)
+	require.Equal(t, localErr, err)
+}
+
+func TestElasticProcessor_SaveTransactionsShouldSaveInAnalyticsSink(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	arguments := createMockElasticProcessorArgs()
+	preparedResults := &data.PreparedResults{
+		Transactions: []*data.Transaction{{Hash: "tx1"}},
+	}
+	arguments.TransactionsProc = &mock.DBTransactionProcessorStub{
+		PrepareTransactionsForDatabaseCalled: func(_ []*dataBlock.MiniBlock, _ coreData.HeaderHandler, _ *outport.TransactionPool) *data.PreparedResults {
+			return preparedResults
+		},
+	}
+	var savedHeaderHash []byte
+	var savedResults *data.PreparedResults
+	arguments.AnalyticsSink = &mock.AnalyticsSinkStub{
+		SaveBlockResultsCalled: func(headerHash []byte, _ coreData.HeaderHandler, preparedResults *data.PreparedResults, logsData *data.PreparedLogsResults) error {
+			require.NotNil(t, logsData)
+			savedHeaderHash = headerHash
+			savedResults = preparedResults
+			return localErr
+		},
+	}
+
+	outportBlock := createEmptyOutportBlockWithHeader()
+	outportBlock.BlockData.HeaderHash = []byte("hash")
+
+	elasticProc := newElasticsearchProcessor(&mock.DatabaseWriterStub{}, arguments)
+	err := elasticProc.SaveTransactions(context.Background(), outportBlock)
 	require.Equal(t, localErr, err)
+	require.Equal(t, []byte("hash"), savedHeaderHash)
+	require.Equal(t, preparedResults, savedResults)
 }
 
 func TestElasticProcessor_SaveValidatorsRating(t *testing.T) {
@@ -383,7 +464,7 @@ func TestElasticProcessor_SaveValidatorsRating(t *testing.T) {
 	arguments.ValidatorsProc, _ = validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32), 0)
 	elasticProc, _ := NewElasticProcessor(arguments)
 
-	err := elasticProc.SaveValidatorsRating(&outport.ValidatorsRating{
+	err := elasticProc.SaveValidatorsRating(context.Background(), &outport.ValidatorsRating{
 		ShardID:              0,
 		Epoch:                1,
 		ValidatorsRatingInfo: []*outport.ValidatorRatingInfo{{}},
@@ -391,6 +472,35 @@ func TestElasticProcessor_SaveValidatorsRating(t *testing.T) {
 	require.Equal(t, localErr, err)
 }
 
+func TestElasticProcessor_SaveValidatorsRatingRejectedItemsShouldRecordRejection(t *testing.T) {
+	t.Parallel()
+
+	rejectedErr := fmt.Errorf("%w: rejected execution", dataindexer.ErrRejectedBulkItems)
+	arguments := createMockElasticProcessorArgs()
+	arguments.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			return rejectedErr
+		},
+	}
+	numRejections := 0
+	arguments.PressureMonitor = &mock.PressureMonitorStub{
+		RecordRejectionCalled: func() {
+			numRejections++
+		},
+	}
+
+	arguments.ValidatorsProc, _ = validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32), 0)
+	elasticProc, _ := NewElasticProcessor(arguments)
+
+	err := elasticProc.SaveValidatorsRating(context.Background(), &outport.ValidatorsRating{
+		ShardID:              0,
+		Epoch:                1,
+		ValidatorsRatingInfo: []*outport.ValidatorRatingInfo{{}},
+	})
+	require.Equal(t, rejectedErr, err)
+	require.Equal(t, 1, numRejections)
+}
+
 func TestElasticProcessor_SaveMiniblocks(t *testing.T) {
 	localErr := errors.New("localErr")
 
@@ -411,7 +521,7 @@ func TestElasticProcessor_SaveMiniblocks(t *testing.T) {
 	body := &dataBlock.Body{MiniBlocks: dataBlock.MiniBlockSlice{
 		{SenderShardID: 0, ReceiverShardID: 1},
 	}}
-	err := elasticProc.SaveMiniblocks(header, body.MiniBlocks)
+	err := elasticProc.SaveMiniblocks(context.Background(), header, body.MiniBlocks)
 	require.Equal(t, localErr, err)
 }
 
@@ -429,7 +539,7 @@ func TestElasticsearch_saveShardValidatorsPubKeys_RequestError(t *testing.T) {
 	arguments.ValidatorsProc, _ = validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32), 0)
 	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
 
-	err := elasticDatabase.SaveShardValidatorsPubKeys(&outport.ValidatorsPubKeys{
+	err := elasticDatabase.SaveShardValidatorsPubKeys(context.Background(), &outport.ValidatorsPubKeys{
 		Epoch: epoch,
 		ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{
 			shardID: {Keys: valPubKeys},
@@ -449,7 +559,7 @@ func TestElasticsearch_saveRoundInfoRequestError(t *testing.T) {
 	}
 	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
 
-	err := elasticDatabase.SaveRoundsInfo(&outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{roundInfo}})
+	err := elasticDatabase.SaveRoundsInfo(context.Background(), &outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{roundInfo}})
 	require.Equal(t, localError, err)
 
 }
@@ -487,6 +597,13 @@ func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 	txDbProc, _ := transactions.NewTransactionsProcessor(args)
 
 	arguments.TransactionsProc = txDbProc
+	var revertedHash []byte
+	arguments.AnalyticsSink = &mock.AnalyticsSinkStub{
+		RevertBlockCalled: func(headerHash []byte) error {
+			revertedHash = headerHash
+			return nil
+		},
+	}
 
 	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
 
@@ -505,9 +622,12 @@ func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 		},
 	}
 
-	err := elasticSearchProc.RemoveTransactions(header, blk)
+	err := elasticSearchProc.RemoveTransactions(context.Background(), header, blk)
 	require.Nil(t, err)
 	require.True(t, called)
+
+	expectedHash, _ := arguments.BlockProc.ComputeHeaderHash(header)
+	require.Equal(t, expectedHash, revertedHash)
 }
 
 func TestElasticProcessor_IndexEpochInfoData(t *testing.T) {
@@ -530,7 +650,7 @@ func TestElasticProcessor_IndexEpochInfoData(t *testing.T) {
 	err := elasticSearchProc.indexEpochInfoData(shardHeader, buffSlice)
 	require.True(t, errors.Is(err, dataindexer.ErrHeaderTypeAssertion))
 
-	err = elasticSearchProc.SaveHeader(createEmptyOutportBlockWithHeader())
+	err = elasticSearchProc.SaveHeader(context.Background(), createEmptyOutportBlockWithHeader())
 	require.Nil(t, err)
 	require.True(t, called)
 }
@@ -560,7 +680,7 @@ func TestElasticProcessor_SaveTransactionNoDataShouldNotDoRequest(t *testing.T)
 	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
 	elasticSearchProc.enabledIndexes[dataindexer.ScResultsIndex] = struct{}{}
 
-	err := elasticSearchProc.SaveTransactions(createEmptyOutportBlockWithHeader())
+	err := elasticSearchProc.SaveTransactions(context.Background(), createEmptyOutportBlockWithHeader())
 	require.Nil(t, err)
 	require.False(t, called)
 }
@@ -588,7 +708,203 @@ func TestElasticProcessor_IndexAlteredAccounts(t *testing.T) {
 
 	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
 	tagsCount := tags.NewTagsCount()
-	err := elasticSearchProc.indexAlteredAccounts(100, nil, nil, buffSlice, tagsCount, 0)
+	err := elasticSearchProc.indexAlteredAccounts(context.Background(), 100, nil, nil, buffSlice, tagsCount, 0)
 	require.Nil(t, err)
 	require.True(t, called)
 }
+
+func TestElasticProcessor_SaveFinalizedBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil finalized block should error", func(t *testing.T) {
+		t.Parallel()
+
+		elasticProc, _ := NewElasticProcessor(createMockElasticProcessorArgs())
+		err := elasticProc.SaveFinalizedBlock(context.Background(), nil)
+		require.Equal(t, dataindexer.ErrNilFinalizedBlock, err)
+	})
+
+	t.Run("block not indexed should not update anything", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockElasticProcessorArgs()
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+			UpdateByQueryCalled: func(index string, buff *bytes.Buffer) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.SaveFinalizedBlock(context.Background(), &outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("h1")})
+		require.Nil(t, err)
+	})
+
+	t.Run("should mark the block, miniblocks and transactions as final", func(t *testing.T) {
+		t.Parallel()
+
+		bulkRequests := make([]string, 0)
+		updatedIndices := make([]string, 0)
+		args := createMockElasticProcessorArgs()
+		args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+				require.Equal(t, []string{hex.EncodeToString([]byte("h1"))}, ids)
+				require.Equal(t, dataindexer.BlockIndex, index)
+
+				responseBlocks := response.(*data.ResponseBlocks)
+				responseBlocks.Docs = []data.ResponseBlockDB{{
+					Found: true,
+					ID:    ids[0],
+					Source: data.SourceBlock{
+						Nonce:            10,
+						ShardID:          1,
+						MiniBlocksHashes: []string{"mb1", "mb2"},
+					},
+				}}
+				return nil
+			},
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				bulkRequests = append(bulkRequests, buff.String())
+				return nil
+			},
+			UpdateByQueryCalled: func(index string, buff *bytes.Buffer) error {
+				require.True(t, strings.Contains(buff.String(), `["mb1","mb2"]`))
+				updatedIndices = append(updatedIndices, index)
+				return nil
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.SaveFinalizedBlock(context.Background(), &outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("h1")})
+		require.Nil(t, err)
+		require.Len(t, bulkRequests, 1)
+		require.True(t, strings.Contains(bulkRequests[0], `"final":true`))
+		require.True(t, strings.Contains(bulkRequests[0], `{"key":"last-final-nonce-1","value":"10"}`))
+		require.Equal(t, []string{dataindexer.MiniblocksIndex, dataindexer.TransactionsIndex}, updatedIndices)
+	})
+}
+
+func TestElasticProcessor_GetIndexingCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("values index disabled should return nil", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockElasticProcessorArgs()
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		checkpoint, err := elasticProc.GetIndexingCheckpoint(context.Background(), 0)
+		require.Nil(t, err)
+		require.Nil(t, checkpoint)
+	})
+
+	t.Run("checkpoint not found should return nil", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockElasticProcessorArgs()
+		args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		checkpoint, err := elasticProc.GetIndexingCheckpoint(context.Background(), 0)
+		require.Nil(t, err)
+		require.Nil(t, checkpoint)
+	})
+
+	t.Run("should return the checkpoint of the shard", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockElasticProcessorArgs()
+		args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+				require.Equal(t, []string{"indexing-checkpoint-1"}, ids)
+				require.Equal(t, dataindexer.ValuesIndex, index)
+
+				responseCheckpoints := response.(*data.ResponseIndexingCheckpoints)
+				responseCheckpoints.Docs = []data.ResponseIndexingCheckpointDB{{
+					Found: true,
+					ID:    ids[0],
+					Source: data.IndexingCheckpoint{
+						Nonce: 10,
+						Hash:  "abcd",
+					},
+				}}
+				return nil
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		checkpoint, err := elasticProc.GetIndexingCheckpoint(context.Background(), 1)
+		require.Nil(t, err)
+		require.Equal(t, uint64(10), checkpoint.Nonce)
+		require.Equal(t, "abcd", checkpoint.Hash)
+	})
+}
+
+func TestElasticProcessor_SaveIndexingCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("values index disabled should not save", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockElasticProcessorArgs()
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.SaveIndexingCheckpoint(context.Background(), 1, &data.IndexingCheckpoint{Nonce: 10})
+		require.Nil(t, err)
+	})
+
+	t.Run("should save the checkpoint of the shard", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		args := createMockElasticProcessorArgs()
+		args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				require.Equal(t, `{ "index" : { "_index":"values", "_id" : "indexing-checkpoint-1" } }
+{"key":"indexing-checkpoint-1","value":"10","nonce":10,"hash":"abcd","timestamp":1000}
+`, buff.String())
+				called = true
+				return nil
+			},
+		}
+		elasticProc, _ := NewElasticProcessor(args)
+
+		err := elasticProc.SaveIndexingCheckpoint(context.Background(), 1, &data.IndexingCheckpoint{Nonce: 10, Hash: "abcd", Timestamp: 1000})
+		require.Nil(t, err)
+		require.True(t, called)
+	})
+}
+
+func TestElasticProcessor_CloseShouldCloseTheDatabaseClient(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		CloseCalled: func() error {
+			return expectedErr
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	require.Equal(t, expectedErr, elasticProc.Close())
+}
@@ -2,11 +2,14 @@ package factory
 
 import (
 	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/hashing"
 	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/pressure"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/accounts"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/analytics"
 	blockProc "github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/logsevents"
@@ -14,6 +17,7 @@ import (
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/operations"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/statistics"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/templatesAndPolicies"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tokencache"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/transactions"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/validators"
 )
@@ -29,13 +33,24 @@ type ArgElasticProcessorFactory struct {
 	Version                  string
 	Denomination             int
 	BulkRequestMaxSize       int
+	NumBulkRequestWorkers    int
 	UseKibana                bool
 	ImportDB                 bool
+	DeadLettersEnabled       bool
+	// Backend is the backend of the cluster, which selects the format of the index templates
+	Backend string
+	// PressureMonitor is optional. Without it, the bulk requests always use the BulkRequestMaxSize
+	PressureMonitor elasticproc.PressureMonitor
+	// TokenCache is optional. Without it, the type and the current owner of the tokens are always fetched from the
+	// tokens index
+	TokenCache elasticproc.TokenCache
+	// AnalyticsSink is optional. Without it, the data is saved in Elasticsearch only
+	AnalyticsSink elasticproc.AnalyticsSink
 }
 
 // CreateElasticProcessor will create a new instance of ElasticProcessor
 func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (dataindexer.ElasticProcessor, error) {
-	templatesAndPoliciesReader := templatesAndPolicies.CreateTemplatesAndPoliciesReader(arguments.UseKibana)
+	templatesAndPoliciesReader := templatesAndPolicies.CreateTemplatesAndPoliciesReader(arguments.UseKibana, arguments.Backend)
 	indexTemplates, indexPolicies, err := templatesAndPoliciesReader.GetElasticTemplatesAndPolicies()
 	if err != nil {
 		return nil, err
@@ -105,23 +120,41 @@ func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (dataindexer.E
 		return nil, err
 	}
 
+	var pressureMonitor elasticproc.PressureMonitor = pressure.NewDisabledPressureMonitor(arguments.BulkRequestMaxSize)
+	if !check.IfNil(arguments.PressureMonitor) {
+		pressureMonitor = arguments.PressureMonitor
+	}
+
+	var tokenCache elasticproc.TokenCache = tokencache.NewDisabledTokenCache()
+	if !check.IfNil(arguments.TokenCache) {
+		tokenCache = arguments.TokenCache
+	}
+
+	var analyticsSink elasticproc.AnalyticsSink = analytics.NewDisabledAnalyticsSink()
+	if !check.IfNil(arguments.AnalyticsSink) {
+		analyticsSink = arguments.AnalyticsSink
+	}
+
 	args := &elasticproc.ArgElasticProcessor{
-		BulkRequestMaxSize: arguments.BulkRequestMaxSize,
-		TransactionsProc:   txsProc,
-		AccountsProc:       accountsProc,
-		BlockProc:          blockProcHandler,
-		MiniblocksProc:     miniblocksProc,
-		ValidatorsProc:     validatorsProc,
-		StatisticsProc:     generalInfoProc,
-		LogsAndEventsProc:  logsAndEventsProc,
-		DBClient:           arguments.DBClient,
-		EnabledIndexes:     enabledIndexesMap,
-		UseKibana:          arguments.UseKibana,
-		IndexTemplates:     indexTemplates,
-		IndexPolicies:      indexPolicies,
-		OperationsProc:     operationsProc,
-		ImportDB:           arguments.ImportDB,
-		Version:            arguments.Version,
+		PressureMonitor:       pressureMonitor,
+		TokenCache:            tokenCache,
+		AnalyticsSink:         analyticsSink,
+		NumBulkRequestWorkers: arguments.NumBulkRequestWorkers,
+		DeadLettersEnabled:    arguments.DeadLettersEnabled,
+		TransactionsProc:      txsProc,
+		AccountsProc:          accountsProc,
+		BlockProc:             blockProcHandler,
+		MiniblocksProc:        miniblocksProc,
+		ValidatorsProc:        validatorsProc,
+		StatisticsProc:        generalInfoProc,
+		LogsAndEventsProc:     logsAndEventsProc,
+		DBClient:              arguments.DBClient,
+		EnabledIndexes:        enabledIndexesMap,
+		IndexTemplates:        indexTemplates,
+		IndexPolicies:         indexPolicies,
+		OperationsProc:        operationsProc,
+		ImportDB:              arguments.ImportDB,
+		Version:               arguments.Version,
 	}
 
 	return elasticproc.NewElasticProcessor(args)
@@ -26,6 +26,7 @@ type DatabaseClientHandler interface {
 	CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error
 	CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error
 
+	Close() error
 	IsInterfaceNil() bool
 }
 
@@ -51,6 +52,9 @@ type DBBlockHandler interface {
 
 	SerializeEpochInfoData(header coreData.HeaderHandler, buffSlice *data.BufferSlice, index string) error
 	SerializeBlock(elasticBlock *data.Block, buffSlice *data.BufferSlice, index string) error
+	SerializeFinalizedBlock(headerHash string, finalizedAt int64, buffSlice *data.BufferSlice, index string) error
+	SerializeLastFinalNonce(shardID uint32, nonce uint64, buffSlice *data.BufferSlice, index string) error
+	SerializeIndexingCheckpoint(shardID uint32, checkpoint *data.IndexingCheckpoint, buffSlice *data.BufferSlice, index string) error
 }
 
 // DBTransactionsHandler defines the actions that a transactions handler should do
@@ -119,3 +123,32 @@ type OperationsHandler interface {
 	ProcessTransactionsAndSCRs(txs []*data.Transaction, scrs []*data.ScResult, isImportDB bool, shardID uint32) ([]*data.Transaction, []*data.ScResult)
 	SerializeSCRs(scrs []*data.ScResult, buffSlice *data.BufferSlice, index string, shardID uint32) error
 }
+
+// TokenCache defines the actions that a component that caches the type and the current owner of the tokens should do
+type TokenCache interface {
+	Get(tokens []string) (map[string]data.SourceToken, []string)
+	Put(token string, source data.SourceToken)
+	Purge()
+	IsInterfaceNil() bool
+}
+
+// AnalyticsSink defines the actions that a component that saves the results prepared for every block in an analytics
+// database, next to Elasticsearch, should do
+type AnalyticsSink interface {
+	SaveBlockResults(
+		ctx context.Context,
+		headerHash []byte,
+		header coreData.HeaderHandler,
+		preparedResults *data.PreparedResults,
+		logsData *data.PreparedLogsResults,
+	) error
+	RevertBlock(ctx context.Context, headerHash []byte) error
+	IsInterfaceNil() bool
+}
+
+// PressureMonitor defines the actions that a component that tracks the pressure of the Elasticsearch cluster should do
+type PressureMonitor interface {
+	RecordRejection()
+	GetBulkRequestMaxSize() int
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,178 @@
+package templatesAndPolicies
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/templates"
+)
+
+const (
+	// the priority is higher than the one of the built-in index templates of Elasticsearch, e.g. logs-*-*, so the
+	// index patterns of the templates can overlap
+	composableTemplatePriority = 500
+
+	settingsComponentSuffix = "-settings"
+	mappingsComponentSuffix = "-mappings"
+)
+
+const (
+	legacyIndexStateManagementSettingsPrefix = "opendistro.index_state_management."
+	indexStateManagementSettingsPrefix       = "plugins.index_state_management."
+)
+
+// the settings of the index state management plugin, which Elasticsearch rejects as unknown settings
+var indexStateManagementSettingsPrefixes = []string{"opendistro.", indexStateManagementSettingsPrefix}
+
+type templatesAndPolicyReaderComposable struct {
+	reader          TemplatesAndPoliciesHandler
+	forOpenSearch   bool
+	convertSettings func(settings map[string]interface{}) map[string]interface{}
+}
+
+// NewTemplatesAndPolicyReaderComposable will create a new instance of templatesAndPolicyReaderComposable, that converts
+// the templates of the provided reader to composable index templates with component templates, for Elasticsearch. The
+// settings of the index state management plugin are removed
+func NewTemplatesAndPolicyReaderComposable(reader TemplatesAndPoliciesHandler) *templatesAndPolicyReaderComposable {
+	return &templatesAndPolicyReaderComposable{
+		reader:          reader,
+		convertSettings: removeIndexStateManagementSettings,
+	}
+}
+
+// NewTemplatesAndPolicyReaderComposableOpenSearch will create a new instance of templatesAndPolicyReaderComposable, that
+// converts the templates of the provided reader to composable index templates with component templates, for OpenSearch.
+// The settings of the index state management plugin are renamed from their OpenDistro prefix to the OpenSearch one
+func NewTemplatesAndPolicyReaderComposableOpenSearch(reader TemplatesAndPoliciesHandler) *templatesAndPolicyReaderComposable {
+	return &templatesAndPolicyReaderComposable{
+		reader:          reader,
+		forOpenSearch:   true,
+		convertSettings: renameIndexStateManagementSettings,
+	}
+}
+
+// GetElasticTemplatesAndPolicies will return the templates, in the composable format, and the policies
+func (tr *templatesAndPolicyReaderComposable) GetElasticTemplatesAndPolicies() (map[string]*bytes.Buffer, map[string]*bytes.Buffer, error) {
+	legacyTemplates, indexPolicies, err := tr.reader.GetElasticTemplatesAndPolicies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexTemplates := make(map[string]*bytes.Buffer, len(legacyTemplates))
+	for name, legacyTemplate := range legacyTemplates {
+		// the opendistro indices are created only by the index state management plugin, which is not available on
+		// Elasticsearch
+		if name == indexer.OpenDistroIndex && !tr.forOpenSearch {
+			continue
+		}
+
+		indexTemplates[name], err = toComposableTemplate(name, legacyTemplate, tr.convertSettings)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w when converting the %s template", err, name)
+		}
+	}
+
+	return indexTemplates, indexPolicies, nil
+}
+
+// toComposableTemplate splits a legacy index template into a settings and a mappings component template and an index
+// template composed of them. The settings are adapted to the cluster with the provided function
+func toComposableTemplate(
+	name string,
+	legacyTemplate *bytes.Buffer,
+	convertSettings func(settings map[string]interface{}) map[string]interface{},
+) (*bytes.Buffer, error) {
+	legacy := make(map[string]interface{})
+	err := json.Unmarshal(legacyTemplate.Bytes(), &legacy)
+	if err != nil {
+		return nil, err
+	}
+
+	composable := templates.ComposableTemplate{
+		ComponentTemplates: make([]templates.ComponentTemplate, 0, 2),
+		IndexTemplate: templates.Object{
+			"index_patterns": legacy["index_patterns"],
+			"priority":       composableTemplatePriority,
+		},
+	}
+
+	settings, ok := legacy["settings"].(map[string]interface{})
+	if ok {
+		composable.ComponentTemplates = append(composable.ComponentTemplates, templates.ComponentTemplate{
+			Name: name + settingsComponentSuffix,
+			Body: templates.Object{
+				"template": templates.Object{
+					"settings": convertSettings(settings),
+				},
+			},
+		})
+	}
+
+	mappings, ok := legacy["mappings"]
+	if ok {
+		composable.ComponentTemplates = append(composable.ComponentTemplates, templates.ComponentTemplate{
+			Name: name + mappingsComponentSuffix,
+			Body: templates.Object{
+				"template": templates.Object{
+					"mappings": mappings,
+				},
+			},
+		})
+	}
+
+	composedOf := make(templates.Array, 0, len(composable.ComponentTemplates))
+	for _, componentTemplate := range composable.ComponentTemplates {
+		composedOf = append(composedOf, componentTemplate.Name)
+	}
+	composable.IndexTemplate["composed_of"] = composedOf
+
+	aliases, ok := legacy["aliases"]
+	if ok {
+		composable.IndexTemplate["template"] = templates.Object{
+			"aliases": aliases,
+		}
+	}
+
+	composableBytes, err := json.Marshal(composable)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(composableBytes), nil
+}
+
+func removeIndexStateManagementSettings(settings map[string]interface{}) map[string]interface{} {
+	filtered := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if !isIndexStateManagementSetting(key) {
+			filtered[key] = value
+		}
+	}
+
+	return filtered
+}
+
+func renameIndexStateManagementSettings(settings map[string]interface{}) map[string]interface{} {
+	renamed := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if strings.HasPrefix(key, legacyIndexStateManagementSettingsPrefix) {
+			key = indexStateManagementSettingsPrefix + strings.TrimPrefix(key, legacyIndexStateManagementSettingsPrefix)
+		}
+		renamed[key] = value
+	}
+
+	return renamed
+}
+
+func isIndexStateManagementSetting(key string) bool {
+	for _, prefix := range indexStateManagementSettingsPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
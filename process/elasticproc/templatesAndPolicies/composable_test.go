@@ -0,0 +1,87 @@
+package templatesAndPolicies
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestTemplatesAndPolicyReaderComposable_GetElasticTemplatesAndPolicies(t *testing.T) {
+	t.Parallel()
+
+	reader := NewTemplatesAndPolicyReaderComposable(NewTemplatesAndPolicyReaderWithKibana())
+
+	templates, policies, err := reader.GetElasticTemplatesAndPolicies()
+	require.Nil(t, err)
+	require.Len(t, policies, 12)
+	require.Len(t, templates, 20)
+	require.NotContains(t, templates, indexer.OpenDistroIndex)
+
+	transactions := templates[indexer.TransactionsIndex].Bytes()
+	require.Equal(t, `["transactions-*"]`, gjson.GetBytes(transactions, "index_template.index_patterns").Raw)
+	require.Equal(t, `["transactions-settings","transactions-mappings"]`, gjson.GetBytes(transactions, "index_template.composed_of").Raw)
+	require.Equal(t, int64(composableTemplatePriority), gjson.GetBytes(transactions, "index_template.priority").Int())
+
+	settings := gjson.GetBytes(transactions, "component_templates.0.body.template.settings")
+	require.Equal(t, int64(5), settings.Get("number_of_shards").Int())
+	require.False(t, settings.Get(`opendistro\.index_state_management\.rollover_alias`).Exists())
+	require.True(t, gjson.GetBytes(transactions, "component_templates.1.body.template.mappings.properties.nonce").Exists())
+}
+
+func TestTemplatesAndPolicyReaderComposableOpenSearch_GetElasticTemplatesAndPolicies(t *testing.T) {
+	t.Parallel()
+
+	reader := NewTemplatesAndPolicyReaderComposableOpenSearch(NewTemplatesAndPolicyReaderWithKibana())
+
+	templates, policies, err := reader.GetElasticTemplatesAndPolicies()
+	require.Nil(t, err)
+	require.Len(t, policies, 12)
+	require.Len(t, templates, 21)
+	require.Contains(t, templates, indexer.OpenDistroIndex)
+
+	transactions := templates[indexer.TransactionsIndex].Bytes()
+	require.Equal(t, `["transactions-*"]`, gjson.GetBytes(transactions, "index_template.index_patterns").Raw)
+
+	settings := gjson.GetBytes(transactions, "component_templates.0.body.template.settings")
+	require.Equal(t, int64(5), settings.Get("number_of_shards").Int())
+	require.False(t, settings.Get(`opendistro\.index_state_management\.rollover_alias`).Exists())
+	require.Equal(t, "transactions", settings.Get(`plugins\.index_state_management\.rollover_alias`).String())
+}
+
+func TestToComposableTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid template should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := toComposableTemplate("blocks", bytes.NewBufferString("not a template"), removeIndexStateManagementSettings)
+		require.NotNil(t, err)
+	})
+
+	t.Run("template without mappings should have only the settings component", func(t *testing.T) {
+		t.Parallel()
+
+		composable, err := toComposableTemplate("blocks", bytes.NewBufferString(
+			`{"index_patterns":["blocks-*"],"settings":{"number_of_shards":3},"aliases":{"blocks":{}}}`),
+			removeIndexStateManagementSettings)
+		require.Nil(t, err)
+
+		expected := `{
+			"component_templates": [
+				{"name": "blocks-settings", "body": {"template": {"settings": {"number_of_shards": 3}}}}
+			],
+			"index_template": {
+				"index_patterns": ["blocks-*"],
+				"priority": 500,
+				"composed_of": ["blocks-settings"],
+				"template": {"aliases": {"blocks": {}}}
+			}
+		}`
+		require.JSONEq(t, expected, composable.String())
+		require.True(t, json.Valid(composable.Bytes()))
+	})
+}
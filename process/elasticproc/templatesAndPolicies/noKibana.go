@@ -42,6 +42,7 @@ func (tr *templatesAndPolicyReaderNoKibana) GetElasticTemplatesAndPolicies() (ma
 	indexTemplates[indexer.ESDTsIndex] = noKibana.ESDTs.ToBuffer()
 	indexTemplates[indexer.ValuesIndex] = noKibana.Values.ToBuffer()
 	indexTemplates[indexer.EventsIndex] = noKibana.Events.ToBuffer()
+	indexTemplates[indexer.DeadLettersIndex] = noKibana.DeadLetters.ToBuffer()
 
 	return indexTemplates, indexPolicies, nil
 }
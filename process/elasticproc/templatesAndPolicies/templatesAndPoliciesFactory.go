@@ -1,7 +1,23 @@
 package templatesAndPolicies
 
-// CreateTemplatesAndPoliciesReader will create a new instance of templatesAndPoliciesReader
-func CreateTemplatesAndPoliciesReader(useKibana bool) TemplatesAndPoliciesHandler {
+import indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+
+// CreateTemplatesAndPoliciesReader will create a new instance of templatesAndPoliciesReader that emits the templates in
+// the format of the provided backend. The Elasticsearch 8 and the OpenSearch clusters get composable index templates,
+// while the other Elasticsearch clusters keep the legacy ones
+func CreateTemplatesAndPoliciesReader(useKibana bool, backend string) TemplatesAndPoliciesHandler {
+	reader := createLegacyTemplatesReader(useKibana)
+	switch backend {
+	case indexer.Elasticsearch8Backend:
+		return NewTemplatesAndPolicyReaderComposable(reader)
+	case indexer.OpenSearchBackend:
+		return NewTemplatesAndPolicyReaderComposableOpenSearch(reader)
+	default:
+		return reader
+	}
+}
+
+func createLegacyTemplatesReader(useKibana bool) TemplatesAndPoliciesHandler {
 	if useKibana {
 		return NewTemplatesAndPolicyReaderWithKibana()
 	}
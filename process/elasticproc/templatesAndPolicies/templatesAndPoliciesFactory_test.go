@@ -3,13 +3,14 @@ package templatesAndPolicies
 import (
 	"testing"
 
+	indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/stretchr/testify/require"
 )
 
 func TestCreateTemplatesAndPoliciesReader_NoKibana(t *testing.T) {
 	t.Parallel()
 
-	reader := CreateTemplatesAndPoliciesReader(false)
+	reader := CreateTemplatesAndPoliciesReader(false, indexer.AutoBackend)
 
 	_, ok := reader.(*templatesAndPolicyReaderNoKibana)
 	require.True(t, ok)
@@ -18,8 +19,32 @@ func TestCreateTemplatesAndPoliciesReader_NoKibana(t *testing.T) {
 func TestCreateTemplatesAndPoliciesReader_WithKibana(t *testing.T) {
 	t.Parallel()
 
-	reader := CreateTemplatesAndPoliciesReader(true)
+	reader := CreateTemplatesAndPoliciesReader(true, indexer.ElasticsearchBackend)
 
 	_, ok := reader.(*templatesAndPolicyReaderWithKibana)
 	require.True(t, ok)
 }
+
+func TestCreateTemplatesAndPoliciesReader_Composable(t *testing.T) {
+	t.Parallel()
+
+	reader := CreateTemplatesAndPoliciesReader(true, indexer.Elasticsearch8Backend)
+
+	composableReader, ok := reader.(*templatesAndPolicyReaderComposable)
+	require.True(t, ok)
+	require.False(t, composableReader.forOpenSearch)
+	_, ok = composableReader.reader.(*templatesAndPolicyReaderWithKibana)
+	require.True(t, ok)
+}
+
+func TestCreateTemplatesAndPoliciesReader_OpenSearch(t *testing.T) {
+	t.Parallel()
+
+	reader := CreateTemplatesAndPoliciesReader(false, indexer.OpenSearchBackend)
+
+	composableReader, ok := reader.(*templatesAndPolicyReaderComposable)
+	require.True(t, ok)
+	require.True(t, composableReader.forOpenSearch)
+	_, ok = composableReader.reader.(*templatesAndPolicyReaderNoKibana)
+	require.True(t, ok)
+}
@@ -0,0 +1,54 @@
+package elasticproc
+
+import (
+	"context"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+// getTokensTypeAndOwner returns the type and the current owner of the provided tokens. Only the tokens that are not in
+// the token cache are fetched from the tokens index, and the ones that already have a type are added to the cache
+func (ei *elasticProcessor) getTokensTypeAndOwner(ctx context.Context, tokens []string, shardID uint32) (*data.ResponseTokens, error) {
+	cachedTokens, missingTokens := ei.tokenCache.Get(tokens)
+
+	responseTokens := &data.ResponseTokens{}
+	if len(missingTokens) > 0 {
+		ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+		err := ei.elasticClient.DoMultiGet(ctxWithValue, missingTokens, elasticIndexer.TokensIndex, true, responseTokens)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tokenData := range responseTokens.Docs {
+		// a token document without a type was created before the issue event was indexed, so it is not cached
+		if !tokenData.Found || tokenData.Source.Type == "" {
+			continue
+		}
+
+		ei.tokenCache.Put(tokenData.ID, tokenData.Source)
+	}
+
+	for token, sourceToken := range cachedTokens {
+		responseTokens.Docs = append(responseTokens.Docs, data.ResponseTokenDB{
+			Found:  true,
+			ID:     token,
+			Source: sourceToken,
+		})
+	}
+
+	return responseTokens, nil
+}
+
+// updateTokenCache will put in the token cache the type and the current owner set by the issue, transfer ownership and
+// change type events of an indexed block
+func (ei *elasticProcessor) updateTokenCache(tokensInfo []*data.TokenInfo) {
+	for _, tokenInfo := range tokensInfo {
+		ei.tokenCache.Put(tokenInfo.Token, data.SourceToken{
+			Type:         tokenInfo.Type,
+			CurrentOwner: tokenInfo.CurrentOwner,
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package elasticproc
+
+import (
+	"context"
+	"testing"
+
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tokencache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_GetTokensTypeAndOwnerShouldUseTheCache(t *testing.T) {
+	t.Parallel()
+
+	requestedTokens := make([][]string, 0)
+	dbWriter := &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, dataindexer.TokensIndex, index)
+			requestedTokens = append(requestedTokens, ids)
+
+			responseTokens := response.(*data.ResponseTokens)
+			for _, id := range ids {
+				if id == "NEW-01" {
+					responseTokens.Docs = append(responseTokens.Docs, data.ResponseTokenDB{Found: false, ID: id})
+					continue
+				}
+
+				responseTokens.Docs = append(responseTokens.Docs, data.ResponseTokenDB{
+					Found:  true,
+					ID:     id,
+					Source: data.SourceToken{Type: "NonFungibleESDT", CurrentOwner: "erd1owner"},
+				})
+			}
+			return nil
+		},
+	}
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.TokenCache, _ = tokencache.NewTokenCache(tokencache.ArgsTokenCache{
+		StatusMetrics: metrics.NewStatusMetrics(),
+		Capacity:      10,
+	})
+	elasticProc := newElasticsearchProcessor(dbWriter, arguments)
+
+	responseTokens, err := elasticProc.getTokensTypeAndOwner(context.Background(), []string{"NFT-01", "NEW-01"}, 0)
+	require.Nil(t, err)
+	require.Len(t, responseTokens.Docs, 2)
+
+	// the token issued by an indexed block is served from the cache, the token not found before is fetched again
+	elasticProc.updateTokenCache([]*data.TokenInfo{{Token: "SFT-02", Type: "SemiFungibleESDT", CurrentOwner: "erd1issuer"}})
+	responseTokens, err = elasticProc.getTokensTypeAndOwner(context.Background(), []string{"NFT-01", "NEW-01", "SFT-02"}, 0)
+	require.Nil(t, err)
+	require.Equal(t, [][]string{{"NFT-01", "NEW-01"}, {"NEW-01"}}, requestedTokens)
+
+	tokensData := data.NewTokensInfo()
+	tokensData.Add(&data.TokenInfo{Token: "NFT-01", Identifier: "NFT-01-01"})
+	tokensData.Add(&data.TokenInfo{Token: "SFT-02", Identifier: "SFT-02-01"})
+	tokensData.AddTypeAndOwnerFromResponse(responseTokens)
+	for _, tokenData := range tokensData.GetAll() {
+		if tokenData.Token == "SFT-02" {
+			require.Equal(t, "SemiFungibleESDT", tokenData.Type)
+			require.Equal(t, "erd1issuer", tokenData.CurrentOwner)
+			continue
+		}
+
+		require.Equal(t, "NonFungibleESDT", tokenData.Type)
+		require.Equal(t, "erd1owner", tokenData.CurrentOwner)
+	}
+
+	// a revert empties the cache
+	err = elasticProc.RemoveTransactions(context.Background(), &dataBlock.Header{}, &dataBlock.Body{})
+	require.Nil(t, err)
+	_, err = elasticProc.getTokensTypeAndOwner(context.Background(), []string{"NFT-01"}, 0)
+	require.Nil(t, err)
+	require.Equal(t, []string{"NFT-01"}, requestedTokens[2])
+}
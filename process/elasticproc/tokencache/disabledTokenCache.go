@@ -0,0 +1,29 @@
+package tokencache
+
+import "github.com/multiversx/mx-chain-es-indexer-go/data"
+
+type disabledTokenCache struct{}
+
+// NewDisabledTokenCache will create a token cache that does not keep anything, so every token is fetched from the
+// database
+func NewDisabledTokenCache() *disabledTokenCache {
+	return &disabledTokenCache{}
+}
+
+// Get returns all the provided tokens as missing
+func (dtc *disabledTokenCache) Get(tokens []string) (map[string]data.SourceToken, []string) {
+	return make(map[string]data.SourceToken), tokens
+}
+
+// Put does nothing
+func (dtc *disabledTokenCache) Put(_ string, _ data.SourceToken) {
+}
+
+// Purge does nothing
+func (dtc *disabledTokenCache) Purge() {
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dtc *disabledTokenCache) IsInterfaceNil() bool {
+	return dtc == nil
+}
@@ -0,0 +1,138 @@
+package tokencache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+const (
+	// HitsMetricTopic is the identifier for the number of token lookups served from the cache metric
+	HitsMetricTopic = "token_cache_hits"
+	// MissesMetricTopic is the identifier for the number of token lookups that had to be fetched from the database metric
+	MissesMetricTopic = "token_cache_misses"
+	// SizeMetricTopic is the identifier for the number of tokens held by the cache metric
+	SizeMetricTopic = "token_cache_size"
+)
+
+var errInvalidCapacity = errors.New("invalid token cache capacity")
+
+// ArgsTokenCache holds all the components needed to create a new instance of tokenCache
+type ArgsTokenCache struct {
+	StatusMetrics core.StatusMetricsHandler
+	// Capacity is the maximum number of tokens held by the cache. When it is reached, the least recently used token
+	// is evicted
+	Capacity int
+}
+
+type cacheEntry struct {
+	token  string
+	source data.SourceToken
+}
+
+type tokenCache struct {
+	mut           sync.Mutex
+	statusMetrics core.StatusMetricsHandler
+	capacity      int
+	entries       map[string]*list.Element
+	lru           *list.List
+	numHits       uint64
+	numMisses     uint64
+}
+
+// NewTokenCache will create a new instance of tokenCache. It keeps the type and the current owner of the most recently
+// used tokens, so they do not have to be fetched from the tokens index for every block
+func NewTokenCache(args ArgsTokenCache) (*tokenCache, error) {
+	if check.IfNil(args.StatusMetrics) {
+		return nil, core.ErrNilMetricsHandler
+	}
+	if args.Capacity <= 0 {
+		return nil, errInvalidCapacity
+	}
+
+	tc := &tokenCache{
+		statusMetrics: args.StatusMetrics,
+		capacity:      args.Capacity,
+		entries:       make(map[string]*list.Element),
+		lru:           list.New(),
+	}
+	tc.setMetricsUnprotected()
+
+	return tc, nil
+}
+
+// Get returns the type and the current owner of the provided tokens that are in the cache, together with the tokens
+// that are not
+func (tc *tokenCache) Get(tokens []string) (map[string]data.SourceToken, []string) {
+	tc.mut.Lock()
+	defer tc.mut.Unlock()
+
+	found := make(map[string]data.SourceToken)
+	missing := make([]string, 0)
+	for _, token := range tokens {
+		element, ok := tc.entries[token]
+		if !ok {
+			missing = append(missing, token)
+			continue
+		}
+
+		tc.lru.MoveToFront(element)
+		found[token] = element.Value.(*cacheEntry).source
+	}
+
+	tc.numHits += uint64(len(found))
+	tc.numMisses += uint64(len(missing))
+	tc.setMetricsUnprotected()
+
+	return found, missing
+}
+
+// Put will add or replace the type and the current owner of the provided token
+func (tc *tokenCache) Put(token string, source data.SourceToken) {
+	tc.mut.Lock()
+	defer tc.mut.Unlock()
+
+	element, ok := tc.entries[token]
+	if ok {
+		element.Value.(*cacheEntry).source = source
+		tc.lru.MoveToFront(element)
+		return
+	}
+
+	tc.entries[token] = tc.lru.PushFront(&cacheEntry{
+		token:  token,
+		source: source,
+	})
+	if tc.lru.Len() > tc.capacity {
+		oldest := tc.lru.Back()
+		tc.lru.Remove(oldest)
+		delete(tc.entries, oldest.Value.(*cacheEntry).token)
+	}
+
+	tc.setMetricsUnprotected()
+}
+
+// Purge will remove all the tokens from the cache
+func (tc *tokenCache) Purge() {
+	tc.mut.Lock()
+	defer tc.mut.Unlock()
+
+	tc.entries = make(map[string]*list.Element)
+	tc.lru.Init()
+	tc.setMetricsUnprotected()
+}
+
+func (tc *tokenCache) setMetricsUnprotected() {
+	tc.statusMetrics.SetGauge(HitsMetricTopic, tc.numHits)
+	tc.statusMetrics.SetGauge(MissesMetricTopic, tc.numMisses)
+	tc.statusMetrics.SetGauge(SizeMetricTopic, uint64(tc.lru.Len()))
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (tc *tokenCache) IsInterfaceNil() bool {
+	return tc == nil
+}
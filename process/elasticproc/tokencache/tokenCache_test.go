@@ -0,0 +1,100 @@
+package tokencache
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTokenCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil status metrics should error", func(t *testing.T) {
+		tc, err := NewTokenCache(ArgsTokenCache{Capacity: 10})
+		require.Nil(t, tc)
+		require.Equal(t, core.ErrNilMetricsHandler, err)
+	})
+	t.Run("invalid capacity should error", func(t *testing.T) {
+		tc, err := NewTokenCache(ArgsTokenCache{StatusMetrics: metrics.NewStatusMetrics()})
+		require.Nil(t, tc)
+		require.Equal(t, errInvalidCapacity, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		tc, err := NewTokenCache(ArgsTokenCache{StatusMetrics: metrics.NewStatusMetrics(), Capacity: 10})
+		require.Nil(t, err)
+		require.False(t, tc.IsInterfaceNil())
+	})
+}
+
+func TestTokenCache_GetAndPut(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	tc, _ := NewTokenCache(ArgsTokenCache{StatusMetrics: statusMetrics, Capacity: 10})
+
+	found, missing := tc.Get([]string{"TKN-01", "NFT-02"})
+	require.Empty(t, found)
+	require.Equal(t, []string{"TKN-01", "NFT-02"}, missing)
+
+	tc.Put("TKN-01", data.SourceToken{Type: "FungibleESDT", CurrentOwner: "erd1owner"})
+	tc.Put("NFT-02", data.SourceToken{Type: "NonFungibleESDT", CurrentOwner: "erd1owner"})
+	tc.Put("NFT-02", data.SourceToken{Type: "NonFungibleESDT", CurrentOwner: "erd1new"})
+
+	found, missing = tc.Get([]string{"TKN-01", "NFT-02", "SFT-03"})
+	require.Equal(t, map[string]data.SourceToken{
+		"TKN-01": {Type: "FungibleESDT", CurrentOwner: "erd1owner"},
+		"NFT-02": {Type: "NonFungibleESDT", CurrentOwner: "erd1new"},
+	}, found)
+	require.Equal(t, []string{"SFT-03"}, missing)
+
+	prometheusMetrics := statusMetrics.GetMetricsForPrometheus()
+	require.Contains(t, prometheusMetrics, `token_cache_hits{shardID="#"} 2`)
+	require.Contains(t, prometheusMetrics, `token_cache_misses{shardID="#"} 3`)
+	require.Contains(t, prometheusMetrics, `token_cache_size{shardID="#"} 2`)
+}
+
+func TestTokenCache_ShouldEvictLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	tc, _ := NewTokenCache(ArgsTokenCache{StatusMetrics: metrics.NewStatusMetrics(), Capacity: 2})
+	tc.Put("TKN-01", data.SourceToken{Type: "FungibleESDT"})
+	tc.Put("TKN-02", data.SourceToken{Type: "FungibleESDT"})
+
+	// TKN-01 becomes the most recently used token, so TKN-02 is evicted
+	_, missing := tc.Get([]string{"TKN-01"})
+	require.Empty(t, missing)
+	tc.Put("TKN-03", data.SourceToken{Type: "FungibleESDT"})
+
+	found, missing := tc.Get([]string{"TKN-01", "TKN-02", "TKN-03"})
+	require.Len(t, found, 2)
+	require.Equal(t, []string{"TKN-02"}, missing)
+}
+
+func TestTokenCache_Purge(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	tc, _ := NewTokenCache(ArgsTokenCache{StatusMetrics: statusMetrics, Capacity: 10})
+	tc.Put("TKN-01", data.SourceToken{Type: "FungibleESDT"})
+	tc.Purge()
+
+	found, missing := tc.Get([]string{"TKN-01"})
+	require.Empty(t, found)
+	require.Equal(t, []string{"TKN-01"}, missing)
+	require.Contains(t, statusMetrics.GetMetricsForPrometheus(), `token_cache_size{shardID="#"} 0`)
+}
+
+func TestDisabledTokenCache(t *testing.T) {
+	t.Parallel()
+
+	dtc := NewDisabledTokenCache()
+	require.False(t, dtc.IsInterfaceNil())
+
+	dtc.Put("TKN-01", data.SourceToken{Type: "FungibleESDT"})
+	found, missing := dtc.Get([]string{"TKN-01"})
+	require.Empty(t, found)
+	require.Equal(t, []string{"TKN-01"}, missing)
+}
@@ -12,7 +12,7 @@ import (
 	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 )
 
-func (ei *elasticProcessor) indexTokens(tokensData []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, shardID uint32) error {
+func (ei *elasticProcessor) indexTokens(ctx context.Context, tokensData []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, shardID uint32, nonce uint64) error {
 	err := ei.prepareAndAddSerializedDataForTokens(tokensData, updateNFTData, buffSlice, elasticIndexer.ESDTsIndex)
 	if err != nil {
 		return err
@@ -22,12 +22,12 @@ func (ei *elasticProcessor) indexTokens(tokensData []*data.TokenInfo, updateNFTD
 		return err
 	}
 
-	err = ei.addTokenType(tokensData, elasticIndexer.AccountsESDTIndex, shardID)
+	err = ei.addTokenType(ctx, tokensData, elasticIndexer.AccountsESDTIndex, shardID, nonce)
 	if err != nil {
 		return err
 	}
 
-	return ei.addTokenType(tokensData, elasticIndexer.TokensIndex, shardID)
+	return ei.addTokenType(ctx, tokensData, elasticIndexer.TokensIndex, shardID, nonce)
 }
 
 func (ei *elasticProcessor) prepareAndAddSerializedDataForTokens(tokensData []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error {
@@ -38,7 +38,7 @@ func (ei *elasticProcessor) prepareAndAddSerializedDataForTokens(tokensData []*d
 	return ei.logsAndEventsProc.SerializeTokens(tokensData, updateNFTData, buffSlice, index)
 }
 
-func (ei *elasticProcessor) addTokenType(tokensData []*data.TokenInfo, index string, shardID uint32) error {
+func (ei *elasticProcessor) addTokenType(ctx context.Context, tokensData []*data.TokenInfo, index string, shardID uint32, nonce uint64) error {
 	if len(tokensData) == 0 {
 		return nil
 	}
@@ -64,23 +64,23 @@ func (ei *elasticProcessor) addTokenType(tokensData []*data.TokenInfo, index str
 				ids = append(ids, res.ID)
 			}
 
-			buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+			buffSlice := data.NewBufferSlice(ei.pressureMonitor.GetBulkRequestMaxSize())
 			err = ei.accountsProc.SerializeTypeForProvidedIDs(ids, td.Type, buffSlice, index)
 			if err != nil {
 				return err
 			}
 
-			return ei.doBulkRequests(index, buffSlice.Buffers(), shardID)
+			return ei.doBulkRequests(ctx, index, buffSlice.Buffers(), shardID, nonce)
 		}
 
-		ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+		ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
 		query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"token": {"query": "%s","operator": "AND"}}}],"must_not":[{"exists": {"field": "type"}}]}}}`, td.Token)
 		resultsCount, err := ei.elasticClient.DoCountRequest(ctxWithValue, index, []byte(query))
 		if err != nil || resultsCount == 0 {
 			return err
 		}
 
-		ctxWithValue = context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
+		ctxWithValue = context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
 		err = ei.elasticClient.DoScrollRequest(ctxWithValue, index, []byte(query), false, handlerFunc)
 		if err != nil {
 			return err
@@ -0,0 +1,37 @@
+package factory
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/analytics"
+)
+
+// ArgsClickHouse holds the settings of the ClickHouse server that receives, next to the cluster, the transactions, the
+// smart contract results, the receipts and the events of every block
+type ArgsClickHouse struct {
+	Enabled        bool
+	URL            string
+	Database       string
+	UserName       string
+	Password       string
+	MaxBatchSize   int
+	RequestTimeout time.Duration
+}
+
+// createAnalyticsSink will create the ClickHouse sink. A nil sink is returned when it is disabled, so the data is saved
+// in the cluster only
+func createAnalyticsSink(args ArgsIndexerFactory) (elasticproc.AnalyticsSink, error) {
+	if !args.ClickHouse.Enabled {
+		return nil, nil
+	}
+
+	return analytics.NewClickHouseSink(analytics.ArgsClickHouseSink{
+		URL:            args.ClickHouse.URL,
+		Database:       args.ClickHouse.Database,
+		UserName:       args.ClickHouse.UserName,
+		Password:       args.ClickHouse.Password,
+		MaxBatchSize:   args.ClickHouse.MaxBatchSize,
+		RequestTimeout: args.ClickHouse.RequestTimeout,
+	})
+}
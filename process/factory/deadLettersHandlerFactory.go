@@ -0,0 +1,28 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/pressure"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/deadletters"
+)
+
+// CreateDeadLettersHandler will create the component that lists and re-submits the documents rejected by
+// Elasticsearch. A disabled dead letters handler is returned when the dead letters are disabled
+func CreateDeadLettersHandler(args ArgsIndexerFactory) (core.DeadLettersHandler, error) {
+	if !args.DeadLettersEnabled {
+		return deadletters.NewDisabledDeadLettersHandler(), nil
+	}
+	if check.IfNil(args.PressureMonitor) {
+		args.PressureMonitor = pressure.NewDisabledPressureMonitor(args.BulkRequestMaxSize)
+	}
+
+	databaseClient, _, err := createElasticClient(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return deadletters.NewDeadLettersHandler(deadletters.ArgsDeadLettersHandler{
+		DBClient: databaseClient,
+	})
+}
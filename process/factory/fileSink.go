@@ -0,0 +1,49 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-es-indexer-go/client/filesink"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+)
+
+// ArgsFileSink holds the settings of the NDJSON files that receive, instead of the cluster, the requests of every block
+type ArgsFileSink struct {
+	Enabled            bool
+	Directory          string
+	MaxFileSizeInBytes int64
+	// ReadFromCluster makes the reads go to the configured cluster. Without it, the reads return no documents
+	ReadFromCluster bool
+}
+
+func checkFileSinkParams(arguments ArgsIndexerFactory) error {
+	if len(arguments.MirrorClusters) > 0 {
+		return dataindexer.ErrFileSinkWithMirrorClusters
+	}
+	if arguments.FileSink.ReadFromCluster {
+		return checkClusterParams(arguments)
+	}
+
+	return nil
+}
+
+// createFileSinkClient will create the client that writes the requests in NDJSON files. The cluster, when used, only
+// serves the reads
+func createFileSinkClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHandler, error) {
+	var readOnlyClient filesink.ReadOnlyClientHandler
+	if args.FileSink.ReadFromCluster {
+		clusterClient, err := createClusterClient(args)
+		if err != nil {
+			return nil, err
+		}
+		readOnlyClient = clusterClient
+	}
+
+	log.Info("the requests are written in files instead of being sent to the cluster",
+		"directory", args.FileSink.Directory, "read from cluster", args.FileSink.ReadFromCluster)
+
+	return filesink.NewFileSinkClient(filesink.ArgsFileSinkClient{
+		Directory:          args.FileSink.Directory,
+		MaxFileSizeInBytes: args.FileSink.MaxFileSizeInBytes,
+		ReadOnlyClient:     readOnlyClient,
+	})
+}
@@ -7,34 +7,63 @@ import (
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
+	elasticsearch8 "github.com/elastic/go-elasticsearch/v8"
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/hashing"
 	"github.com/multiversx/mx-chain-core-go/marshal"
 	"github.com/multiversx/mx-chain-es-indexer-go/client"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/fanout"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/pressure"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/transport"
 	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tokencache"
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
 var log = logger.GetOrCreate("indexer/factory")
 
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// retryOnStatus holds the response status codes for which a request is sent again. A node that is overloaded or cannot
+// be reached through a proxy answers with a gateway status, so the request is retried on the next node
+var retryOnStatus = []int{
+	http.StatusConflict,
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
 // ArgsIndexerFactory holds all dependencies required by the data indexer factory in order to create
 // new instances
 type ArgsIndexerFactory struct {
 	Enabled                  bool
 	UseKibana                bool
+	Backend                  string
 	ImportDB                 bool
+	SniffOnStart             bool
+	DeadLettersEnabled       bool
+	CompressRequests         bool
 	Denomination             int
 	BulkRequestMaxSize       int
-	Url                      string
+	NumBulkRequestWorkers    int
+	SearchPageSize           int
+	BulkItemsMaxRetries      int
+	TokenCacheCapacity       int
+	Urls                     []string
 	UserName                 string
 	Password                 string
+	APIKey                   string
+	ServiceToken             string
 	TemplatesPath            string
 	Version                  string
 	EnabledIndexes           []string
@@ -44,29 +73,67 @@ type ArgsIndexerFactory struct {
 	AddressPubkeyConverter   core.PubkeyConverter
 	ValidatorPubkeyConverter core.PubkeyConverter
 	StatusMetrics            indexerCore.StatusMetricsHandler
+	GapsTracker              dataindexer.GapsTrackerHandler
+	PressureMonitor          dataindexer.PressureMonitorHandler
+	ShutdownTimeout          time.Duration
+	BulkItemsRetryBackOff    time.Duration
+	SniffInterval            time.Duration
+	SearchKeepAlive          time.Duration
+	RequestTimeouts          client.RequestTimeouts
+	TLS                      transport.ArgsHTTPTransport
+	MirrorClusters           []ArgsMirrorCluster
+	PostgreSQL               ArgsPostgreSQL
+	ClickHouse               ArgsClickHouse
+	FileSink                 ArgsFileSink
 }
 
 // NewIndexer will create a new instance of Indexer
 func NewIndexer(args ArgsIndexerFactory) (dataindexer.Indexer, error) {
-	err := checkDataIndexerParams(args)
+	if check.IfNil(args.HeaderMarshaller) {
+		return nil, fmt.Errorf("%w: header marshaller", dataindexer.ErrNilMarshalizer)
+	}
+
+	elasticProcessor, err := NewElasticProcessor(args)
 	if err != nil {
 		return nil, err
 	}
 
-	elasticProcessor, err := createElasticProcessor(args)
+	return NewDataIndexer(args.HeaderMarshaller, elasticProcessor, args.GapsTracker, args.ShutdownTimeout)
+}
+
+// NewElasticProcessor will create a new instance of ElasticProcessor that can be shared by multiple data indexers
+func NewElasticProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcessor, error) {
+	err := checkDataIndexerParams(args)
 	if err != nil {
 		return nil, err
 	}
+	if args.PostgreSQL.Enabled {
+		return createSQLProcessor(args)
+	}
+
+	return createElasticProcessor(args)
+}
 
-	blockContainer, err := createBlockCreatorsContainer()
+// NewDataIndexer will create a new instance of Indexer that decodes the headers with the provided marshaller and
+// saves the data with the provided elastic processor. On close, the in-flight operations have the provided shutdown
+// timeout to finish
+func NewDataIndexer(
+	headerMarshaller marshal.Marshalizer,
+	elasticProcessor dataindexer.ElasticProcessor,
+	gapsTracker dataindexer.GapsTrackerHandler,
+	shutdownTimeout time.Duration,
+) (dataindexer.Indexer, error) {
+	blockContainer, err := CreateBlockCreatorsContainer()
 	if err != nil {
 		return nil, err
 	}
 
 	arguments := dataindexer.ArgDataIndexer{
-		HeaderMarshaller: args.HeaderMarshaller,
+		HeaderMarshaller: headerMarshaller,
 		ElasticProcessor: elasticProcessor,
 		BlockContainer:   blockContainer,
+		GapsTracker:      gapsTracker,
+		ShutdownTimeout:  shutdownTimeout,
 	}
 
 	return dataindexer.NewDataIndexer(arguments)
@@ -80,7 +147,21 @@ func retryBackOff(attempt int) time.Duration {
 }
 
 func createElasticProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcessor, error) {
-	databaseClient, err := createElasticClient(args)
+	if check.IfNil(args.PressureMonitor) {
+		args.PressureMonitor = pressure.NewDisabledPressureMonitor(args.BulkRequestMaxSize)
+	}
+
+	databaseClient, backend, err := createElasticClient(args)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenCache, err := createTokenCache(args)
+	if err != nil {
+		return nil, err
+	}
+
+	analyticsSink, err := createAnalyticsSink(args)
 	if err != nil {
 		return nil, err
 	}
@@ -91,38 +172,239 @@ func createElasticProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcess
 		AddressPubkeyConverter:   args.AddressPubkeyConverter,
 		ValidatorPubkeyConverter: args.ValidatorPubkeyConverter,
 		UseKibana:                args.UseKibana,
+		Backend:                  backend,
 		DBClient:                 databaseClient,
 		Denomination:             args.Denomination,
 		EnabledIndexes:           args.EnabledIndexes,
 		BulkRequestMaxSize:       args.BulkRequestMaxSize,
+		NumBulkRequestWorkers:    args.NumBulkRequestWorkers,
 		ImportDB:                 args.ImportDB,
+		DeadLettersEnabled:       args.DeadLettersEnabled,
 		Version:                  args.Version,
+		PressureMonitor:          args.PressureMonitor,
+		TokenCache:               tokenCache,
+		AnalyticsSink:            analyticsSink,
 	}
 
 	return factory.CreateElasticProcessor(argsElasticProcFac)
 }
 
-func createElasticClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHandler, error) {
-	argsEsClient := elasticsearch.Config{
-		Addresses:     []string{args.Url},
-		Username:      args.UserName,
-		Password:      args.Password,
-		Logger:        &logging.CustomLogger{},
-		RetryOnStatus: []int{http.StatusConflict},
-		RetryBackoff:  retryBackOff,
+// createTokenCache will create the cache of the token types and owners. A nil cache is returned when the capacity is
+// 0, so the elastic processor fetches them from the tokens index every time
+func createTokenCache(args ArgsIndexerFactory) (elasticproc.TokenCache, error) {
+	if args.TokenCacheCapacity == 0 {
+		return nil, nil
 	}
 
-	if check.IfNil(args.StatusMetrics) {
-		return client.NewElasticClient(argsEsClient)
+	return tokencache.NewTokenCache(tokencache.ArgsTokenCache{
+		StatusMetrics: args.StatusMetrics,
+		Capacity:      args.TokenCacheCapacity,
+	})
+}
+
+// backendDetector defines what a client that detects the distribution of its cluster should be able to do
+type backendDetector interface {
+	GetBackend() (string, error)
+}
+
+// createElasticClient will create the client of the cluster. When mirror clusters are configured, the returned client
+// also sends every write to the mirror clusters. When the file sink is enabled, the writes go to files instead. The
+// backend the index templates are created for is returned as well
+func createElasticClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHandler, string, error) {
+	if args.FileSink.Enabled {
+		fileSinkClient, err := createFileSinkClient(args)
+		if err != nil {
+			return nil, "", err
+		}
+
+		backend, err := getClusterBackend(args.Backend, fileSinkClient)
+		return fileSinkClient, backend, err
+	}
+
+	primaryClient, err := createClusterClient(args)
+	if err != nil {
+		return nil, "", err
+	}
+	backend, err := getClusterBackend(args.Backend, primaryClient)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(args.MirrorClusters) == 0 {
+		return primaryClient, backend, nil
+	}
+
+	mirrors := make([]fanout.ArgsMirror, 0, len(args.MirrorClusters))
+	for _, mirrorCluster := range args.MirrorClusters {
+		mirrorClient, errCreate := createClusterClient(mirrorCluster.clientArgs(args))
+		if errCreate != nil {
+			return nil, "", fmt.Errorf("%w for the %s mirror cluster", errCreate, mirrorCluster.Name)
+		}
+
+		// the same index templates are created on all the clusters
+		mirrorBackend, errCreate := getClusterBackend(mirrorCluster.Backend, mirrorClient)
+		if errCreate != nil {
+			return nil, "", fmt.Errorf("%w for the %s mirror cluster", errCreate, mirrorCluster.Name)
+		}
+		if mirrorBackend != backend {
+			return nil, "", fmt.Errorf("%w: the %s mirror cluster runs %s, while the main cluster runs %s",
+				dataindexer.ErrIncompatibleMirrorBackend, mirrorCluster.Name, mirrorBackend, backend)
+		}
+
+		mirrors = append(mirrors, fanout.ArgsMirror{
+			Name:           mirrorCluster.Name,
+			Policy:         fanout.Policy(mirrorCluster.Policy),
+			AsyncQueueSize: mirrorCluster.AsyncQueueSize,
+			Client:         mirrorClient,
+		})
+	}
+
+	fanOutClient, err := fanout.NewFanOutClient(fanout.ArgsFanOutClient{
+		Primary:         primaryClient,
+		Mirrors:         mirrors,
+		StatusMetrics:   args.StatusMetrics,
+		ShutdownTimeout: args.ShutdownTimeout,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return fanOutClient, backend, nil
+}
+
+// getClusterBackend returns the configured backend or, when the backend is not configured, the one detected on the
+// cluster. The clients that cannot detect it, e.g. the file sink, use the Elasticsearch backend
+func getClusterBackend(backend string, databaseClient elasticproc.DatabaseClientHandler) (string, error) {
+	if !isDetectedBackend(backend) {
+		return backend, nil
+	}
+
+	detector, ok := databaseClient.(backendDetector)
+	if !ok {
+		return dataindexer.ElasticsearchBackend, nil
+	}
+
+	return detector.GetBackend()
+}
+
+func isDetectedBackend(backend string) bool {
+	return backend == "" || backend == dataindexer.AutoBackend
+}
+
+func createClusterClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHandler, error) {
+	roundTripper, err := createRoundTripper(args)
+	if err != nil {
+		return nil, err
 	}
 
-	transportMetrics, err := transport.NewMetricsTransport(args.StatusMetrics)
+	if args.Backend == dataindexer.Elasticsearch8Backend {
+		return createClusterClient8(args, roundTripper)
+	}
+
+	return client.NewElasticClient(client.ArgsElasticClient{
+		Config: elasticsearch.Config{
+			Addresses:             args.Urls,
+			Username:              args.UserName,
+			Password:              args.Password,
+			APIKey:                args.APIKey,
+			Header:                createAuthHeader(args.ServiceToken),
+			Transport:             roundTripper,
+			Logger:                &logging.CustomLogger{},
+			RetryOnStatus:         retryOnStatus,
+			RetryBackoff:          retryBackOff,
+			DiscoverNodesOnStart:  args.SniffOnStart,
+			DiscoverNodesInterval: args.SniffInterval,
+		},
+		BulkItemsMaxRetries:   args.BulkItemsMaxRetries,
+		BulkItemsRetryBackOff: args.BulkItemsRetryBackOff,
+		CompressRequests:      args.CompressRequests,
+		SearchPageSize:        args.SearchPageSize,
+		SearchKeepAlive:       args.SearchKeepAlive,
+		RequestTimeouts:       args.RequestTimeouts,
+		Backend:               args.Backend,
+	})
+}
+
+// createClusterClient8 will create the client of an Elasticsearch 8.x cluster. The security of these clusters is
+// enabled by default, so the requests are authenticated and the TLS settings of the transport are used
+func createClusterClient8(args ArgsIndexerFactory, roundTripper http.RoundTripper) (elasticproc.DatabaseClientHandler, error) {
+	return client.NewElasticClient8(client.ArgsElasticClient8{
+		Config: elasticsearch8.Config{
+			Addresses:             args.Urls,
+			Username:              args.UserName,
+			Password:              args.Password,
+			APIKey:                args.APIKey,
+			ServiceToken:          args.ServiceToken,
+			Transport:             roundTripper,
+			Logger:                &logging.CustomLogger{},
+			RetryOnStatus:         retryOnStatus,
+			RetryBackoff:          retryBackOff,
+			DiscoverNodesOnStart:  args.SniffOnStart,
+			DiscoverNodesInterval: args.SniffInterval,
+		},
+		BulkItemsMaxRetries:   args.BulkItemsMaxRetries,
+		BulkItemsRetryBackOff: args.BulkItemsRetryBackOff,
+		CompressRequests:      args.CompressRequests,
+		SearchPageSize:        args.SearchPageSize,
+		SearchKeepAlive:       args.SearchKeepAlive,
+		RequestTimeouts:       args.RequestTimeouts,
+	})
+}
+
+// createRoundTripper returns the transport of the requests sent to the cluster. The requests are added to the indexing
+// metrics when the status metrics are provided
+func createRoundTripper(args ArgsIndexerFactory) (http.RoundTripper, error) {
+	httpTransport, err := transport.NewHTTPTransport(args.TLS)
 	if err != nil {
 		return nil, err
 	}
-	argsEsClient.Transport = transportMetrics
+	if check.IfNil(args.StatusMetrics) {
+		return httpTransport, nil
+	}
+
+	return transport.NewMetricsTransport(args.StatusMetrics, args.PressureMonitor, httpTransport)
+}
+
+// createAuthHeader returns the header that authenticates every request with the service token, if one is provided
+func createAuthHeader(serviceToken string) http.Header {
+	if serviceToken == "" {
+		return nil
+	}
 
-	return client.NewElasticClient(argsEsClient)
+	header := make(http.Header)
+	header.Set(authorizationHeader, bearerPrefix+serviceToken)
+
+	return header
+}
+
+func checkClusterParams(arguments ArgsIndexerFactory) error {
+	if len(arguments.Urls) == 0 {
+		return dataindexer.ErrNilUrl
+	}
+	for _, url := range arguments.Urls {
+		if url == "" {
+			return dataindexer.ErrNilUrl
+		}
+	}
+
+	return checkAuthMethods(arguments)
+}
+
+func checkAuthMethods(arguments ArgsIndexerFactory) error {
+	numAuthMethods := 0
+	if arguments.UserName != "" || arguments.Password != "" {
+		numAuthMethods++
+	}
+	if arguments.APIKey != "" {
+		numAuthMethods++
+	}
+	if arguments.ServiceToken != "" {
+		numAuthMethods++
+	}
+	if numAuthMethods > 1 {
+		return dataindexer.ErrMultipleAuthMethods
+	}
+
+	return nil
 }
 
 func checkDataIndexerParams(arguments ArgsIndexerFactory) error {
@@ -132,8 +414,9 @@ func checkDataIndexerParams(arguments ArgsIndexerFactory) error {
 	if check.IfNil(arguments.ValidatorPubkeyConverter) {
 		return fmt.Errorf("%w when setting ValidatorPubkeyConverter in indexer", dataindexer.ErrNilPubkeyConverter)
 	}
-	if arguments.Url == "" {
-		return dataindexer.ErrNilUrl
+	err := checkDatabaseParams(arguments)
+	if err != nil {
+		return err
 	}
 	if check.IfNil(arguments.Marshalizer) {
 		return dataindexer.ErrNilMarshalizer
@@ -141,14 +424,51 @@ func checkDataIndexerParams(arguments ArgsIndexerFactory) error {
 	if check.IfNil(arguments.Hasher) {
 		return dataindexer.ErrNilHasher
 	}
-	if check.IfNil(arguments.HeaderMarshaller) {
-		return fmt.Errorf("%w: header marshaller", dataindexer.ErrNilMarshalizer)
+
+	return nil
+}
+
+// checkDatabaseParams checks the settings of the database the data is saved in, which is either the PostgreSQL
+// database, when enabled, or the cluster together with its mirror clusters
+func checkDatabaseParams(arguments ArgsIndexerFactory) error {
+	if arguments.PostgreSQL.Enabled {
+		return checkPostgreSQLParams(arguments)
+	}
+	if arguments.FileSink.Enabled {
+		return checkFileSinkParams(arguments)
+	}
+
+	err := checkClusterParams(arguments)
+	if err != nil {
+		return err
+	}
+	for _, mirrorCluster := range arguments.MirrorClusters {
+		err = checkClusterParams(mirrorCluster.clientArgs(arguments))
+		if err != nil {
+			return fmt.Errorf("%w for the %s mirror cluster", err, mirrorCluster.Name)
+		}
+		// the same templates are created on all the clusters, so they have to run the same backend. The detected
+		// backends are checked once the clients are created
+		if !canRunTheSameBackend(arguments.Backend, mirrorCluster.Backend) {
+			return fmt.Errorf("%w for the %s mirror cluster", dataindexer.ErrIncompatibleMirrorBackend, mirrorCluster.Name)
+		}
 	}
 
 	return nil
 }
 
-func createBlockCreatorsContainer() (dataindexer.BlockContainerHandler, error) {
+// canRunTheSameBackend returns false if the provided backends are known to be different. A detected backend is never
+// the Elasticsearch 8 one, which has its own client
+func canRunTheSameBackend(backend string, mirrorBackend string) bool {
+	if isDetectedBackend(backend) || isDetectedBackend(mirrorBackend) {
+		return backend != dataindexer.Elasticsearch8Backend && mirrorBackend != dataindexer.Elasticsearch8Backend
+	}
+
+	return backend == mirrorBackend
+}
+
+// CreateBlockCreatorsContainer will create a container with the creators for all the supported header types
+func CreateBlockCreatorsContainer() (dataindexer.BlockContainerHandler, error) {
 	container := block.NewEmptyBlockCreatorsContainer()
 	err := container.Add(core.ShardHeaderV1, block.NewEmptyHeaderCreator())
 	if err != nil {
@@ -4,19 +4,56 @@ import (
 	errorsGo "errors"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
 	"github.com/multiversx/mx-chain-es-indexer-go/mock"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/stretchr/testify/require"
 )
 
+// newClusterServer returns a server that answers the root endpoint as an Elasticsearch 7 node and all the other
+// requests with an empty response
+func newClusterServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version":{"number":"7.16.2"}}`))
+		}
+	}))
+}
+
+// newElasticsearch8ClusterServer returns a server that answers the root endpoint as an Elasticsearch 8 node and all the
+// other requests with an empty response
+func newElasticsearch8ClusterServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version":{"number":"8.6.0"}}`))
+		}
+	}))
+}
+
+// newOpenSearchClusterServer returns a server that answers the root endpoint as an OpenSearch 2 node and all the other
+// requests with an empty response. The paths of the requests are sent on the provided channel, if not nil
+func newOpenSearchClusterServer(requests chan<- string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			requests <- r.Method + " " + r.URL.Path
+		}
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version":{"distribution":"opensearch","number":"2.11.0"}}`))
+		}
+	}))
+}
+
 func createMockIndexerFactoryArgs() ArgsIndexerFactory {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts := newClusterServer()
 
 	return ArgsIndexerFactory{
 		Enabled:                  true,
-		Url:                      ts.URL,
+		Urls:                     []string{ts.URL},
 		UserName:                 "",
 		Password:                 "",
 		Marshalizer:              &mock.MarshalizerMock{},
@@ -26,6 +63,7 @@ func createMockIndexerFactoryArgs() ArgsIndexerFactory {
 		ValidatorPubkeyConverter: &mock.PubkeyConverterMock{},
 		TemplatesPath:            "../testdata",
 		EnabledIndexes:           []string{"blocks", "transactions", "miniblocks", "validators", "round", "accounts", "rating"},
+		GapsTracker:              &mock.GapsTrackerStub{},
 	}
 }
 
@@ -80,11 +118,171 @@ func TestNewIndexerFactory(t *testing.T) {
 			},
 			exError: dataindexer.ErrNilMarshalizer,
 		},
+		{
+			name: "NilGapsTracker",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.GapsTracker = nil
+				return args
+			},
+			exError: dataindexer.ErrNilGapsTracker,
+		},
 		{
 			name: "EmptyUrl",
 			argsFunc: func() ArgsIndexerFactory {
 				args := createMockIndexerFactoryArgs()
-				args.Url = ""
+				args.Urls = nil
+				return args
+			},
+			exError: dataindexer.ErrNilUrl,
+		},
+		{
+			name: "EmptyUrlInList",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.Urls = []string{"http://localhost:9200", ""}
+				return args
+			},
+			exError: dataindexer.ErrNilUrl,
+		},
+		{
+			name: "MultipleAuthMethods",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.UserName = "user"
+				args.Password = "pass"
+				args.ServiceToken = "token"
+				return args
+			},
+			exError: dataindexer.ErrMultipleAuthMethods,
+		},
+		{
+			name: "EmptyMirrorClusterUrl",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.MirrorClusters = []ArgsMirrorCluster{{Name: "standby", Policy: "async"}}
+				return args
+			},
+			exError: dataindexer.ErrNilUrl,
+		},
+		{
+			name: "InvalidBackend",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.Backend = "solr"
+				return args
+			},
+			exError: dataindexer.ErrInvalidBackend,
+		},
+		{
+			name: "BackendMismatch",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.Backend = dataindexer.OpenSearchBackend
+				return args
+			},
+			exError: dataindexer.ErrBackendMismatch,
+		},
+		{
+			name: "IncompatibleMirrorBackend",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.MirrorClusters = []ArgsMirrorCluster{{
+					Name:    "standby",
+					Policy:  "async",
+					Backend: dataindexer.Elasticsearch8Backend,
+					Urls:    []string{"http://localhost:9201"},
+				}}
+				return args
+			},
+			exError: dataindexer.ErrIncompatibleMirrorBackend,
+		},
+		{
+			name: "DetectedIncompatibleMirrorBackend",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.MirrorClusters = []ArgsMirrorCluster{{
+					Name:   "standby",
+					Policy: "async",
+					Urls:   []string{newOpenSearchClusterServer(nil).URL},
+				}}
+				return args
+			},
+			exError: dataindexer.ErrIncompatibleMirrorBackend,
+		},
+		{
+			name: "Elasticsearch8BackendOnElasticsearch7",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.Backend = dataindexer.Elasticsearch8Backend
+				return args
+			},
+			exError: dataindexer.ErrUnsupportedClusterVersion,
+		},
+		{
+			name: "EmptyPostgreSQLDSN",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.PostgreSQL = ArgsPostgreSQL{Enabled: true}
+				return args
+			},
+			exError: dataindexer.ErrEmptyPostgreSQLDSN,
+		},
+		{
+			name: "PostgreSQLWithMirrorClusters",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.PostgreSQL = ArgsPostgreSQL{Enabled: true, DSN: "postgres://localhost:5432/indexer"}
+				args.MirrorClusters = []ArgsMirrorCluster{{Name: "standby", Policy: "async", Urls: []string{"http://localhost:9201"}}}
+				return args
+			},
+			exError: dataindexer.ErrPostgreSQLWithMirrorClusters,
+		},
+		{
+			name: "PostgreSQLWithClickHouse",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.PostgreSQL = ArgsPostgreSQL{Enabled: true, DSN: "postgres://localhost:5432/indexer"}
+				args.ClickHouse = ArgsClickHouse{Enabled: true, URL: "http://localhost:8123", MaxBatchSize: 1}
+				return args
+			},
+			exError: dataindexer.ErrPostgreSQLWithClickHouse,
+		},
+		{
+			name: "EmptyClickHouseURL",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.ClickHouse = ArgsClickHouse{Enabled: true, MaxBatchSize: 1}
+				return args
+			},
+			exError: dataindexer.ErrEmptyClickHouseURL,
+		},
+		{
+			name: "PostgreSQLWithFileSink",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.PostgreSQL = ArgsPostgreSQL{Enabled: true, DSN: "postgres://localhost:5432/indexer"}
+				args.FileSink = ArgsFileSink{Enabled: true, Directory: "dry-run", MaxFileSizeInBytes: 1024}
+				return args
+			},
+			exError: dataindexer.ErrPostgreSQLWithFileSink,
+		},
+		{
+			name: "FileSinkWithMirrorClusters",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.FileSink = ArgsFileSink{Enabled: true, Directory: "dry-run", MaxFileSizeInBytes: 1024}
+				args.MirrorClusters = []ArgsMirrorCluster{{Name: "standby", Policy: "async", Urls: []string{"http://localhost:9201"}}}
+				return args
+			},
+			exError: dataindexer.ErrFileSinkWithMirrorClusters,
+		},
+		{
+			name: "FileSinkReadingFromClusterWithoutUrls",
+			argsFunc: func() ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.Urls = nil
+				args.FileSink = ArgsFileSink{Enabled: true, Directory: "dry-run", MaxFileSizeInBytes: 1024, ReadFromCluster: true}
 				return args
 			},
 			exError: dataindexer.ErrNilUrl,
@@ -107,9 +305,25 @@ func TestNewIndexerFactory(t *testing.T) {
 }
 
 func TestIndexerFactoryCreate_ElasticIndexer(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts := newClusterServer()
+	args := createMockIndexerFactoryArgs()
+	args.Urls = []string{ts.URL}
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_Elasticsearch8Indexer(t *testing.T) {
+	ts := newElasticsearch8ClusterServer()
+	defer ts.Close()
+
 	args := createMockIndexerFactoryArgs()
-	args.Url = ts.URL
+	args.Backend = dataindexer.Elasticsearch8Backend
+	args.Urls = []string{ts.URL}
+	args.StatusMetrics = metrics.NewStatusMetrics()
 
 	elasticIndexer, err := NewIndexer(args)
 	require.NoError(t, err)
@@ -117,3 +331,97 @@ func TestIndexerFactoryCreate_ElasticIndexer(t *testing.T) {
 	err = elasticIndexer.Close()
 	require.NoError(t, err)
 }
+
+func TestIndexerFactoryCreate_DetectedOpenSearchShouldCreateTheComposableTemplates(t *testing.T) {
+	requests := make(chan string, 1000)
+	ts := newOpenSearchClusterServer(requests)
+	defer ts.Close()
+
+	args := createMockIndexerFactoryArgs()
+	args.Urls = []string{ts.URL}
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+
+	close(requests)
+	sentRequests := make([]string, 0, len(requests))
+	for request := range requests {
+		sentRequests = append(sentRequests, request)
+	}
+	require.Contains(t, sentRequests, "HEAD /_component_template/blocks-settings")
+	require.Contains(t, sentRequests, "HEAD /_index_template/blocks")
+	require.NotContains(t, sentRequests, "HEAD /_template/blocks")
+}
+
+func TestIndexerFactoryCreate_ElasticIndexerWithClickHouse(t *testing.T) {
+	numRequests := 0
+	clickHouseTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numRequests++
+	}))
+	defer clickHouseTs.Close()
+
+	args := createMockIndexerFactoryArgs()
+	args.ClickHouse = ArgsClickHouse{
+		Enabled:      true,
+		URL:          clickHouseTs.URL,
+		MaxBatchSize: 1000,
+	}
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+	require.Positive(t, numRequests)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_ElasticIndexerWithFileSink(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.Urls = nil
+	args.FileSink = ArgsFileSink{
+		Enabled:            true,
+		Directory:          t.TempDir(),
+		MaxFileSizeInBytes: 1024,
+	}
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+	require.DirExists(t, filepath.Join(args.FileSink.Directory, "templates"))
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_ElasticIndexerWithMirrorClusters(t *testing.T) {
+	mirrorTs := newClusterServer()
+	defer mirrorTs.Close()
+
+	args := createMockIndexerFactoryArgs()
+	args.StatusMetrics = metrics.NewStatusMetrics()
+	args.MirrorClusters = []ArgsMirrorCluster{{
+		Name:   "standby",
+		Policy: "best-effort",
+		Urls:   []string{mirrorTs.URL},
+	}}
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+
+	args.MirrorClusters[0].Policy = "sometimes"
+	_, err = NewIndexer(args)
+	require.Error(t, err)
+}
+
+func TestCreateAuthHeader(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, createAuthHeader(""))
+
+	header := createAuthHeader("token")
+	require.Equal(t, "Bearer token", header.Get("Authorization"))
+}
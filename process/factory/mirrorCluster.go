@@ -0,0 +1,50 @@
+package factory
+
+import (
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/client"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/transport"
+)
+
+// ArgsMirrorCluster holds the settings of a cluster that receives a copy of every write of the indexer
+type ArgsMirrorCluster struct {
+	Name                  string
+	Policy                string
+	AsyncQueueSize        int
+	Backend               string
+	Urls                  []string
+	UserName              string
+	Password              string
+	APIKey                string
+	ServiceToken          string
+	SniffOnStart          bool
+	CompressRequests      bool
+	BulkItemsMaxRetries   int
+	BulkItemsRetryBackOff time.Duration
+	SniffInterval         time.Duration
+	RequestTimeouts       client.RequestTimeouts
+	TLS                   transport.ArgsHTTPTransport
+}
+
+// clientArgs returns the arguments of the mirror cluster client, based on the ones of the primary cluster. The requests
+// sent to a mirror cluster are not added to the indexing metrics and do not count for the pressure of the primary cluster
+func (amc ArgsMirrorCluster) clientArgs(args ArgsIndexerFactory) ArgsIndexerFactory {
+	args.Urls = amc.Urls
+	args.Backend = amc.Backend
+	args.UserName = amc.UserName
+	args.Password = amc.Password
+	args.APIKey = amc.APIKey
+	args.ServiceToken = amc.ServiceToken
+	args.SniffOnStart = amc.SniffOnStart
+	args.CompressRequests = amc.CompressRequests
+	args.BulkItemsMaxRetries = amc.BulkItemsMaxRetries
+	args.BulkItemsRetryBackOff = amc.BulkItemsRetryBackOff
+	args.SniffInterval = amc.SniffInterval
+	args.TLS = amc.TLS
+	args.RequestTimeouts = amc.RequestTimeouts
+	args.StatusMetrics = nil
+	args.MirrorClusters = nil
+
+	return args
+}
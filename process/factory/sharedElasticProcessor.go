@@ -0,0 +1,73 @@
+package factory
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+// SharedElasticProcessor lets several data indexers use the same elastic processor. Every data indexer gets its own
+// handle of the elastic processor, and the elastic processor is closed only when the last handle is closed, so a data
+// indexer that is closed sooner does not close it while the other ones are still draining
+type SharedElasticProcessor struct {
+	mut            sync.Mutex
+	processor      dataindexer.ElasticProcessor
+	numOpenHandles int
+}
+
+type elasticProcessorHandle struct {
+	dataindexer.ElasticProcessor
+	shared    *SharedElasticProcessor
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewSharedElasticProcessor will create a new instance of SharedElasticProcessor
+func NewSharedElasticProcessor(processor dataindexer.ElasticProcessor) (*SharedElasticProcessor, error) {
+	if check.IfNil(processor) {
+		return nil, dataindexer.ErrNilElasticProcessor
+	}
+
+	return &SharedElasticProcessor{
+		processor: processor,
+	}, nil
+}
+
+// NewHandle returns a new handle of the shared elastic processor, to be used by a single data indexer
+func (sep *SharedElasticProcessor) NewHandle() dataindexer.ElasticProcessor {
+	sep.mut.Lock()
+	sep.numOpenHandles++
+	sep.mut.Unlock()
+
+	return &elasticProcessorHandle{
+		ElasticProcessor: sep.processor,
+		shared:           sep,
+	}
+}
+
+func (sep *SharedElasticProcessor) releaseHandle() error {
+	sep.mut.Lock()
+	defer sep.mut.Unlock()
+
+	sep.numOpenHandles--
+	if sep.numOpenHandles > 0 {
+		return nil
+	}
+
+	return sep.processor.Close()
+}
+
+// Close will release the handle. The elastic processor is closed together with its last handle
+func (eph *elasticProcessorHandle) Close() error {
+	eph.closeOnce.Do(func() {
+		eph.closeErr = eph.shared.releaseHandle()
+	})
+
+	return eph.closeErr
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (eph *elasticProcessorHandle) IsInterfaceNil() bool {
+	return eph == nil
+}
@@ -0,0 +1,45 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSharedElasticProcessor(t *testing.T) {
+	t.Parallel()
+
+	sharedProcessor, err := NewSharedElasticProcessor(nil)
+	require.Nil(t, sharedProcessor)
+	require.Equal(t, dataindexer.ErrNilElasticProcessor, err)
+
+	sharedProcessor, err = NewSharedElasticProcessor(&mock.ElasticProcessorStub{})
+	require.Nil(t, err)
+	require.NotNil(t, sharedProcessor)
+}
+
+func TestSharedElasticProcessor_ShouldCloseTheProcessorWithTheLastHandle(t *testing.T) {
+	t.Parallel()
+
+	numClosed := 0
+	sharedProcessor, _ := NewSharedElasticProcessor(&mock.ElasticProcessorStub{
+		CloseCalled: func() error {
+			numClosed++
+			return nil
+		},
+	})
+
+	firstHandle := sharedProcessor.NewHandle()
+	secondHandle := sharedProcessor.NewHandle()
+	require.False(t, firstHandle.IsInterfaceNil())
+
+	require.Nil(t, firstHandle.Close())
+	// closing the same handle twice should not release the other one
+	require.Nil(t, firstHandle.Close())
+	require.Equal(t, 0, numClosed)
+
+	require.Nil(t, secondHandle.Close())
+	require.Equal(t, 1, numClosed)
+}
@@ -0,0 +1,180 @@
+package gaps
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	// GapsMetricTopic is the identifier for the number of gaps detected metric
+	GapsMetricTopic = "indexing_gaps"
+	// MissingBlocksMetricTopic is the identifier for the number of missing blocks metric
+	MissingBlocksMetricTopic = "indexing_missing_blocks"
+	// LastIndexedNonceMetricTopic is the identifier for the last indexed nonce metric
+	LastIndexedNonceMetricTopic = "last_indexed_nonce"
+
+	// ReasonMissingNonces signals that one or more nonces were not indexed
+	ReasonMissingNonces = "missing nonces"
+	// ReasonPrevHashMismatch signals that the previous hash of a block does not match the hash of the last indexed block
+	ReasonPrevHashMismatch = "prev hash mismatch"
+
+	defaultMaxNumGaps = 1000
+)
+
+var log = logger.GetOrCreate("process/gaps")
+
+// ArgsGapsTracker holds all the components needed to create a new instance of gapsTracker
+type ArgsGapsTracker struct {
+	StatusMetrics core.StatusMetricsHandler
+	// MaxNumGaps is the maximum number of gaps that are kept in memory. When it is reached, the oldest gap is dropped
+	MaxNumGaps int
+}
+
+type checkpoint struct {
+	nonce uint64
+	hash  []byte
+}
+
+type shardStats struct {
+	numGaps          uint64
+	numMissingBlocks uint64
+}
+
+type gapsTracker struct {
+	mut           sync.RWMutex
+	statusMetrics core.StatusMetricsHandler
+	maxNumGaps    int
+	checkpoints   map[uint32]*checkpoint
+	stats         map[uint32]*shardStats
+	gaps          []*request.GapResponse
+}
+
+// NewGapsTracker will create a new instance of gapsTracker
+func NewGapsTracker(args ArgsGapsTracker) (*gapsTracker, error) {
+	if check.IfNil(args.StatusMetrics) {
+		return nil, core.ErrNilMetricsHandler
+	}
+
+	maxNumGaps := args.MaxNumGaps
+	if maxNumGaps <= 0 {
+		maxNumGaps = defaultMaxNumGaps
+	}
+
+	return &gapsTracker{
+		statusMetrics: args.StatusMetrics,
+		maxNumGaps:    maxNumGaps,
+		checkpoints:   make(map[uint32]*checkpoint),
+		stats:         make(map[uint32]*shardStats),
+		gaps:          make([]*request.GapResponse, 0),
+	}, nil
+}
+
+// HasCheckpoint returns true if the last indexed block of the provided shard is known
+func (gt *gapsTracker) HasCheckpoint(shardID uint32) bool {
+	gt.mut.RLock()
+	defer gt.mut.RUnlock()
+
+	_, found := gt.checkpoints[shardID]
+	return found
+}
+
+// SetCheckpoint will set the last indexed block of the provided shard, without checking for gaps
+func (gt *gapsTracker) SetCheckpoint(shardID uint32, nonce uint64, hash []byte) {
+	gt.mut.Lock()
+	defer gt.mut.Unlock()
+
+	gt.setCheckpointUnprotected(shardID, nonce, hash)
+}
+
+// ProcessBlock will check if the provided block continues the last indexed block of its shard and will record a gap
+// if it does not. The provided block becomes the last indexed block of the shard
+func (gt *gapsTracker) ProcessBlock(shardID uint32, nonce uint64, hash []byte, prevHash []byte) {
+	gt.mut.Lock()
+	defer gt.mut.Unlock()
+
+	defer gt.setCheckpointUnprotected(shardID, nonce, hash)
+
+	lastIndexed, found := gt.checkpoints[shardID]
+	if !found || nonce <= lastIndexed.nonce {
+		return
+	}
+
+	if nonce > lastIndexed.nonce+1 {
+		gt.addGapUnprotected(shardID, lastIndexed.nonce+1, nonce-1, ReasonMissingNonces)
+		return
+	}
+
+	if !bytes.Equal(prevHash, lastIndexed.hash) {
+		log.Warn("gapsTracker: prev hash mismatch",
+			"shardID", shardID,
+			"nonce", nonce,
+			"prevHash", hex.EncodeToString(prevHash),
+			"last indexed hash", hex.EncodeToString(lastIndexed.hash),
+		)
+		gt.addGapUnprotected(shardID, lastIndexed.nonce, lastIndexed.nonce, ReasonPrevHashMismatch)
+	}
+}
+
+func (gt *gapsTracker) setCheckpointUnprotected(shardID uint32, nonce uint64, hash []byte) {
+	gt.checkpoints[shardID] = &checkpoint{
+		nonce: nonce,
+		hash:  hash,
+	}
+	gt.statusMetrics.SetGauge(request.ExtendTopicWithShardID(LastIndexedNonceMetricTopic, shardID), nonce)
+}
+
+func (gt *gapsTracker) addGapUnprotected(shardID uint32, fromNonce uint64, toNonce uint64, reason string) {
+	log.Warn("gapsTracker: detected gap in the indexed blocks",
+		"shardID", shardID,
+		"from nonce", fromNonce,
+		"to nonce", toNonce,
+		"reason", reason,
+	)
+
+	gt.gaps = append(gt.gaps, &request.GapResponse{
+		ShardID:    shardID,
+		FromNonce:  fromNonce,
+		ToNonce:    toNonce,
+		Reason:     reason,
+		DetectedAt: time.Now().Unix(),
+	})
+	if len(gt.gaps) > gt.maxNumGaps {
+		gt.gaps = gt.gaps[len(gt.gaps)-gt.maxNumGaps:]
+	}
+
+	stats, found := gt.stats[shardID]
+	if !found {
+		stats = &shardStats{}
+		gt.stats[shardID] = stats
+	}
+	stats.numGaps++
+	if reason == ReasonMissingNonces {
+		stats.numMissingBlocks += toNonce - fromNonce + 1
+	}
+
+	gt.statusMetrics.SetGauge(request.ExtendTopicWithShardID(GapsMetricTopic, shardID), stats.numGaps)
+	gt.statusMetrics.SetGauge(request.ExtendTopicWithShardID(MissingBlocksMetricTopic, shardID), stats.numMissingBlocks)
+}
+
+// GetGaps returns the detected gaps, from the oldest to the newest
+func (gt *gapsTracker) GetGaps() []*request.GapResponse {
+	gt.mut.RLock()
+	defer gt.mut.RUnlock()
+
+	gaps := make([]*request.GapResponse, len(gt.gaps))
+	copy(gaps, gt.gaps)
+
+	return gaps
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (gt *gapsTracker) IsInterfaceNil() bool {
+	return gt == nil
+}
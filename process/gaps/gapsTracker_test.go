@@ -0,0 +1,134 @@
+package gaps
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockGapsTrackerArgs() ArgsGapsTracker {
+	return ArgsGapsTracker{
+		StatusMetrics: metrics.NewStatusMetrics(),
+	}
+}
+
+func TestNewGapsTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil status metrics should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockGapsTrackerArgs()
+		args.StatusMetrics = nil
+		gt, err := NewGapsTracker(args)
+		require.Nil(t, gt)
+		require.Equal(t, core.ErrNilMetricsHandler, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		gt, err := NewGapsTracker(createMockGapsTrackerArgs())
+		require.Nil(t, err)
+		require.False(t, gt.IsInterfaceNil())
+		require.Equal(t, defaultMaxNumGaps, gt.maxNumGaps)
+		require.Empty(t, gt.GetGaps())
+	})
+}
+
+func TestGapsTracker_ProcessBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("first block of a shard should not record a gap", func(t *testing.T) {
+		t.Parallel()
+
+		gt, _ := NewGapsTracker(createMockGapsTrackerArgs())
+		require.False(t, gt.HasCheckpoint(0))
+
+		gt.ProcessBlock(0, 10, []byte("h10"), []byte("h9"))
+		require.True(t, gt.HasCheckpoint(0))
+		require.False(t, gt.HasCheckpoint(1))
+		require.Empty(t, gt.GetGaps())
+	})
+
+	t.Run("consecutive blocks should not record a gap", func(t *testing.T) {
+		t.Parallel()
+
+		gt, _ := NewGapsTracker(createMockGapsTrackerArgs())
+		gt.SetCheckpoint(0, 10, []byte("h10"))
+
+		gt.ProcessBlock(0, 11, []byte("h11"), []byte("h10"))
+		gt.ProcessBlock(0, 12, []byte("h12"), []byte("h11"))
+		require.Empty(t, gt.GetGaps())
+	})
+
+	t.Run("missing nonces should record a gap", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockGapsTrackerArgs()
+		statusMetrics := metrics.NewStatusMetrics()
+		args.StatusMetrics = statusMetrics
+		gt, _ := NewGapsTracker(args)
+		gt.SetCheckpoint(1, 10, []byte("h10"))
+
+		gt.ProcessBlock(1, 14, []byte("h14"), []byte("h13"))
+
+		gaps := gt.GetGaps()
+		require.Len(t, gaps, 1)
+		require.Equal(t, uint32(1), gaps[0].ShardID)
+		require.Equal(t, uint64(11), gaps[0].FromNonce)
+		require.Equal(t, uint64(13), gaps[0].ToNonce)
+		require.Equal(t, ReasonMissingNonces, gaps[0].Reason)
+
+		prometheusMetrics := statusMetrics.GetMetricsForPrometheus()
+		require.Contains(t, prometheusMetrics, `indexing_gaps{shardID="1"} 1`)
+		require.Contains(t, prometheusMetrics, `indexing_missing_blocks{shardID="1"} 3`)
+		require.Contains(t, prometheusMetrics, `last_indexed_nonce{shardID="1"} 14`)
+	})
+
+	t.Run("prev hash mismatch should record a gap", func(t *testing.T) {
+		t.Parallel()
+
+		gt, _ := NewGapsTracker(createMockGapsTrackerArgs())
+		gt.SetCheckpoint(0, 10, []byte("h10"))
+
+		gt.ProcessBlock(0, 11, []byte("h11"), []byte("other h10"))
+
+		gaps := gt.GetGaps()
+		require.Len(t, gaps, 1)
+		require.Equal(t, uint64(10), gaps[0].FromNonce)
+		require.Equal(t, uint64(10), gaps[0].ToNonce)
+		require.Equal(t, ReasonPrevHashMismatch, gaps[0].Reason)
+	})
+
+	t.Run("re-indexed block should not record a gap", func(t *testing.T) {
+		t.Parallel()
+
+		gt, _ := NewGapsTracker(createMockGapsTrackerArgs())
+		gt.SetCheckpoint(0, 10, []byte("h10"))
+
+		gt.ProcessBlock(0, 10, []byte("h10"), []byte("h9"))
+		gt.ProcessBlock(0, 11, []byte("h11"), []byte("h10"))
+		require.Empty(t, gt.GetGaps())
+	})
+
+	t.Run("should keep only the newest gaps", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockGapsTrackerArgs()
+		args.MaxNumGaps = 2
+		gt, _ := NewGapsTracker(args)
+		gt.SetCheckpoint(0, 0, []byte("h0"))
+
+		gt.ProcessBlock(0, 2, []byte("h2"), []byte("h1"))
+		gt.ProcessBlock(0, 4, []byte("h4"), []byte("h3"))
+		gt.ProcessBlock(0, 6, []byte("h6"), []byte("h5"))
+
+		gaps := gt.GetGaps()
+		require.Len(t, gaps, 2)
+		require.Equal(t, uint64(3), gaps[0].FromNonce)
+		require.Equal(t, uint64(5), gaps[1].FromNonce)
+	})
+}
@@ -0,0 +1,449 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	// ProgressFileName is the name of the file, from the import directory, that holds the import progress
+	ProgressFileName = "import-progress.json"
+	// JsonFileExtension is the extension of the files that hold JSON marshalled data
+	JsonFileExtension = "json"
+	// ProtoFileExtension is the extension of the files that hold gogo protobuf marshalled data
+	ProtoFileExtension = "pb"
+
+	progressLogInterval = 100
+	filesPermissions    = 0644
+)
+
+var (
+	log = logger.GetOrCreate("process/importer")
+
+	// the files have to be named <shardID>-<nonce>-<topic>.<json|pb>
+	fileNameRegex = regexp.MustCompile(`^(\d+)-(\d+)-([A-Za-z]+)\.([A-Za-z]+)$`)
+
+	// topicsOrder holds the order in which the files for the same shard and nonce are imported
+	topicsOrder = map[string]int{
+		outport.TopicSettings:              0,
+		outport.TopicSaveBlock:             1,
+		outport.TopicSaveRoundsInfo:        2,
+		outport.TopicSaveValidatorsPubKeys: 3,
+		outport.TopicSaveValidatorsRating:  4,
+		outport.TopicSaveAccounts:          5,
+		outport.TopicFinalizedBlock:        6,
+		outport.TopicRevertIndexedBlock:    7,
+	}
+
+	errEmptyImportDirectory = errors.New("empty import directory")
+	errNoIndexers           = errors.New("no indexers provided")
+	errNilIndexer           = errors.New("nil indexer")
+)
+
+// ArgsImporter holds all the components needed to create a new instance of importer
+type ArgsImporter struct {
+	Directory string
+	// Indexers holds a data indexer for every supported file extension. The files are decoded with the marshaller of
+	// the corresponding indexer
+	Indexers map[string]dataindexer.Indexer
+}
+
+// ImportFile holds the information extracted from the name of a file that can be imported
+type ImportFile struct {
+	Path      string
+	ShardID   uint32
+	Nonce     uint64
+	Topic     string
+	Extension string
+}
+
+type importProgress struct {
+	LastImportedFile string `json:"lastImportedFile"`
+	NumImportedFiles uint64 `json:"numImportedFiles"`
+}
+
+type importer struct {
+	directory string
+	indexers  map[string]dataindexer.Indexer
+	actions   map[string]func(indexer dataindexer.Indexer, fileBytes []byte) error
+}
+
+// NewImporter will create a new instance of importer
+func NewImporter(args ArgsImporter) (*importer, error) {
+	if args.Directory == "" {
+		return nil, errEmptyImportDirectory
+	}
+	if len(args.Indexers) == 0 {
+		return nil, errNoIndexers
+	}
+	for extension, indexer := range args.Indexers {
+		if check.IfNil(indexer) {
+			return nil, fmt.Errorf("%w for extension %s", errNilIndexer, extension)
+		}
+	}
+
+	imp := &importer{
+		directory: args.Directory,
+		indexers:  args.Indexers,
+	}
+	imp.initActionsMap()
+
+	return imp, nil
+}
+
+func (imp *importer) initActionsMap() {
+	imp.actions = map[string]func(indexer dataindexer.Indexer, fileBytes []byte) error{
+		outport.TopicSaveBlock:             saveBlock,
+		outport.TopicRevertIndexedBlock:    revertIndexedBlock,
+		outport.TopicSaveRoundsInfo:        saveRounds,
+		outport.TopicSaveValidatorsRating:  saveValidatorsRating,
+		outport.TopicSaveValidatorsPubKeys: saveValidatorsPubKeys,
+		outport.TopicSaveAccounts:          saveAccounts,
+		outport.TopicFinalizedBlock:        finalizedBlock,
+	}
+}
+
+// Import will index, in import-DB mode, all the files from the import directory, ordered by shard and nonce. The
+// progress is saved after every imported file so the import can be resumed after an interruption. A cancelled context
+// stops the import after the file that is currently imported
+func (imp *importer) Import(ctx context.Context) error {
+	files, err := imp.GetFilesToImport()
+	if err != nil {
+		return err
+	}
+
+	progress, err := imp.loadProgress()
+	if err != nil {
+		return err
+	}
+
+	err = imp.setImportDBMode()
+	if err != nil {
+		return err
+	}
+
+	startIndex, err := getResumeIndex(files, progress.LastImportedFile)
+	if err != nil {
+		return err
+	}
+	if progress.LastImportedFile != "" {
+		log.Info("resuming import", "last imported file", progress.LastImportedFile, "remaining files", len(files)-startIndex)
+	}
+
+	startTime := time.Now()
+	for idx := startIndex; idx < len(files); idx++ {
+		if ctx.Err() != nil {
+			log.Info("import interrupted", "last imported file", progress.LastImportedFile)
+			return nil
+		}
+
+		err = imp.importFile(files[idx])
+		if err != nil {
+			return fmt.Errorf("%w while importing file %s", err, files[idx].Path)
+		}
+
+		progress.LastImportedFile = filepath.Base(files[idx].Path)
+		progress.NumImportedFiles++
+		err = imp.saveProgress(progress)
+		if err != nil {
+			return err
+		}
+
+		imp.logProgress(idx-startIndex+1, len(files)-startIndex, startTime, files[idx])
+	}
+
+	log.Info("import finished", "imported files", progress.NumImportedFiles, "duration", time.Since(startTime))
+
+	return nil
+}
+
+func (imp *importer) logProgress(numDone int, numTotal int, startTime time.Time, file *ImportFile) {
+	if numDone%progressLogInterval != 0 && numDone != numTotal {
+		return
+	}
+
+	elapsed := time.Since(startTime)
+	filesPerSecond := float64(numDone) / elapsed.Seconds()
+	log.Info("import progress",
+		"files", fmt.Sprintf("%d/%d", numDone, numTotal),
+		"percent", fmt.Sprintf("%.2f", float64(numDone)*100/float64(numTotal)),
+		"files/s", fmt.Sprintf("%.2f", filesPerSecond),
+		"shard", file.ShardID,
+		"nonce", file.Nonce,
+	)
+}
+
+func (imp *importer) setImportDBMode() error {
+	for extension, indexer := range imp.indexers {
+		err := indexer.SetCurrentSettings(outport.OutportConfig{IsInImportDBMode: true})
+		if err != nil {
+			return fmt.Errorf("%w while setting the import-DB mode for the %s indexer", err, extension)
+		}
+	}
+
+	return nil
+}
+
+func (imp *importer) importFile(file *ImportFile) error {
+	indexer := imp.indexers[file.Extension]
+	action, ok := imp.actions[file.Topic]
+	if !ok {
+		log.Warn("importer: skipping file with unsupported topic", "file", file.Path, "topic", file.Topic)
+		return nil
+	}
+
+	fileBytes, err := os.ReadFile(file.Path)
+	if err != nil {
+		return err
+	}
+
+	return action(indexer, fileBytes)
+}
+
+// GetFilesToImport returns all the files from the import directory that can be imported, ordered by shard, nonce and
+// topic
+func (imp *importer) GetFilesToImport() ([]*ImportFile, error) {
+	entries, err := os.ReadDir(imp.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*ImportFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == ProgressFileName {
+			continue
+		}
+
+		file, errParse := parseFileName(entry.Name())
+		if errParse != nil {
+			log.Warn("importer: skipping file with invalid name", "file", entry.Name(), "error", errParse)
+			continue
+		}
+		_, isSupported := imp.indexers[file.Extension]
+		if !isSupported {
+			log.Warn("importer: skipping file with unsupported extension", "file", entry.Name())
+			continue
+		}
+
+		file.Path = filepath.Join(imp.directory, entry.Name())
+		files = append(files, file)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return lessFiles(files[i], files[j])
+	})
+
+	return files, nil
+}
+
+func parseFileName(name string) (*ImportFile, error) {
+	matches := fileNameRegex.FindStringSubmatch(name)
+	if len(matches) != 5 {
+		return nil, errors.New("the file name should be <shardID>-<nonce>-<topic>.<extension>")
+	}
+
+	shardID, err := strconv.ParseUint(matches[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := strconv.ParseUint(matches[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportFile{
+		ShardID:   uint32(shardID),
+		Nonce:     nonce,
+		Topic:     matches[3],
+		Extension: matches[4],
+	}, nil
+}
+
+func lessFiles(first *ImportFile, second *ImportFile) bool {
+	if first.ShardID != second.ShardID {
+		return first.ShardID < second.ShardID
+	}
+	if first.Nonce != second.Nonce {
+		return first.Nonce < second.Nonce
+	}
+
+	firstOrder, secondOrder := topicOrder(first.Topic), topicOrder(second.Topic)
+	if firstOrder != secondOrder {
+		return firstOrder < secondOrder
+	}
+
+	return first.Path < second.Path
+}
+
+func topicOrder(topic string) int {
+	order, ok := topicsOrder[topic]
+	if !ok {
+		return len(topicsOrder)
+	}
+
+	return order
+}
+
+// getResumeIndex returns the index of the first file that comes after the last imported one
+func getResumeIndex(files []*ImportFile, lastImportedFile string) (int, error) {
+	if lastImportedFile == "" {
+		return 0, nil
+	}
+
+	lastFile, err := parseFileName(lastImportedFile)
+	if err != nil {
+		return 0, fmt.Errorf("%w for the last imported file from the progress file", err)
+	}
+
+	for idx, file := range files {
+		lastFile.Path = filepath.Join(filepath.Dir(file.Path), lastImportedFile)
+		if lessFiles(lastFile, file) {
+			return idx, nil
+		}
+	}
+
+	return len(files), nil
+}
+
+func (imp *importer) loadProgress() (*importProgress, error) {
+	progress := &importProgress{}
+	progressBytes, err := os.ReadFile(filepath.Join(imp.directory, ProgressFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(progressBytes, progress)
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the import progress file", err)
+	}
+
+	return progress, nil
+}
+
+func (imp *importer) saveProgress(progress *importProgress) error {
+	progressBytes, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	progressFilePath := filepath.Join(imp.directory, ProgressFileName)
+	tmpFilePath := progressFilePath + ".tmp"
+	err = os.WriteFile(tmpFilePath, progressBytes, filesPermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, progressFilePath)
+}
+
+func saveBlock(indexer dataindexer.Indexer, fileBytes []byte) error {
+	outportBlock := &outport.OutportBlock{}
+	err := indexer.GetMarshaller().Unmarshal(outportBlock, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	return indexer.SaveBlock(outportBlock)
+}
+
+func revertIndexedBlock(indexer dataindexer.Indexer, fileBytes []byte) error {
+	blockData := &outport.BlockData{}
+	err := indexer.GetMarshaller().Unmarshal(blockData, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	return indexer.RevertIndexedBlock(blockData)
+}
+
+func saveRounds(indexer dataindexer.Indexer, fileBytes []byte) error {
+	roundsInfo := &outport.RoundsInfo{}
+	err := indexer.GetMarshaller().Unmarshal(roundsInfo, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	return indexer.SaveRoundsInfo(roundsInfo)
+}
+
+func saveValidatorsRating(indexer dataindexer.Indexer, fileBytes []byte) error {
+	ratingData := &outport.ValidatorsRating{}
+	err := indexer.GetMarshaller().Unmarshal(ratingData, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	return indexer.SaveValidatorsRating(ratingData)
+}
+
+func saveValidatorsPubKeys(indexer dataindexer.Indexer, fileBytes []byte) error {
+	validatorsPubKeys := &outport.ValidatorsPubKeys{}
+	err := indexer.GetMarshaller().Unmarshal(validatorsPubKeys, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	return indexer.SaveValidatorsPubKeys(validatorsPubKeys)
+}
+
+func saveAccounts(indexer dataindexer.Indexer, fileBytes []byte) error {
+	accounts := &outport.Accounts{}
+	err := indexer.GetMarshaller().Unmarshal(accounts, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	return indexer.SaveAccounts(accounts)
+}
+
+func finalizedBlock(indexer dataindexer.Indexer, fileBytes []byte) error {
+	finalized := &outport.FinalizedBlock{}
+	err := indexer.GetMarshaller().Unmarshal(finalized, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	return indexer.FinalizedBlock(finalized)
+}
+
+// Close will close all the data indexers, so the operations that are still in-flight are drained. It should be called
+// after the import finished or failed
+func (imp *importer) Close() error {
+	extensions := make([]string, 0, len(imp.indexers))
+	for extension := range imp.indexers {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+
+	var lastErr error
+	for _, extension := range extensions {
+		err := imp.indexers[extension].Close()
+		if err != nil {
+			log.Error("importer.Close: cannot close the indexer", "extension", extension, "error", err)
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (imp *importer) IsInterfaceNil() bool {
+	return imp == nil
+}
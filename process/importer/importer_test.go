@@ -0,0 +1,236 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, directory string, name string, marshaller marshal.Marshalizer, obj interface{}) {
+	fileBytes, err := marshaller.Marshal(obj)
+	require.Nil(t, err)
+
+	err = os.WriteFile(filepath.Join(directory, name), fileBytes, filesPermissions)
+	require.Nil(t, err)
+}
+
+func TestNewImporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty directory should error", func(t *testing.T) {
+		t.Parallel()
+
+		imp, err := NewImporter(ArgsImporter{Indexers: map[string]dataindexer.Indexer{JsonFileExtension: &mock.IndexerStub{}}})
+		require.Nil(t, imp)
+		require.Equal(t, errEmptyImportDirectory, err)
+	})
+
+	t.Run("no indexers should error", func(t *testing.T) {
+		t.Parallel()
+
+		imp, err := NewImporter(ArgsImporter{Directory: t.TempDir()})
+		require.Nil(t, imp)
+		require.Equal(t, errNoIndexers, err)
+	})
+
+	t.Run("nil indexer should error", func(t *testing.T) {
+		t.Parallel()
+
+		var indexer *mock.IndexerStub
+		imp, err := NewImporter(ArgsImporter{Directory: t.TempDir(), Indexers: map[string]dataindexer.Indexer{JsonFileExtension: indexer}})
+		require.Nil(t, imp)
+		require.True(t, errors.Is(err, errNilIndexer))
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		imp, err := NewImporter(ArgsImporter{Directory: t.TempDir(), Indexers: map[string]dataindexer.Indexer{JsonFileExtension: &mock.IndexerStub{}}})
+		require.Nil(t, err)
+		require.NotNil(t, imp)
+	})
+}
+
+func TestImporter_GetFilesToImport(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	marshaller := &marshal.JsonMarshalizer{}
+	writeFile(t, directory, "1-10-SaveAccounts.json", marshaller, &outport.Accounts{})
+	writeFile(t, directory, "1-10-SaveBlock.json", marshaller, &outport.OutportBlock{})
+	writeFile(t, directory, "0-11-SaveBlock.json", marshaller, &outport.OutportBlock{})
+	writeFile(t, directory, "0-9-SaveBlock.json", marshaller, &outport.OutportBlock{})
+	writeFile(t, directory, "0-9-SaveBlock.xml", marshaller, &outport.OutportBlock{})
+	writeFile(t, directory, "invalid.json", marshaller, &outport.OutportBlock{})
+
+	imp, _ := NewImporter(ArgsImporter{Directory: directory, Indexers: map[string]dataindexer.Indexer{JsonFileExtension: &mock.IndexerStub{}}})
+	files, err := imp.GetFilesToImport()
+	require.Nil(t, err)
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	require.Equal(t, []string{"0-9-SaveBlock.json", "0-11-SaveBlock.json", "1-10-SaveBlock.json", "1-10-SaveAccounts.json"}, names)
+}
+
+func TestImporter_Import(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	jsonMarshaller := &marshal.JsonMarshalizer{}
+	protoMarshaller := &marshal.GogoProtoMarshalizer{}
+	writeFile(t, directory, "0-1-SaveBlock.json", jsonMarshaller, &outport.OutportBlock{ShardID: 0, HighestFinalBlockNonce: 1})
+	writeFile(t, directory, "0-2-SaveBlock.pb", protoMarshaller, &outport.OutportBlock{ShardID: 0, HighestFinalBlockNonce: 2})
+	writeFile(t, directory, "0-2-SaveRoundsInfo.json", jsonMarshaller, &outport.RoundsInfo{ShardID: 0})
+
+	importDBModes := make([]bool, 0)
+	savedBlocks := make([]uint64, 0)
+	numRounds := 0
+	jsonIndexer := &mock.IndexerStub{
+		Marshaller: jsonMarshaller,
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			savedBlocks = append(savedBlocks, outportBlock.HighestFinalBlockNonce)
+			return nil
+		},
+		SaveRoundsInfoCalled: func(roundsInfos *outport.RoundsInfo) error {
+			numRounds++
+			return nil
+		},
+		SetCurrentSettingsCalled: func(cfg outport.OutportConfig) error {
+			importDBModes = append(importDBModes, cfg.IsInImportDBMode)
+			return nil
+		},
+	}
+	protoIndexer := &mock.IndexerStub{
+		Marshaller: protoMarshaller,
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			savedBlocks = append(savedBlocks, outportBlock.HighestFinalBlockNonce)
+			return nil
+		},
+		SetCurrentSettingsCalled: func(cfg outport.OutportConfig) error {
+			importDBModes = append(importDBModes, cfg.IsInImportDBMode)
+			return nil
+		},
+	}
+
+	imp, _ := NewImporter(ArgsImporter{
+		Directory: directory,
+		Indexers: map[string]dataindexer.Indexer{
+			JsonFileExtension:  jsonIndexer,
+			ProtoFileExtension: protoIndexer,
+		},
+	})
+
+	err := imp.Import(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []bool{true, true}, importDBModes)
+	require.Equal(t, []uint64{1, 2}, savedBlocks)
+	require.Equal(t, 1, numRounds)
+
+	progressBytes, err := os.ReadFile(filepath.Join(directory, ProgressFileName))
+	require.Nil(t, err)
+	progress := &importProgress{}
+	require.Nil(t, json.Unmarshal(progressBytes, progress))
+	require.Equal(t, "0-2-SaveRoundsInfo.json", progress.LastImportedFile)
+	require.Equal(t, uint64(3), progress.NumImportedFiles)
+}
+
+func TestImporter_ImportShouldResumeAfterError(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	marshaller := &marshal.JsonMarshalizer{}
+	writeFile(t, directory, "0-1-SaveBlock.json", marshaller, &outport.OutportBlock{HighestFinalBlockNonce: 1})
+	writeFile(t, directory, "0-2-SaveBlock.json", marshaller, &outport.OutportBlock{HighestFinalBlockNonce: 2})
+	writeFile(t, directory, "0-3-SaveBlock.json", marshaller, &outport.OutportBlock{HighestFinalBlockNonce: 3})
+
+	expectedErr := errors.New("expected error")
+	shouldFail := true
+	savedBlocks := make([]uint64, 0)
+	indexer := &mock.IndexerStub{
+		Marshaller: marshaller,
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			if outportBlock.HighestFinalBlockNonce == 2 && shouldFail {
+				return expectedErr
+			}
+
+			savedBlocks = append(savedBlocks, outportBlock.HighestFinalBlockNonce)
+			return nil
+		},
+	}
+
+	imp, _ := NewImporter(ArgsImporter{Directory: directory, Indexers: map[string]dataindexer.Indexer{JsonFileExtension: indexer}})
+	err := imp.Import(context.Background())
+	require.True(t, errors.Is(err, expectedErr))
+	require.Equal(t, []uint64{1}, savedBlocks)
+
+	shouldFail = false
+	err = imp.Import(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []uint64{1, 2, 3}, savedBlocks)
+}
+
+func TestImporter_ImportShouldStop(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	marshaller := &marshal.JsonMarshalizer{}
+	writeFile(t, directory, "0-1-SaveBlock.json", marshaller, &outport.OutportBlock{})
+	writeFile(t, directory, "0-2-SaveBlock.json", marshaller, &outport.OutportBlock{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	numSaved := 0
+	indexer := &mock.IndexerStub{
+		Marshaller: marshaller,
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			numSaved++
+			cancel()
+			return nil
+		},
+	}
+
+	imp, _ := NewImporter(ArgsImporter{Directory: directory, Indexers: map[string]dataindexer.Indexer{JsonFileExtension: indexer}})
+	err := imp.Import(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 1, numSaved)
+}
+
+func TestImporter_Close(t *testing.T) {
+	t.Parallel()
+
+	closed := make([]string, 0)
+	expectedErr := errors.New("expected error")
+	jsonIndexer := &mock.IndexerStub{
+		CloseCalled: func() error {
+			closed = append(closed, JsonFileExtension)
+			return expectedErr
+		},
+	}
+	protoIndexer := &mock.IndexerStub{
+		CloseCalled: func() error {
+			closed = append(closed, ProtoFileExtension)
+			return nil
+		},
+	}
+
+	imp, _ := NewImporter(ArgsImporter{
+		Directory: t.TempDir(),
+		Indexers: map[string]dataindexer.Indexer{
+			JsonFileExtension:  jsonIndexer,
+			ProtoFileExtension: protoIndexer,
+		},
+	})
+	err := imp.Close()
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, []string{JsonFileExtension, ProtoFileExtension}, closed)
+}
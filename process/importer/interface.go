@@ -0,0 +1,10 @@
+package importer
+
+import "context"
+
+// Importer defines what an importer should be able to do
+type Importer interface {
+	Import(ctx context.Context) error
+	Close() error
+	IsInterfaceNil() bool
+}
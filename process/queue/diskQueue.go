@@ -0,0 +1,492 @@
+package queue
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	segmentFilePrefix       = "segment-"
+	segmentFileExtension    = ".dat"
+	committedOffsetFileName = "committed.offset"
+	filesPermissions        = 0644
+	directoryPermissions    = 0755
+)
+
+// ArgsDiskQueue holds all the arguments needed to create a new instance of diskQueue
+type ArgsDiskQueue struct {
+	Directory             string
+	MaxSegmentSizeInBytes int64
+}
+
+type diskQueue struct {
+	mut            sync.Mutex
+	directory      string
+	maxSegmentSize int64
+	segments       []uint64
+	closed         bool
+
+	writeFile       *os.File
+	writeSize       int64
+	nextWriteOffset uint64
+
+	readFile         *os.File
+	readSegmentStart uint64
+	readPosition     int64
+	nextReadOffset   uint64
+
+	committedOffset uint64
+}
+
+// NewDiskQueue will create a new instance of diskQueue. The queue stores records in segment files under the provided
+// directory and resumes from the last committed offset, if any
+func NewDiskQueue(args ArgsDiskQueue) (*diskQueue, error) {
+	if args.Directory == "" {
+		return nil, ErrEmptyQueueDirectory
+	}
+	if args.MaxSegmentSizeInBytes <= 0 {
+		return nil, ErrInvalidSegmentSize
+	}
+
+	err := os.MkdirAll(args.Directory, directoryPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	dq := &diskQueue{
+		directory:      args.Directory,
+		maxSegmentSize: args.MaxSegmentSizeInBytes,
+	}
+
+	err = dq.recover()
+	if err != nil {
+		dq.closeFiles()
+		return nil, err
+	}
+
+	log.Info("disk queue opened", "directory", dq.directory,
+		"committed offset", dq.committedOffset,
+		"next write offset", dq.nextWriteOffset,
+		"depth", dq.nextWriteOffset-dq.committedOffset,
+	)
+
+	return dq, nil
+}
+
+func (dq *diskQueue) recover() error {
+	committedOffset, err := dq.loadCommittedOffset()
+	if err != nil {
+		return err
+	}
+
+	dq.segments, err = dq.listSegments()
+	if err != nil {
+		return err
+	}
+
+	if len(dq.segments) == 0 {
+		dq.nextWriteOffset = committedOffset
+		dq.committedOffset = committedOffset
+		err = dq.openWriteSegment(committedOffset)
+		if err != nil {
+			return err
+		}
+
+		return dq.openReadSegment(committedOffset)
+	}
+
+	lastSegmentStart := dq.segments[len(dq.segments)-1]
+	numRecords, validSize, err := dq.scanSegment(lastSegmentStart)
+	if err != nil {
+		return err
+	}
+
+	dq.nextWriteOffset = lastSegmentStart + numRecords
+	err = dq.openWriteSegment(lastSegmentStart)
+	if err != nil {
+		return err
+	}
+	err = dq.truncateWriteSegment(validSize)
+	if err != nil {
+		return err
+	}
+
+	firstSegmentStart := dq.segments[0]
+	if committedOffset < firstSegmentStart {
+		log.Warn("disk queue: committed offset is older than the first segment, some records were lost",
+			"committed offset", committedOffset, "first segment", firstSegmentStart)
+		committedOffset = firstSegmentStart
+	}
+	if committedOffset > dq.nextWriteOffset {
+		log.Warn("disk queue: committed offset is newer than the last written record",
+			"committed offset", committedOffset, "next write offset", dq.nextWriteOffset)
+		committedOffset = dq.nextWriteOffset
+	}
+	dq.committedOffset = committedOffset
+
+	return dq.seekReader(committedOffset)
+}
+
+func (dq *diskQueue) seekReader(offset uint64) error {
+	idx := sort.Search(len(dq.segments), func(i int) bool {
+		return dq.segments[i] > offset
+	}) - 1
+	if idx < 0 {
+		idx = 0
+	}
+
+	segmentStart := dq.segments[idx]
+	err := dq.openReadSegment(segmentStart)
+	if err != nil {
+		return err
+	}
+
+	for dq.nextReadOffset < offset {
+		_, size, errRead := ReadRecord(io.NewSectionReader(dq.readFile, dq.readPosition, math.MaxInt64-dq.readPosition))
+		if errRead != nil {
+			return fmt.Errorf("%w while seeking the offset %d in segment %d", errRead, offset, segmentStart)
+		}
+
+		dq.readPosition += size
+		dq.nextReadOffset++
+	}
+
+	return nil
+}
+
+// Append will durably write the provided record at the end of the queue
+func (dq *diskQueue) Append(record *Record) error {
+	encodedRecord, err := EncodeRecord(record)
+	if err != nil {
+		return err
+	}
+
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	if dq.closed {
+		return ErrQueueClosed
+	}
+
+	shouldRotate := dq.writeSize > 0 && dq.writeSize+int64(len(encodedRecord)) > dq.maxSegmentSize
+	if shouldRotate {
+		err = dq.rotateWriteSegment()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = dq.writeFile.Write(encodedRecord)
+	if err != nil {
+		return err
+	}
+	err = dq.writeFile.Sync()
+	if err != nil {
+		return err
+	}
+
+	dq.writeSize += int64(len(encodedRecord))
+	dq.nextWriteOffset++
+
+	return nil
+}
+
+// ReadNext will return the next record that was not read yet, together with its offset. If there is no such record,
+// ErrEmptyQueue will be returned
+func (dq *diskQueue) ReadNext() (*Record, uint64, error) {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	if dq.closed {
+		return nil, 0, ErrQueueClosed
+	}
+
+	for {
+		if dq.nextReadOffset >= dq.nextWriteOffset {
+			return nil, 0, ErrEmptyQueue
+		}
+
+		record, size, err := ReadRecord(io.NewSectionReader(dq.readFile, dq.readPosition, math.MaxInt64-dq.readPosition))
+		if err == io.EOF {
+			err = dq.moveReaderToNextSegment()
+			if err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		offset := dq.nextReadOffset
+		dq.readPosition += size
+		dq.nextReadOffset++
+
+		return record, offset, nil
+	}
+}
+
+// Commit will mark all the records up to the provided offset, inclusive, as processed. Segments that contain only
+// processed records are removed from the disk
+func (dq *diskQueue) Commit(offset uint64) error {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	if dq.closed {
+		return ErrQueueClosed
+	}
+	if offset >= dq.nextReadOffset {
+		return fmt.Errorf("%w, offset %d, next read offset %d", ErrInvalidCommitOffset, offset, dq.nextReadOffset)
+	}
+
+	dq.committedOffset = offset + 1
+	err := dq.saveCommittedOffset()
+	if err != nil {
+		return err
+	}
+
+	return dq.removeConsumedSegments()
+}
+
+// Depth returns the number of records that were appended but not committed yet
+func (dq *diskQueue) Depth() uint64 {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	return dq.nextWriteOffset - dq.committedOffset
+}
+
+// Close will close the opened segment files
+func (dq *diskQueue) Close() error {
+	dq.mut.Lock()
+	defer dq.mut.Unlock()
+
+	if dq.closed {
+		return nil
+	}
+	dq.closed = true
+
+	return dq.closeFiles()
+}
+
+func (dq *diskQueue) closeFiles() error {
+	var lastErr error
+	if dq.writeFile != nil {
+		lastErr = dq.writeFile.Close()
+	}
+	if dq.readFile != nil {
+		err := dq.readFile.Close()
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (dq *diskQueue) rotateWriteSegment() error {
+	err := dq.writeFile.Close()
+	if err != nil {
+		return err
+	}
+
+	dq.segments = append(dq.segments, dq.nextWriteOffset)
+
+	return dq.openWriteSegment(dq.nextWriteOffset)
+}
+
+func (dq *diskQueue) openWriteSegment(startOffset uint64) error {
+	file, err := os.OpenFile(dq.segmentPath(startOffset), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filesPermissions)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if len(dq.segments) == 0 {
+		dq.segments = append(dq.segments, startOffset)
+	}
+	dq.writeFile = file
+	dq.writeSize = info.Size()
+
+	return nil
+}
+
+func (dq *diskQueue) truncateWriteSegment(validSize int64) error {
+	if validSize == dq.writeSize {
+		return nil
+	}
+
+	log.Warn("disk queue: truncating the incomplete record from the last segment",
+		"segment size", dq.writeSize, "valid size", validSize)
+
+	err := dq.writeFile.Truncate(validSize)
+	if err != nil {
+		return err
+	}
+	dq.writeSize = validSize
+
+	return nil
+}
+
+func (dq *diskQueue) openReadSegment(startOffset uint64) error {
+	file, err := os.Open(dq.segmentPath(startOffset))
+	if err != nil {
+		return err
+	}
+
+	if dq.readFile != nil {
+		_ = dq.readFile.Close()
+	}
+
+	dq.readFile = file
+	dq.readSegmentStart = startOffset
+	dq.readPosition = 0
+	dq.nextReadOffset = startOffset
+
+	return nil
+}
+
+func (dq *diskQueue) moveReaderToNextSegment() error {
+	for _, segmentStart := range dq.segments {
+		if segmentStart <= dq.readSegmentStart {
+			continue
+		}
+		if segmentStart != dq.nextReadOffset {
+			return fmt.Errorf("%w, expected segment %d, found segment %d", ErrCorruptedSegment, dq.nextReadOffset, segmentStart)
+		}
+
+		return dq.openReadSegment(segmentStart)
+	}
+
+	return fmt.Errorf("%w, no segment after %d", ErrCorruptedSegment, dq.readSegmentStart)
+}
+
+func (dq *diskQueue) removeConsumedSegments() error {
+	for len(dq.segments) > 1 {
+		nextSegmentStart := dq.segments[1]
+		isConsumed := nextSegmentStart <= dq.committedOffset && nextSegmentStart <= dq.readSegmentStart
+		if !isConsumed {
+			return nil
+		}
+
+		err := os.Remove(dq.segmentPath(dq.segments[0]))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		dq.segments = dq.segments[1:]
+	}
+
+	return nil
+}
+
+func (dq *diskQueue) scanSegment(startOffset uint64) (uint64, int64, error) {
+	file, err := os.Open(dq.segmentPath(startOffset))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	numRecords := uint64(0)
+	position := int64(0)
+	for {
+		_, size, errRead := ReadRecord(io.NewSectionReader(file, position, math.MaxInt64-position))
+		if errRead == io.EOF {
+			return numRecords, position, nil
+		}
+		if errRead == io.ErrUnexpectedEOF || errors.Is(errRead, ErrCorruptedRecord) {
+			return numRecords, position, nil
+		}
+		if errRead != nil {
+			return 0, 0, errRead
+		}
+
+		position += size
+		numRecords++
+	}
+}
+
+func (dq *diskQueue) listSegments() ([]uint64, error) {
+	entries, err := os.ReadDir(dq.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		isSegmentFile := !entry.IsDir() && strings.HasPrefix(name, segmentFilePrefix) && strings.HasSuffix(name, segmentFileExtension)
+		if !isSegmentFile {
+			continue
+		}
+
+		startOffsetStr := strings.TrimSuffix(strings.TrimPrefix(name, segmentFilePrefix), segmentFileExtension)
+		startOffset, errParse := strconv.ParseUint(startOffsetStr, 10, 64)
+		if errParse != nil {
+			log.Warn("disk queue: skipping file with invalid segment name", "file", name)
+			continue
+		}
+
+		segments = append(segments, startOffset)
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i] < segments[j]
+	})
+
+	return segments, nil
+}
+
+func (dq *diskQueue) loadCommittedOffset() (uint64, error) {
+	offsetBytes, err := os.ReadFile(filepath.Join(dq.directory, committedOffsetFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(offsetBytes) != 8 {
+		return 0, ErrCorruptedOffsetFile
+	}
+
+	return binary.BigEndian.Uint64(offsetBytes), nil
+}
+
+func (dq *diskQueue) saveCommittedOffset() error {
+	offsetBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(offsetBytes, dq.committedOffset)
+
+	offsetFilePath := filepath.Join(dq.directory, committedOffsetFileName)
+	tmpFilePath := offsetFilePath + ".tmp"
+	err := os.WriteFile(tmpFilePath, offsetBytes, filesPermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, offsetFilePath)
+}
+
+func (dq *diskQueue) segmentPath(startOffset uint64) string {
+	return filepath.Join(dq.directory, fmt.Sprintf("%s%020d%s", segmentFilePrefix, startOffset, segmentFileExtension))
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dq *diskQueue) IsInterfaceNil() bool {
+	return dq == nil
+}
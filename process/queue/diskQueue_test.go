@@ -0,0 +1,203 @@
+package queue
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createMockDiskQueueArgs(t *testing.T) ArgsDiskQueue {
+	return ArgsDiskQueue{
+		Directory:             t.TempDir(),
+		MaxSegmentSizeInBytes: 100,
+	}
+}
+
+func appendRecords(t *testing.T, dq *diskQueue, start int, end int) {
+	for i := start; i < end; i++ {
+		err := dq.Append(&Record{Topic: "topic", Version: 1, Payload: []byte(fmt.Sprintf("payload-%d", i))})
+		require.Nil(t, err)
+	}
+}
+
+func TestNewDiskQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty directory should error", func(t *testing.T) {
+		args := createMockDiskQueueArgs(t)
+		args.Directory = ""
+
+		dq, err := NewDiskQueue(args)
+		require.Nil(t, dq)
+		require.Equal(t, ErrEmptyQueueDirectory, err)
+	})
+	t.Run("invalid segment size should error", func(t *testing.T) {
+		args := createMockDiskQueueArgs(t)
+		args.MaxSegmentSizeInBytes = 0
+
+		dq, err := NewDiskQueue(args)
+		require.Nil(t, dq)
+		require.Equal(t, ErrInvalidSegmentSize, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		dq, err := NewDiskQueue(createMockDiskQueueArgs(t))
+		require.Nil(t, err)
+		require.False(t, dq.IsInterfaceNil())
+		require.Equal(t, uint64(0), dq.Depth())
+		require.Nil(t, dq.Close())
+	})
+}
+
+func TestDiskQueue_AppendReadAndCommit(t *testing.T) {
+	t.Parallel()
+
+	dq, _ := NewDiskQueue(createMockDiskQueueArgs(t))
+	defer func() {
+		_ = dq.Close()
+	}()
+
+	_, _, err := dq.ReadNext()
+	require.Equal(t, ErrEmptyQueue, err)
+
+	appendRecords(t, dq, 0, 10)
+	require.Equal(t, uint64(10), dq.Depth())
+
+	for i := 0; i < 10; i++ {
+		record, offset, errRead := dq.ReadNext()
+		require.Nil(t, errRead)
+		require.Equal(t, uint64(i), offset)
+		require.Equal(t, fmt.Sprintf("payload-%d", i), string(record.Payload))
+
+		require.Nil(t, dq.Commit(offset))
+	}
+
+	_, _, err = dq.ReadNext()
+	require.Equal(t, ErrEmptyQueue, err)
+	require.Equal(t, uint64(0), dq.Depth())
+
+	err = dq.Commit(10)
+	require.ErrorIs(t, err, ErrInvalidCommitOffset)
+}
+
+func TestDiskQueue_ShouldRemoveConsumedSegments(t *testing.T) {
+	t.Parallel()
+
+	args := createMockDiskQueueArgs(t)
+	dq, _ := NewDiskQueue(args)
+	defer func() {
+		_ = dq.Close()
+	}()
+
+	appendRecords(t, dq, 0, 10)
+	require.Greater(t, len(dq.segments), 2)
+
+	for i := 0; i < 10; i++ {
+		_, offset, _ := dq.ReadNext()
+		_ = dq.Commit(offset)
+	}
+
+	require.Equal(t, 1, len(dq.segments))
+	files, _ := filepath.Glob(filepath.Join(args.Directory, segmentFilePrefix+"*"))
+	require.Equal(t, 1, len(files))
+}
+
+func TestDiskQueue_ShouldReplayFromLastCommittedOffsetAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	args := createMockDiskQueueArgs(t)
+	dq, _ := NewDiskQueue(args)
+	appendRecords(t, dq, 0, 10)
+
+	for i := 0; i < 4; i++ {
+		_, offset, _ := dq.ReadNext()
+		_ = dq.Commit(offset)
+	}
+	// read but not committed
+	_, _, _ = dq.ReadNext()
+	require.Nil(t, dq.Close())
+
+	dq, err := NewDiskQueue(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = dq.Close()
+	}()
+	require.Equal(t, uint64(6), dq.Depth())
+
+	record, offset, err := dq.ReadNext()
+	require.Nil(t, err)
+	require.Equal(t, uint64(4), offset)
+	require.Equal(t, "payload-4", string(record.Payload))
+
+	appendRecords(t, dq, 10, 12)
+	require.Equal(t, uint64(8), dq.Depth())
+}
+
+func TestDiskQueue_ShouldTruncateIncompleteRecordAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	args := createMockDiskQueueArgs(t)
+	args.MaxSegmentSizeInBytes = 1024
+	dq, _ := NewDiskQueue(args)
+	appendRecords(t, dq, 0, 2)
+	require.Nil(t, dq.Close())
+
+	segmentPath := dq.segmentPath(0)
+	file, _ := os.OpenFile(segmentPath, os.O_APPEND|os.O_WRONLY, filesPermissions)
+	_, _ = file.Write([]byte{0, 0, 0, 100, 1, 2})
+	_ = file.Close()
+
+	dq, err := NewDiskQueue(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = dq.Close()
+	}()
+	require.Equal(t, uint64(2), dq.Depth())
+
+	appendRecords(t, dq, 2, 3)
+	for i := 0; i < 3; i++ {
+		record, _, errRead := dq.ReadNext()
+		require.Nil(t, errRead)
+		require.Equal(t, fmt.Sprintf("payload-%d", i), string(record.Payload))
+	}
+}
+
+func TestDiskQueue_ShouldTruncateCorruptedRecordLengthAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	args := createMockDiskQueueArgs(t)
+	args.MaxSegmentSizeInBytes = 1024
+	dq, _ := NewDiskQueue(args)
+	appendRecords(t, dq, 0, 2)
+	require.Nil(t, dq.Close())
+
+	segmentPath := dq.segmentPath(0)
+	validInfo, _ := os.Stat(segmentPath)
+	file, _ := os.OpenFile(segmentPath, os.O_APPEND|os.O_WRONLY, filesPermissions)
+	_, _ = file.Write([]byte{0xff, 0xff, 0xff, 0xf0, 1, 2, 3, 4, 5, 6})
+	_ = file.Close()
+
+	dq, err := NewDiskQueue(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = dq.Close()
+	}()
+	require.Equal(t, uint64(2), dq.Depth())
+
+	info, _ := os.Stat(segmentPath)
+	require.Equal(t, validInfo.Size(), info.Size())
+}
+
+func TestDiskQueue_ClosedQueueShouldError(t *testing.T) {
+	t.Parallel()
+
+	dq, _ := NewDiskQueue(createMockDiskQueueArgs(t))
+	require.Nil(t, dq.Close())
+	require.Nil(t, dq.Close())
+
+	require.Equal(t, ErrQueueClosed, dq.Append(&Record{}))
+	_, _, err := dq.ReadNext()
+	require.Equal(t, ErrQueueClosed, err)
+}
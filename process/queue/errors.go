@@ -0,0 +1,42 @@
+package queue
+
+import "errors"
+
+// ErrNilRecord signals that a nil record has been provided
+var ErrNilRecord = errors.New("nil record")
+
+// ErrTopicTooLong signals that the topic of a record exceeds the maximum supported length
+var ErrTopicTooLong = errors.New("record topic is too long")
+
+// ErrRecordTooLarge signals that a record exceeds the maximum supported size
+var ErrRecordTooLarge = errors.New("record is too large")
+
+// ErrCorruptedRecord signals that a record read from the disk does not match its checksum or length
+var ErrCorruptedRecord = errors.New("corrupted record")
+
+// ErrCorruptedSegment signals that the segment files of the queue are not contiguous
+var ErrCorruptedSegment = errors.New("corrupted segment")
+
+// ErrCorruptedOffsetFile signals that the committed offset file has an invalid content
+var ErrCorruptedOffsetFile = errors.New("corrupted committed offset file")
+
+// ErrEmptyQueueDirectory signals that an empty directory path has been provided
+var ErrEmptyQueueDirectory = errors.New("empty queue directory")
+
+// ErrInvalidSegmentSize signals that an invalid segment size has been provided
+var ErrInvalidSegmentSize = errors.New("invalid segment size")
+
+// ErrEmptyQueue signals that there are no records left to be read
+var ErrEmptyQueue = errors.New("empty queue")
+
+// ErrQueueClosed signals that an operation was attempted on a closed queue
+var ErrQueueClosed = errors.New("queue is closed")
+
+// ErrInvalidCommitOffset signals that the provided commit offset was not read yet
+var ErrInvalidCommitOffset = errors.New("invalid commit offset")
+
+// ErrNilDiskQueue signals that a nil disk queue has been provided
+var ErrNilDiskQueue = errors.New("nil disk queue")
+
+// ErrNilPayloadHandler signals that a nil payload handler has been provided
+var ErrNilPayloadHandler = errors.New("nil payload handler")
@@ -0,0 +1,18 @@
+package queue
+
+// DiskQueueHandler defines what a persistent queue should be able to do
+type DiskQueueHandler interface {
+	Append(record *Record) error
+	ReadNext() (*Record, uint64, error)
+	Commit(offset uint64) error
+	Depth() uint64
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// PayloadHandler defines what a payload handler should be able to do
+type PayloadHandler interface {
+	ProcessPayload(payload []byte, topic string, version uint32) error
+	Close() error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,225 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	// DepthMetricTopic is the identifier for the persistent queue depth metric
+	DepthMetricTopic = "persistent_queue_depth"
+	// SkippedPayloadsMetricTopic is the identifier for the metric with the number of payloads that were removed from the
+	// persistent queue without being processed, because they can never be processed
+	SkippedPayloadsMetricTopic = "persistent_queue_skipped_payloads"
+	// SourceLabelName is the name of the label that holds the WebSocket source of the persistent queue metrics
+	SourceLabelName  = "source"
+	minRetryDuration = time.Millisecond
+)
+
+var log = logger.GetOrCreate("process/queue")
+
+// ArgsQueuedPayloadHandler holds all the components needed to create a new instance of queuedPayloadHandler
+type ArgsQueuedPayloadHandler struct {
+	Queue          DiskQueueHandler
+	PayloadHandler PayloadHandler
+	StatusMetrics  core.StatusMetricsHandler
+	RetryDuration  time.Duration
+	// SourceName is the name of the WebSocket source that owns the queue. If not empty, it is added as the source label
+	// of the queue metrics
+	SourceName string
+}
+
+type queuedPayloadHandler struct {
+	queue          DiskQueueHandler
+	payloadHandler PayloadHandler
+	statusMetrics  core.StatusMetricsHandler
+	retryDuration  time.Duration
+	metricTopic    string
+	skippedTopic   string
+	numSkipped     uint64
+	newDataChan    chan struct{}
+	doneChan       chan struct{}
+	cancel         context.CancelFunc
+	closeOnce      sync.Once
+}
+
+// NewQueuedPayloadHandler will create a new instance of queuedPayloadHandler. Every payload received is appended in the
+// persistent queue and acknowledged right away, while a separate goroutine drains the queue, in order, into the
+// provided payload handler
+func NewQueuedPayloadHandler(args ArgsQueuedPayloadHandler) (*queuedPayloadHandler, error) {
+	if check.IfNil(args.Queue) {
+		return nil, ErrNilDiskQueue
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, ErrNilPayloadHandler
+	}
+	if check.IfNil(args.StatusMetrics) {
+		return nil, core.ErrNilMetricsHandler
+	}
+
+	retryDuration := args.RetryDuration
+	if retryDuration < minRetryDuration {
+		retryDuration = minRetryDuration
+	}
+
+	metricTopic := DepthMetricTopic
+	skippedTopic := SkippedPayloadsMetricTopic
+	if args.SourceName != "" {
+		metricTopic = request.ExtendTopicWithLabel(DepthMetricTopic, SourceLabelName, args.SourceName)
+		skippedTopic = request.ExtendTopicWithLabel(SkippedPayloadsMetricTopic, SourceLabelName, args.SourceName)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	qph := &queuedPayloadHandler{
+		queue:          args.Queue,
+		payloadHandler: args.PayloadHandler,
+		statusMetrics:  args.StatusMetrics,
+		retryDuration:  retryDuration,
+		metricTopic:    metricTopic,
+		skippedTopic:   skippedTopic,
+		newDataChan:    make(chan struct{}, 1),
+		doneChan:       make(chan struct{}),
+		cancel:         cancel,
+	}
+	qph.updateDepthMetric()
+
+	go qph.consume(ctx)
+
+	return qph, nil
+}
+
+// ProcessPayload will append the provided payload in the persistent queue
+func (qph *queuedPayloadHandler) ProcessPayload(payload []byte, topic string, version uint32) error {
+	err := qph.queue.Append(&Record{
+		Topic:   topic,
+		Version: version,
+		Payload: payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	qph.updateDepthMetric()
+	qph.notifyNewData()
+
+	return nil
+}
+
+func (qph *queuedPayloadHandler) notifyNewData() {
+	select {
+	case qph.newDataChan <- struct{}{}:
+	default:
+	}
+}
+
+func (qph *queuedPayloadHandler) consume(ctx context.Context) {
+	defer close(qph.doneChan)
+
+	for {
+		record, offset, err := qph.queue.ReadNext()
+		if errors.Is(err, ErrEmptyQueue) {
+			select {
+			case <-qph.newDataChan:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			log.Error("queuedPayloadHandler.consume: cannot read from the queue", "error", err)
+			if !qph.waitRetry(ctx) {
+				return
+			}
+			continue
+		}
+
+		if !qph.processRecord(ctx, record, offset) {
+			return
+		}
+	}
+}
+
+// processRecord retries the record until it is processed. The records that can never be processed are skipped, so
+// they do not block the ones queued after them
+func (qph *queuedPayloadHandler) processRecord(ctx context.Context, record *Record, offset uint64) bool {
+	for {
+		err := qph.payloadHandler.ProcessPayload(record.Payload, record.Topic, record.Version)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, dataindexer.ErrUnprocessablePayload) {
+			qph.skipRecord(record, offset, err)
+			break
+		}
+
+		log.Warn("queuedPayloadHandler.processRecord: cannot process payload - will retry",
+			"topic", record.Topic, "offset", offset, "error", err)
+		if !qph.waitRetry(ctx) {
+			return false
+		}
+	}
+
+	err := qph.queue.Commit(offset)
+	if err != nil {
+		log.Error("queuedPayloadHandler.processRecord: cannot commit offset", "offset", offset, "error", err)
+	}
+	qph.updateDepthMetric()
+
+	return true
+}
+
+func (qph *queuedPayloadHandler) skipRecord(record *Record, offset uint64, err error) {
+	log.Error("queuedPayloadHandler.processRecord: cannot process payload - skipping it",
+		"topic", record.Topic, "version", record.Version, "offset", offset, "size", len(record.Payload), "error", err)
+
+	qph.numSkipped++
+	qph.statusMetrics.SetGauge(qph.skippedTopic, qph.numSkipped)
+}
+
+func (qph *queuedPayloadHandler) waitRetry(ctx context.Context) bool {
+	timer := time.NewTimer(qph.retryDuration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (qph *queuedPayloadHandler) updateDepthMetric() {
+	qph.statusMetrics.SetGauge(qph.metricTopic, qph.queue.Depth())
+}
+
+// Close will stop consuming the queue and will close the underlying payload handler and the persistent queue. The
+// payload handler is closed before waiting for the consumer, so it can drain or abort the payload being processed. An
+// aborted payload is not committed and it will be processed again after a restart
+func (qph *queuedPayloadHandler) Close() error {
+	var err error
+	qph.closeOnce.Do(func() {
+		qph.cancel()
+		err = qph.payloadHandler.Close()
+		<-qph.doneChan
+
+		errQueue := qph.queue.Close()
+		if errQueue != nil {
+			log.Warn("queuedPayloadHandler.Close: cannot close the queue", "error", errQueue)
+		}
+	})
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (qph *queuedPayloadHandler) IsInterfaceNil() bool {
+	return qph == nil
+}
@@ -0,0 +1,174 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockQueuedPayloadHandlerArgs(t *testing.T) ArgsQueuedPayloadHandler {
+	dq, _ := NewDiskQueue(createMockDiskQueueArgs(t))
+
+	return ArgsQueuedPayloadHandler{
+		Queue:          dq,
+		PayloadHandler: &mock.PayloadHandlerStub{},
+		StatusMetrics:  metrics.NewStatusMetrics(),
+		RetryDuration:  time.Millisecond,
+	}
+}
+
+func TestNewQueuedPayloadHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil queue should error", func(t *testing.T) {
+		args := createMockQueuedPayloadHandlerArgs(t)
+		args.Queue = nil
+
+		qph, err := NewQueuedPayloadHandler(args)
+		require.Nil(t, qph)
+		require.Equal(t, ErrNilDiskQueue, err)
+	})
+	t.Run("nil payload handler should error", func(t *testing.T) {
+		args := createMockQueuedPayloadHandlerArgs(t)
+		args.PayloadHandler = nil
+
+		qph, err := NewQueuedPayloadHandler(args)
+		require.Nil(t, qph)
+		require.Equal(t, ErrNilPayloadHandler, err)
+	})
+	t.Run("nil status metrics should error", func(t *testing.T) {
+		args := createMockQueuedPayloadHandlerArgs(t)
+		args.StatusMetrics = nil
+
+		qph, err := NewQueuedPayloadHandler(args)
+		require.Nil(t, qph)
+		require.Equal(t, core.ErrNilMetricsHandler, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		qph, err := NewQueuedPayloadHandler(createMockQueuedPayloadHandlerArgs(t))
+		require.Nil(t, err)
+		require.False(t, qph.IsInterfaceNil())
+		require.Nil(t, qph.Close())
+	})
+}
+
+func TestQueuedPayloadHandler_ShouldProcessPayloadsInOrderAndRetryOnError(t *testing.T) {
+	t.Parallel()
+
+	mut := sync.Mutex{}
+	processed := make([]string, 0)
+	numFailures := 2
+	args := createMockQueuedPayloadHandlerArgs(t)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			mut.Lock()
+			defer mut.Unlock()
+
+			if string(payload) == "payload-1" && numFailures > 0 {
+				numFailures--
+				return errors.New("local error")
+			}
+
+			processed = append(processed, string(payload))
+			return nil
+		},
+	}
+	qph, _ := NewQueuedPayloadHandler(args)
+
+	for i := 0; i < 5; i++ {
+		err := qph.ProcessPayload([]byte(fmt.Sprintf("payload-%d", i)), "topic", 1)
+		require.Nil(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return args.Queue.Depth() == 0
+	}, time.Second, time.Millisecond)
+	require.Nil(t, qph.Close())
+
+	require.Equal(t, []string{"payload-0", "payload-1", "payload-2", "payload-3", "payload-4"}, processed)
+}
+
+func TestQueuedPayloadHandler_UnprocessablePayloadShouldNotBlockTheNextOnes(t *testing.T) {
+	t.Parallel()
+
+	mut := sync.Mutex{}
+	processed := make([]string, 0)
+	statusMetrics := metrics.NewStatusMetrics()
+	args := createMockQueuedPayloadHandlerArgs(t)
+	args.StatusMetrics = statusMetrics
+	args.SourceName = "meta"
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			mut.Lock()
+			defer mut.Unlock()
+
+			if string(payload) == "poison" {
+				return fmt.Errorf("%w: local error", dataindexer.ErrUnprocessablePayload)
+			}
+
+			processed = append(processed, string(payload))
+			return nil
+		},
+	}
+	qph, _ := NewQueuedPayloadHandler(args)
+
+	for _, payload := range []string{"payload-0", "poison", "payload-1"} {
+		err := qph.ProcessPayload([]byte(payload), "topic", 1)
+		require.Nil(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return args.Queue.Depth() == 0
+	}, time.Second, time.Millisecond)
+	require.Nil(t, qph.Close())
+
+	require.Equal(t, []string{"payload-0", "payload-1"}, processed)
+	require.Contains(t, statusMetrics.GetMetricsForPrometheus(), "persistent_queue_skipped_payloads{shardID=\"#\",source=\"meta\"} 1")
+}
+
+func TestQueuedPayloadHandler_CloseShouldCloseUnderlyingHandlerOnce(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	args := createMockQueuedPayloadHandlerArgs(t)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		CloseCalled: func() error {
+			numCalls++
+			return nil
+		},
+	}
+	qph, _ := NewQueuedPayloadHandler(args)
+
+	require.Nil(t, qph.Close())
+	require.Nil(t, qph.Close())
+	require.Equal(t, 1, numCalls)
+}
+
+func TestQueuedPayloadHandler_ShouldSetTheDepthGaugeWithTheSourceLabel(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	args := createMockQueuedPayloadHandlerArgs(t)
+	args.StatusMetrics = statusMetrics
+	args.SourceName = "meta"
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			return errors.New("local error")
+		},
+	}
+	qph, _ := NewQueuedPayloadHandler(args)
+
+	err := qph.ProcessPayload([]byte("payload"), "topic", 1)
+	require.Nil(t, err)
+	require.Nil(t, qph.Close())
+
+	require.Contains(t, statusMetrics.GetMetricsForPrometheus(), "persistent_queue_depth{shardID=\"#\",source=\"meta\"} 1")
+}
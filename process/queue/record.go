@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+const (
+	// recordHeaderSize holds the size of the body length and the checksum that are written before each record body
+	recordHeaderSize = 8
+	// recordBodyPrefixSize holds the size of the version and of the topic length that are written before the topic
+	recordBodyPrefixSize = 6
+	// maxRecordBodySize holds the maximum size of a record body. The body length is read from the disk before the
+	// checksum can be verified, so a corrupted length must not be trusted for the allocation of the body
+	maxRecordBodySize = 512 * 1024 * 1024
+)
+
+// Record holds a websocket payload together with its topic and version
+type Record struct {
+	Topic   string
+	Version uint32
+	Payload []byte
+}
+
+// EncodeRecord will serialize the provided record in the following format:
+// body length (4 bytes) | crc32 of body (4 bytes) | version (4 bytes) | topic length (2 bytes) | topic | payload
+func EncodeRecord(record *Record) ([]byte, error) {
+	if record == nil {
+		return nil, ErrNilRecord
+	}
+	if len(record.Topic) > math.MaxUint16 {
+		return nil, ErrTopicTooLong
+	}
+
+	bodyLen := recordBodyPrefixSize + len(record.Topic) + len(record.Payload)
+	if bodyLen > maxRecordBodySize {
+		return nil, ErrRecordTooLarge
+	}
+
+	buff := make([]byte, recordHeaderSize+bodyLen)
+	body := buff[recordHeaderSize:]
+	binary.BigEndian.PutUint32(body[0:4], record.Version)
+	binary.BigEndian.PutUint16(body[4:6], uint16(len(record.Topic)))
+	copy(body[recordBodyPrefixSize:], record.Topic)
+	copy(body[recordBodyPrefixSize+len(record.Topic):], record.Payload)
+
+	binary.BigEndian.PutUint32(buff[0:4], uint32(bodyLen))
+	binary.BigEndian.PutUint32(buff[4:8], crc32.ChecksumIEEE(body))
+
+	return buff, nil
+}
+
+// ReadRecord will read and decode the next record from the provided reader. It returns io.EOF if the reader
+// has no more data and io.ErrUnexpectedEOF if only a part of the record could be read
+func ReadRecord(reader io.Reader) (*Record, int64, error) {
+	header := make([]byte, recordHeaderSize)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bodyLen := binary.BigEndian.Uint32(header[0:4])
+	if bodyLen < recordBodyPrefixSize || bodyLen > maxRecordBodySize {
+		return nil, 0, ErrCorruptedRecord
+	}
+
+	body := make([]byte, bodyLen)
+	_, err = io.ReadFull(reader, body)
+	if err == io.EOF {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	record, err := decodeRecordBody(body, binary.BigEndian.Uint32(header[4:8]))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return record, int64(recordHeaderSize + bodyLen), nil
+}
+
+func decodeRecordBody(body []byte, checksum uint32) (*Record, error) {
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, ErrCorruptedRecord
+	}
+
+	topicLen := int(binary.BigEndian.Uint16(body[4:6]))
+	if recordBodyPrefixSize+topicLen > len(body) {
+		return nil, ErrCorruptedRecord
+	}
+
+	topicEnd := recordBodyPrefixSize + topicLen
+	payload := make([]byte, len(body)-topicEnd)
+	copy(payload, body[topicEnd:])
+
+	return &Record{
+		Version: binary.BigEndian.Uint32(body[0:4]),
+		Topic:   string(body[recordBodyPrefixSize:topicEnd]),
+		Payload: payload,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package queue
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeRecord_NilRecord(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := EncodeRecord(nil)
+	require.Nil(t, encoded)
+	require.Equal(t, ErrNilRecord, err)
+}
+
+func TestEncodeAndReadRecord(t *testing.T) {
+	t.Parallel()
+
+	record := &Record{
+		Topic:   "SaveBlock",
+		Version: 1,
+		Payload: []byte("payload"),
+	}
+	encoded, err := EncodeRecord(record)
+	require.Nil(t, err)
+
+	reader := bytes.NewReader(encoded)
+	decoded, size, err := ReadRecord(reader)
+	require.Nil(t, err)
+	require.Equal(t, record, decoded)
+	require.Equal(t, int64(len(encoded)), size)
+
+	_, _, err = ReadRecord(reader)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReadRecord_PartialRecord(t *testing.T) {
+	t.Parallel()
+
+	encoded, _ := EncodeRecord(&Record{Topic: "t", Payload: []byte("payload")})
+
+	_, _, err := ReadRecord(bytes.NewReader(encoded[:len(encoded)-2]))
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestReadRecord_CorruptedRecord(t *testing.T) {
+	t.Parallel()
+
+	encoded, _ := EncodeRecord(&Record{Topic: "t", Payload: []byte("payload")})
+	encoded[len(encoded)-1]++
+
+	_, _, err := ReadRecord(bytes.NewReader(encoded))
+	require.Equal(t, ErrCorruptedRecord, err)
+}
+
+func TestReadRecord_TooLargeBodyLengthShouldBeCorrupted(t *testing.T) {
+	t.Parallel()
+
+	header := []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}
+	_, _, err := ReadRecord(bytes.NewReader(header))
+	require.Equal(t, ErrCorruptedRecord, err)
+}
@@ -0,0 +1,266 @@
+package replayer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const progressLogInterval = 1000
+
+var (
+	log = logger.GetOrCreate("process/replayer")
+
+	errNilPayloadHandler = errors.New("nil payload handler")
+	errNoFilesToReplay   = errors.New("no files to replay")
+	errInvalidSpeed      = errors.New("invalid replay speed")
+	errInvalidNonceRange = errors.New("invalid nonce range")
+)
+
+// Filter holds the criteria that a captured payload has to match in order to be replayed. Empty topics or shards
+// mean that all the topics or shards are accepted, while a zero end nonce means that there is no upper limit. The
+// nonce range is checked only for the payloads that carry a block header
+type Filter struct {
+	Topics     map[string]struct{}
+	Shards     map[uint32]struct{}
+	StartNonce uint64
+	EndNonce   uint64
+}
+
+// ArgsReplayer holds all the components needed to create a new instance of replayer
+type ArgsReplayer struct {
+	Files          []string
+	PayloadHandler wsindexer.PayloadHandler
+	Marshaller     marshal.Marshalizer
+	BlockContainer dataindexer.BlockContainerHandler
+	Filter         Filter
+	// Speed is the replay speed relative to the capture: 1 replays at the original pace, 2 twice as fast and 0 as
+	// fast as possible
+	Speed float64
+}
+
+// ReplayStats holds the number of payloads processed and skipped during a replay
+type ReplayStats struct {
+	NumProcessed uint64
+	NumSkipped   uint64
+}
+
+type replayer struct {
+	files          []string
+	payloadHandler wsindexer.PayloadHandler
+	marshaller     marshal.Marshalizer
+	blockContainer dataindexer.BlockContainerHandler
+	filter         Filter
+	speed          float64
+}
+
+// NewReplayer will create a new instance of replayer
+func NewReplayer(args ArgsReplayer) (*replayer, error) {
+	if len(args.Files) == 0 {
+		return nil, errNoFilesToReplay
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, errNilPayloadHandler
+	}
+	if check.IfNil(args.Marshaller) {
+		return nil, dataindexer.ErrNilMarshalizer
+	}
+	if check.IfNilReflect(args.BlockContainer) {
+		return nil, dataindexer.ErrNilBlockContainerHandler
+	}
+	if args.Speed < 0 {
+		return nil, errInvalidSpeed
+	}
+	if args.Filter.EndNonce != 0 && args.Filter.EndNonce < args.Filter.StartNonce {
+		return nil, errInvalidNonceRange
+	}
+
+	return &replayer{
+		files:          args.Files,
+		payloadHandler: args.PayloadHandler,
+		marshaller:     args.Marshaller,
+		blockContainer: args.BlockContainer,
+		filter:         args.Filter,
+		speed:          args.Speed,
+	}, nil
+}
+
+// Replay will feed all the captured payloads that match the filter to the payload handler, in the order they were
+// captured
+func (r *replayer) Replay(ctx context.Context) (*ReplayStats, error) {
+	stats := &ReplayStats{}
+	var lastTimestamp time.Time
+	for _, file := range r.files {
+		log.Info("replaying file", "file", file)
+
+		err := r.replayFile(ctx, file, stats, &lastTimestamp)
+		if err != nil {
+			return stats, fmt.Errorf("%w while replaying file %s", err, file)
+		}
+	}
+
+	log.Info("replay finished", "processed", stats.NumProcessed, "skipped", stats.NumSkipped)
+
+	return stats, nil
+}
+
+func (r *replayer) replayFile(ctx context.Context, filePath string, stats *ReplayStats, lastTimestamp *time.Time) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := bufio.NewReader(file)
+	for {
+		capturedPayload, errRead := wsindexer.ReadCapturedPayload(reader)
+		if errRead == io.EOF {
+			return nil
+		}
+		if errRead != nil {
+			return errRead
+		}
+
+		if !r.shouldReplay(capturedPayload) {
+			stats.NumSkipped++
+			continue
+		}
+
+		err = r.waitForPayload(ctx, capturedPayload.Timestamp, lastTimestamp)
+		if err != nil {
+			return err
+		}
+
+		err = r.payloadHandler.ProcessPayload(capturedPayload.Payload, capturedPayload.Topic, capturedPayload.Version)
+		if err != nil {
+			return fmt.Errorf("%w while processing a payload with topic %s captured at %s",
+				err, capturedPayload.Topic, capturedPayload.Timestamp.UTC().Format(time.RFC3339Nano))
+		}
+
+		stats.NumProcessed++
+		if stats.NumProcessed%progressLogInterval == 0 {
+			log.Info("replay progress", "processed", stats.NumProcessed, "skipped", stats.NumSkipped)
+		}
+	}
+}
+
+func (r *replayer) waitForPayload(ctx context.Context, timestamp time.Time, lastTimestamp *time.Time) error {
+	previous := *lastTimestamp
+	*lastTimestamp = timestamp
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if r.speed == 0 || previous.IsZero() || !timestamp.After(previous) {
+		return nil
+	}
+
+	delay := time.Duration(float64(timestamp.Sub(previous)) / r.speed)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *replayer) shouldReplay(capturedPayload *wsindexer.CapturedPayload) bool {
+	if capturedPayload.Topic == outport.TopicSettings {
+		return true
+	}
+	if len(r.filter.Topics) > 0 {
+		_, ok := r.filter.Topics[capturedPayload.Topic]
+		if !ok {
+			return false
+		}
+	}
+	if len(r.filter.Shards) > 0 {
+		shard := &outport.Shard{}
+		err := r.marshaller.Unmarshal(shard, capturedPayload.Payload)
+		if err != nil {
+			log.Warn("replayer: cannot get the shard ID from payload", "topic", capturedPayload.Topic, "error", err)
+			return false
+		}
+
+		_, ok := r.filter.Shards[shard.ShardID]
+		if !ok {
+			return false
+		}
+	}
+
+	return r.isInNonceRange(capturedPayload)
+}
+
+func (r *replayer) isInNonceRange(capturedPayload *wsindexer.CapturedPayload) bool {
+	hasNonceRange := r.filter.StartNonce != 0 || r.filter.EndNonce != 0
+	if !hasNonceRange {
+		return true
+	}
+
+	header, err := r.getHeader(capturedPayload)
+	if err != nil {
+		log.Warn("replayer: cannot get the header from payload", "topic", capturedPayload.Topic, "error", err)
+		return false
+	}
+	if check.IfNil(header) {
+		return true
+	}
+
+	nonce := header.GetNonce()
+	if nonce < r.filter.StartNonce {
+		return false
+	}
+
+	return r.filter.EndNonce == 0 || nonce <= r.filter.EndNonce
+}
+
+func (r *replayer) getHeader(capturedPayload *wsindexer.CapturedPayload) (coreData.HeaderHandler, error) {
+	var blockData *outport.BlockData
+	switch capturedPayload.Topic {
+	case outport.TopicSaveBlock:
+		outportBlock := &outport.OutportBlock{}
+		err := r.marshaller.Unmarshal(outportBlock, capturedPayload.Payload)
+		if err != nil {
+			return nil, err
+		}
+		blockData = outportBlock.BlockData
+	case outport.TopicRevertIndexedBlock:
+		blockData = &outport.BlockData{}
+		err := r.marshaller.Unmarshal(blockData, capturedPayload.Payload)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	if blockData == nil {
+		return nil, dataindexer.ErrNilHeaderHandler
+	}
+
+	creator, err := r.blockContainer.Get(core.HeaderType(blockData.HeaderType))
+	if err != nil {
+		return nil, err
+	}
+
+	return block.GetHeaderFromBytes(r.marshaller, creator, blockData.HeaderBytes)
+}
@@ -0,0 +1,172 @@
+package replayer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockReplayerArgs() ArgsReplayer {
+	return ArgsReplayer{
+		Files:          []string{"file"},
+		PayloadHandler: &mock.PayloadHandlerStub{},
+		Marshaller:     &marshal.JsonMarshalizer{},
+		BlockContainer: &mock.BlockContainerStub{
+			GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+				return dataBlock.NewEmptyHeaderV2Creator(), nil
+			},
+		},
+	}
+}
+
+func captureBlocks(t *testing.T, marshaller marshal.Marshalizer, shardID uint32, nonces ...uint64) []string {
+	dir := t.TempDir()
+	recorder, _ := wsindexer.NewPayloadRecorder(wsindexer.ArgsPayloadRecorder{Directory: dir, MaxFileSizeInBytes: 1 << 20})
+
+	for _, nonce := range nonces {
+		headerBytes, _ := marshaller.Marshal(&dataBlock.HeaderV2{Header: &dataBlock.Header{Nonce: nonce, ShardID: shardID}})
+		outportBlock := &outport.OutportBlock{
+			ShardID: shardID,
+			BlockData: &outport.BlockData{
+				ShardID:     shardID,
+				HeaderBytes: headerBytes,
+				HeaderType:  string(core.ShardHeaderV2),
+			},
+		}
+		payload, _ := marshaller.Marshal(outportBlock)
+		require.Nil(t, recorder.Record(payload, outport.TopicSaveBlock, 1))
+	}
+
+	rounds, _ := json.Marshal(&outport.RoundsInfo{ShardID: shardID})
+	require.Nil(t, recorder.Record(rounds, outport.TopicSaveRoundsInfo, 1))
+	require.Nil(t, recorder.Close())
+
+	files, _ := wsindexer.GetCaptureFiles(dir)
+	return files
+}
+
+func TestNewReplayer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no files should error", func(t *testing.T) {
+		args := createMockReplayerArgs()
+		args.Files = nil
+
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, errNoFilesToReplay, err)
+	})
+	t.Run("nil payload handler should error", func(t *testing.T) {
+		args := createMockReplayerArgs()
+		args.PayloadHandler = nil
+
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, errNilPayloadHandler, err)
+	})
+	t.Run("nil marshaller should error", func(t *testing.T) {
+		args := createMockReplayerArgs()
+		args.Marshaller = nil
+
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+	})
+	t.Run("negative speed should error", func(t *testing.T) {
+		args := createMockReplayerArgs()
+		args.Speed = -1
+
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, errInvalidSpeed, err)
+	})
+	t.Run("invalid nonce range should error", func(t *testing.T) {
+		args := createMockReplayerArgs()
+		args.Filter.StartNonce = 10
+		args.Filter.EndNonce = 5
+
+		r, err := NewReplayer(args)
+		require.Nil(t, r)
+		require.Equal(t, errInvalidNonceRange, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		r, err := NewReplayer(createMockReplayerArgs())
+		require.Nil(t, err)
+		require.NotNil(t, r)
+	})
+}
+
+func TestReplayer_ReplayAll(t *testing.T) {
+	t.Parallel()
+
+	args := createMockReplayerArgs()
+	args.Files = captureBlocks(t, args.Marshaller, 1, 1, 2, 3)
+
+	topics := make([]string, 0)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			topics = append(topics, topic)
+			return nil
+		},
+	}
+
+	r, _ := NewReplayer(args)
+	stats, err := r.Replay(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, &ReplayStats{NumProcessed: 4}, stats)
+	require.Equal(t, []string{outport.TopicSaveBlock, outport.TopicSaveBlock, outport.TopicSaveBlock, outport.TopicSaveRoundsInfo}, topics)
+}
+
+func TestReplayer_ReplayWithFilters(t *testing.T) {
+	t.Parallel()
+
+	args := createMockReplayerArgs()
+	args.Files = append(captureBlocks(t, args.Marshaller, 1, 1, 2, 3, 4), captureBlocks(t, args.Marshaller, 2, 2, 3)...)
+	args.Filter = Filter{
+		Topics:     map[string]struct{}{outport.TopicSaveBlock: {}},
+		Shards:     map[uint32]struct{}{1: {}},
+		StartNonce: 2,
+		EndNonce:   3,
+	}
+
+	nonces := make([]uint64, 0)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			outportBlock := &outport.OutportBlock{}
+			_ = args.Marshaller.Unmarshal(outportBlock, payload)
+			header := &dataBlock.HeaderV2{}
+			_ = args.Marshaller.Unmarshal(header, outportBlock.BlockData.HeaderBytes)
+			nonces = append(nonces, header.GetNonce())
+			return nil
+		},
+	}
+
+	r, _ := NewReplayer(args)
+	stats, err := r.Replay(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, &ReplayStats{NumProcessed: 2, NumSkipped: 6}, stats)
+	require.Equal(t, []uint64{2, 3}, nonces)
+}
+
+func TestReplayer_ReplayCancelledShouldError(t *testing.T) {
+	t.Parallel()
+
+	args := createMockReplayerArgs()
+	args.Files = captureBlocks(t, args.Marshaller, 1, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r, _ := NewReplayer(args)
+	_, err := r.Replay(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
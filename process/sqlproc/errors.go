@@ -0,0 +1,5 @@
+package sqlproc
+
+import "errors"
+
+var errInvalidMigrationName = errors.New("the name of the migration file does not start with its version")
@@ -0,0 +1,93 @@
+package factory
+
+import (
+	"database/sql"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/hashing"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/accounts"
+	blockProc "github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/logsevents"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/miniblocks"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/transactions"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/sqlproc"
+)
+
+// ArgSQLProcessorFactory is struct that is used to store all components that are needed to create a sql processor
+type ArgSQLProcessorFactory struct {
+	Marshalizer              marshal.Marshalizer
+	Hasher                   hashing.Hasher
+	AddressPubkeyConverter   core.PubkeyConverter
+	ValidatorPubkeyConverter core.PubkeyConverter
+	DB                       *sql.DB
+	EnabledIndexes           []string
+	Denomination             int
+	ImportDB                 bool
+}
+
+// CreateSQLProcessor will create a new instance of the processor that saves the data in a PostgreSQL database. The data
+// is prepared by the same processors as in the elastic processor
+func CreateSQLProcessor(arguments ArgSQLProcessorFactory) (dataindexer.ElasticProcessor, error) {
+	enabledIndexesMap := make(map[string]struct{})
+	for _, index := range arguments.EnabledIndexes {
+		enabledIndexesMap[index] = struct{}{}
+	}
+	if len(enabledIndexesMap) == 0 {
+		return nil, dataindexer.ErrEmptyEnabledIndexes
+	}
+
+	balanceConverter, err := converters.NewBalanceConverter(arguments.Denomination)
+	if err != nil {
+		return nil, err
+	}
+
+	accountsProc, err := accounts.NewAccountsProcessor(arguments.AddressPubkeyConverter, balanceConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	blockProcHandler, err := blockProc.NewBlockProcessor(arguments.Hasher, arguments.Marshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	miniblocksProc, err := miniblocks.NewMiniblocksProcessor(arguments.Hasher, arguments.Marshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	txsProc, err := transactions.NewTransactionsProcessor(&transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: arguments.AddressPubkeyConverter,
+		Hasher:                 arguments.Hasher,
+		Marshalizer:            arguments.Marshalizer,
+		BalanceConverter:       balanceConverter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logsAndEventsProc, err := logsevents.NewLogsAndEventsProcessor(logsevents.ArgsLogsAndEventsProcessor{
+		PubKeyConverter:  arguments.AddressPubkeyConverter,
+		Marshalizer:      arguments.Marshalizer,
+		BalanceConverter: balanceConverter,
+		Hasher:           arguments.Hasher,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlproc.NewSQLProcessor(sqlproc.ArgsSQLProcessor{
+		DB:                       arguments.DB,
+		EnabledIndexes:           enabledIndexesMap,
+		TransactionsProc:         txsProc,
+		AccountsProc:             accountsProc,
+		BlockProc:                blockProcHandler,
+		MiniblocksProc:           miniblocksProc,
+		LogsAndEventsProc:        logsAndEventsProc,
+		ValidatorPubkeyConverter: arguments.ValidatorPubkeyConverter,
+		ImportDB:                 arguments.ImportDB,
+	})
+}
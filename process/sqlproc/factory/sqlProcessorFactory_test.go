@@ -0,0 +1,52 @@
+package factory
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgs() ArgSQLProcessorFactory {
+	connector := &mock.SQLConnectorStub{
+		QueryCalled: func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+			return []string{"exists"}, [][]driver.Value{{true}}, nil
+		},
+	}
+
+	return ArgSQLProcessorFactory{
+		Marshalizer:              &mock.MarshalizerMock{},
+		Hasher:                   &mock.HasherMock{},
+		AddressPubkeyConverter:   mock.NewPubkeyConverterMock(32),
+		ValidatorPubkeyConverter: &mock.PubkeyConverterMock{},
+		DB:                       sql.OpenDB(connector),
+		EnabledIndexes:           []string{"blocks"},
+		Denomination:             1,
+	}
+}
+
+func TestCreateSQLProcessor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no enabled indexes should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgs()
+		args.EnabledIndexes = nil
+
+		sp, err := CreateSQLProcessor(args)
+		require.Nil(t, sp)
+		require.Equal(t, dataindexer.ErrEmptyEnabledIndexes, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		sp, err := CreateSQLProcessor(createMockArgs())
+		require.Nil(t, err)
+		require.NotNil(t, sp)
+	})
+}
@@ -0,0 +1,95 @@
+package sqlproc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// maxParametersPerStatement is the maximum number of parameters PostgreSQL accepts in a statement
+const maxParametersPerStatement = 65535
+
+// insertStatement inserts many rows in a table with as few statements as possible
+type insertStatement struct {
+	table   string
+	columns []string
+	// onConflict is the clause that handles the rows that are already in the table, e.g. ON CONFLICT (hash) DO NOTHING
+	onConflict string
+}
+
+// exec inserts the provided rows. Every row holds a value for every column of the statement, in the same order
+func (is *insertStatement) exec(ctx context.Context, tx *sql.Tx, rows [][]interface{}) error {
+	maxRowsPerStatement := maxParametersPerStatement / len(is.columns)
+	for start := 0; start < len(rows); start += maxRowsPerStatement {
+		end := start + maxRowsPerStatement
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		query, args := is.build(rows[start:end])
+		_, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("%w when inserting in the %s table", err, is.table)
+		}
+	}
+
+	return nil
+}
+
+func (is *insertStatement) build(rows [][]interface{}) (string, []interface{}) {
+	args := make([]interface{}, 0, len(rows)*len(is.columns))
+
+	query := &strings.Builder{}
+	query.WriteString("INSERT INTO " + is.table + " (" + strings.Join(is.columns, ", ") + ") VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+
+		query.WriteString("(")
+		for j := range row {
+			if j > 0 {
+				query.WriteString(", ")
+			}
+			args = append(args, row[j])
+			query.WriteString(fmt.Sprintf("$%d", len(args)))
+		}
+		query.WriteString(")")
+	}
+	if is.onConflict != "" {
+		query.WriteString(" " + is.onConflict)
+	}
+
+	return query.String(), args
+}
+
+// upsertClause returns the clause that replaces the provided columns of a row that is already in the table
+func upsertClause(conflictTarget string, columns ...string) string {
+	assignments := make([]string, 0, len(columns))
+	for _, column := range columns {
+		assignments = append(assignments, column+" = EXCLUDED."+column)
+	}
+
+	return "ON CONFLICT (" + conflictTarget + ") DO UPDATE SET " + strings.Join(assignments, ", ")
+}
+
+// inTransaction runs the handler in a database transaction, which is committed only if the handler succeeds
+func inTransaction(ctx context.Context, db *sql.DB, handler func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = handler(tx)
+	if err != nil {
+		errRollback := tx.Rollback()
+		if errRollback != nil {
+			log.Warn("sqlproc: cannot roll back the database transaction", "error", errRollback)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,121 @@
+package sqlproc
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationsLockID is the key of the advisory lock that keeps the indexers sharing the same database from applying the
+// migrations at the same time
+const migrationsLockID = 7305623188
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    INTEGER PRIMARY KEY,
+    name       TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type migration struct {
+	version int
+	name    string
+	script  string
+}
+
+// loadMigrations returns the embedded migrations, sorted by version. The name of every migration file starts with its
+// version, e.g. 0001_create_tables.sql
+func loadMigrations() ([]*migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		versionStr, _, found := strings.Cut(name, "_")
+		version, errParse := strconv.Atoi(versionStr)
+		if !found || errParse != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidMigrationName, name)
+		}
+
+		script, errRead := migrationFiles.ReadFile(path.Join("migrations", name))
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		migrations = append(migrations, &migration{
+			version: version,
+			name:    name,
+			script:  string(script),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// applyMigrations applies, in order, the migrations that were not applied yet. Every migration is applied in its own
+// transaction, together with its record in the schema_migrations table
+func applyMigrations(ctx context.Context, db *sql.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, createMigrationsTable)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		err = applyMigration(ctx, db, m)
+		if err != nil {
+			return fmt.Errorf("%w when applying the %s migration", err, m.name)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m *migration) error {
+	return inTransaction(ctx, db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationsLockID)
+		if err != nil {
+			return err
+		}
+
+		var isApplied bool
+		err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, m.version).Scan(&isApplied)
+		if err != nil {
+			return err
+		}
+		if isApplied {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, m.script)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.version, m.name)
+		if err != nil {
+			return err
+		}
+
+		log.Info("applied the database migration", "name", m.name)
+		return nil
+	})
+}
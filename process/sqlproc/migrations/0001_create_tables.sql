@@ -0,0 +1,213 @@
+-- The shard IDs are stored as BIGINT, as the metachain shard ID does not fit in an INTEGER. The amounts are stored as
+-- NUMERIC, as they do not fit in a BIGINT
+
+CREATE TABLE blocks (
+    hash                TEXT PRIMARY KEY,
+    nonce               BIGINT NOT NULL,
+    round               BIGINT NOT NULL,
+    epoch               BIGINT NOT NULL,
+    shard_id            BIGINT NOT NULL,
+    proposer            BIGINT NOT NULL,
+    timestamp           BIGINT NOT NULL,
+    prev_hash           TEXT NOT NULL,
+    state_root_hash     TEXT NOT NULL,
+    tx_count            BIGINT NOT NULL,
+    notarized_txs_count BIGINT NOT NULL,
+    size                BIGINT NOT NULL,
+    size_txs            BIGINT NOT NULL,
+    accumulated_fees    NUMERIC NOT NULL,
+    developer_fees      NUMERIC NOT NULL,
+    epoch_start_block   BOOLEAN NOT NULL,
+    mini_blocks_hashes  TEXT[] NOT NULL,
+    finalized_at        BIGINT,
+    document            JSONB NOT NULL
+);
+CREATE INDEX blocks_shard_id_nonce_idx ON blocks (shard_id, nonce);
+CREATE INDEX blocks_timestamp_idx ON blocks (timestamp);
+
+CREATE TABLE miniblocks (
+    hash                TEXT PRIMARY KEY,
+    sender_shard_id     BIGINT NOT NULL,
+    receiver_shard_id   BIGINT NOT NULL,
+    sender_block_hash   TEXT NOT NULL,
+    receiver_block_hash TEXT NOT NULL,
+    type                TEXT NOT NULL,
+    proc_type_s         TEXT NOT NULL,
+    proc_type_d         TEXT NOT NULL,
+    timestamp           BIGINT NOT NULL,
+    finalized_at        BIGINT
+);
+CREATE INDEX miniblocks_sender_block_hash_idx ON miniblocks (sender_block_hash);
+CREATE INDEX miniblocks_receiver_block_hash_idx ON miniblocks (receiver_block_hash);
+
+CREATE TABLE transactions (
+    hash              TEXT PRIMARY KEY,
+    miniblock_hash    TEXT NOT NULL,
+    nonce             BIGINT NOT NULL,
+    round             BIGINT NOT NULL,
+    value             NUMERIC NOT NULL,
+    sender            TEXT NOT NULL,
+    receiver          TEXT NOT NULL,
+    sender_shard_id   BIGINT NOT NULL,
+    receiver_shard_id BIGINT NOT NULL,
+    gas_price         BIGINT NOT NULL,
+    gas_limit         BIGINT NOT NULL,
+    gas_used          BIGINT NOT NULL,
+    fee               NUMERIC NOT NULL,
+    data              BYTEA,
+    signature         TEXT NOT NULL,
+    timestamp         BIGINT NOT NULL,
+    status            TEXT NOT NULL,
+    type              TEXT NOT NULL,
+    operation         TEXT NOT NULL,
+    function          TEXT NOT NULL,
+    is_sc_call        BOOLEAN NOT NULL,
+    is_relayed        BOOLEAN NOT NULL,
+    has_sc_results    BOOLEAN NOT NULL,
+    has_logs          BOOLEAN NOT NULL,
+    tokens            TEXT[] NOT NULL,
+    esdt_values       TEXT[] NOT NULL,
+    receivers         TEXT[] NOT NULL,
+    guardian          TEXT NOT NULL,
+    error_event       BOOLEAN NOT NULL,
+    completed_event   BOOLEAN NOT NULL,
+    finalized_at      BIGINT
+);
+CREATE INDEX transactions_miniblock_hash_idx ON transactions (miniblock_hash);
+CREATE INDEX transactions_sender_idx ON transactions (sender, timestamp);
+CREATE INDEX transactions_receiver_idx ON transactions (receiver, timestamp);
+CREATE INDEX transactions_timestamp_idx ON transactions (timestamp);
+
+CREATE TABLE sc_results (
+    hash              TEXT PRIMARY KEY,
+    miniblock_hash    TEXT NOT NULL,
+    nonce             BIGINT NOT NULL,
+    gas_limit         BIGINT NOT NULL,
+    gas_price         BIGINT NOT NULL,
+    value             NUMERIC NOT NULL,
+    sender            TEXT NOT NULL,
+    receiver          TEXT NOT NULL,
+    sender_shard_id   BIGINT NOT NULL,
+    receiver_shard_id BIGINT NOT NULL,
+    relayer           TEXT NOT NULL,
+    original_sender   TEXT NOT NULL,
+    prev_tx_hash      TEXT NOT NULL,
+    original_tx_hash  TEXT NOT NULL,
+    call_type         TEXT NOT NULL,
+    data              BYTEA,
+    return_message    TEXT NOT NULL,
+    timestamp         BIGINT NOT NULL,
+    status            TEXT NOT NULL,
+    type              TEXT NOT NULL,
+    operation         TEXT NOT NULL,
+    function          TEXT NOT NULL,
+    tokens            TEXT[] NOT NULL,
+    esdt_values       TEXT[] NOT NULL
+);
+CREATE INDEX sc_results_original_tx_hash_idx ON sc_results (original_tx_hash);
+CREATE INDEX sc_results_sender_idx ON sc_results (sender, timestamp);
+CREATE INDEX sc_results_receiver_idx ON sc_results (receiver, timestamp);
+
+CREATE TABLE receipts (
+    hash      TEXT PRIMARY KEY,
+    tx_hash   TEXT NOT NULL,
+    sender    TEXT NOT NULL,
+    value     NUMERIC NOT NULL,
+    data      TEXT NOT NULL,
+    timestamp BIGINT NOT NULL
+);
+CREATE INDEX receipts_tx_hash_idx ON receipts (tx_hash);
+
+CREATE TABLE logs (
+    id               TEXT PRIMARY KEY,
+    original_tx_hash TEXT NOT NULL,
+    address          TEXT NOT NULL,
+    timestamp        BIGINT NOT NULL,
+    events           JSONB NOT NULL
+);
+
+CREATE TABLE events (
+    id               TEXT PRIMARY KEY,
+    tx_hash          TEXT NOT NULL,
+    original_tx_hash TEXT NOT NULL,
+    log_address      TEXT NOT NULL,
+    address          TEXT NOT NULL,
+    identifier       TEXT NOT NULL,
+    data             TEXT NOT NULL,
+    additional_data  TEXT[] NOT NULL,
+    topics           TEXT[] NOT NULL,
+    event_order      INTEGER NOT NULL,
+    tx_order         INTEGER NOT NULL,
+    shard_id         BIGINT NOT NULL,
+    timestamp        BIGINT NOT NULL
+);
+CREATE INDEX events_tx_hash_idx ON events (tx_hash);
+CREATE INDEX events_address_identifier_idx ON events (address, identifier);
+CREATE INDEX events_shard_id_timestamp_idx ON events (shard_id, timestamp);
+
+CREATE TABLE accounts (
+    address           TEXT PRIMARY KEY,
+    nonce             BIGINT NOT NULL,
+    balance           NUMERIC NOT NULL,
+    user_name         TEXT NOT NULL,
+    owner             TEXT NOT NULL,
+    developer_rewards NUMERIC NOT NULL,
+    root_hash         BYTEA,
+    code_hash         BYTEA,
+    code_metadata     BYTEA,
+    shard_id          BIGINT NOT NULL,
+    timestamp         BIGINT NOT NULL
+);
+
+CREATE TABLE accounts_esdt (
+    address          TEXT NOT NULL,
+    token            TEXT NOT NULL,
+    token_nonce      BIGINT NOT NULL,
+    token_identifier TEXT NOT NULL,
+    balance          NUMERIC NOT NULL,
+    properties       TEXT NOT NULL,
+    frozen           BOOLEAN NOT NULL,
+    metadata         JSONB,
+    shard_id         BIGINT NOT NULL,
+    timestamp        BIGINT NOT NULL,
+    PRIMARY KEY (address, token, token_nonce)
+);
+CREATE INDEX accounts_esdt_token_idx ON accounts_esdt (token, token_nonce);
+CREATE INDEX accounts_esdt_shard_id_timestamp_idx ON accounts_esdt (shard_id, timestamp);
+
+CREATE TABLE rounds (
+    shard_id           BIGINT NOT NULL,
+    round              BIGINT NOT NULL,
+    epoch              BIGINT NOT NULL,
+    block_was_proposed BOOLEAN NOT NULL,
+    signers_indexes    BIGINT[] NOT NULL,
+    timestamp          BIGINT NOT NULL,
+    PRIMARY KEY (shard_id, round)
+);
+
+CREATE TABLE validators_rating (
+    public_key TEXT NOT NULL,
+    epoch      BIGINT NOT NULL,
+    shard_id   BIGINT NOT NULL,
+    rating     REAL NOT NULL,
+    PRIMARY KEY (public_key, epoch)
+);
+
+CREATE TABLE shard_validators (
+    shard_id    BIGINT NOT NULL,
+    epoch       BIGINT NOT NULL,
+    public_keys TEXT[] NOT NULL,
+    PRIMARY KEY (shard_id, epoch)
+);
+
+CREATE TABLE indexing_checkpoints (
+    shard_id  BIGINT PRIMARY KEY,
+    nonce     BIGINT NOT NULL,
+    hash      TEXT NOT NULL,
+    timestamp BIGINT NOT NULL
+);
+
+CREATE TABLE last_final_nonces (
+    shard_id BIGINT PRIMARY KEY,
+    nonce    BIGINT NOT NULL
+);
@@ -0,0 +1,266 @@
+package sqlproc
+
+import (
+	"encoding/json"
+
+	"github.com/lib/pq"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+var (
+	blockColumns = []string{
+		"hash", "nonce", "round", "epoch", "shard_id", "proposer", "timestamp", "prev_hash", "state_root_hash", "tx_count",
+		"notarized_txs_count", "size", "size_txs", "accumulated_fees", "developer_fees", "epoch_start_block",
+		"mini_blocks_hashes", "document",
+	}
+	blocksInsert = &insertStatement{
+		table:      "blocks",
+		columns:    blockColumns,
+		onConflict: upsertClause("hash", blockColumns[1:]...),
+	}
+
+	checkpointColumns = []string{"shard_id", "nonce", "hash", "timestamp"}
+	checkpointsInsert = &insertStatement{
+		table:      "indexing_checkpoints",
+		columns:    checkpointColumns,
+		onConflict: upsertClause("shard_id", checkpointColumns[1:]...),
+	}
+
+	lastFinalNonceColumns = []string{"shard_id", "nonce"}
+	lastFinalNonceInsert  = &insertStatement{
+		table:      "last_final_nonces",
+		columns:    lastFinalNonceColumns,
+		onConflict: upsertClause("shard_id", lastFinalNonceColumns[1:]...),
+	}
+
+	miniblockColumns = []string{
+		"hash", "sender_shard_id", "receiver_shard_id", "sender_block_hash", "receiver_block_hash", "type", "proc_type_s",
+		"proc_type_d", "timestamp",
+	}
+	// a miniblock is saved by both its sender and its receiver shards, and each of them fills only its own block hash
+	// and processing type in a miniblock that is already saved
+	miniblocksInsertOnSender = &insertStatement{
+		table:      "miniblocks",
+		columns:    miniblockColumns,
+		onConflict: upsertClause("hash", "sender_block_hash", "proc_type_s"),
+	}
+	miniblocksInsertOnReceiver = &insertStatement{
+		table:      "miniblocks",
+		columns:    miniblockColumns,
+		onConflict: upsertClause("hash", "receiver_block_hash", "proc_type_d"),
+	}
+
+	transactionColumns = []string{
+		"hash", "miniblock_hash", "nonce", "round", "value", "sender", "receiver", "sender_shard_id", "receiver_shard_id",
+		"gas_price", "gas_limit", "gas_used", "fee", "data", "signature", "timestamp", "status", "type", "operation",
+		"function", "is_sc_call", "is_relayed", "has_sc_results", "has_logs", "tokens", "esdt_values", "receivers",
+		"guardian", "error_event", "completed_event",
+	}
+	transactionsInsert = &insertStatement{
+		table:      "transactions",
+		columns:    transactionColumns,
+		onConflict: upsertClause("hash", transactionColumns[1:]...),
+	}
+	// the cross-shard transactions are saved by the source shard only if the destination shard did not save them yet,
+	// as the destination shard knows their final status
+	transactionsInsertOnSource = &insertStatement{
+		table:      "transactions",
+		columns:    transactionColumns,
+		onConflict: "ON CONFLICT (hash) DO NOTHING",
+	}
+
+	scResultColumns = []string{
+		"hash", "miniblock_hash", "nonce", "gas_limit", "gas_price", "value", "sender", "receiver", "sender_shard_id",
+		"receiver_shard_id", "relayer", "original_sender", "prev_tx_hash", "original_tx_hash", "call_type", "data",
+		"return_message", "timestamp", "status", "type", "operation", "function", "tokens", "esdt_values",
+	}
+	scResultsInsert = &insertStatement{
+		table:      "sc_results",
+		columns:    scResultColumns,
+		onConflict: upsertClause("hash", scResultColumns[1:]...),
+	}
+
+	receiptColumns = []string{"hash", "tx_hash", "sender", "value", "data", "timestamp"}
+	receiptsInsert = &insertStatement{
+		table:      "receipts",
+		columns:    receiptColumns,
+		onConflict: upsertClause("hash", receiptColumns[1:]...),
+	}
+
+	logColumns = []string{"id", "original_tx_hash", "address", "timestamp", "events"}
+	logsInsert = &insertStatement{
+		table:      "logs",
+		columns:    logColumns,
+		onConflict: upsertClause("id", logColumns[1:]...),
+	}
+
+	eventColumns = []string{
+		"id", "tx_hash", "original_tx_hash", "log_address", "address", "identifier", "data", "additional_data", "topics",
+		"event_order", "tx_order", "shard_id", "timestamp",
+	}
+	eventsInsert = &insertStatement{
+		table:      "events",
+		columns:    eventColumns,
+		onConflict: upsertClause("id", eventColumns[1:]...),
+	}
+
+	accountColumns = []string{
+		"address", "nonce", "balance", "user_name", "owner", "developer_rewards", "root_hash", "code_hash", "code_metadata",
+		"shard_id", "timestamp",
+	}
+	accountsInsert = &insertStatement{
+		table:      "accounts",
+		columns:    accountColumns,
+		onConflict: upsertClause("address", accountColumns[1:]...),
+	}
+
+	// the fungible tokens have no token identifier, so the balances are keyed by the token and its nonce, like the
+	// documents of the accountsesdt index
+	accountESDTColumns = []string{
+		"address", "token", "token_nonce", "token_identifier", "balance", "properties", "frozen", "metadata", "shard_id",
+		"timestamp",
+	}
+	accountsESDTInsert = &insertStatement{
+		table:      "accounts_esdt",
+		columns:    accountESDTColumns,
+		onConflict: upsertClause("address, token, token_nonce", accountESDTColumns[3:]...),
+	}
+
+	roundColumns = []string{"shard_id", "round", "epoch", "block_was_proposed", "signers_indexes", "timestamp"}
+	roundsInsert = &insertStatement{
+		table:      "rounds",
+		columns:    roundColumns,
+		onConflict: upsertClause("shard_id, round", roundColumns[2:]...),
+	}
+
+	validatorRatingColumns = []string{"public_key", "epoch", "shard_id", "rating"}
+	validatorsRatingInsert = &insertStatement{
+		table:      "validators_rating",
+		columns:    validatorRatingColumns,
+		onConflict: upsertClause("public_key, epoch", validatorRatingColumns[2:]...),
+	}
+
+	shardValidatorsColumns = []string{"shard_id", "epoch", "public_keys"}
+	shardValidatorsInsert  = &insertStatement{
+		table:      "shard_validators",
+		columns:    shardValidatorsColumns,
+		onConflict: upsertClause("shard_id, epoch", shardValidatorsColumns[2:]...),
+	}
+)
+
+func blockRow(block *data.Block) ([]interface{}, error) {
+	document, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		block.Hash, block.Nonce, block.Round, block.Epoch, block.ShardID, block.Proposer, int64(block.Timestamp),
+		block.PrevHash, block.StateRootHash, block.TxCount, block.NotarizedTxsCount, block.Size, block.SizeTxs,
+		numeric(block.AccumulatedFees), numeric(block.DeveloperFees), block.EpochStartBlock,
+		textArray(block.MiniBlocksHashes), string(document),
+	}, nil
+}
+
+func miniblockRow(mb *data.Miniblock) []interface{} {
+	return []interface{}{
+		mb.Hash, mb.SenderShardID, mb.ReceiverShardID, mb.SenderBlockHash, mb.ReceiverBlockHash, mb.Type,
+		mb.ProcessingTypeOnSource, mb.ProcessingTypeOnDestination, int64(mb.Timestamp),
+	}
+}
+
+func transactionRow(tx *data.Transaction) []interface{} {
+	return []interface{}{
+		tx.Hash, tx.MBHash, tx.Nonce, tx.Round, numeric(tx.Value), tx.Sender, tx.Receiver, tx.SenderShard,
+		tx.ReceiverShard, tx.GasPrice, tx.GasLimit, tx.GasUsed, numeric(tx.Fee), tx.Data, tx.Signature,
+		int64(tx.Timestamp), tx.Status, tx.Type, tx.Operation, tx.Function, tx.IsScCall, tx.IsRelayed, tx.HasSCR,
+		tx.HasLogs, textArray(tx.Tokens), textArray(tx.ESDTValues), textArray(tx.Receivers), tx.GuardianAddress,
+		tx.ErrorEvent, tx.CompletedEvent,
+	}
+}
+
+func scResultRow(scr *data.ScResult) []interface{} {
+	return []interface{}{
+		scr.Hash, scr.MBHash, scr.Nonce, scr.GasLimit, scr.GasPrice, numeric(scr.Value), scr.Sender, scr.Receiver,
+		scr.SenderShard, scr.ReceiverShard, scr.RelayerAddr, scr.OriginalSender, scr.PrevTxHash, scr.OriginalTxHash,
+		scr.CallType, scr.Data, scr.ReturnMessage, int64(scr.Timestamp), scr.Status, scr.Type, scr.Operation,
+		scr.Function, textArray(scr.Tokens), textArray(scr.ESDTValues),
+	}
+}
+
+func receiptRow(receipt *data.Receipt) []interface{} {
+	return []interface{}{
+		receipt.Hash, receipt.TxHash, receipt.Sender, numeric(receipt.Value), receipt.Data, int64(receipt.Timestamp),
+	}
+}
+
+func logRow(logData *data.Logs) ([]interface{}, error) {
+	events, err := json.Marshal(logData.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		logData.ID, logData.OriginalTxHash, logData.Address, int64(logData.Timestamp), string(events),
+	}, nil
+}
+
+func eventRow(event *data.LogEvent) []interface{} {
+	return []interface{}{
+		event.ID, event.TxHash, event.OriginalTxHash, event.LogAddress, event.Address, event.Identifier, event.Data,
+		textArray(event.AdditionalData), textArray(event.Topics), event.Order, event.TxOrder, event.ShardID,
+		int64(event.Timestamp),
+	}
+}
+
+func accountRow(account *data.AccountInfo) []interface{} {
+	return []interface{}{
+		account.Address, account.Nonce, numeric(account.Balance), account.UserName, account.Owner,
+		numeric(account.DeveloperRewards), account.RootHash, account.CodeHash, account.CodeMetadata, account.ShardID,
+		int64(account.Timestamp),
+	}
+}
+
+func accountESDTRow(account *data.AccountInfo) ([]interface{}, error) {
+	var metadata interface{}
+	if account.Data != nil {
+		metadataBytes, err := json.Marshal(account.Data)
+		if err != nil {
+			return nil, err
+		}
+		metadata = string(metadataBytes)
+	}
+
+	return []interface{}{
+		account.Address, account.TokenName, account.TokenNonce, account.TokenIdentifier, numeric(account.Balance),
+		account.Properties, account.Frozen, metadata, account.ShardID, int64(account.Timestamp),
+	}, nil
+}
+
+// numeric returns the value of a NUMERIC column. The amounts that are not set are saved as 0
+func numeric(value string) string {
+	if value == "" {
+		return "0"
+	}
+
+	return value
+}
+
+// textArray returns the value of a TEXT[] column. A nil slice is saved as an empty array instead of NULL
+func textArray(values []string) pq.StringArray {
+	if values == nil {
+		return pq.StringArray{}
+	}
+
+	return values
+}
+
+// bigintArray returns the value of a BIGINT[] column
+func bigintArray(values []uint64) pq.Int64Array {
+	array := make(pq.Int64Array, 0, len(values))
+	for _, value := range values {
+		array = append(array, int64(value))
+	}
+
+	return array
+}
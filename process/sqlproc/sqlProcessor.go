@@ -0,0 +1,630 @@
+package sqlproc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	indexerBlock "github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tags"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("indexer/process/sqlproc")
+
+// ArgsSQLProcessor holds all dependencies required by the sqlProcessor in order to create new instances
+type ArgsSQLProcessor struct {
+	DB *sql.DB
+	// EnabledIndexes holds the Elasticsearch indices whose data is saved. Every index has its own table, while the
+	// indices that do not have a table, e.g. the tokens or the operations, are ignored
+	EnabledIndexes           map[string]struct{}
+	TransactionsProc         elasticproc.DBTransactionsHandler
+	AccountsProc             elasticproc.DBAccountHandler
+	BlockProc                elasticproc.DBBlockHandler
+	MiniblocksProc           elasticproc.DBMiniblocksHandler
+	LogsAndEventsProc        elasticproc.DBLogsAndEventsHandler
+	ValidatorPubkeyConverter core.PubkeyConverter
+	ImportDB                 bool
+}
+
+type sqlProcessor struct {
+	importDB                 bool
+	mutex                    sync.RWMutex
+	db                       *sql.DB
+	enabledIndexes           map[string]struct{}
+	transactionsProc         elasticproc.DBTransactionsHandler
+	accountsProc             elasticproc.DBAccountHandler
+	blockProc                elasticproc.DBBlockHandler
+	miniblocksProc           elasticproc.DBMiniblocksHandler
+	logsAndEventsProc        elasticproc.DBLogsAndEventsHandler
+	validatorPubkeyConverter core.PubkeyConverter
+}
+
+// NewSQLProcessor will create a new instance of sqlProcessor, which saves the same data as the elastic processor in
+// the tables of a PostgreSQL database. The migrations of the database schema that were not applied yet are applied
+func NewSQLProcessor(args ArgsSQLProcessor) (*sqlProcessor, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyMigrations(context.Background(), args.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlProcessor{
+		importDB:                 args.ImportDB,
+		db:                       args.DB,
+		enabledIndexes:           args.EnabledIndexes,
+		transactionsProc:         args.TransactionsProc,
+		accountsProc:             args.AccountsProc,
+		blockProc:                args.BlockProc,
+		miniblocksProc:           args.MiniblocksProc,
+		logsAndEventsProc:        args.LogsAndEventsProc,
+		validatorPubkeyConverter: args.ValidatorPubkeyConverter,
+	}, nil
+}
+
+func checkArgs(args ArgsSQLProcessor) error {
+	if args.DB == nil {
+		return dataindexer.ErrNilSQLDatabase
+	}
+	if args.EnabledIndexes == nil {
+		return dataindexer.ErrNilEnabledIndexesMap
+	}
+	if check.IfNilReflect(args.TransactionsProc) {
+		return dataindexer.ErrNilTransactionsHandler
+	}
+	if check.IfNilReflect(args.AccountsProc) {
+		return dataindexer.ErrNilAccountsHandler
+	}
+	if check.IfNilReflect(args.BlockProc) {
+		return dataindexer.ErrNilBlockHandler
+	}
+	if check.IfNilReflect(args.MiniblocksProc) {
+		return dataindexer.ErrNilMiniblocksHandler
+	}
+	if check.IfNilReflect(args.LogsAndEventsProc) {
+		return dataindexer.ErrNilLogsAndEventsHandler
+	}
+	if check.IfNil(args.ValidatorPubkeyConverter) {
+		return dataindexer.ErrNilPubkeyConverter
+	}
+
+	return nil
+}
+
+// SaveHeader will prepare and save a block
+func (sp *sqlProcessor) SaveHeader(ctx context.Context, outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+	if !sp.isIndexEnabled(dataindexer.BlockIndex) {
+		return nil
+	}
+
+	dbBlock, err := sp.blockProc.PrepareBlockForDB(outportBlockWithHeader)
+	if err != nil {
+		return err
+	}
+
+	row, err := blockRow(dbBlock)
+	if err != nil {
+		return err
+	}
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		return blocksInsert.exec(ctx, tx, [][]interface{}{row})
+	})
+}
+
+// SaveIndexingCheckpoint will save the last indexed block of the provided shard. It should be called only after all
+// the data of the block was saved
+func (sp *sqlProcessor) SaveIndexingCheckpoint(ctx context.Context, shardID uint32, checkpoint *data.IndexingCheckpoint) error {
+	if !sp.isIndexEnabled(dataindexer.ValuesIndex) {
+		return nil
+	}
+	if checkpoint == nil {
+		return dataindexer.ErrNilIndexingCheckpoint
+	}
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		row := []interface{}{shardID, checkpoint.Nonce, checkpoint.Hash, int64(checkpoint.Timestamp)}
+		return checkpointsInsert.exec(ctx, tx, [][]interface{}{row})
+	})
+}
+
+// GetIndexingCheckpoint returns the last saved block of the provided shard. It returns nil if no block was saved for
+// the shard
+func (sp *sqlProcessor) GetIndexingCheckpoint(ctx context.Context, shardID uint32) (*data.IndexingCheckpoint, error) {
+	if !sp.isIndexEnabled(dataindexer.ValuesIndex) {
+		return nil, nil
+	}
+
+	var timestamp int64
+	checkpoint := &data.IndexingCheckpoint{
+		Key: indexerBlock.IndexingCheckpointKey(shardID),
+	}
+	err := sp.db.QueryRowContext(ctx, `SELECT nonce, hash, timestamp FROM indexing_checkpoints WHERE shard_id = $1`, shardID).
+		Scan(&checkpoint.Nonce, &checkpoint.Hash, &timestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint.Value = strconv.FormatUint(checkpoint.Nonce, 10)
+	checkpoint.Timestamp = time.Duration(timestamp)
+
+	return checkpoint, nil
+}
+
+// RemoveHeader will remove a block
+func (sp *sqlProcessor) RemoveHeader(ctx context.Context, header coreData.HeaderHandler) error {
+	headerHash, err := sp.blockProc.ComputeHeaderHash(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = sp.db.ExecContext(ctx, `DELETE FROM blocks WHERE hash = $1`, hex.EncodeToString(headerHash))
+	return err
+}
+
+// RemoveMiniblocks will remove all the miniblocks of the provided block
+func (sp *sqlProcessor) RemoveMiniblocks(ctx context.Context, header coreData.HeaderHandler, body *block.Body) error {
+	encodedMiniblocksHashes := sp.miniblocksProc.GetMiniblocksHashesHexEncoded(header, body)
+	if len(encodedMiniblocksHashes) == 0 {
+		return nil
+	}
+
+	_, err := sp.db.ExecContext(ctx, `DELETE FROM miniblocks WHERE hash = ANY($1)`, pq.StringArray(encodedMiniblocksHashes))
+	return err
+}
+
+// RemoveTransactions will remove the transactions and the smart contract results of the provided block, together with
+// their logs and events. Everything is removed in a single database transaction
+func (sp *sqlProcessor) RemoveTransactions(ctx context.Context, header coreData.HeaderHandler, body *block.Body) error {
+	encodedTxsHashes, encodedScrsHashes := sp.transactionsProc.GetHexEncodedHashesForRemove(header, body)
+	allHashes := append(encodedTxsHashes, encodedScrsHashes...)
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		err := removeIfHashesNotEmpty(ctx, tx, `DELETE FROM transactions WHERE hash = ANY($1)`, encodedTxsHashes)
+		if err != nil {
+			return err
+		}
+
+		err = removeIfHashesNotEmpty(ctx, tx, `DELETE FROM sc_results WHERE hash = ANY($1)`, encodedScrsHashes)
+		if err != nil {
+			return err
+		}
+
+		err = removeIfHashesNotEmpty(ctx, tx, `DELETE FROM logs WHERE id = ANY($1)`, allHashes)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM events WHERE shard_id = $1 AND timestamp = $2`, header.GetShardID(), header.GetTimeStamp())
+		return err
+	})
+}
+
+func removeIfHashesNotEmpty(ctx context.Context, tx *sql.Tx, query string, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, query, pq.StringArray(hashes))
+	return err
+}
+
+// RemoveAccountsESDT will remove the ESDT balances saved with the provided block timestamp
+func (sp *sqlProcessor) RemoveAccountsESDT(ctx context.Context, headerTimestamp uint64, shardID uint32) error {
+	_, err := sp.db.ExecContext(ctx, `DELETE FROM accounts_esdt WHERE shard_id = $1 AND timestamp = $2`, shardID, headerTimestamp)
+	return err
+}
+
+// SaveMiniblocks will prepare and save the miniblocks of a block
+func (sp *sqlProcessor) SaveMiniblocks(ctx context.Context, header coreData.HeaderHandler, miniBlocks []*block.MiniBlock) error {
+	if !sp.isIndexEnabled(dataindexer.MiniblocksIndex) {
+		return nil
+	}
+
+	mbs := sp.miniblocksProc.PrepareDBMiniblocks(header, miniBlocks)
+	if len(mbs) == 0 {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	rowsOnSender := make([][]interface{}, 0, len(mbs))
+	rowsOnReceiver := make([][]interface{}, 0, len(mbs))
+	for _, mb := range mbs {
+		notProcessedOnSource := shardID == mb.SenderShardID && mb.ProcessingTypeOnDestination != block.Processed.String()
+		if notProcessedOnSource {
+			rowsOnSender = append(rowsOnSender, miniblockRow(mb))
+			continue
+		}
+		rowsOnReceiver = append(rowsOnReceiver, miniblockRow(mb))
+	}
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		err := miniblocksInsertOnSender.exec(ctx, tx, rowsOnSender)
+		if err != nil {
+			return err
+		}
+
+		return miniblocksInsertOnReceiver.exec(ctx, tx, rowsOnReceiver)
+	})
+}
+
+// SaveTransactions will prepare and save the transactions, the smart contract results, the receipts, the logs, the
+// events and the altered accounts of a block, in a single database transaction
+func (sp *sqlProcessor) SaveTransactions(ctx context.Context, obh *outport.OutportBlockWithHeader) error {
+	headerTimestamp := obh.Header.GetTimeStamp()
+	shardID := obh.Header.GetShardID()
+
+	miniBlocks := append(obh.BlockData.Body.MiniBlocks, obh.BlockData.IntraShardMiniBlocks...)
+	preparedResults := sp.transactionsProc.PrepareTransactionsForDatabase(miniBlocks, obh.Header, obh.TransactionPool, sp.isImportDB(), obh.NumberOfShards)
+	logsData := sp.logsAndEventsProc.ExtractDataFromLogs(obh.TransactionPool.Logs, preparedResults, headerTimestamp, shardID, obh.NumberOfShards)
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		err := sp.saveTransactions(ctx, tx, preparedResults.Transactions, logsData.TxHashStatusInfo, preparedResults.TxHashFee, shardID)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveScResults(ctx, tx, preparedResults.ScResults)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveReceipts(ctx, tx, preparedResults.Receipts)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveLogs(ctx, tx, logsData.DBLogs)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveEvents(ctx, tx, logsData.DBEvents)
+		if err != nil {
+			return err
+		}
+
+		return sp.saveAlteredAccounts(ctx, tx, headerTimestamp, obh.AlteredAccounts, shardID)
+	})
+}
+
+func (sp *sqlProcessor) saveTransactions(
+	ctx context.Context,
+	tx *sql.Tx,
+	txs []*data.Transaction,
+	txHashStatusInfo map[string]*outport.StatusInfo,
+	txHashFee map[string]*data.FeeData,
+	selfShardID uint32,
+) error {
+	if !sp.isIndexEnabled(dataindexer.TransactionsIndex) {
+		return nil
+	}
+
+	rowsOnSource := make([][]interface{}, 0)
+	rows := make([][]interface{}, 0, len(txs))
+	for _, transaction := range txs {
+		isCrossShardOnSource := transaction.SenderShard != transaction.ReceiverShard && transaction.SenderShard == selfShardID
+		if isCrossShardOnSource {
+			rowsOnSource = append(rowsOnSource, transactionRow(transaction))
+			continue
+		}
+		rows = append(rows, transactionRow(transaction))
+	}
+
+	err := transactionsInsertOnSource.exec(ctx, tx, rowsOnSource)
+	if err != nil {
+		return err
+	}
+
+	err = transactionsInsert.exec(ctx, tx, rows)
+	if err != nil {
+		return err
+	}
+
+	for txHash, statusInfo := range txHashStatusInfo {
+		_, err = tx.ExecContext(ctx, `UPDATE transactions SET status = COALESCE(NULLIF($2, ''), status), `+
+			`completed_event = completed_event OR $3, error_event = error_event OR $4 WHERE hash = $1`,
+			txHash, statusInfo.Status, statusInfo.CompletedEvent, statusInfo.ErrorEvent)
+		if err != nil {
+			return err
+		}
+	}
+
+	for txHash, feeData := range txHashFee {
+		_, err = tx.ExecContext(ctx, `UPDATE transactions SET fee = $2, gas_used = $3 WHERE hash = $1`,
+			txHash, numeric(feeData.Fee), feeData.GasUsed)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sp *sqlProcessor) saveScResults(ctx context.Context, tx *sql.Tx, scrs []*data.ScResult) error {
+	if !sp.isIndexEnabled(dataindexer.ScResultsIndex) {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(scrs))
+	for _, scr := range scrs {
+		rows = append(rows, scResultRow(scr))
+	}
+
+	return scResultsInsert.exec(ctx, tx, rows)
+}
+
+func (sp *sqlProcessor) saveReceipts(ctx context.Context, tx *sql.Tx, receipts []*data.Receipt) error {
+	if !sp.isIndexEnabled(dataindexer.ReceiptsIndex) {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(receipts))
+	for _, receipt := range receipts {
+		rows = append(rows, receiptRow(receipt))
+	}
+
+	return receiptsInsert.exec(ctx, tx, rows)
+}
+
+func (sp *sqlProcessor) saveLogs(ctx context.Context, tx *sql.Tx, logs []*data.Logs) error {
+	if !sp.isIndexEnabled(dataindexer.LogsIndex) {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(logs))
+	for _, logData := range logs {
+		row, err := logRow(logData)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	return logsInsert.exec(ctx, tx, rows)
+}
+
+func (sp *sqlProcessor) saveEvents(ctx context.Context, tx *sql.Tx, events []*data.LogEvent) error {
+	if !sp.isIndexEnabled(dataindexer.EventsIndex) {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, eventRow(event))
+	}
+
+	return eventsInsert.exec(ctx, tx, rows)
+}
+
+func (sp *sqlProcessor) saveAlteredAccounts(
+	ctx context.Context,
+	tx *sql.Tx,
+	timestamp uint64,
+	coreAlteredAccounts map[string]*alteredAccount.AlteredAccount,
+	shardID uint32,
+) error {
+	regularAccounts, accountsESDT := sp.accountsProc.GetAccounts(coreAlteredAccounts)
+
+	err := sp.saveAccounts(ctx, tx, timestamp, regularAccounts, shardID)
+	if err != nil {
+		return err
+	}
+
+	return sp.saveAccountsESDT(ctx, tx, timestamp, accountsESDT, shardID)
+}
+
+func (sp *sqlProcessor) saveAccounts(ctx context.Context, tx *sql.Tx, timestamp uint64, accounts []*data.Account, shardID uint32) error {
+	if !sp.isIndexEnabled(dataindexer.AccountsIndex) {
+		return nil
+	}
+
+	accountsMap := sp.accountsProc.PrepareRegularAccountsMap(timestamp, accounts, shardID)
+	rows := make([][]interface{}, 0, len(accountsMap))
+	for _, account := range accountsMap {
+		rows = append(rows, accountRow(account))
+	}
+
+	return accountsInsert.exec(ctx, tx, rows)
+}
+
+// saveAccountsESDT saves the ESDT balances of the accounts. As in the accountsesdt index, the balances that dropped to
+// zero are removed
+func (sp *sqlProcessor) saveAccountsESDT(ctx context.Context, tx *sql.Tx, timestamp uint64, accounts []*data.AccountESDT, shardID uint32) error {
+	if !sp.isIndexEnabled(dataindexer.AccountsESDTIndex) {
+		return nil
+	}
+
+	accountsESDTMap, _ := sp.accountsProc.PrepareAccountsMapESDT(timestamp, accounts, tags.NewTagsCount(), shardID)
+	rows := make([][]interface{}, 0, len(accountsESDTMap))
+	for _, account := range accountsESDTMap {
+		if account.Balance == "0" || account.Balance == "" {
+			_, err := tx.ExecContext(ctx, `DELETE FROM accounts_esdt WHERE address = $1 AND token = $2 AND token_nonce = $3`,
+				account.Address, account.TokenName, account.TokenNonce)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		row, err := accountESDTRow(account)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	return accountsESDTInsert.exec(ctx, tx, rows)
+}
+
+// SaveAccounts will prepare and save the provided accounts
+func (sp *sqlProcessor) SaveAccounts(accountsData *outport.Accounts) error {
+	accounts := make([]*data.Account, 0, len(accountsData.AlteredAccounts))
+	for _, account := range accountsData.AlteredAccounts {
+		accounts = append(accounts, &data.Account{
+			UserAccount: account,
+			IsSender:    false,
+		})
+	}
+
+	ctx := context.Background()
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		return sp.saveAccounts(ctx, tx, accountsData.BlockTimestamp, accounts, accountsData.ShardID)
+	})
+}
+
+// SaveValidatorsRating will save the validators rating
+func (sp *sqlProcessor) SaveValidatorsRating(ctx context.Context, ratingData *outport.ValidatorsRating) error {
+	if !sp.isIndexEnabled(dataindexer.RatingIndex) {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(ratingData.ValidatorsRatingInfo))
+	for _, ratingInfo := range ratingData.ValidatorsRatingInfo {
+		rows = append(rows, []interface{}{ratingInfo.PublicKey, ratingData.Epoch, ratingData.ShardID, ratingInfo.Rating})
+	}
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		return validatorsRatingInsert.exec(ctx, tx, rows)
+	})
+}
+
+// SaveShardValidatorsPubKeys will save the public keys of the validators of every shard
+func (sp *sqlProcessor) SaveShardValidatorsPubKeys(ctx context.Context, validatorsPubKeys *outport.ValidatorsPubKeys) error {
+	if !sp.isIndexEnabled(dataindexer.ValidatorsIndex) {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(validatorsPubKeys.ShardValidatorsPubKeys))
+	for shardID, validatorsPk := range validatorsPubKeys.ShardValidatorsPubKeys {
+		publicKeys := make([]string, 0, len(validatorsPk.Keys))
+		for _, key := range validatorsPk.Keys {
+			// it will never throw an error here
+			encodedKey, _ := sp.validatorPubkeyConverter.Encode(key)
+			publicKeys = append(publicKeys, encodedKey)
+		}
+		rows = append(rows, []interface{}{shardID, validatorsPubKeys.Epoch, textArray(publicKeys)})
+	}
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		return shardValidatorsInsert.exec(ctx, tx, rows)
+	})
+}
+
+// SaveRoundsInfo will save the information about the provided rounds
+func (sp *sqlProcessor) SaveRoundsInfo(ctx context.Context, rounds *outport.RoundsInfo) error {
+	if !sp.isIndexEnabled(dataindexer.RoundsIndex) {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(rounds.RoundsInfo))
+	for _, info := range rounds.RoundsInfo {
+		rows = append(rows, []interface{}{
+			info.ShardId, info.Round, info.Epoch, info.BlockWasProposed, bigintArray(info.SignersIndexes), info.Timestamp,
+		})
+	}
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		return roundsInsert.exec(ctx, tx, rows)
+	})
+}
+
+// SaveFinalizedBlock will mark as final the block with the provided hash, together with its miniblocks and
+// transactions, and will save the block nonce as the last final nonce of the block's shard
+func (sp *sqlProcessor) SaveFinalizedBlock(ctx context.Context, finalizedBlock *outport.FinalizedBlock) error {
+	if finalizedBlock == nil {
+		return dataindexer.ErrNilFinalizedBlock
+	}
+	if !sp.isIndexEnabled(dataindexer.BlockIndex) {
+		return nil
+	}
+
+	shardID := finalizedBlock.ShardID
+	headerHash := hex.EncodeToString(finalizedBlock.HeaderHash)
+	finalizedAt := time.Now().Unix()
+
+	return inTransaction(ctx, sp.db, func(tx *sql.Tx) error {
+		var nonce uint64
+		var miniblocksHashes pq.StringArray
+		err := tx.QueryRowContext(ctx, `UPDATE blocks SET finalized_at = $2 WHERE hash = $1 RETURNING nonce, mini_blocks_hashes`,
+			headerHash, finalizedAt).Scan(&nonce, &miniblocksHashes)
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Debug("sqlProcessor.SaveFinalizedBlock: block not found", "hash", headerHash, "shardID", shardID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if sp.isIndexEnabled(dataindexer.ValuesIndex) {
+			err = lastFinalNonceInsert.exec(ctx, tx, [][]interface{}{{shardID, nonce}})
+			if err != nil {
+				return err
+			}
+		}
+		if len(miniblocksHashes) == 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE miniblocks SET finalized_at = $2 WHERE hash = ANY($1)`, miniblocksHashes, finalizedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE transactions SET finalized_at = $2 WHERE miniblock_hash = ANY($1)`, miniblocksHashes, finalizedAt)
+		return err
+	})
+}
+
+// SetOutportConfig will set the outport config
+func (sp *sqlProcessor) SetOutportConfig(cfg outport.OutportConfig) error {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	sp.importDB = cfg.IsInImportDBMode
+
+	return nil
+}
+
+func (sp *sqlProcessor) isImportDB() bool {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+
+	return sp.importDB
+}
+
+func (sp *sqlProcessor) isIndexEnabled(index string) bool {
+	_, isEnabled := sp.enabledIndexes[index]
+	return isEnabled
+}
+
+// Close will close the database
+func (sp *sqlProcessor) Close() error {
+	return sp.db.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sp *sqlProcessor) IsInterfaceNil() bool {
+	return sp == nil
+}
@@ -0,0 +1,605 @@
+package sqlproc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/accounts"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/logsevents"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/miniblocks"
+	"github.com/stretchr/testify/require"
+)
+
+// newDatabaseStub returns a database on which all the migrations are already applied
+func newDatabaseStub() *mock.SQLConnectorStub {
+	return &mock.SQLConnectorStub{
+		QueryCalled: func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+			if strings.Contains(query, "FROM schema_migrations") {
+				return []string{"exists"}, [][]driver.Value{{true}}, nil
+			}
+
+			return nil, nil, nil
+		},
+	}
+}
+
+func createMockArgsSQLProcessor(connector *mock.SQLConnectorStub) ArgsSQLProcessor {
+	balanceConverter, _ := converters.NewBalanceConverter(10)
+	acp, _ := accounts.NewAccountsProcessor(&mock.PubkeyConverterMock{}, balanceConverter)
+	bp, _ := block.NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
+	mp, _ := miniblocks.NewMiniblocksProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
+	lp, _ := logsevents.NewLogsAndEventsProcessor(logsevents.ArgsLogsAndEventsProcessor{
+		PubKeyConverter:  &mock.PubkeyConverterMock{},
+		Marshalizer:      &mock.MarshalizerMock{},
+		BalanceConverter: balanceConverter,
+		Hasher:           &mock.HasherMock{},
+	})
+
+	return ArgsSQLProcessor{
+		DB: sql.OpenDB(connector),
+		EnabledIndexes: map[string]struct{}{
+			dataindexer.BlockIndex: {}, dataindexer.ValuesIndex: {}, dataindexer.MiniblocksIndex: {}, dataindexer.TransactionsIndex: {},
+			dataindexer.ScResultsIndex: {}, dataindexer.ReceiptsIndex: {}, dataindexer.LogsIndex: {}, dataindexer.EventsIndex: {},
+			dataindexer.AccountsIndex: {}, dataindexer.AccountsESDTIndex: {}, dataindexer.RoundsIndex: {}, dataindexer.RatingIndex: {},
+			dataindexer.ValidatorsIndex: {},
+		},
+		TransactionsProc:         &mock.DBTransactionProcessorStub{},
+		AccountsProc:             acp,
+		BlockProc:                bp,
+		MiniblocksProc:           mp,
+		LogsAndEventsProc:        lp,
+		ValidatorPubkeyConverter: mock.NewPubkeyConverterMock(32),
+	}
+}
+
+func createSQLProcessor(t *testing.T, connector *mock.SQLConnectorStub) *sqlProcessor {
+	sp, err := NewSQLProcessor(createMockArgsSQLProcessor(connector))
+	require.Nil(t, err)
+
+	return sp
+}
+
+// statementsWithPrefix returns the recorded statements that start with the provided prefix
+func statementsWithPrefix(connector *mock.SQLConnectorStub, prefix string) []mock.SQLStatement {
+	statements := make([]mock.SQLStatement, 0)
+	for _, statement := range connector.Statements() {
+		if strings.HasPrefix(statement.Query, prefix) {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements
+}
+
+func TestNewSQLProcessor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		args  func() ArgsSQLProcessor
+		exErr error
+	}{
+		{
+			name: "NilDatabase",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.DB = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilSQLDatabase,
+		},
+		{
+			name: "NilEnabledIndexes",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.EnabledIndexes = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilEnabledIndexesMap,
+		},
+		{
+			name: "NilTransactionsHandler",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.TransactionsProc = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilTransactionsHandler,
+		},
+		{
+			name: "NilAccountsHandler",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.AccountsProc = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilAccountsHandler,
+		},
+		{
+			name: "NilBlockHandler",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.BlockProc = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilBlockHandler,
+		},
+		{
+			name: "NilMiniblocksHandler",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.MiniblocksProc = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilMiniblocksHandler,
+		},
+		{
+			name: "NilLogsAndEventsHandler",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.LogsAndEventsProc = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilLogsAndEventsHandler,
+		},
+		{
+			name: "NilValidatorPubkeyConverter",
+			args: func() ArgsSQLProcessor {
+				args := createMockArgsSQLProcessor(newDatabaseStub())
+				args.ValidatorPubkeyConverter = nil
+				return args
+			},
+			exErr: dataindexer.ErrNilPubkeyConverter,
+		},
+		{
+			name: "All arguments ok",
+			args: func() ArgsSQLProcessor {
+				return createMockArgsSQLProcessor(newDatabaseStub())
+			},
+			exErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSQLProcessor(tt.args())
+			require.Equal(t, tt.exErr, err)
+		})
+	}
+}
+
+func TestNewSQLProcessor_Migrations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should apply the migrations that were not applied yet", func(t *testing.T) {
+		t.Parallel()
+
+		connector := &mock.SQLConnectorStub{
+			QueryCalled: func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+				return []string{"exists"}, [][]driver.Value{{false}}, nil
+			},
+		}
+		_ = createSQLProcessor(t, connector)
+
+		statements := connector.Statements()
+		require.True(t, strings.HasPrefix(statements[0].Query, "CREATE TABLE IF NOT EXISTS schema_migrations"))
+		require.Equal(t, "SELECT pg_advisory_xact_lock($1)", statements[1].Query)
+		require.True(t, strings.Contains(statements[3].Query, "CREATE TABLE blocks"))
+
+		inserted := statementsWithPrefix(connector, "INSERT INTO schema_migrations")
+		require.Len(t, inserted, 1)
+		require.Equal(t, []driver.Value{int64(1), "0001_create_tables.sql"}, inserted[0].Args)
+		require.Equal(t, 1, connector.NumCommits())
+	})
+
+	t.Run("should not apply the migrations again", func(t *testing.T) {
+		t.Parallel()
+
+		connector := newDatabaseStub()
+		_ = createSQLProcessor(t, connector)
+
+		require.Empty(t, statementsWithPrefix(connector, "INSERT INTO schema_migrations"))
+		require.Len(t, connector.Statements(), 3)
+	})
+
+	t.Run("a failed migration should be rolled back", func(t *testing.T) {
+		t.Parallel()
+
+		localErr := errors.New("local error")
+		connector := &mock.SQLConnectorStub{
+			ExecCalled: func(query string, args []driver.Value) error {
+				if strings.Contains(query, "CREATE TABLE blocks") {
+					return localErr
+				}
+				return nil
+			},
+			QueryCalled: func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+				return []string{"exists"}, [][]driver.Value{{false}}, nil
+			},
+		}
+
+		sp, err := NewSQLProcessor(createMockArgsSQLProcessor(connector))
+		require.Nil(t, sp)
+		require.True(t, errors.Is(err, localErr))
+		require.Equal(t, 0, connector.NumCommits())
+		require.Equal(t, 1, connector.NumRollbacks())
+	})
+}
+
+func TestSQLProcessor_SaveHeader(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	sp := createSQLProcessor(t, connector)
+
+	obh := &outport.OutportBlockWithHeader{
+		Header: &dataBlock.Header{Nonce: 7, ShardID: 1, TimeStamp: 1000},
+		OutportBlock: &outport.OutportBlock{
+			BlockData:            &outport.BlockData{Body: &dataBlock.Body{}, HeaderHash: []byte("hash")},
+			HeaderGasConsumption: &outport.HeaderGasConsumption{},
+			TransactionPool:      &outport.TransactionPool{},
+		},
+	}
+	err := sp.SaveHeader(context.Background(), obh)
+	require.Nil(t, err)
+
+	blocks := statementsWithPrefix(connector, "INSERT INTO blocks")
+	require.Len(t, blocks, 1)
+	require.Equal(t, "68617368", blocks[0].Args[0])
+	require.Equal(t, int64(7), blocks[0].Args[1])
+	require.Equal(t, "{}", blocks[0].Args[16])
+	require.Empty(t, statementsWithPrefix(connector, "INSERT INTO indexing_checkpoints"))
+	require.Equal(t, 2, connector.NumCommits())
+}
+
+func TestSQLProcessor_SaveIndexingCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	sp := createSQLProcessor(t, connector)
+
+	err := sp.SaveIndexingCheckpoint(context.Background(), 1, nil)
+	require.Equal(t, dataindexer.ErrNilIndexingCheckpoint, err)
+
+	err = sp.SaveIndexingCheckpoint(context.Background(), 1, &data.IndexingCheckpoint{Nonce: 7, Hash: "68617368", Timestamp: 1000})
+	require.Nil(t, err)
+
+	checkpoints := statementsWithPrefix(connector, "INSERT INTO indexing_checkpoints")
+	require.Len(t, checkpoints, 1)
+	require.Equal(t, []driver.Value{int64(1), int64(7), "68617368", int64(1000)}, checkpoints[0].Args)
+	require.True(t, strings.HasSuffix(checkpoints[0].Query, "ON CONFLICT (shard_id) DO UPDATE SET nonce = EXCLUDED.nonce, hash = EXCLUDED.hash, timestamp = EXCLUDED.timestamp"))
+	require.Equal(t, 2, connector.NumCommits())
+}
+
+func TestSQLProcessor_GetIndexingCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no checkpoint should return nil", func(t *testing.T) {
+		t.Parallel()
+
+		sp := createSQLProcessor(t, newDatabaseStub())
+
+		checkpoint, err := sp.GetIndexingCheckpoint(context.Background(), 2)
+		require.Nil(t, err)
+		require.Nil(t, checkpoint)
+	})
+
+	t.Run("should return the checkpoint of the shard", func(t *testing.T) {
+		t.Parallel()
+
+		connector := newDatabaseStub()
+		connector.QueryCalled = func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+			if strings.Contains(query, "FROM schema_migrations") {
+				return []string{"exists"}, [][]driver.Value{{true}}, nil
+			}
+
+			require.Equal(t, []driver.Value{int64(core.MetachainShardId)}, args)
+			return []string{"nonce", "hash", "timestamp"}, [][]driver.Value{{int64(10), "aa", int64(5000)}}, nil
+		}
+		sp := createSQLProcessor(t, connector)
+
+		checkpoint, err := sp.GetIndexingCheckpoint(context.Background(), core.MetachainShardId)
+		require.Nil(t, err)
+		require.Equal(t, &data.IndexingCheckpoint{
+			Key:       block.IndexingCheckpointKey(core.MetachainShardId),
+			Value:     "10",
+			Nonce:     10,
+			Hash:      "aa",
+			Timestamp: 5000,
+		}, checkpoint)
+	})
+}
+
+func TestSQLProcessor_SaveMiniblocks(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	sp := createSQLProcessor(t, connector)
+
+	header := &dataBlock.Header{ShardID: 1}
+	err := sp.SaveMiniblocks(context.Background(), header, []*dataBlock.MiniBlock{
+		{SenderShardID: 1, ReceiverShardID: 2},
+		{SenderShardID: 0, ReceiverShardID: 1},
+	})
+	require.Nil(t, err)
+
+	miniblocks := statementsWithPrefix(connector, "INSERT INTO miniblocks")
+	require.Len(t, miniblocks, 2)
+	require.True(t, strings.HasSuffix(miniblocks[0].Query, "DO UPDATE SET sender_block_hash = EXCLUDED.sender_block_hash, proc_type_s = EXCLUDED.proc_type_s"))
+	require.Equal(t, int64(1), miniblocks[0].Args[1])
+	require.True(t, strings.HasSuffix(miniblocks[1].Query, "DO UPDATE SET receiver_block_hash = EXCLUDED.receiver_block_hash, proc_type_d = EXCLUDED.proc_type_d"))
+	require.Equal(t, int64(0), miniblocks[1].Args[1])
+}
+
+func TestSQLProcessor_SaveTransactions(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	args := createMockArgsSQLProcessor(connector)
+	args.TransactionsProc = &mock.DBTransactionProcessorStub{
+		PrepareTransactionsForDatabaseCalled: func(_ []*dataBlock.MiniBlock, _ coreData.HeaderHandler, _ *outport.TransactionPool) *data.PreparedResults {
+			return &data.PreparedResults{
+				Transactions: []*data.Transaction{
+					{Hash: "intra", SenderShard: 1, ReceiverShard: 1, Value: "10"},
+					{Hash: "cross", SenderShard: 1, ReceiverShard: 2},
+				},
+				ScResults: []*data.ScResult{{Hash: "scr", Value: "1"}},
+				Receipts:  []*data.Receipt{{Hash: "receipt", TxHash: "intra"}},
+				TxHashFee: map[string]*data.FeeData{"intra": {Fee: "5", GasUsed: 50}},
+			}
+		},
+	}
+	sp, err := NewSQLProcessor(args)
+	require.Nil(t, err)
+
+	obh := &outport.OutportBlockWithHeader{
+		Header: &dataBlock.Header{ShardID: 1, TimeStamp: 1000},
+		OutportBlock: &outport.OutportBlock{
+			BlockData:       &outport.BlockData{Body: &dataBlock.Body{}},
+			TransactionPool: &outport.TransactionPool{},
+		},
+	}
+	err = sp.SaveTransactions(context.Background(), obh)
+	require.Nil(t, err)
+
+	transactions := statementsWithPrefix(connector, "INSERT INTO transactions")
+	require.Len(t, transactions, 2)
+	require.Equal(t, "cross", transactions[0].Args[0])
+	require.True(t, strings.HasSuffix(transactions[0].Query, "ON CONFLICT (hash) DO NOTHING"))
+	require.Equal(t, "intra", transactions[1].Args[0])
+	require.Equal(t, "10", transactions[1].Args[4])
+	require.Equal(t, "0", transactions[1].Args[12])
+
+	fees := statementsWithPrefix(connector, "UPDATE transactions SET fee")
+	require.Len(t, fees, 1)
+	require.Equal(t, []driver.Value{"intra", "5", int64(50)}, fees[0].Args)
+
+	require.Len(t, statementsWithPrefix(connector, "INSERT INTO sc_results"), 1)
+	require.Len(t, statementsWithPrefix(connector, "INSERT INTO receipts"), 1)
+	require.Empty(t, statementsWithPrefix(connector, "INSERT INTO logs"))
+	require.Empty(t, statementsWithPrefix(connector, "INSERT INTO accounts"))
+	require.Equal(t, 2, connector.NumCommits())
+}
+
+func TestSQLProcessor_SaveTransactionsShouldSaveTheFungibleTokensOfTheSameAddress(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	args := createMockArgsSQLProcessor(connector)
+	args.TransactionsProc = &mock.DBTransactionProcessorStub{
+		PrepareTransactionsForDatabaseCalled: func(_ []*dataBlock.MiniBlock, _ coreData.HeaderHandler, _ *outport.TransactionPool) *data.PreparedResults {
+			return &data.PreparedResults{}
+		},
+	}
+	sp, err := NewSQLProcessor(args)
+	require.Nil(t, err)
+
+	address := "61646472"
+	obh := &outport.OutportBlockWithHeader{
+		Header: &dataBlock.Header{ShardID: 1, TimeStamp: 1000},
+		OutportBlock: &outport.OutportBlock{
+			BlockData:       &outport.BlockData{Body: &dataBlock.Body{}},
+			TransactionPool: &outport.TransactionPool{},
+			AlteredAccounts: map[string]*alteredAccount.AlteredAccount{
+				address: {
+					Address: address,
+					Balance: "0",
+					Tokens: []*alteredAccount.AccountTokenData{
+						{Identifier: "AAA-abcd", Balance: "10"},
+						{Identifier: "BBB-abcd", Balance: "20"},
+						{Identifier: "CCC-abcd", Balance: "0"},
+					},
+				},
+			},
+		},
+	}
+	err = sp.SaveTransactions(context.Background(), obh)
+	require.Nil(t, err)
+
+	inserted := statementsWithPrefix(connector, "INSERT INTO accounts_esdt")
+	require.Len(t, inserted, 1)
+	require.Contains(t, inserted[0].Query, "ON CONFLICT (address, token, token_nonce) DO UPDATE")
+	insertedTokens := make([]driver.Value, 0)
+	for i := 1; i < len(inserted[0].Args); i += len(accountESDTColumns) {
+		insertedTokens = append(insertedTokens, inserted[0].Args[i])
+	}
+	require.ElementsMatch(t, []driver.Value{"AAA-abcd", "BBB-abcd"}, insertedTokens)
+
+	deleted := statementsWithPrefix(connector, "DELETE FROM accounts_esdt")
+	require.Len(t, deleted, 1)
+	require.Equal(t, []driver.Value{address, "CCC-abcd", int64(0)}, deleted[0].Args)
+}
+
+func TestSQLProcessor_SaveTransactionsErrorShouldRollback(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	connector := newDatabaseStub()
+	connector.ExecCalled = func(query string, args []driver.Value) error {
+		if strings.HasPrefix(query, "INSERT INTO sc_results") {
+			return localErr
+		}
+		return nil
+	}
+	args := createMockArgsSQLProcessor(connector)
+	args.TransactionsProc = &mock.DBTransactionProcessorStub{
+		PrepareTransactionsForDatabaseCalled: func(_ []*dataBlock.MiniBlock, _ coreData.HeaderHandler, _ *outport.TransactionPool) *data.PreparedResults {
+			return &data.PreparedResults{
+				ScResults: []*data.ScResult{{Hash: "scr"}},
+			}
+		},
+	}
+	sp, err := NewSQLProcessor(args)
+	require.Nil(t, err)
+
+	obh := &outport.OutportBlockWithHeader{
+		Header: &dataBlock.Header{},
+		OutportBlock: &outport.OutportBlock{
+			BlockData:       &outport.BlockData{Body: &dataBlock.Body{}},
+			TransactionPool: &outport.TransactionPool{},
+		},
+	}
+	err = sp.SaveTransactions(context.Background(), obh)
+	require.True(t, errors.Is(err, localErr))
+	require.Equal(t, 1, connector.NumCommits())
+	require.Equal(t, 1, connector.NumRollbacks())
+}
+
+func TestSQLProcessor_RemoveTransactions(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	args := createMockArgsSQLProcessor(connector)
+	args.TransactionsProc = &mock.DBTransactionProcessorStub{
+		GetHexEncodedHashesForRemoveCalled: func(_ coreData.HeaderHandler, _ *dataBlock.Body) ([]string, []string) {
+			return []string{"tx1", "tx2"}, []string{"scr1"}
+		},
+	}
+	sp, err := NewSQLProcessor(args)
+	require.Nil(t, err)
+
+	header := &dataBlock.Header{ShardID: 2, TimeStamp: 1234}
+	err = sp.RemoveTransactions(context.Background(), header, &dataBlock.Body{})
+	require.Nil(t, err)
+
+	statements := statementsWithPrefix(connector, "DELETE FROM")
+	require.Equal(t, []mock.SQLStatement{
+		{Query: "DELETE FROM transactions WHERE hash = ANY($1)", Args: []driver.Value{`{"tx1","tx2"}`}},
+		{Query: "DELETE FROM sc_results WHERE hash = ANY($1)", Args: []driver.Value{`{"scr1"}`}},
+		{Query: "DELETE FROM logs WHERE id = ANY($1)", Args: []driver.Value{`{"tx1","tx2","scr1"}`}},
+		{Query: "DELETE FROM events WHERE shard_id = $1 AND timestamp = $2", Args: []driver.Value{int64(2), int64(1234)}},
+	}, statements)
+	require.Equal(t, 2, connector.NumCommits())
+}
+
+func TestSQLProcessor_SaveRoundsInfo(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	sp := createSQLProcessor(t, connector)
+
+	err := sp.SaveRoundsInfo(context.Background(), &outport.RoundsInfo{
+		RoundsInfo: []*outport.RoundInfo{
+			{Round: 5, ShardId: 1, Epoch: 2, BlockWasProposed: true, SignersIndexes: []uint64{1, 3}, Timestamp: 100},
+		},
+	})
+	require.Nil(t, err)
+
+	rounds := statementsWithPrefix(connector, "INSERT INTO rounds")
+	require.Len(t, rounds, 1)
+	require.Equal(t, []driver.Value{int64(1), int64(5), int64(2), true, "{1,3}", int64(100)}, rounds[0].Args)
+}
+
+func TestSQLProcessor_SaveFinalizedBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil finalized block should error", func(t *testing.T) {
+		t.Parallel()
+
+		sp := createSQLProcessor(t, newDatabaseStub())
+
+		err := sp.SaveFinalizedBlock(context.Background(), nil)
+		require.Equal(t, dataindexer.ErrNilFinalizedBlock, err)
+	})
+
+	t.Run("block not found should do nothing", func(t *testing.T) {
+		t.Parallel()
+
+		connector := newDatabaseStub()
+		sp := createSQLProcessor(t, connector)
+
+		err := sp.SaveFinalizedBlock(context.Background(), &outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("h")})
+		require.Nil(t, err)
+		require.Empty(t, statementsWithPrefix(connector, "INSERT INTO last_final_nonces"))
+		require.Empty(t, statementsWithPrefix(connector, "UPDATE miniblocks"))
+	})
+
+	t.Run("should mark the block, its miniblocks and its transactions as final", func(t *testing.T) {
+		t.Parallel()
+
+		connector := newDatabaseStub()
+		connector.QueryCalled = func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+			if strings.Contains(query, "FROM schema_migrations") {
+				return []string{"exists"}, [][]driver.Value{{true}}, nil
+			}
+
+			require.Equal(t, "68", args[0])
+			return []string{"nonce", "mini_blocks_hashes"}, [][]driver.Value{{int64(42), []byte("{mb1,mb2}")}}, nil
+		}
+		sp := createSQLProcessor(t, connector)
+
+		start := time.Now().Unix()
+		err := sp.SaveFinalizedBlock(context.Background(), &outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("h")})
+		require.Nil(t, err)
+
+		lastFinalNonces := statementsWithPrefix(connector, "INSERT INTO last_final_nonces")
+		require.Len(t, lastFinalNonces, 1)
+		require.Equal(t, []driver.Value{int64(1), int64(42)}, lastFinalNonces[0].Args)
+
+		miniblocks := statementsWithPrefix(connector, "UPDATE miniblocks SET finalized_at")
+		require.Len(t, miniblocks, 1)
+		require.Equal(t, `{"mb1","mb2"}`, miniblocks[0].Args[0])
+		require.GreaterOrEqual(t, miniblocks[0].Args[1], start)
+
+		require.Len(t, statementsWithPrefix(connector, "UPDATE transactions SET finalized_at"), 1)
+		require.Equal(t, 2, connector.NumCommits())
+	})
+}
+
+func TestSQLProcessor_DisabledIndexesShouldNotBeSaved(t *testing.T) {
+	t.Parallel()
+
+	connector := newDatabaseStub()
+	args := createMockArgsSQLProcessor(connector)
+	args.EnabledIndexes = map[string]struct{}{}
+	sp, err := NewSQLProcessor(args)
+	require.Nil(t, err)
+
+	numStatements := len(connector.Statements())
+	err = sp.SaveRoundsInfo(context.Background(), &outport.RoundsInfo{})
+	require.Nil(t, err)
+	err = sp.SaveValidatorsRating(context.Background(), &outport.ValidatorsRating{})
+	require.Nil(t, err)
+	err = sp.SaveMiniblocks(context.Background(), &dataBlock.Header{}, []*dataBlock.MiniBlock{{}})
+	require.Nil(t, err)
+	checkpoint, err := sp.GetIndexingCheckpoint(context.Background(), 0)
+	require.Nil(t, err)
+	require.Nil(t, checkpoint)
+
+	require.Len(t, connector.Statements(), numStatements)
+}
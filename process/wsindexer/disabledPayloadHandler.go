@@ -0,0 +1,23 @@
+package wsindexer
+
+type disabledPayloadHandler struct{}
+
+// NewDisabledPayloadHandler will create a payload handler that ignores all the payloads
+func NewDisabledPayloadHandler() *disabledPayloadHandler {
+	return &disabledPayloadHandler{}
+}
+
+// ProcessPayload does nothing
+func (dph *disabledPayloadHandler) ProcessPayload(_ []byte, _ string, _ uint32) error {
+	return nil
+}
+
+// Close does nothing
+func (dph *disabledPayloadHandler) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dph *disabledPayloadHandler) IsInterfaceNil() bool {
+	return dph == nil
+}
@@ -1,13 +1,14 @@
 package wsindexer
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
-	"github.com/multiversx/mx-chain-core-go/marshal"
 	"github.com/multiversx/mx-chain-es-indexer-go/core"
 	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
@@ -15,40 +16,71 @@ import (
 )
 
 var (
-	log               = logger.GetOrCreate("process/wsindexer")
-	errNilDataIndexer = errors.New("nil data indexer")
+	log                       = logger.GetOrCreate("process/wsindexer")
+	errNilDataIndexer         = errors.New("nil data indexer")
+	errEmptyCaptureDirectory  = errors.New("empty capture directory")
+	errInvalidCaptureFileSize = errors.New("invalid capture file size")
+	errRecorderClosed         = errors.New("payload recorder is closed")
+	errNilPayloadDecoders     = errors.New("nil payload decoders")
+	errNilAckDelayProvider    = errors.New("nil ack delay provider")
 )
 
+// UnsupportedPayloadVersionMetricTopic is the prefix of the metric that counts the rejected payloads of every topic
+const UnsupportedPayloadVersionMetricTopic = "unsupported_payload_version"
+
+type shardIDHandler interface {
+	GetShardID() uint32
+}
+
 // ArgsIndexer holds all the components needed to create a new instance of indexer
 type ArgsIndexer struct {
-	Marshaller    marshal.Marshalizer
 	DataIndexer   DataIndexer
 	StatusMetrics core.StatusMetricsHandler
+	Recorder      PayloadRecorder
+	Decoders      PayloadDecodersHandler
+	// AckDelay provides the delay applied before returning from ProcessPayload, which also delays the acknowledgement
+	// of the payload, so the source slows down while the Elasticsearch cluster is under pressure
+	AckDelay AckDelayProvider
+}
+
+type recordedPayloadKey struct {
+	topic   string
+	version uint32
+	hash    [sha256.Size]byte
 }
 
 type indexer struct {
-	marshaller    marshal.Marshalizer
 	di            DataIndexer
 	statusMetrics core.StatusMetricsHandler
-	actions       map[string]func(marshalledData []byte) error
+	recorder      PayloadRecorder
+	mutRecord     sync.Mutex
+	lastRecorded  recordedPayloadKey
+	decoders      PayloadDecodersHandler
+	ackDelay      AckDelayProvider
+	actions       map[string]func(decodedPayload interface{}) error
 }
 
 // NewIndexer will create a new instance of *indexer
 func NewIndexer(args ArgsIndexer) (*indexer, error) {
-	if check.IfNil(args.Marshaller) {
-		return nil, dataindexer.ErrNilMarshalizer
-	}
 	if check.IfNil(args.DataIndexer) {
 		return nil, errNilDataIndexer
 	}
 	if check.IfNil(args.StatusMetrics) {
 		return nil, core.ErrNilMetricsHandler
 	}
+	if check.IfNil(args.Decoders) {
+		return nil, errNilPayloadDecoders
+	}
+	if check.IfNil(args.AckDelay) {
+		return nil, errNilAckDelayProvider
+	}
 
 	payloadIndexer := &indexer{
-		marshaller:    args.Marshaller,
 		di:            args.DataIndexer,
 		statusMetrics: args.StatusMetrics,
+		recorder:      args.Recorder,
+		decoders:      args.Decoders,
+		ackDelay:      args.AckDelay,
 	}
 	payloadIndexer.initActionsMap()
 
@@ -57,7 +89,7 @@ func NewIndexer(args ArgsIndexer) (*indexer, error) {
 
 // GetOperationsMap returns the map with all the operations that will index data
 func (i *indexer) initActionsMap() {
-	i.actions = map[string]func(d []byte) error{
+	i.actions = map[string]func(decodedPayload interface{}) error{
 		outport.TopicSaveBlock:             i.saveBlock,
 		outport.TopicRevertIndexedBlock:    i.revertIndexedBlock,
 		outport.TopicSaveRoundsInfo:        i.saveRounds,
@@ -71,9 +103,7 @@ func (i *indexer) initActionsMap() {
 
 // ProcessPayload will proces the provided payload based on the topic
 func (i *indexer) ProcessPayload(payload []byte, topic string, version uint32) error {
-	if version != 1 {
-		log.Warn("received a payload with a different version", "version", version)
-	}
+	i.recordPayload(payload, topic, version)
 
 	payloadTypeAction, ok := i.actions[topic]
 	if !ok {
@@ -81,13 +111,19 @@ func (i *indexer) ProcessPayload(payload []byte, topic string, version uint32) e
 		return nil
 	}
 
-	shardID, err := i.getShardID(payload)
-	if err != nil {
-		log.Warn("indexer.ProcessPayload: cannot get shardID from payload", "error", err)
+	start := time.Now()
+	decodedPayload, err := i.decoders.Decode(topic, version, payload)
+	if errors.Is(err, ErrUnsupportedPayloadVersion) {
+		i.rejectPayload(payload, topic, version, err)
+		return markUnprocessable(err)
 	}
 
-	start := time.Now()
-	err = payloadTypeAction(payload)
+	shardID := getShardID(decodedPayload)
+	if err != nil {
+		err = markUnprocessable(err)
+	} else {
+		err = payloadTypeAction(decodedPayload)
+	}
 	duration := time.Since(start)
 
 	topicKey := fmt.Sprintf("%s_%d", topic, shardID)
@@ -98,85 +134,154 @@ func (i *indexer) ProcessPayload(payload []byte, topic string, version uint32) e
 		Duration:   duration,
 	})
 
+	i.delayAck(topic)
+
 	return err
 }
 
-func (i *indexer) saveBlock(marshalledData []byte) error {
-	outportBlock := &outport.OutportBlock{}
-	err := i.marshaller.Unmarshal(outportBlock, marshalledData)
+func (i *indexer) delayAck(topic string) {
+	delay := i.ackDelay.GetAckDelay()
+	if delay == 0 {
+		return
+	}
+
+	log.Debug("indexer.ProcessPayload: delaying the acknowledgement because of the Elasticsearch pressure", "topic", topic, "delay", delay)
+	time.Sleep(delay)
+}
+
+func (i *indexer) rejectPayload(payload []byte, topic string, version uint32, err error) {
+	log.Error("indexer.ProcessPayload: rejected payload", "topic", topic, "version", version, "error", err)
+
+	i.statusMetrics.AddIndexingData(metrics.ArgsAddIndexingData{
+		GotError:   true,
+		MessageLen: uint64(len(payload)),
+		Topic:      fmt.Sprintf("%s_%s", UnsupportedPayloadVersionMetricTopic, topic),
+	})
+}
+
+// markUnprocessable marks the errors of the payloads that can never be decoded, so they are not retried
+func markUnprocessable(err error) error {
+	return fmt.Errorf("%w: %w", dataindexer.ErrUnprocessablePayload, err)
+}
+
+func getShardID(decodedPayload interface{}) uint32 {
+	payloadWithShardID, ok := decodedPayload.(shardIDHandler)
+	if !ok {
+		return 0
+	}
+
+	return payloadWithShardID.GetShardID()
+}
+
+// recordPayload records every received payload, including the ones that fail to be processed. A payload that is sent
+// again right after it failed is recorded once
+func (i *indexer) recordPayload(payload []byte, topic string, version uint32) {
+	if check.IfNil(i.recorder) {
+		return
+	}
+
+	i.mutRecord.Lock()
+	defer i.mutRecord.Unlock()
+
+	key := recordedPayloadKey{
+		topic:   topic,
+		version: version,
+		hash:    sha256.Sum256(payload),
+	}
+	if key == i.lastRecorded {
+		return
+	}
+
+	err := i.recorder.Record(payload, topic, version)
 	if err != nil {
-		return err
+		log.Warn("indexer.recordPayload: cannot record payload", "topic", topic, "error", err)
+		return
+	}
+
+	i.lastRecorded = key
+}
+
+func (i *indexer) saveBlock(decodedPayload interface{}) error {
+	outportBlock, ok := decodedPayload.(*outport.OutportBlock)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicSaveBlock)
 	}
 
 	return i.di.SaveBlock(outportBlock)
 }
 
-func (i *indexer) revertIndexedBlock(marshalledData []byte) error {
-	blockData := &outport.BlockData{}
-	err := i.marshaller.Unmarshal(blockData, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) revertIndexedBlock(decodedPayload interface{}) error {
+	blockData, ok := decodedPayload.(*outport.BlockData)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicRevertIndexedBlock)
 	}
 
 	return i.di.RevertIndexedBlock(blockData)
 }
 
-func (i *indexer) saveRounds(marshalledData []byte) error {
-	roundsInfo := &outport.RoundsInfo{}
-	err := i.marshaller.Unmarshal(roundsInfo, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveRounds(decodedPayload interface{}) error {
+	roundsInfo, ok := decodedPayload.(*outport.RoundsInfo)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicSaveRoundsInfo)
 	}
 
 	return i.di.SaveRoundsInfo(roundsInfo)
 }
 
-func (i *indexer) saveValidatorsRating(marshalledData []byte) error {
-	ratingData := &outport.ValidatorsRating{}
-	err := i.marshaller.Unmarshal(ratingData, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveValidatorsRating(decodedPayload interface{}) error {
+	ratingData, ok := decodedPayload.(*outport.ValidatorsRating)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicSaveValidatorsRating)
 	}
 
 	return i.di.SaveValidatorsRating(ratingData)
 }
 
-func (i *indexer) saveValidatorsPubKeys(marshalledData []byte) error {
-	validatorsPubKeys := &outport.ValidatorsPubKeys{}
-	err := i.marshaller.Unmarshal(validatorsPubKeys, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveValidatorsPubKeys(decodedPayload interface{}) error {
+	validatorsPubKeys, ok := decodedPayload.(*outport.ValidatorsPubKeys)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicSaveValidatorsPubKeys)
 	}
 
 	return i.di.SaveValidatorsPubKeys(validatorsPubKeys)
 }
 
-func (i *indexer) saveAccounts(marshalledData []byte) error {
-	accounts := &outport.Accounts{}
-	err := i.marshaller.Unmarshal(accounts, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveAccounts(decodedPayload interface{}) error {
+	accounts, ok := decodedPayload.(*outport.Accounts)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicSaveAccounts)
 	}
 
 	return i.di.SaveAccounts(accounts)
 }
 
-func (i *indexer) finalizedBlock(_ []byte) error {
-	return nil
+func (i *indexer) finalizedBlock(decodedPayload interface{}) error {
+	finalizedBlock, ok := decodedPayload.(*outport.FinalizedBlock)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicFinalizedBlock)
+	}
+
+	return i.di.FinalizedBlock(finalizedBlock)
 }
 
-func (i *indexer) setSettings(marshalledData []byte) error {
-	settings := outport.OutportConfig{}
-	err := i.marshaller.Unmarshal(&settings, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) setSettings(decodedPayload interface{}) error {
+	settings, ok := decodedPayload.(*outport.OutportConfig)
+	if !ok {
+		return fmt.Errorf("%w: %w for topic %s", dataindexer.ErrUnprocessablePayload, errInvalidDecodedPayload, outport.TopicSettings)
 	}
 
-	return i.di.SetCurrentSettings(settings)
+	return i.di.SetCurrentSettings(*settings)
 }
 
 // Close will close the indexer
 func (i *indexer) Close() error {
+	if !check.IfNil(i.recorder) {
+		err := i.recorder.Close()
+		if err != nil {
+			log.Warn("indexer.Close: cannot close the payload recorder", "error", err)
+		}
+	}
+
 	return i.di.Close()
 }
 
@@ -184,13 +289,3 @@ func (i *indexer) Close() error {
 func (i *indexer) IsInterfaceNil() bool {
 	return i == nil
 }
-
-func (i *indexer) getShardID(payload []byte) (uint32, error) {
-	shard := &outport.Shard{}
-	err := i.marshaller.Unmarshal(shard, payload)
-	if err != nil {
-		return 0, err
-	}
-
-	return shard.ShardID, nil
-}
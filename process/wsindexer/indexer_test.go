@@ -0,0 +1,196 @@
+package wsindexer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockIndexerArgs() ArgsIndexer {
+	decoders, _ := NewPayloadDecoders(&marshal.JsonMarshalizer{})
+
+	return ArgsIndexer{
+		DataIndexer:   &mock.IndexerStub{},
+		StatusMetrics: metrics.NewStatusMetrics(),
+		Decoders:      decoders,
+		AckDelay:      &mock.PressureMonitorStub{},
+	}
+}
+
+func TestNewIndexer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil data indexer should error", func(t *testing.T) {
+		args := createMockIndexerArgs()
+		args.DataIndexer = nil
+		i, err := NewIndexer(args)
+		require.Nil(t, i)
+		require.Equal(t, errNilDataIndexer, err)
+	})
+	t.Run("nil status metrics should error", func(t *testing.T) {
+		args := createMockIndexerArgs()
+		args.StatusMetrics = nil
+		i, err := NewIndexer(args)
+		require.Nil(t, i)
+		require.Equal(t, core.ErrNilMetricsHandler, err)
+	})
+	t.Run("nil decoders should error", func(t *testing.T) {
+		args := createMockIndexerArgs()
+		args.Decoders = nil
+		i, err := NewIndexer(args)
+		require.Nil(t, i)
+		require.Equal(t, errNilPayloadDecoders, err)
+	})
+	t.Run("nil ack delay provider should error", func(t *testing.T) {
+		args := createMockIndexerArgs()
+		args.AckDelay = nil
+		i, err := NewIndexer(args)
+		require.Nil(t, i)
+		require.Equal(t, errNilAckDelayProvider, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		i, err := NewIndexer(createMockIndexerArgs())
+		require.Nil(t, err)
+		require.False(t, i.IsInterfaceNil())
+	})
+}
+
+func TestIndexer_ProcessPayload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unsupported version should be rejected and metered", func(t *testing.T) {
+		t.Parallel()
+
+		statusMetrics := metrics.NewStatusMetrics()
+		args := createMockIndexerArgs()
+		args.StatusMetrics = statusMetrics
+		args.DataIndexer = &mock.IndexerStub{
+			FinalizedBlockCalled: func(finalizedBlock *outport.FinalizedBlock) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		i, _ := NewIndexer(args)
+
+		err := i.ProcessPayload([]byte(`{"ShardID":1}`), outport.TopicFinalizedBlock, 2)
+		require.True(t, errors.Is(err, ErrUnsupportedPayloadVersion))
+		require.True(t, errors.Is(err, dataindexer.ErrUnprocessablePayload))
+
+		metricsResponse := statusMetrics.GetMetrics()["unsupported_payload_version_finalized_block"]
+		require.NotNil(t, metricsResponse)
+		require.Equal(t, uint64(1), metricsResponse.TotalErrorsCount)
+	})
+
+	t.Run("corrupted payload should be unprocessable", func(t *testing.T) {
+		t.Parallel()
+
+		i, _ := NewIndexer(createMockIndexerArgs())
+
+		err := i.ProcessPayload([]byte("corrupted"), outport.TopicFinalizedBlock, CurrentPayloadVersion)
+		require.True(t, errors.Is(err, dataindexer.ErrUnprocessablePayload))
+	})
+
+	t.Run("current version should be indexed", func(t *testing.T) {
+		t.Parallel()
+
+		statusMetrics := metrics.NewStatusMetrics()
+		called := false
+		args := createMockIndexerArgs()
+		args.StatusMetrics = statusMetrics
+		args.DataIndexer = &mock.IndexerStub{
+			FinalizedBlockCalled: func(finalizedBlock *outport.FinalizedBlock) error {
+				require.Equal(t, uint32(1), finalizedBlock.ShardID)
+				called = true
+				return nil
+			},
+		}
+		i, _ := NewIndexer(args)
+
+		err := i.ProcessPayload([]byte(`{"ShardID":1}`), outport.TopicFinalizedBlock, CurrentPayloadVersion)
+		require.Nil(t, err)
+		require.True(t, called)
+		require.NotNil(t, statusMetrics.GetMetrics()["finalized_block_1"])
+	})
+
+	t.Run("ack delay should be applied after indexing", func(t *testing.T) {
+		t.Parallel()
+
+		ackDelay := 50 * time.Millisecond
+		args := createMockIndexerArgs()
+		args.AckDelay = &mock.PressureMonitorStub{
+			GetAckDelayCalled: func() time.Duration {
+				return ackDelay
+			},
+		}
+		i, _ := NewIndexer(args)
+
+		start := time.Now()
+		err := i.ProcessPayload([]byte(`{"ShardID":1}`), outport.TopicFinalizedBlock, CurrentPayloadVersion)
+		require.Nil(t, err)
+		require.GreaterOrEqual(t, time.Since(start), ackDelay)
+	})
+
+	t.Run("the failed payloads should be recorded once", func(t *testing.T) {
+		t.Parallel()
+
+		directory := t.TempDir()
+		recorder, _ := NewPayloadRecorder(ArgsPayloadRecorder{
+			Directory:          directory,
+			MaxFileSizeInBytes: 1024,
+		})
+		args := createMockIndexerArgs()
+		args.Recorder = recorder
+		args.DataIndexer = &mock.IndexerStub{
+			FinalizedBlockCalled: func(finalizedBlock *outport.FinalizedBlock) error {
+				if finalizedBlock.ShardID == 1 {
+					return errors.New("local error")
+				}
+				return nil
+			},
+		}
+		i, _ := NewIndexer(args)
+
+		for attempt := 0; attempt < 3; attempt++ {
+			_ = i.ProcessPayload([]byte(`{"ShardID":1}`), outport.TopicFinalizedBlock, CurrentPayloadVersion)
+		}
+		_ = i.ProcessPayload([]byte(`{"ShardID":2}`), outport.TopicFinalizedBlock, CurrentPayloadVersion)
+		_ = i.ProcessPayload([]byte(`{"ShardID":3}`), outport.TopicFinalizedBlock, 2)
+		require.Nil(t, recorder.Close())
+
+		files, err := GetCaptureFiles(directory)
+		require.Nil(t, err)
+		require.Len(t, files, 1)
+		file, err := os.Open(files[0])
+		require.Nil(t, err)
+		defer func() {
+			_ = file.Close()
+		}()
+
+		for _, expectedPayload := range []string{`{"ShardID":1}`, `{"ShardID":2}`, `{"ShardID":3}`} {
+			captured, errRead := ReadCapturedPayload(file)
+			require.Nil(t, errRead)
+			require.Equal(t, expectedPayload, string(captured.Payload))
+		}
+		_, err = ReadCapturedPayload(file)
+		require.Equal(t, io.EOF, err)
+	})
+
+	t.Run("unknown topic should be ignored", func(t *testing.T) {
+		t.Parallel()
+
+		i, _ := NewIndexer(createMockIndexerArgs())
+
+		err := i.ProcessPayload([]byte("payload"), "unknown", 5)
+		require.Nil(t, err)
+	})
+}
@@ -1,6 +1,8 @@
 package wsindexer
 
 import (
+	"time"
+
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 )
 
@@ -23,3 +25,31 @@ type DataIndexer interface {
 	Close() error
 	IsInterfaceNil() bool
 }
+
+// PayloadHandler defines what a payload handler should be able to do
+type PayloadHandler interface {
+	ProcessPayload(payload []byte, topic string, version uint32) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// PayloadRecorder defines what a component that records the received payloads should be able to do
+type PayloadRecorder interface {
+	Record(payload []byte, topic string, version uint32) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// PayloadDecodersHandler defines what a component that decodes the payloads, based on their topic and version,
+// should be able to do
+type PayloadDecodersHandler interface {
+	Register(topic string, version uint32, decoder PayloadDecoder) error
+	Decode(topic string, version uint32, payload []byte) (interface{}, error)
+	IsInterfaceNil() bool
+}
+
+// AckDelayProvider defines what a component that computes the delay of the payloads acknowledgement should be able to do
+type AckDelayProvider interface {
+	GetAckDelay() time.Duration
+	IsInterfaceNil() bool
+}
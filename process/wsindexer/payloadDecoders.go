@@ -0,0 +1,119 @@
+package wsindexer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+// CurrentPayloadVersion is the version of the outport payloads that are marshalled from the current outport structures
+const CurrentPayloadVersion uint32 = 1
+
+// ErrUnsupportedPayloadVersion signals that there is no decoder registered for the topic and version of a payload
+var ErrUnsupportedPayloadVersion = errors.New("unsupported payload version")
+
+var (
+	errNilPayloadDecoder        = errors.New("nil payload decoder")
+	errDecoderAlreadyRegistered = errors.New("payload decoder already registered")
+	errInvalidDecodedPayload    = errors.New("invalid decoded payload")
+)
+
+// PayloadDecoder decodes a marshalled payload into the current outport structure of its topic. A decoder registered
+// for an older or a newer payload version has to adapt the payload layout to the current outport structure
+type PayloadDecoder func(payload []byte) (interface{}, error)
+
+type decoderKey struct {
+	topic   string
+	version uint32
+}
+
+type payloadDecoders struct {
+	mut      sync.RWMutex
+	decoders map[decoderKey]PayloadDecoder
+}
+
+// NewPayloadDecoders will create a new instance of payloadDecoders that already holds the decoders for the
+// CurrentPayloadVersion of every topic. The payloads are unmarshalled with the provided marshaller
+func NewPayloadDecoders(marshaller marshal.Marshalizer) (*payloadDecoders, error) {
+	if check.IfNil(marshaller) {
+		return nil, dataindexer.ErrNilMarshalizer
+	}
+
+	pd := &payloadDecoders{
+		decoders: make(map[decoderKey]PayloadDecoder),
+	}
+
+	currentDecoders := map[string]func() interface{}{
+		outport.TopicSaveBlock:             func() interface{} { return &outport.OutportBlock{} },
+		outport.TopicRevertIndexedBlock:    func() interface{} { return &outport.BlockData{} },
+		outport.TopicSaveRoundsInfo:        func() interface{} { return &outport.RoundsInfo{} },
+		outport.TopicSaveValidatorsRating:  func() interface{} { return &outport.ValidatorsRating{} },
+		outport.TopicSaveValidatorsPubKeys: func() interface{} { return &outport.ValidatorsPubKeys{} },
+		outport.TopicSaveAccounts:          func() interface{} { return &outport.Accounts{} },
+		outport.TopicFinalizedBlock:        func() interface{} { return &outport.FinalizedBlock{} },
+		outport.TopicSettings:              func() interface{} { return &outport.OutportConfig{} },
+	}
+	for topic, createEmptyObject := range currentDecoders {
+		err := pd.Register(topic, CurrentPayloadVersion, newUnmarshalDecoder(marshaller, createEmptyObject))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pd, nil
+}
+
+func newUnmarshalDecoder(marshaller marshal.Marshalizer, createEmptyObject func() interface{}) PayloadDecoder {
+	return func(payload []byte) (interface{}, error) {
+		object := createEmptyObject()
+		err := marshaller.Unmarshal(object, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		return object, nil
+	}
+}
+
+// Register will register the decoder for the payloads with the provided topic and version
+func (pd *payloadDecoders) Register(topic string, version uint32, decoder PayloadDecoder) error {
+	if decoder == nil {
+		return errNilPayloadDecoder
+	}
+
+	pd.mut.Lock()
+	defer pd.mut.Unlock()
+
+	key := decoderKey{topic: topic, version: version}
+	_, found := pd.decoders[key]
+	if found {
+		return fmt.Errorf("%w for topic %s and version %d", errDecoderAlreadyRegistered, topic, version)
+	}
+
+	pd.decoders[key] = decoder
+
+	return nil
+}
+
+// Decode will decode the provided payload with the decoder registered for its topic and version
+func (pd *payloadDecoders) Decode(topic string, version uint32, payload []byte) (interface{}, error) {
+	pd.mut.RLock()
+	decoder, found := pd.decoders[decoderKey{topic: topic, version: version}]
+	pd.mut.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("%w: topic %s, version %d", ErrUnsupportedPayloadVersion, topic, version)
+	}
+
+	return decoder(payload)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pd *payloadDecoders) IsInterfaceNil() bool {
+	return pd == nil
+}
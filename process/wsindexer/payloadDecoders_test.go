@@ -0,0 +1,94 @@
+package wsindexer
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPayloadDecoders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil marshaller should error", func(t *testing.T) {
+		pd, err := NewPayloadDecoders(nil)
+		require.Nil(t, pd)
+		require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		pd, err := NewPayloadDecoders(&marshal.JsonMarshalizer{})
+		require.Nil(t, err)
+		require.False(t, pd.IsInterfaceNil())
+	})
+}
+
+func TestPayloadDecoders_Register(t *testing.T) {
+	t.Parallel()
+
+	pd, _ := NewPayloadDecoders(&marshal.JsonMarshalizer{})
+
+	err := pd.Register(outport.TopicSaveBlock, 2, nil)
+	require.Equal(t, errNilPayloadDecoder, err)
+
+	decoder := func(payload []byte) (interface{}, error) {
+		return &outport.OutportBlock{}, nil
+	}
+	err = pd.Register(outport.TopicSaveBlock, CurrentPayloadVersion, decoder)
+	require.True(t, errors.Is(err, errDecoderAlreadyRegistered))
+
+	err = pd.Register(outport.TopicSaveBlock, 2, decoder)
+	require.Nil(t, err)
+}
+
+func TestPayloadDecoders_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unsupported version should error", func(t *testing.T) {
+		t.Parallel()
+
+		pd, _ := NewPayloadDecoders(&marshal.JsonMarshalizer{})
+
+		decoded, err := pd.Decode(outport.TopicSaveBlock, 2, []byte("{}"))
+		require.Nil(t, decoded)
+		require.True(t, errors.Is(err, ErrUnsupportedPayloadVersion))
+	})
+
+	t.Run("current version should be unmarshalled", func(t *testing.T) {
+		t.Parallel()
+
+		pd, _ := NewPayloadDecoders(&marshal.JsonMarshalizer{})
+
+		decoded, err := pd.Decode(outport.TopicFinalizedBlock, CurrentPayloadVersion, []byte(`{"ShardID":2,"HeaderHash":"aGFzaA=="}`))
+		require.Nil(t, err)
+		require.Equal(t, &outport.FinalizedBlock{ShardID: 2, HeaderHash: []byte("hash")}, decoded)
+	})
+
+	t.Run("registered version should be adapted", func(t *testing.T) {
+		t.Parallel()
+
+		type finalizedBlockV2 struct {
+			Shard uint32 `json:"shard"`
+			Hash  []byte `json:"hash"`
+		}
+
+		pd, _ := NewPayloadDecoders(&marshal.JsonMarshalizer{})
+		err := pd.Register(outport.TopicFinalizedBlock, 2, func(payload []byte) (interface{}, error) {
+			v2 := &finalizedBlockV2{}
+			errUnmarshal := json.Unmarshal(payload, v2)
+			if errUnmarshal != nil {
+				return nil, errUnmarshal
+			}
+
+			return &outport.FinalizedBlock{ShardID: v2.Shard, HeaderHash: v2.Hash}, nil
+		})
+		require.Nil(t, err)
+
+		decoded, err := pd.Decode(outport.TopicFinalizedBlock, 2, []byte(`{"shard":1,"hash":"aGFzaA=="}`))
+		require.Nil(t, err)
+		require.Equal(t, &outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("hash")}, decoded)
+	})
+}
@@ -0,0 +1,215 @@
+package wsindexer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/process/queue"
+)
+
+const (
+	captureFilePrefix    = "capture-"
+	captureFileExtension = ".dat"
+	captureFileTimestamp = "20060102-150405.000000000"
+	timestampSize        = 8
+	filesPermissions     = 0644
+	directoryPermissions = 0755
+)
+
+// CapturedPayload holds a payload received by the indexer together with its topic, version and the time it was received
+type CapturedPayload struct {
+	Timestamp time.Time
+	queue.Record
+}
+
+// ArgsPayloadRecorder holds all the arguments needed to create a new instance of payloadRecorder
+type ArgsPayloadRecorder struct {
+	Directory          string
+	MaxFileSizeInBytes int64
+	MaxNumFiles        int
+}
+
+type payloadRecorder struct {
+	mut         sync.Mutex
+	directory   string
+	maxFileSize int64
+	maxNumFiles int
+	file        *os.File
+	fileSize    int64
+	closed      bool
+}
+
+// NewPayloadRecorder will create a new instance of payloadRecorder. Every recorded payload is appended in the current
+// capture file, and a new file is started once the maximum file size is reached
+func NewPayloadRecorder(args ArgsPayloadRecorder) (*payloadRecorder, error) {
+	if args.Directory == "" {
+		return nil, errEmptyCaptureDirectory
+	}
+	if args.MaxFileSizeInBytes <= 0 {
+		return nil, errInvalidCaptureFileSize
+	}
+
+	err := os.MkdirAll(args.Directory, directoryPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payloadRecorder{
+		directory:   args.Directory,
+		maxFileSize: args.MaxFileSizeInBytes,
+		maxNumFiles: args.MaxNumFiles,
+	}, nil
+}
+
+// Record will write the provided payload, topic and version in the current capture file
+func (pr *payloadRecorder) Record(payload []byte, topic string, version uint32) error {
+	encodedRecord, err := queue.EncodeRecord(&queue.Record{
+		Topic:   topic,
+		Version: version,
+		Payload: payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	entry := make([]byte, timestampSize+len(encodedRecord))
+	binary.BigEndian.PutUint64(entry[:timestampSize], uint64(time.Now().UnixNano()))
+	copy(entry[timestampSize:], encodedRecord)
+
+	pr.mut.Lock()
+	defer pr.mut.Unlock()
+
+	if pr.closed {
+		return errRecorderClosed
+	}
+
+	shouldRotate := pr.file == nil || (pr.fileSize > 0 && pr.fileSize+int64(len(entry)) > pr.maxFileSize)
+	if shouldRotate {
+		err = pr.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = pr.file.Write(entry)
+	if err != nil {
+		return err
+	}
+	pr.fileSize += int64(len(entry))
+
+	return nil
+}
+
+func (pr *payloadRecorder) rotate() error {
+	if pr.file != nil {
+		err := pr.file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	fileName := fmt.Sprintf("%s%s%s", captureFilePrefix, time.Now().UTC().Format(captureFileTimestamp), captureFileExtension)
+	file, err := os.OpenFile(filepath.Join(pr.directory, fileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filesPermissions)
+	if err != nil {
+		pr.file = nil
+		return err
+	}
+
+	pr.file = file
+	pr.fileSize = 0
+
+	return pr.removeOldFiles()
+}
+
+func (pr *payloadRecorder) removeOldFiles() error {
+	if pr.maxNumFiles <= 0 {
+		return nil
+	}
+
+	files, err := GetCaptureFiles(pr.directory)
+	if err != nil {
+		return err
+	}
+
+	for len(files) > pr.maxNumFiles {
+		err = os.Remove(files[0])
+		if err != nil {
+			return err
+		}
+
+		log.Debug("payloadRecorder: removed old capture file", "file", files[0])
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// Close will close the current capture file
+func (pr *payloadRecorder) Close() error {
+	pr.mut.Lock()
+	defer pr.mut.Unlock()
+
+	if pr.closed || pr.file == nil {
+		pr.closed = true
+		return nil
+	}
+	pr.closed = true
+
+	return pr.file.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pr *payloadRecorder) IsInterfaceNil() bool {
+	return pr == nil
+}
+
+// GetCaptureFiles returns the capture files from the provided directory, sorted from the oldest to the newest
+func GetCaptureFiles(directory string) ([]string, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		isCaptureFile := !entry.IsDir() && strings.HasPrefix(name, captureFilePrefix) && strings.HasSuffix(name, captureFileExtension)
+		if isCaptureFile {
+			files = append(files, filepath.Join(directory, name))
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// ReadCapturedPayload will read the next captured payload from the provided reader. It returns io.EOF when there are
+// no more payloads to be read
+func ReadCapturedPayload(reader io.Reader) (*CapturedPayload, error) {
+	timestampBytes := make([]byte, timestampSize)
+	_, err := io.ReadFull(reader, timestampBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	record, _, err := queue.ReadRecord(reader)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &CapturedPayload{
+		Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(timestampBytes))),
+		Record:    *record,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package wsindexer
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPayloadRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty directory should error", func(t *testing.T) {
+		pr, err := NewPayloadRecorder(ArgsPayloadRecorder{MaxFileSizeInBytes: 10})
+		require.Nil(t, pr)
+		require.Equal(t, errEmptyCaptureDirectory, err)
+	})
+	t.Run("invalid file size should error", func(t *testing.T) {
+		pr, err := NewPayloadRecorder(ArgsPayloadRecorder{Directory: t.TempDir()})
+		require.Nil(t, pr)
+		require.Equal(t, errInvalidCaptureFileSize, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		pr, err := NewPayloadRecorder(ArgsPayloadRecorder{Directory: t.TempDir(), MaxFileSizeInBytes: 10})
+		require.Nil(t, err)
+		require.False(t, pr.IsInterfaceNil())
+		require.Nil(t, pr.Close())
+	})
+}
+
+func TestPayloadRecorder_RecordAndRead(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pr, _ := NewPayloadRecorder(ArgsPayloadRecorder{Directory: dir, MaxFileSizeInBytes: 1024})
+
+	require.Nil(t, pr.Record([]byte("block"), "SaveBlock", 1))
+	require.Nil(t, pr.Record([]byte("rounds"), "SaveRoundsInfo", 2))
+	require.Nil(t, pr.Close())
+	require.Equal(t, errRecorderClosed, pr.Record([]byte("accounts"), "SaveAccounts", 1))
+
+	files, err := GetCaptureFiles(dir)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(files))
+
+	file, _ := os.Open(files[0])
+	defer func() {
+		_ = file.Close()
+	}()
+	reader := bufio.NewReader(file)
+
+	captured, err := ReadCapturedPayload(reader)
+	require.Nil(t, err)
+	require.Equal(t, "SaveBlock", captured.Topic)
+	require.Equal(t, uint32(1), captured.Version)
+	require.Equal(t, []byte("block"), captured.Payload)
+	require.False(t, captured.Timestamp.IsZero())
+
+	captured, err = ReadCapturedPayload(reader)
+	require.Nil(t, err)
+	require.Equal(t, "SaveRoundsInfo", captured.Topic)
+	require.Equal(t, uint32(2), captured.Version)
+
+	_, err = ReadCapturedPayload(reader)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestPayloadRecorder_ShouldRotateAndRemoveOldFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pr, _ := NewPayloadRecorder(ArgsPayloadRecorder{Directory: dir, MaxFileSizeInBytes: 10, MaxNumFiles: 2})
+
+	for i := 0; i < 5; i++ {
+		require.Nil(t, pr.Record([]byte("payload"), "SaveBlock", 1))
+	}
+	require.Nil(t, pr.Close())
+
+	files, err := GetCaptureFiles(dir)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(files))
+}
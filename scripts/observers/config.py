@@ -18,14 +18,14 @@ def update_toml_indexer(path, shard_id):
     if is_indexer_server:
         port = WS_PORT_BASE
         meta_port = WS_PORT_BASE
-        prefs_data['config']['web-socket']['mode'] = "server"
+        prefs_data['config']['web-socket'][0]['mode'] = "server"
 
     if shard_id != METACHAIN:
-        prefs_data['config']['web-socket']['url'] = f"localhost:{str(port)}"
+        prefs_data['config']['web-socket'][0]['url'] = f"localhost:{str(port)}"
     else:
-        prefs_data['config']['web-socket']['url'] = f"localhost:{str(meta_port)}"
-    prefs_data['config']['web-socket']['data-marshaller-type'] = str(os.getenv('WS_MARSHALLER_TYPE'))
-    prefs_data['config']['web-socket']['acknowledge-timeout-in-seconds'] = int(os.getenv('ACK_TIMEOUT_IN_SECONDS'))
+        prefs_data['config']['web-socket'][0]['url'] = f"localhost:{str(meta_port)}"
+    prefs_data['config']['web-socket'][0]['data-marshaller-type'] = str(os.getenv('WS_MARSHALLER_TYPE'))
+    prefs_data['config']['web-socket'][0]['acknowledge-timeout-in-seconds'] = int(os.getenv('ACK_TIMEOUT_IN_SECONDS'))
 
     f = open(path_prefs, 'w')
     toml.dump(prefs_data, f)
@@ -37,6 +37,14 @@ delete() {
 
   curl -XDELETE http://localhost:9200/_template/*
   echo
+
+  # the OpenSearch clusters and the Elasticsearch 8 client get composable index templates, the latter made of
+  # component templates
+  for str in ${INDICES_LIST[@]} "opendistro"; do
+      curl -s -o /dev/null -XDELETE http://localhost:9200/_index_template/$str
+      curl -s -o /dev/null -XDELETE http://localhost:9200/_component_template/$str-settings
+      curl -s -o /dev/null -XDELETE http://localhost:9200/_component_template/$str-mappings
+  done
 }
 
 
@@ -134,6 +134,13 @@ var Blocks = Object{
 					},
 				},
 			},
+			"final": Object{
+				"type": "boolean",
+			},
+			"finalizedAt": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
 			"gasPenalized": Object{
 				"type": "double",
 			},
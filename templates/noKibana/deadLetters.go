@@ -0,0 +1,42 @@
+package noKibana
+
+// DeadLetters will hold the configuration for the deadletters index
+var DeadLetters = Object{
+	"index_patterns": Array{
+		"deadletters-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"index": Object{
+				"type": "keyword",
+			},
+			"docID": Object{
+				"type": "keyword",
+			},
+			"operation": Object{
+				"index": "false",
+				"type":  "text",
+			},
+			"status": Object{
+				"type": "long",
+			},
+			"reason": Object{
+				"type": "text",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"nonce": Object{
+				"type": "double",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
@@ -11,6 +11,13 @@ var Miniblocks = Object{
 	},
 	"mappings": Object{
 		"properties": Object{
+			"final": Object{
+				"type": "boolean",
+			},
+			"finalizedAt": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
 			"procTypeD": Object{
 				"type": "keyword",
 			},
@@ -12,9 +12,19 @@ var Values = Object{
 
 	"mappings": Object{
 		"properties": Object{
+			"hash": Object{
+				"type": "keyword",
+			},
 			"key": Object{
 				"type": "keyword",
 			},
+			"nonce": Object{
+				"type": "double",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
 			"value": Object{
 				"type": "keyword",
 			},
@@ -20,3 +20,16 @@ func (o *Object) ToBuffer() *bytes.Buffer {
 
 	return buff
 }
+
+// ComponentTemplate holds the name and the body of a component template
+type ComponentTemplate struct {
+	Name string `json:"name"`
+	Body Object `json:"body"`
+}
+
+// ComposableTemplate holds a composable index template together with the component templates it is composed of. The
+// component templates have to be created before the index template
+type ComposableTemplate struct {
+	ComponentTemplates []ComponentTemplate `json:"component_templates"`
+	IndexTemplate      Object              `json:"index_template"`
+}
@@ -36,6 +36,13 @@ var Transactions = Object{
 			"feeNum": Object{
 				"type": "double",
 			},
+			"final": Object{
+				"type": "boolean",
+			},
+			"finalizedAt": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
 			"function": Object{
 				"type": "keyword",
 			},